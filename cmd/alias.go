@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// aliasStateFile is the name of the file holding UUID aliases
+const aliasStateFile = "aliases.json"
+
+// aliasPrefix marks an argument as an alias to resolve
+const aliasPrefix = "@"
+
+// aliasPath returns the path of the alias registry
+func aliasPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, aliasStateFile), nil
+}
+
+// loadAliases reads the registry, which is empty until the first set
+func loadAliases() (map[string]string, error) {
+	path, err := aliasPath()
+	if err != nil {
+		return nil, err
+	}
+	aliases := map[string]string{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("corrupt alias registry %s: %w", path, err)
+	}
+	return aliases, nil
+}
+
+// updateAliases applies update to the registry while holding its lock, so
+// concurrent changes are not lost, and saves the result through a rename
+func updateAliases(update func(aliases map[string]string) error) error {
+	path, err := aliasPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	if err := update(aliases); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// validateAliasName rejects names that could not be typed back as @name
+func validateAliasName(name string) error {
+	if name == "" || strings.HasPrefix(name, aliasPrefix) || strings.ContainsFunc(name, func(r rune) bool { return r <= ' ' }) {
+		return fmt.Errorf("invalid alias name '%s': names must be non-empty, contain no whitespace, and not start with %s", name, aliasPrefix)
+	}
+	return nil
+}
+
+// aliasNotFoundError is returned for an @name with no alias, so it is not
+// reported as an invalid UUID
+func aliasNotFoundError(name string) error {
+	return fmt.Errorf("alias '%s' not found; see 'uuid alias list'", name)
+}
+
+// resolveAliases replaces every @name argument with the UUID it names. The
+// registry is only read when an argument needs it.
+func resolveAliases(args []string) ([]string, error) {
+	var aliases map[string]string
+	resolved := make([]string, len(args))
+	for i, arg := range args {
+		name, ok := strings.CutPrefix(arg, aliasPrefix)
+		if !ok {
+			resolved[i] = arg
+			continue
+		}
+		if aliases == nil {
+			var err error
+			if aliases, err = loadAliases(); err != nil {
+				return nil, err
+			}
+		}
+		uuid, ok := aliases[name]
+		if !ok {
+			return nil, aliasNotFoundError(name)
+		}
+		debugLogger.Debug("resolved alias", "alias", name, "uuid", uuid)
+		resolved[i] = uuid
+	}
+	return resolved, nil
+}
+
+// aliasCmd groups the alias subcommands
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Give UUIDs short names to use as @name arguments",
+	Long: `Give UUIDs short names, kept in ` + aliasStateFile + ` under the user cache
+directory. Wherever a command takes UUIDs as arguments, @name is replaced
+with the UUID of that alias.
+
+Examples:
+  uuid alias set checkout-svc 018f3c6e-2b4a-7d1e-9c3f-5a6b7c8d9e0f
+  uuid alias get checkout-svc
+  uuid inspect @checkout-svc
+  uuid alias list
+  uuid alias rm checkout-svc`,
+	Args: cobra.NoArgs,
+}
+
+// aliasSetCmd creates or replaces an alias
+var aliasSetCmd = &cobra.Command{
+	Use:          "set <name> <uuid>",
+	Short:        "Create or replace an alias",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := validateAliasName(name); err != nil {
+			return err
+		}
+		values, err := resolveAliases(args[1:])
+		if err != nil {
+			return err
+		}
+		uuid, err := generator.ParseUUID(values[0])
+		if err != nil {
+			return fmt.Errorf("invalid UUID '%s': %w", args[1], err)
+		}
+		return updateAliases(func(aliases map[string]string) error {
+			aliases[name] = generator.FormatUUID(uuid)
+			return nil
+		})
+	},
+}
+
+// aliasGetCmd prints the UUID of an alias
+var aliasGetCmd = &cobra.Command{
+	Use:          "get <name>",
+	Short:        "Print the UUID of an alias",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(args[0], aliasPrefix)
+		uuid, ok := aliases[name]
+		if !ok {
+			return aliasNotFoundError(name)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), uuid)
+		return nil
+	},
+}
+
+// aliasListCmd prints every alias
+var aliasListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List aliases",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tUUID")
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s\t%s\n", name, aliases[name])
+		}
+		return w.Flush()
+	},
+}
+
+// aliasRmCmd removes an alias
+var aliasRmCmd = &cobra.Command{
+	Use:          "rm <name>",
+	Short:        "Remove an alias",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimPrefix(args[0], aliasPrefix)
+		return updateAliases(func(aliases map[string]string) error {
+			if _, ok := aliases[name]; !ok {
+				return aliasNotFoundError(name)
+			}
+			delete(aliases, name)
+			return nil
+		})
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd, aliasGetCmd, aliasListCmd, aliasRmCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
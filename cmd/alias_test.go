@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeAliasState points the alias registry at a temporary state directory
+func fakeAliasState(t *testing.T) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "state")
+	original := stateDir
+	stateDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { stateDir = original })
+}
+
+func TestAliasRoundTrip(t *testing.T) {
+	fakeAliasState(t)
+
+	if _, err := executeCommand(t, "", "alias", "set", "checkout-svc", "018F3C6E-2B4A-7D1E-9C3F-5A6B7C8D9E0F"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := executeCommand(t, "", "alias", "set", "auth", "919108f7-52d1-4320-9bac-f847db4148a8"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	output, err := executeCommand(t, "", "alias", "get", "checkout-svc")
+	if err != nil || output != "018f3c6e-2b4a-7d1e-9c3f-5a6b7c8d9e0f\n" {
+		t.Errorf("Expected the canonical UUID, got %q, %v", output, err)
+	}
+
+	// set replaces, and may copy another alias
+	if _, err := executeCommand(t, "", "alias", "set", "checkout-svc", "@auth"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output, _ = executeCommand(t, "", "alias", "get", "@checkout-svc")
+	if output != "919108f7-52d1-4320-9bac-f847db4148a8\n" {
+		t.Errorf("Expected the replaced UUID, got %q", output)
+	}
+
+	output, err = executeCommand(t, "", "alias", "list")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "auth ") || !strings.HasPrefix(lines[2], "checkout-svc ") {
+		t.Errorf("Expected both aliases sorted by name, got:\n%s", output)
+	}
+
+	if _, err := executeCommand(t, "", "alias", "rm", "auth"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err = executeCommand(t, "", "alias", "get", "auth")
+	if err == nil || err.Error() != "alias 'auth' not found; see 'uuid alias list'" {
+		t.Errorf("Expected a not found error, got: %v", err)
+	}
+}
+
+func TestAliasResolution(t *testing.T) {
+	fakeAliasState(t)
+	executeCommand(t, "", "alias", "set", "v7", "0188b733-b800-7079-9ce7-7022b2ba0185")
+	executeCommand(t, "", "alias", "set", "v4", "919108f7-52d1-4320-9bac-f847db4148a8")
+
+	direct, err := executeCommand(t, "", "inspect", "0188b733-b800-7079-9ce7-7022b2ba0185")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	viaAlias, err := executeCommand(t, "", "inspect", "@v7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if viaAlias != direct {
+		t.Errorf("Expected @v7 to inspect like the UUID, got:\n%s", viaAlias)
+	}
+
+	// Aliases mix with literal UUIDs
+	output, err := executeCommand(t, "", "sort", "@v4", "00000000-0000-4000-8000-000000000000", "@v7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "00000000-0000-4000-8000-000000000000\n0188b733-b800-7079-9ce7-7022b2ba0185\n919108f7-52d1-4320-9bac-f847db4148a8\n" {
+		t.Errorf("Expected the resolved UUIDs in order, got:\n%s", output)
+	}
+	if _, err := executeCommand(t, "", "inspect", "--order", "bytes", "@v4", "@v7"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	_, err = executeCommand(t, "", "inspect", "@missing")
+	if err == nil || err.Error() != "alias 'missing' not found; see 'uuid alias list'" {
+		t.Errorf("Expected a not found error rather than an invalid UUID, got: %v", err)
+	}
+}
+
+func TestAliasConcurrentWrites(t *testing.T) {
+	fakeAliasState(t)
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := updateAliases(func(aliases map[string]string) error {
+				aliases[fmt.Sprintf("svc-%d", i)] = fmt.Sprintf("00000000-0000-4000-8000-%012d", i)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	aliases, err := loadAliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases) != writers {
+		t.Errorf("Expected %d aliases, got %d: %v", writers, len(aliases), aliases)
+	}
+}
+
+func TestAliasErrors(t *testing.T) {
+	fakeAliasState(t)
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"leading at", []string{"alias", "set", "@svc", "919108f7-52d1-4320-9bac-f847db4148a8"}, "invalid alias name '@svc'"},
+		{"whitespace", []string{"alias", "set", "my svc", "919108f7-52d1-4320-9bac-f847db4148a8"}, "contain no whitespace"},
+		{"empty", []string{"alias", "set", "", "919108f7-52d1-4320-9bac-f847db4148a8"}, "must be non-empty"},
+		{"invalid uuid", []string{"alias", "set", "svc", "nope"}, "invalid UUID 'nope'"},
+		{"unknown source", []string{"alias", "set", "svc", "@nope"}, "alias 'nope' not found"},
+		{"rm missing", []string{"alias", "rm", "nope"}, "alias 'nope' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// allConflicts are the flags that choose a single version or another kind of
+// output, which --all replaces
+var allConflicts = []string{
+	"1", "4", "5", "6", "7", "8", "v4", "v6", "v7", "uuid-version",
+	"count", "name", "namespace", "v8-layout", "payload", "hmac-key-file", "node",
+	"from-hex", "sqlserver-sequential", "nanoid", "ulid", "corrupt", "idempotent", "exclude-file", "unique",
+	"per-line", "emit", "format-name", "oid", "check-digit", "header", "qr", "phonetic",
+	"variant", "group", "upper", "time-granularity", "encrypt-time",
+	"pg-copy", "pg-copy-binary", "parquet", "porcelain",
+}
+
+// allVersions is one UUID of each version --all prints, in output order.
+// The JSON field order is part of the output format.
+type allVersions struct {
+	V4 string `json:"v4"`
+	V6 string `json:"v6"`
+	V7 string `json:"v7"`
+}
+
+// writeAllVersions prints a UUIDv4, UUIDv6, and UUIDv7 as labelled lines,
+// or as one JSON object with --json. The time-based versions share one
+// instant: the -t timestamp, or the clock read once.
+func writeAllVersions(cmd *cobra.Command, timestamp string) error {
+	var instant time.Time
+	if timestamp != "" {
+		parsed, err := parseTimestampFlag(cmd, timestamp)
+		if err != nil {
+			return err
+		}
+		instant = parsed
+	} else {
+		if err := checkClock(cmd); err != nil {
+			return err
+		}
+		instant = now()
+	}
+	debugLogger.Debug("selected generator", "versions", "4,6,7", "timestamp", instant)
+
+	v6, err := generator.GenerateUUIDv6WithTimestamp(instant)
+	if err != nil {
+		return err
+	}
+	v7, err := generator.GenerateUUIDv7WithTimestamp(instant)
+	if err != nil {
+		return err
+	}
+	all := allVersions{V4: generator.GenerateUUIDv4(), V6: v6, V7: v7}
+	out := cmd.OutOrStdout()
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		line, _ := json.Marshal(all)
+		fmt.Fprintln(out, string(line))
+		return nil
+	}
+	fmt.Fprintf(out, "v4: %s\nv6: %s\nv7: %s\n", all.V4, all.V6, all.V7)
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestAllVersions(t *testing.T) {
+	output, err := executeCommand(t, "", "--all", "-t", "2023-06-14")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pattern := `^v4: ` + uuidV4 + `\nv6: 1ee0a466-7c4c-6[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}\nv7: 0188b733-b800-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}\n$`
+	if !regexp.MustCompile(pattern).MatchString(output) {
+		t.Errorf("Expected labelled v4, v6, and v7 lines for the -t instant, got %q", output)
+	}
+}
+
+func TestAllVersionsSameInstant(t *testing.T) {
+	output, err := executeCommand(t, "", "--all", "--now", "2024-03-01T10:04:12.345Z")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "v7: 018df978-aeb9-7") {
+		t.Errorf("Expected the UUIDv7 to carry the clock's instant, got %q", output)
+	}
+}
+
+func TestAllVersionsJSON(t *testing.T) {
+	output, err := executeCommand(t, "", "--all", "--json", "-t", "2023-06-14")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(output, `{"v4":"`) || strings.Count(output, "\n") != 1 {
+		t.Fatalf("Expected one JSON object starting with v4, got %q", output)
+	}
+	var got map[string]string
+	if err := json.Unmarshal([]byte(output), &got); err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %v", output, err)
+	}
+	v4, v6, v7 := strings.Index(output, `"v4"`), strings.Index(output, `"v6"`), strings.Index(output, `"v7"`)
+	if !(v4 < v6 && v6 < v7) {
+		t.Errorf("Expected keys in the order v4, v6, v7, got %q", output)
+	}
+	if !strings.HasPrefix(got["v7"], "0188b733-b800-7") {
+		t.Errorf("Expected the UUIDv7 to carry the -t timestamp, got %s", got["v7"])
+	}
+}
+
+func TestAllVersionsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"with version", []string{"--all", "-7"}, "--all cannot be combined with -7"},
+		{"with count", []string{"--all", "-n", "2"}, "--all cannot be combined with -n"},
+		{"with ulid", []string{"--all", "--ulid"}, "--all cannot be combined with --ulid"},
+		{"bad timestamp", []string{"--all", "-t", "yesterday"}, "yesterday"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
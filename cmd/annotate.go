@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// annotateTimeLayout prints timestamps to the millisecond, in UTC
+const annotateTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// annotateCandidate matches canonical UUIDs within text
+var annotateCandidate = sync.OnceValue(func() *regexp.Regexp {
+	return regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+})
+
+// annotateCmd decodes the timestamps of UUIDs in a text stream
+var annotateCmd = &cobra.Command{
+	Use:   "annotate",
+	Short: "Copy files or stdin to stdout, adding the creation time of each UUID",
+	Long: `Copy each --file in order, or stdin when none is given, to stdout line by
+line, adding the creation time of every time-based UUID (v1, v6, and v7)
+found in the line as [2024-03-01T10:04:12.345Z].
+
+  --style end     append the times to the end of the line (default)
+  --style inline  insert each time after its UUID
+
+--relative shows each UUID's age, such as [3m12.345s ago], instead of the
+time. Other UUIDs, such as v4s, are left untouched, and lines without a
+time-based UUID are copied byte for byte. Each line is written as soon as it
+is read, so the command can follow 'tail -f'.
+
+Examples:
+  tail -f app.log | uuid annotate
+  uuid annotate --style inline --relative < app.log
+  uuid annotate -f app.log.1 -f app.log`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		style, _ := cmd.Flags().GetString("style")
+		relative, _ := cmd.Flags().GetBool("relative")
+		if style != "end" && style != "inline" {
+			return fmt.Errorf("unknown --style '%s'. Available styles: end, inline", style)
+		}
+
+		out := cmd.OutOrStdout()
+		return eachInput(cmd, nil, eachExactInputLine, func(line string) error {
+			_, err := out.Write(annotateLine([]byte(line), style == "inline", relative))
+			return err
+		})
+	},
+}
+
+// annotateLine returns line with the times of its time-based UUIDs added,
+// or line itself when it has none
+func annotateLine(line []byte, inline, relative bool) []byte {
+	matches := annotateCandidate().FindAllIndex(line, -1)
+	var out, notes []byte
+	last := 0
+	for _, m := range matches {
+		uuid, err := generator.ParseUUID(string(line[m[0]:m[1]]))
+		if err != nil {
+			continue
+		}
+		// Only v1, v6, and v7 decode with a timestamp
+		details := generator.Inspect(uuid)
+		if details.Timestamp == nil {
+			continue
+		}
+		note := annotateTime(*details.Timestamp, relative)
+		if inline {
+			out = append(out, line[last:m[1]]...)
+			out = append(out, ' ')
+			out = append(out, note...)
+			last = m[1]
+		} else {
+			notes = append(notes, ' ')
+			notes = append(notes, note...)
+		}
+	}
+	if inline {
+		if out == nil {
+			return line
+		}
+		return append(out, line[last:]...)
+	}
+	if notes == nil {
+		return line
+	}
+	// The times go before the line ending, which is kept as it was
+	content := bytes.TrimRight(line, "\r\n")
+	out = append(append([]byte(nil), content...), notes...)
+	return append(out, line[len(content):]...)
+}
+
+// annotateTime formats a timestamp, or its age relative to now
+func annotateTime(ts time.Time, relative bool) string {
+	if !relative {
+		return "[" + ts.UTC().Format(annotateTimeLayout) + "]"
+	}
+	age := now().Sub(ts).Round(time.Millisecond)
+	if age < 0 {
+		return "[in " + (-age).String() + "]"
+	}
+	return "[" + age.String() + " ago]"
+}
+
+func init() {
+	annotateCmd.Flags().String("style", "end", "Where to add times: end (of the line) or inline (after each UUID)")
+	annotateCmd.Flags().Bool("relative", false, "Show each UUID's age instead of its creation time")
+	addInputFlags(annotateCmd)
+	rootCmd.AddCommand(annotateCmd)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	annotateV7 = "018df978-aeb9-7c2a-9b1e-3f4a5b6c7d8e" // 2024-03-01T10:04:12.345Z
+	annotateV6 = "1eed7b30-daa5-6a90-8000-000000000000"
+	annotateV1 = "0daa5a90-d7b3-11ee-8000-000000000000"
+	annotateV4 = "919108f7-52d1-4320-9bac-f847db4148a8"
+)
+
+func TestAnnotateStyles(t *testing.T) {
+	input := "req " + annotateV7 + " user " + annotateV4 + " ok\n" +
+		"no ids here\r\n" +
+		"a=" + annotateV6 + " b=" + strings.ToUpper(annotateV1) + " c=" + annotateV7 + "\n" +
+		"only " + annotateV4 + "\n" +
+		"last line without newline " + annotateV7
+
+	output, err := executeCommand(t, input, "annotate")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "req " + annotateV7 + " user " + annotateV4 + " ok [2024-03-01T10:04:12.345Z]\n" +
+		"no ids here\r\n" +
+		"a=" + annotateV6 + " b=" + strings.ToUpper(annotateV1) + " c=" + annotateV7 + " [2024-03-01T10:04:12.345Z] [2024-03-01T10:04:12.345Z] [2024-03-01T10:04:12.345Z]\n" +
+		"only " + annotateV4 + "\n" +
+		"last line without newline " + annotateV7 + " [2024-03-01T10:04:12.345Z]"
+	if output != want {
+		t.Errorf("Unexpected end-style output:\n%s\nwant:\n%s", output, want)
+	}
+
+	output, err = executeCommand(t, input, "annotate", "--style", "inline")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want = "req " + annotateV7 + " [2024-03-01T10:04:12.345Z] user " + annotateV4 + " ok\n" +
+		"no ids here\r\n" +
+		"a=" + annotateV6 + " [2024-03-01T10:04:12.345Z] b=" + strings.ToUpper(annotateV1) + " [2024-03-01T10:04:12.345Z] c=" + annotateV7 + " [2024-03-01T10:04:12.345Z]\n" +
+		"only " + annotateV4 + "\n" +
+		"last line without newline " + annotateV7 + " [2024-03-01T10:04:12.345Z]"
+	if output != want {
+		t.Errorf("Unexpected inline output:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestAnnotateUnmatchedLinesUnchanged(t *testing.T) {
+	input := "plain\n\n\ttabs and trailing space \r\n" + annotateV4 + "\n" +
+		"almost-" + annotateV7[:35] + "\n" + "binary \x00\xff\xfe\n"
+	output, err := executeCommand(t, input, "annotate", "--style", "inline")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != input {
+		t.Errorf("Expected the input byte for byte, got %q", output)
+	}
+}
+
+func TestAnnotateRelative(t *testing.T) {
+	original := now
+	now = func() time.Time { return time.Date(2024, 3, 1, 10, 7, 24, 690_000_000, time.UTC) }
+	t.Cleanup(func() { now = original })
+
+	output, err := executeCommand(t, annotateV7+"\n", "annotate", "--relative")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != annotateV7+" [3m12.345s ago]\n" {
+		t.Errorf("Unexpected relative output %q", output)
+	}
+
+	now = func() time.Time { return time.Date(2024, 3, 1, 10, 4, 10, 345_000_000, time.UTC) }
+	output, _ = executeCommand(t, annotateV7+"\n", "annotate", "--relative")
+	if output != annotateV7+" [in 2s]\n" {
+		t.Errorf("Unexpected future output %q", output)
+	}
+}
+
+func TestAnnotateStreams(t *testing.T) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	rootCmd.SetArgs([]string{"annotate"})
+	rootCmd.SetIn(inR)
+	rootCmd.SetOut(outW)
+	done := make(chan error)
+	go func() {
+		_, err := rootCmd.ExecuteC()
+		outW.Close()
+		done <- err
+	}()
+	t.Cleanup(func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetIn(nil)
+		rootCmd.SetOut(nil)
+		resetFlags(rootCmd)
+	})
+
+	// Each line comes out before the next is written, as under tail -f
+	lines := bufio.NewReader(outR)
+	for range 3 {
+		if _, err := io.WriteString(inW, "id="+annotateV7+"\n"); err != nil {
+			t.Fatal(err)
+		}
+		line, err := lines.ReadString('\n')
+		if err != nil || line != "id="+annotateV7+" [2024-03-01T10:04:12.345Z]\n" {
+			t.Fatalf("Expected the annotated line, got %q, %v", line, err)
+		}
+	}
+	inW.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestAnnotateFiles(t *testing.T) {
+	files := writeInputFiles(t, "first "+annotateV7+"\n\n", "second "+annotateV4)
+	output, err := executeCommand(t, "stdin "+annotateV7+"\n", "annotate", "-f", files[0], "-f", "-", "-f", files[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "first " + annotateV7 + " [2024-03-01T10:04:12.345Z]\n\n" +
+		"stdin " + annotateV7 + " [2024-03-01T10:04:12.345Z]\n" +
+		"second " + annotateV4
+	if output != want {
+		t.Errorf("Expected the files and stdin in order, got %q", output)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.log")
+	output, errOut, err := executeCommandSplit(t, "", "annotate", "--keep-going", "-f", missing, "-f", files[1])
+	if err == nil || err.Error() != "1 of 2 files failed" {
+		t.Errorf("Expected one failed file, got %v", err)
+	}
+	if !strings.Contains(errOut, missing+": no such file or directory") || output != "second "+annotateV4 {
+		t.Errorf("Expected the missing file to be named and the next one copied, got %q, %q", output, errOut)
+	}
+}
+
+func TestAnnotateUnknownStyle(t *testing.T) {
+	_, err := executeCommand(t, "", "annotate", "--style", "before")
+	if err == nil || err.Error() != "unknown --style 'before'. Available styles: end, inline" {
+		t.Errorf("Expected an unknown style error, got: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// backfillCmd maps existing keys to UUIDv7s embedding each row's timestamp
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Map existing IDs to UUIDv7s carrying each row's timestamp",
+	Long: `Read old_id,timestamp pairs as CSV from each --file in order, or stdin when
+none is given, and print old_id,new_id pairs, where new_id is a UUIDv7
+embedding the row's timestamp, for migrating a table to UUIDv7 keys whose
+time matches each row's created_at.
+
+The timestamp takes any form -t accepts (see uuid parse-timestamp); quote
+it when it contains a comma. old_id is copied through as it is. Output is
+in input order, one pair per input row, so the mapping can be joined back.
+--header skips the first row of each input and prints one old_id,new_id
+header.
+
+A row that is not two fields or whose timestamp cannot be used stops the
+run with its file and line number; the pairs already printed are valid.
+
+Examples:
+  psql -Atc "COPY (SELECT id, (extract(epoch FROM created_at) * 1000)::bigint FROM orders) TO STDOUT WITH CSV" | uuid backfill > mapping.csv
+  uuid backfill --header < export.csv
+  uuid backfill -f orders-2023.csv -f orders-2024.csv`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		header, _ := cmd.Flags().GetBool("header")
+
+		out := csv.NewWriter(cmd.OutOrStdout())
+		defer out.Flush()
+		if header {
+			if err := out.Write([]string{"old_id", "new_id"}); err != nil {
+				return err
+			}
+		}
+
+		// readBackfillCSV hands each row to the callback below through row
+		var row []string
+		rows := 0
+		err := eachInput(cmd, nil, readBackfillCSV(header, &row), func(string) error {
+			timestamp, err := parseTimestamp(row[1])
+			if err != nil {
+				return err
+			}
+			newID, err := generator.GenerateUUIDv7WithTimestamp(timestamp)
+			if err != nil {
+				return err
+			}
+			rows++
+			return out.Write([]string{row[0], newID})
+		})
+		debugLogger.Debug("backfilled rows", "rows", rows)
+		if err != nil {
+			return err
+		}
+
+		out.Flush()
+		return out.Error()
+	},
+}
+
+// readBackfillCSV returns a lineReader for old_id,timestamp CSV, skipping
+// the first row when header is set. Each row is stored in *row before fn
+// is called with its old_id, and errors are prefixed with the row's line
+// number.
+func readBackfillCSV(header bool, row *[]string) lineReader {
+	return func(r io.Reader, fn func(string) error) error {
+		in := csv.NewReader(r)
+		in.FieldsPerRecord = 2
+		in.ReuseRecord = true
+		if header {
+			if _, err := in.Read(); err != nil && err != io.EOF {
+				return fmt.Errorf("invalid CSV: %w", err)
+			}
+		}
+		for {
+			record, err := in.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				var parseErr *csv.ParseError
+				if errors.As(err, &parseErr) && parseErr.Err == csv.ErrFieldCount {
+					return fmt.Errorf("line %d: expected old_id,timestamp, got %d fields", parseErr.StartLine, len(record))
+				}
+				return fmt.Errorf("invalid CSV: %w", err)
+			}
+			line, _ := in.FieldPos(1)
+			*row = record
+			if err := fn(record[0]); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+	}
+}
+
+func init() {
+	backfillCmd.Flags().Bool("header", false, "Skip the first row of each input and print an old_id,new_id header")
+	addInputFlags(backfillCmd)
+
+	rootCmd.AddCommand(backfillCmd)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestBackfill(t *testing.T) {
+	base := time.Date(2023, 6, 14, 10, 30, 0, 0, time.UTC)
+	var input strings.Builder
+	for i := 0; i < 100; i++ {
+		// Alternate the timestamp forms so order cannot follow time
+		created := base.Add(time.Duration(100-i) * time.Minute)
+		if i%2 == 0 {
+			fmt.Fprintf(&input, "old-%d,%d\n", i, created.UnixMilli())
+		} else {
+			fmt.Fprintf(&input, "old-%d,%s\n", i, created.Format(time.RFC3339))
+		}
+	}
+
+	output, err := executeCommand(t, input.String(), "backfill")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("Expected 100 mappings, got %d", len(lines))
+	}
+	for i, line := range lines {
+		oldID, newID, ok := strings.Cut(line, ",")
+		if !ok || oldID != fmt.Sprintf("old-%d", i) {
+			t.Fatalf("Line %d: expected old-%d first, got %q", i+1, i, line)
+		}
+		uuid, err := generator.ParseUUID(newID)
+		if err != nil {
+			t.Fatalf("Line %d: invalid UUID %q: %v", i+1, newID, err)
+		}
+		info := generator.Inspect(uuid)
+		want := base.Add(time.Duration(100-i) * time.Minute)
+		if info.Version != 7 || info.Timestamp == nil || !info.Timestamp.Equal(want) {
+			t.Errorf("Line %d: expected a UUIDv7 at %s, got %+v", i+1, want, info)
+		}
+	}
+}
+
+func TestBackfillHeaderAndQuoting(t *testing.T) {
+	output, err := executeCommand(t, "id,created_at\n\"a,1\",\"2023-06-14 10:30:00\"\n", "backfill", "--header")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 2 || lines[0] != "old_id,new_id" || !strings.HasPrefix(lines[1], "\"a,1\","+generator.UUIDv7Min(time.Date(2023, 6, 14, 10, 30, 0, 0, time.UTC))[:14]) {
+		t.Errorf("Expected a header and the quoted ID, got:\n%s", output)
+	}
+}
+
+func TestBackfillFiles(t *testing.T) {
+	files := writeInputFiles(t, "id,created_at\na,2023-06-14\n", "id,created_at\nb,2023-06-15\nc,yesterday\n")
+	output, _, err := executeCommandSplit(t, "id,created_at\nz,2023-06-16\n", "backfill", "--header", "-f", files[0], "-f", "-", "-f", files[1])
+	if want := files[1] + ": line 3: unable to parse timestamp 'yesterday'"; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("Expected an error starting %q, got %v", want, err)
+	}
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		id, _, _ := strings.Cut(line, ",")
+		ids = append(ids, id)
+	}
+	if got := strings.Join(ids, " "); got != "old_id a z b" {
+		t.Errorf("Expected one header and the rows in input order, got %q", got)
+	}
+}
+
+func TestBackfillErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		stdin    string
+		contains string
+	}{
+		{"bad timestamp", "a,2023-06-14\nb,yesterday\n", "line 2: unable to parse timestamp 'yesterday'"},
+		{"missing field", "a,2023-06-14\nb\n", "line 2: expected old_id,timestamp, got 1 fields"},
+		{"before epoch", "a,1969-12-31\n", "line 1: timestamp 1969-12-31T00:00:00Z is before the Unix epoch"},
+		{"bad quoting", "a,\"2023\n", "invalid CSV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, tt.stdin, "backfill")
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// boundsCmd prints the UUIDv7 range covering a millisecond or a span
+var boundsCmd = &cobra.Command{
+	Use:   "bounds -t <timestamp>",
+	Short: "Print the smallest and largest UUIDv7 for a timestamp",
+	Long: `Print the smallest and largest possible UUIDv7 for the millisecond of
+-t, one per line, for range queries against a UUIDv7 key. The smallest has
+every bit after the timestamp zeroed and the largest has them all set,
+apart from the version and variant bits, so every UUIDv7 generated in that
+millisecond sorts between them.
+
+--span widens the range to cover -t and the span after it, up to but not
+including its end, as a Go duration or a whole number of days or weeks
+such as 1d; -t 2023-06-14 --span 1d covers the whole day in UTC.
+
+--sql prints a BETWEEN clause to paste after the key column instead.
+
+Examples:
+  uuid bounds -t 2023-06-14T10:30:00.123Z
+  uuid bounds -t 2023-06-14 --span 1d --sql`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, _ := cmd.Flags().GetString("timestamp")
+		asSQL, _ := cmd.Flags().GetBool("sql")
+
+		from, err := parseTimestamp(value)
+		if err != nil {
+			return err
+		}
+		to := from
+		if cmd.Flags().Changed("span") {
+			spanValue, _ := cmd.Flags().GetString("span")
+			span, err := parseDays(spanValue)
+			if err != nil {
+				return fmt.Errorf("invalid --span '%s': %w", spanValue, err)
+			}
+			if span < time.Millisecond {
+				return fmt.Errorf("--span must be at least 1ms, got %s", spanValue)
+			}
+			to = from.Add(span - time.Millisecond)
+		}
+
+		for _, endpoint := range []time.Time{from, to} {
+			if err := generator.CheckUUIDv7Timestamp(endpoint); err != nil {
+				return err
+			}
+		}
+		low, high := generator.UUIDv7Min(from), generator.UUIDv7Max(to)
+		if asSQL {
+			fmt.Fprintf(cmd.OutOrStdout(), "BETWEEN '%s' AND '%s'\n", low, high)
+			return nil
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n%s\n", low, high)
+		return nil
+	},
+}
+
+func init() {
+	boundsCmd.Flags().StringP("timestamp", "t", "", "Timestamp whose millisecond to bound")
+	boundsCmd.Flags().String("span", "", "Bound this span from -t instead of one millisecond, such as 1h or 1d")
+	boundsCmd.Flags().Bool("sql", false, "Print a SQL BETWEEN clause")
+	_ = boundsCmd.MarkFlagRequired("timestamp")
+
+	rootCmd.AddCommand(boundsCmd)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBounds(t *testing.T) {
+	output, err := executeCommand(t, "", "bounds", "-t", "2023-06-14")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := "0188b733-b800-7000-8000-000000000000\n0188b733-b800-7fff-bfff-ffffffffffff\n"; output != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, output)
+	}
+}
+
+func TestBoundsContainGenerated(t *testing.T) {
+	output, err := executeCommand(t, "", "bounds", "-t", "2023-06-14", "--span", "1d")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	low, high, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	if high != "0188bc5a-13ff-7fff-bfff-ffffffffffff" {
+		t.Errorf("Expected the upper bound at 23:59:59.999, got %s", high)
+	}
+
+	day := time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC)
+	for _, ts := range []time.Time{day, day.Add(12 * time.Hour), day.Add(24*time.Hour - time.Millisecond)} {
+		uuid := uuidV7At(t, ts)
+		if uuid < low || uuid > high {
+			t.Errorf("Expected %s (%s) to sort between %s and %s", uuid, ts, low, high)
+		}
+	}
+	if next := uuidV7At(t, day.Add(24*time.Hour)); next <= high {
+		t.Errorf("Expected %s from the next day to sort after %s", next, high)
+	}
+}
+
+func TestBoundsSQL(t *testing.T) {
+	output, err := executeCommand(t, "", "bounds", "-t", "2023-06-14", "--sql")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := "BETWEEN '0188b733-b800-7000-8000-000000000000' AND '0188b733-b800-7fff-bfff-ffffffffffff'\n"; output != want {
+		t.Errorf("Expected %q, got %q", want, output)
+	}
+}
+
+func TestBoundsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"missing timestamp", []string{}, `required flag(s) "timestamp" not set`},
+		{"bad timestamp", []string{"-t", "yesterday"}, "yesterday"},
+		{"bad span", []string{"-t", "2023-06-14", "--span", "often"}, "invalid --span 'often'"},
+		{"short span", []string{"-t", "2023-06-14", "--span", "0s"}, "--span must be at least 1ms, got 0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", append([]string{"bounds"}, tt.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
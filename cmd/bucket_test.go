@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBucketCommand(t *testing.T) {
+	output, err := executeCommand(t, "", "bucket", "--n", "16",
+		"0188b733-b800-7079-9ce7-7022b2ba0185", "{919108F7-52D1-4320-9BAC-F847DB4148A8}")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "0188b733-b800-7079-9ce7-7022b2ba0185\t12\n{919108F7-52D1-4320-9BAC-F847DB4148A8}\t6\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+}
+
+func TestBucketCommandConsistentStdin(t *testing.T) {
+	input := "0188b733-b800-7079-9ce7-7022b2ba0185\n\n017f22e2-79b0-7cc3-98c4-dc0c0c07398f\n"
+	output, err := executeCommand(t, input, "bucket", "--n", "16", "--consistent")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "0188b733-b800-7079-9ce7-7022b2ba0185\t6\n017f22e2-79b0-7cc3-98c4-dc0c0c07398f\t0\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+}
+
+func TestBucketCommandOnlyBucket(t *testing.T) {
+	input := "0188b733-b800-7079-9ce7-7022b2ba0185\n" +
+		"c232ab00-9414-11ec-b3c8-9f6bdeced846\n" +
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398f\n"
+	output, err := executeCommand(t, input, "bucket", "--n", "16", "--only-bucket", "4")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "c232ab00-9414-11ec-b3c8-9f6bdeced846\n017f22e2-79b0-7cc3-98c4-dc0c0c07398f\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+}
+
+func TestBucketCommandErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		contains string
+	}{
+		{"missing n", []string{"bucket", "0188b733-b800-7079-9ce7-7022b2ba0185"}, "", "--n must be at least 1"},
+		{"bucket out of range", []string{"bucket", "--n", "4", "--only-bucket", "4"}, "", "--only-bucket must be between 0 and 3"},
+		{"invalid uuid", []string{"bucket", "--n", "4"}, "0188b733-b800-7079-9ce7-7022b2ba0185\nnope\n", "line 2: invalid UUID 'nope'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, tt.stdin, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// buildTime is the RFC3339 build timestamp, overridable by build flags. When
+// empty, the VCS commit time recorded by the Go toolchain is used instead.
+var buildTime = ""
+
+// now returns the current time; tests replace it to simulate a bad clock
+var now = time.Now
+
+// defaultClockMaxFuture is how far past the build time the clock may be before
+// it is considered implausible
+const defaultClockMaxFuture = 10 * 365 * 24 * time.Hour
+
+// binaryBuildTime returns when this binary was built, if known
+func binaryBuildTime() (time.Time, bool) {
+	if buildTime != "" {
+		if t, err := time.Parse(time.RFC3339, buildTime); err == nil {
+			return t, true
+		}
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.time" {
+				if t, err := time.Parse(time.RFC3339, setting.Value); err == nil {
+					return t, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// addClockFlags registers the flags checkClock reads
+func addClockFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("strict-clock", false, "Fail instead of warning when the system clock looks wrong")
+	cmd.Flags().Bool("no-clock-check", false, "Skip the system clock sanity check")
+	cmd.Flags().Duration("clock-max-future", defaultClockMaxFuture, "How far past the build time the system clock may be before it is considered wrong")
+	cmd.MarkFlagsMutuallyExclusive("strict-clock", "no-clock-check")
+}
+
+// checkClock compares the system clock against a plausibility window starting
+// at the binary's build time. Problems are printed as warnings, or returned as
+// errors under --strict-clock. The check is skipped with --no-clock-check or
+// when the build time is unknown.
+func checkClock(cmd *cobra.Command) error {
+	if skip, _ := cmd.Flags().GetBool("no-clock-check"); skip {
+		return nil
+	}
+	// A pinned clock is deliberate, so there is nothing to sanity check
+	if flag := cmd.Flags().Lookup("now"); flag != nil && flag.Changed {
+		return nil
+	}
+	built, ok := binaryBuildTime()
+	if !ok {
+		return nil
+	}
+
+	strict, _ := cmd.Flags().GetBool("strict-clock")
+	maxFuture, _ := cmd.Flags().GetDuration("clock-max-future")
+
+	current := now()
+	var problem string
+	if current.Before(built) {
+		problem = fmt.Sprintf("system clock (%s) is earlier than this binary's build time (%s)",
+			current.UTC().Format(time.RFC3339), built.UTC().Format(time.RFC3339))
+	} else if current.Sub(built) > maxFuture {
+		problem = fmt.Sprintf("system clock (%s) is more than %s past this binary's build time (%s)",
+			current.UTC().Format(time.RFC3339), maxFuture, built.UTC().Format(time.RFC3339))
+	}
+
+	if problem == "" {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("%s; refusing to generate time-based UUIDs (--strict-clock)", problem)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: %s. Time-based UUIDs will embed this time. Use --no-clock-check to silence this warning.\n", problem)
+	return nil
+}
+
+// restoreClock undoes the --now override once the command finishes
+var restoreClock func()
+
+// applyNowOverride pins the clock used by every generator and by this package
+// to the --now timestamp. It is a testing facility for reproducible runs.
+func applyNowOverride(cmd *cobra.Command) error {
+	flag := cmd.Flags().Lookup("now")
+	if flag == nil || !flag.Changed {
+		return nil
+	}
+	pinned, err := parseTimestamp(flag.Value.String())
+	if err != nil {
+		return fmt.Errorf("invalid --now: %w", err)
+	}
+	if check := nowRangeCheck(cmd); check != nil {
+		if err := check(pinned); err != nil {
+			return fmt.Errorf("invalid --now: %w", err)
+		}
+	}
+
+	saved := now
+	now = func() time.Time { return pinned }
+	generator.SetClock(now)
+	restoreClock = func() {
+		now = saved
+		generator.SetClock(nil)
+	}
+	return nil
+}
+
+// nowRangeCheck returns the range check -t applies to the generator cmd
+// selects, so --now is held to the same range: the Gregorian one for UUIDv1
+// and UUIDv6, the layout's for -8 --v8-layout, and the UUIDv7 one, which
+// ULIDs and SQL Server sequential UUIDs share. It returns nil when the
+// selected generator embeds no timestamp, or when the version flags are
+// invalid, which RunE reports.
+func nowRangeCheck(cmd *cobra.Command) func(time.Time) error {
+	selected, err := versionFlag(cmd)
+	if err != nil {
+		return nil
+	}
+	enabled := func(name string) bool {
+		value, _ := cmd.Flags().GetBool(name)
+		return value
+	}
+	switch {
+	case selected == 1 || selected == 6:
+		return generator.CheckUUIDv6Timestamp
+	case selected == 8:
+		spec, _ := cmd.Flags().GetString("v8-layout")
+		if layout, err := generator.ParseV8Layout(spec); err == nil && spec != "" {
+			return layout.CheckTimestamp
+		}
+		return nil
+	case selected == 7, enabled("ulid"), enabled("sqlserver-sequential"), enabled("all"):
+		// --all embeds one instant in a UUIDv6 and a UUIDv7; the UUIDv7
+		// range is the narrower
+		return generator.CheckUUIDv7Timestamp
+	}
+	return nil
+}
+
+func init() {
+	cobra.OnFinalize(func() {
+		if restoreClock != nil {
+			restoreClock()
+			restoreClock = nil
+		}
+	})
+}
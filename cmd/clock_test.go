@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// withClock sets the build time and current time for the duration of a test
+func withClock(t *testing.T, built string, current time.Time) {
+	t.Helper()
+	originalBuildTime, originalNow := buildTime, now
+	buildTime = built
+	now = func() time.Time { return current }
+	t.Cleanup(func() {
+		buildTime, now = originalBuildTime, originalNow
+	})
+}
+
+func TestClockCheckPlausible(t *testing.T) {
+	withClock(t, "2025-01-01T00:00:00Z", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	output, err := executeCommand(t, "", "-7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(output, "WARNING") {
+		t.Errorf("No warning expected for a plausible clock, got: %q", output)
+	}
+}
+
+func TestClockCheckBeforeBuildTime(t *testing.T) {
+	withClock(t, "2025-01-01T00:00:00Z", time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	output, err := executeCommand(t, "", "-7")
+	if err != nil {
+		t.Fatalf("Warning mode should not fail, got: %v", err)
+	}
+	if !strings.Contains(output, "WARNING: system clock (2019-03-01T00:00:00Z) is earlier than this binary's build time") {
+		t.Errorf("Expected clock warning, got: %q", output)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if !uuidRegex.MatchString(lines[len(lines)-1]) {
+		t.Errorf("UUID should still be generated, got: %q", output)
+	}
+}
+
+func TestClockCheckTooFarInFuture(t *testing.T) {
+	withClock(t, "2025-01-01T00:00:00Z", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	output, err := executeCommand(t, "", "-6", "--clock-max-future", "24h")
+	if err != nil {
+		t.Fatalf("Warning mode should not fail, got: %v", err)
+	}
+	if !strings.Contains(output, "is more than 24h0m0s past this binary's build time") {
+		t.Errorf("Expected future clock warning, got: %q", output)
+	}
+}
+
+func TestClockCheckStrict(t *testing.T) {
+	withClock(t, "2025-01-01T00:00:00Z", time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	output, err := executeCommand(t, "", "-7", "--strict-clock")
+	if err == nil {
+		t.Fatal("Expected --strict-clock to fail on an implausible clock")
+	}
+	if !strings.Contains(err.Error(), "refusing to generate") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if uuidRegex.MatchString(strings.TrimSpace(output)) {
+		t.Errorf("No UUID should be printed in strict mode, got: %q", output)
+	}
+}
+
+func TestClockCheckSkipped(t *testing.T) {
+	withClock(t, "2025-01-01T00:00:00Z", time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no-clock-check", []string{"-7", "--no-clock-check"}},
+		{"UUIDv4 does not use the clock", []string{"-4"}},
+		{"explicit timestamp", []string{"-t", "2023-06-14"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(t, "", tt.args...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Contains(output, "WARNING") {
+				t.Errorf("Clock check should be skipped, got: %q", output)
+			}
+		})
+	}
+}
+
+func TestNowOverride(t *testing.T) {
+	pinned := time.Date(2023, 6, 14, 15, 30, 45, 0, time.UTC)
+
+	for _, flag := range []string{"-6", "-7"} {
+		output, err := executeCommand(t, "", "--now", "2023-06-14T15:30:45Z", flag, "-n", "3")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			uuid, err := generator.ParseUUID(line)
+			if err != nil {
+				t.Fatalf("Invalid UUID %q: %v", line, err)
+			}
+			if ts := generator.Inspect(uuid).Timestamp; ts == nil || !ts.Equal(pinned) {
+				t.Errorf("%s output should embed the --now instant, got %v", flag, ts)
+			}
+		}
+	}
+
+	// The override must not leak into later commands
+	output, err := executeCommand(t, "", "-7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	uuid, _ := generator.ParseUUID(strings.TrimSpace(output))
+	if ts := generator.Inspect(uuid).Timestamp; ts == nil || ts.Year() == 2023 {
+		t.Errorf("The clock should be restored after the command, got %v", ts)
+	}
+}
+
+func TestNowOverrideSkipsClockCheck(t *testing.T) {
+	withClock(t, "2025-01-01T00:00:00Z", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	output, err := executeCommand(t, "", "--now", "2001-01-01", "-7", "--strict-clock")
+	if err != nil {
+		t.Fatalf("A pinned clock should not be sanity checked, got: %v", err)
+	}
+	if strings.Contains(output, "WARNING") {
+		t.Errorf("Expected no clock warning, got: %s", output)
+	}
+}
+
+func TestNowOverrideInvalid(t *testing.T) {
+	_, err := executeCommand(t, "", "--now", "yesterday-ish")
+	if err == nil || !strings.Contains(err.Error(), "invalid --now: unable to parse timestamp 'yesterday-ish'") {
+		t.Errorf("Expected an invalid --now error, got: %v", err)
+	}
+}
+
+func TestNowOverrideOutOfRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"v7 before the epoch", []string{"--now", "1500-01-01", "-7"}, "invalid --now: timestamp 1500-01-01T00:00:00Z is before the Unix epoch"},
+		{"ulid before the epoch", []string{"--now", "1969-12-31", "--ulid"}, "is before the Unix epoch"},
+		{"v6 before the Gregorian calendar", []string{"--now", "1500-01-01", "-6"}, "the first UUIDv6 timestamp"},
+		{"v1 before the Gregorian calendar", []string{"--now", "1500-01-01", "-1"}, "the first UUIDv6 timestamp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Fatalf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+			if !errors.Is(err, generator.ErrTimestampOutOfRange) {
+				t.Errorf("Expected ErrTimestampOutOfRange, got: %v", err)
+			}
+		})
+	}
+
+	// UUIDv6 reaches back to 1582, before the UUIDv7 range starts
+	pinned := time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC)
+	output, err := executeCommand(t, "", "--now", "1600-01-01", "-6")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	uuid, _ := generator.ParseUUID(strings.TrimSpace(output))
+	if ts := generator.Inspect(uuid).Timestamp; ts == nil || !ts.Equal(pinned) {
+		t.Errorf("Expected -6 to embed %v, got %v", pinned, ts)
+	}
+}
+
+func TestNowOverrideConfiguredLayout(t *testing.T) {
+	dir := fakeConfigDir(t)
+	os.MkdirAll(dir, 0o700)
+	if err := os.WriteFile(filepath.Join(dir, timestampLayoutsFile), []byte(`{"test-compact": "20060102T1504"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := executeCommand(t, "", "--now", "20220222T1922", "-7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(output, "017f22e2-23c0-7") {
+		t.Errorf("Expected --now to accept the configured layout, got %q", output)
+	}
+}
+
+func TestNowOverrideResolvedVersion(t *testing.T) {
+	// The range follows the version selected, however it is spelled, and
+	// versions without a timestamp take any --now
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"long v6", []string{"--now", "1960-01-01", "--v6"}},
+		{"uuid-version 6", []string{"--now", "1960-01-01", "--uuid-version", "6"}},
+		{"uuid-version 1", []string{"--now", "1960-01-01", "--uuid-version", "1"}},
+		{"v4", []string{"--now", "1500-01-01", "-4"}},
+		{"default version", []string{"--now", "1500-01-01"}},
+		{"inspect", []string{"--now", "1500-01-01", "inspect", "0189e5f8-7b2c-7a4d-8e3f-1a2b3c4d5e6f"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := executeCommand(t, "", tt.args...); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+
+	_, err := executeCommand(t, "", "--now", "1960-01-01", "--uuid-version", "7")
+	if !errors.Is(err, generator.ErrTimestampOutOfRange) {
+		t.Errorf("Expected --uuid-version 7 to reject a pre-epoch --now, got: %v", err)
+	}
+}
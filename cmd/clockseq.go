@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// clockSequenceStateFile is the name of the file persisting the UUIDv6
+// clock sequence, unless --clock-state names another
+const clockSequenceStateFile = "clockseq.json"
+
+// clockSequenceState is the persisted UUIDv6 clock sequence and the
+// timestamp of the last UUIDv6 generated with it
+type clockSequenceState struct {
+	ClockSeq      uint16    `json:"clock_seq"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+}
+
+// uuidV6Generator returns the generator for -6 with node, or with random
+// nodes when it is nil, and a function to call once generation is done.
+//
+// The clock sequence is persisted between runs in --clock-state or the
+// state file in the cache directory. The file is locked from loading until
+// the returned function has saved it back, so concurrent runs take turns
+// rather than reusing a sequence. With --stateless, or when the state file
+// cannot be used, every UUID gets a random clock sequence as before.
+func uuidV6Generator(cmd *cobra.Command, node *[6]byte) (func() string, func()) {
+	g := &generator.Generator{Node: node}
+	generate := func() string {
+		uuid, err := g.NewV6(context.Background())
+		if err != nil {
+			// As the package-level generators do; see withRandomFile
+			panic(err)
+		}
+		return uuid
+	}
+	if stateless, _ := cmd.Flags().GetBool("stateless"); stateless {
+		debugLogger.Debug("selected clock sequence", "clock_seq_source", "random")
+		return generate, func() {}
+	}
+
+	var unlock func()
+	path, err := clockSequenceStatePath(cmd)
+	if err == nil {
+		g.ClockSequence, unlock, err = lockClockSequence(path)
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: cannot use the clock sequence state (%v); using random clock sequences instead.\n", err)
+		return generate, func() {}
+	}
+	debugLogger.Debug("selected clock sequence", "clock_seq_source", "state", "path", path)
+
+	save := func() {
+		defer unlock()
+		if err := saveClockSequence(path, g.ClockSequence); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: cannot save the clock sequence state: %v\n", err)
+		}
+	}
+	return generate, save
+}
+
+// lockClockSequence locks the state file at path with lockStateFile,
+// creating its directory if needed, and loads the clock sequence from it.
+// The caller saves the sequence and then unlocks.
+func lockClockSequence(path string) (*generator.ClockSequence, func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, nil, err
+	}
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := loadClockSequence(path)
+	if err != nil {
+		unlock()
+		return nil, nil, err
+	}
+	return c, unlock, nil
+}
+
+// clockSequenceStatePath returns --clock-state, or the state file in the
+// cache directory
+func clockSequenceStatePath(cmd *cobra.Command) (string, error) {
+	if path, _ := cmd.Flags().GetString("clock-state"); path != "" {
+		return path, nil
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, clockSequenceStateFile), nil
+}
+
+// loadClockSequence restores the clock sequence persisted at path, or
+// returns a fresh one, seeded at random on first use, when there is none
+func loadClockSequence(path string) (*generator.ClockSequence, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return new(generator.ClockSequence), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state clockSequenceState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("corrupt state file %s: %w", path, err)
+	}
+	if state.ClockSeq > 0x3fff {
+		return nil, fmt.Errorf("corrupt state file %s: clock sequence %d does not fit in 14 bits", path, state.ClockSeq)
+	}
+	return generator.RestoreClockSequence(state.ClockSeq, state.LastTimestamp), nil
+}
+
+// saveClockSequence persists the clock sequence at path, unless no UUID
+// was generated with it. The caller holds the lock from lockClockSequence.
+// The file is replaced with replaceFile, so a crash leaves the previous
+// state rather than a partial one.
+func saveClockSequence(path string, c *generator.ClockSequence) error {
+	seq, last, ok := c.State()
+	if !ok {
+		return nil
+	}
+	data, _ := json.Marshal(clockSequenceState{ClockSeq: seq, LastTimestamp: last})
+	return replaceFile(path, append(data, '\n'))
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// clockSeqOf returns the clock sequence of a UUIDv6 printed by the command
+func clockSeqOf(t *testing.T, output string) int {
+	t.Helper()
+	uuid, err := generator.ParseUUID(strings.TrimSpace(output))
+	if err != nil {
+		t.Fatalf("Invalid UUID %q: %v", output, err)
+	}
+	return *generator.Inspect(uuid).ClockSeq
+}
+
+func TestClockSequencePersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "clockseq.json")
+	run := func(now string) int {
+		t.Helper()
+		output, err := executeCommand(t, "", "-6", "--clock-state", path, "--now", now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return clockSeqOf(t, output)
+	}
+
+	first := run("2024-03-01T10:00:00Z")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the state file to be written: %v", err)
+	}
+	var state clockSequenceState
+	if err := json.Unmarshal(data, &state); err != nil || int(state.ClockSeq) != first || state.LastTimestamp.Format("15:04") != "10:00" {
+		t.Errorf("Expected clock sequence %d at 10:00 in the state file, got %s", first, data)
+	}
+
+	// The clock has advanced, so the sequence holds
+	if got := run("2024-03-01T10:00:01Z"); got != first {
+		t.Errorf("Expected the clock sequence to hold while the clock advances, got %d then %d", first, got)
+	}
+	// An NTP step back between runs increments it, and it stays incremented
+	if got := run("2024-03-01T09:00:00Z"); got != (first+1)&0x3fff {
+		t.Errorf("Expected the clock sequence to increment when the clock goes backwards, got %d then %d", first, got)
+	}
+	if got := run("2024-03-01T11:00:00Z"); got != (first+1)&0x3fff {
+		t.Errorf("Expected the incremented clock sequence to persist, got %d", got)
+	}
+}
+
+func TestClockSequenceWithinRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clockseq.json")
+	output, err := executeCommand(t, "", "-6", "-n", "3", "--node", "02005e100001", "--clock-state", path, "--now", "2024-03-01T10:00:00Z")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Fields(output)
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 UUIDs, got %q", output)
+	}
+	// The pinned clock never advances, so each UUID takes the next sequence
+	first := clockSeqOf(t, lines[0])
+	for i, line := range lines {
+		if got := clockSeqOf(t, line); got != (first+i)&0x3fff {
+			t.Errorf("UUID %d: expected clock sequence %d, got %d", i, (first+i)&0x3fff, got)
+		}
+	}
+}
+
+func TestClockSequenceDefaultPath(t *testing.T) {
+	dir := t.TempDir()
+	original := cacheDir
+	cacheDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { cacheDir = original })
+
+	if _, err := executeCommand(t, "", "-6"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, clockSequenceStateFile)); err != nil {
+		t.Errorf("Expected the state file in the cache directory: %v", err)
+	}
+}
+
+func TestClockSequenceConcurrentRuns(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	generator.SetClock(func() time.Time { return pinned })
+	t.Cleanup(func() { generator.SetClock(nil) })
+
+	// Each goroutine stands in for a separate invocation. The clock never
+	// advances, so every UUIDv6 must take a clock sequence no other run took.
+	path := filepath.Join(t.TempDir(), "clockseq.json")
+	node := [6]byte{0x02, 0x00, 0x5e, 0x10, 0x00, 0x01}
+	const runs = 16
+	seqs := make(chan int, runs)
+	errs := make(chan error, runs)
+	var wg sync.WaitGroup
+	for range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, unlock, err := lockClockSequence(path)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer unlock()
+			g := &generator.Generator{Node: &node, ClockSequence: c}
+			uuid, err := g.NewV6(context.Background())
+			if err != nil {
+				errs <- err
+				return
+			}
+			seqs <- clockSeqOf(t, uuid)
+			errs <- saveClockSequence(path, c)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	close(seqs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	seen := map[int]bool{}
+	for seq := range seqs {
+		if seen[seq] {
+			t.Errorf("Clock sequence %d was used by two runs", seq)
+		}
+		seen[seq] = true
+	}
+	if len(seen) != runs {
+		t.Errorf("Expected %d clock sequences, got %d", runs, len(seen))
+	}
+}
+
+func TestClockSequenceStateless(t *testing.T) {
+	dir := t.TempDir()
+	original := cacheDir
+	cacheDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { cacheDir = original })
+
+	output, err := executeCommand(t, "", "-6", "-n", "2", "--stateless")
+	if err != nil || strings.Count(output, "\n") != 2 {
+		t.Fatalf("Expected 2 UUIDs, got %q, %v", output, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected --stateless to write no state, found %v", entries)
+	}
+}
+
+func TestClockSequenceCorruptState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clockseq.json")
+	for _, content := range []string{"not json", `{"clock_seq": 20000}`} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		stdout, stderr, err := executeCommandSplit(t, "", "-6", "--clock-state", path)
+		if err != nil || !uuidRegex.MatchString(strings.TrimSpace(stdout)) {
+			t.Fatalf("Expected a UUIDv6 despite the corrupt state, got %q, %v", stdout, err)
+		}
+		if !strings.Contains(stderr, "WARNING: cannot use the clock sequence state (corrupt state file") {
+			t.Errorf("Expected a corrupt state warning, got %q", stderr)
+		}
+	}
+}
+
+func TestClockSequenceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"stateless without v6", []string{"-7", "--stateless"}, "--stateless requires -6 without -t"},
+		{"clock state without v6", []string{"--clock-state", "x.json"}, "--clock-state requires -6 without -t"},
+		{"with timestamp", []string{"-6", "-t", "2024-01-01", "--stateless"}, "--stateless requires -6 without -t"},
+		{"both", []string{"-6", "--stateless", "--clock-state", "x.json"}, "none of the others can be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestConvertImmutableID(t *testing.T) {
+	tests := []struct {
+		guid        string
+		immutableID string
+	}{
+		{"00112233-4455-6677-8899-aabbccddeeff", "MyIRAFVEd2aImaq7zN3u/w=="},
+		{"d9428888-122b-11e1-b85c-61cd3cbb3210", "iIhC2SsS4RG4XGHNPLsyEA=="},
+		{"a1b2c3d4-e5f6-4789-8abc-def012345678", "1MOyofbliUeKvN7wEjRWeA=="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.guid, func(t *testing.T) {
+			output, err := executeCommand(t, "", "convert", "--to", "immutableid", tt.guid)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != tt.immutableID+"\n" {
+				t.Errorf("Expected %s, got %q", tt.immutableID, output)
+			}
+
+			output, err = executeCommand(t, "", "convert", "--from", "immutableid", tt.immutableID)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != tt.guid+"\n" {
+				t.Errorf("Expected %s, got %q", tt.guid, output)
+			}
+		})
+	}
+}
+
+func TestConvertStdin(t *testing.T) {
+	input := "{00112233-4455-6677-8899-AABBCCDDEEFF}\n\nurn:uuid:d9428888-122b-11e1-b85c-61cd3cbb3210\n"
+	output, err := executeCommand(t, input, "convert", "--to", "immutableid")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "MyIRAFVEd2aImaq7zN3u/w==\niIhC2SsS4RG4XGHNPLsyEA==\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		contains string
+	}{
+		{"invalid base64", []string{"convert", "--from", "immutableid", "not*base64"}, "", "cannot convert 'not*base64': invalid base64 value"},
+		{"wrong length", []string{"convert", "--from", "immutableid", "AAAAAAAAAAAAAAAAAAAA"}, "", "decodes to 15 bytes, expected 16"},
+		{"bad stdin line", []string{"convert", "--from", "immutableid"}, "MyIRAFVEd2aImaq7zN3u/w==\nAAAA\n", "line 2"},
+		{"unknown target", []string{"convert", "--to", "base99", "00112233-4455-6677-8899-aabbccddeeff"}, "", "unknown encoding 'base99'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, tt.stdin, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestConvertObjectID(t *testing.T) {
+	output, err := executeCommand(t, "", "convert", "--to", "objectid", "--deterministic", "017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "621537ee4e2e22477383ff21\n" {
+		t.Errorf("Unexpected ObjectID %q", output)
+	}
+
+	output, err = executeCommand(t, "", "convert", "--from", "objectid", "--to", "canonical", "621537ee4e2e22477383ff21")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	uuid, err := generator.ParseUUID(strings.TrimSpace(output))
+	if err != nil {
+		t.Fatalf("Invalid UUID %q: %v", output, err)
+	}
+	details := generator.Inspect(uuid)
+	if details.Version != 7 || details.Timestamp.UnixMilli() != 1645557742000 {
+		t.Errorf("Expected a UUIDv7 at 2022-02-22T19:22:22Z, got %+v", details)
+	}
+
+	_, err = executeCommand(t, "", "convert", "--to", "objectid", "919108f7-52d1-4320-9bac-f847db4148a8")
+	if err == nil || !strings.Contains(err.Error(), "UUID version 4 has no embedded timestamp") {
+		t.Errorf("Expected a missing timestamp error, got: %v", err)
+	}
+}
+
+func TestConvertSnowflake(t *testing.T) {
+	output, err := executeCommand(t, "", "convert", "--from", "snowflake", "--epoch", "2010-11-04T01:42:54.657Z", "1050118621198921728")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	value := strings.TrimSpace(output)
+	uuid, err := generator.ParseUUID(value)
+	if err != nil {
+		t.Fatalf("Invalid UUID %q: %v", output, err)
+	}
+	if ts := generator.Inspect(uuid).Timestamp; ts == nil || ts.UnixMilli() != 1539202764211 {
+		t.Errorf("Expected the tweet's creation time 2018-10-10T20:19:24.211Z, got %v", ts)
+	}
+
+	output, err = executeCommand(t, "", "convert", "--to", "snowflake", value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "1050118621198921728\n" {
+		t.Errorf("Expected the original ID back, got %q", output)
+	}
+
+	// The same UUID read against a different epoch yields a different ID
+	output, err = executeCommand(t, "", "convert", "--to", "snowflake", "--epoch", "2015-01-01", value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output == "1050118621198921728\n" {
+		t.Error("--epoch should change the recovered ID")
+	}
+
+	_, err = executeCommand(t, "", "convert", "--from", "snowflake", "--epoch", "soon", "1")
+	if err == nil || !strings.Contains(err.Error(), "invalid --epoch") {
+		t.Errorf("Expected an invalid --epoch error, got: %v", err)
+	}
+}
+
+func TestConvertV1ToV6(t *testing.T) {
+	input := "c232ab00-9414-11ec-b3c8-9f6bdeced846\n{D9428888-122B-11E1-B85C-61CD3CBB3210}\n"
+	output, err := executeCommand(t, input, "convert", "--to", "v6")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || lines[0] != "1ec9414c-232a-6b00-b3c8-9f6bdeced846" {
+		t.Fatalf("Expected the RFC 9562 UUIDv6 example first, got %q", output)
+	}
+	for i, v1 := range strings.Fields(input) {
+		before, _ := generator.ParseUUID(v1)
+		after, err := generator.ParseUUID(lines[i])
+		if err != nil {
+			t.Fatalf("Invalid UUID %q: %v", lines[i], err)
+		}
+		b, a := generator.Inspect(before), generator.Inspect(after)
+		if a.Version != 6 || !a.Timestamp.Equal(*b.Timestamp) || *a.ClockSeq != *b.ClockSeq || *a.Node != *b.Node {
+			t.Errorf("Expected %s to keep the timestamp, clock sequence, and node of %s", lines[i], v1)
+		}
+	}
+
+	_, err = executeCommand(t, "", "convert", "--to", "v6", "919108f7-52d1-4320-9bac-f847db4148a8")
+	if err == nil || !strings.Contains(err.Error(), "cannot convert '919108f7-52d1-4320-9bac-f847db4148a8': only UUIDv1 converts to UUIDv6, got version 4") {
+		t.Errorf("Expected a version error, got: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// corruptKinds are the mutations --corrupt can apply. Each is named after the
+// validate reason code it triggers.
+var corruptKinds = []string{
+	generator.ReasonLength,
+	generator.ReasonHyphens,
+	generator.ReasonHex,
+	generator.ReasonVersion,
+	generator.ReasonVariant,
+}
+
+// nonHexCharacters replace a digit for the hex mutation
+const nonHexCharacters = "ghijklmnopqrstuvwxyzGHIJKLMNOPQRSTUVWXYZ_!."
+
+// writeCorrupt prints count near-miss UUIDs. The valid UUIDv4s they start from
+// and the mutations applied share one ChaCha8 stream, so --seed reproduces the
+// output exactly.
+func writeCorrupt(cmd *cobra.Command, kind string, count int) error {
+	if kind != "random" && !slices.Contains(corruptKinds, kind) {
+		return fmt.Errorf("unknown corruption '%s'. Available kinds: %s, random", kind, strings.Join(corruptKinds, ", "))
+	}
+	seed, err := seedFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	source := rand.NewChaCha8(key)
+	rng := rand.New(source)
+	debugLogger.Debug("selected generator", "format", "corrupt", "kind", kind, "seed", seed, "count", count)
+
+	out := cmd.OutOrStdout()
+	for i := 0; i < count; i++ {
+		var random [16]byte
+		source.Read(random[:])
+		k := kind
+		if k == "random" {
+			k = corruptKinds[rng.IntN(len(corruptKinds))]
+		}
+		fmt.Fprintln(out, corruptUUID(rng, k, generator.NewV4FromBytes(random)))
+	}
+	return nil
+}
+
+// corruptUUID applies one mutation to a canonical UUID
+func corruptUUID(rng *rand.Rand, kind, canonical string) string {
+	b := []byte(canonical)
+	switch kind {
+	case generator.ReasonLength:
+		// Drop or add one to four characters
+		n := 1 + rng.IntN(4)
+		if rng.IntN(2) == 0 {
+			return canonical[:len(canonical)-n]
+		}
+		for i := 0; i < n; i++ {
+			b = append(b, "0123456789abcdef"[rng.IntN(16)])
+		}
+	case generator.ReasonHyphens:
+		// Swap a hyphen with a neighbouring digit, keeping the length
+		h := []int{8, 13, 18, 23}[rng.IntN(4)]
+		n := h - 1 + 2*rng.IntN(2)
+		b[h], b[n] = b[n], b[h]
+	case generator.ReasonHex:
+		var digits []int
+		for i, c := range b {
+			if c != '-' {
+				digits = append(digits, i)
+			}
+		}
+		b[digits[rng.IntN(len(digits))]] = nonHexCharacters[rng.IntN(len(nonHexCharacters))]
+	case generator.ReasonVersion:
+		// Versions 0 and 9-f are not defined
+		b[14] = "09abcdef"[rng.IntN(8)]
+	case generator.ReasonVariant:
+		// NCS, Microsoft, and future variants
+		b[19] = "01234567cdef"[rng.IntN(12)]
+	}
+	return string(b)
+}
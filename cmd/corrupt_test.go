@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestCorruptFailsValidation(t *testing.T) {
+	kinds := append(slices.Clone(corruptKinds), "random")
+	for _, kind := range kinds {
+		t.Run(kind, func(t *testing.T) {
+			output, err := executeCommand(t, "", "--corrupt", kind, "-n", "200")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+			if len(lines) != 200 {
+				t.Fatalf("Expected 200 values, got %d", len(lines))
+			}
+
+			seen := map[string]bool{}
+			for _, value := range lines {
+				var verr *generator.ValidationError
+				if err := generator.Validate(value); !errors.As(err, &verr) {
+					t.Fatalf("Expected %q to fail validation, got: %v", value, err)
+				}
+				if kind != "random" && verr.Reason != kind {
+					t.Errorf("Expected %q to fail with reason %s, got %s", value, kind, verr.Reason)
+				}
+				seen[verr.Reason] = true
+			}
+			if kind == "random" && len(seen) != len(corruptKinds) {
+				t.Errorf("Expected every kind among 200 random corruptions, got %v", seen)
+			}
+		})
+	}
+}
+
+func TestCorruptValidateCommand(t *testing.T) {
+	input, err := executeCommand(t, "", "--corrupt", "version", "-n", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output, err := executeCommand(t, input, "validate")
+	if err == nil || !strings.Contains(err.Error(), "3 of 3 values are invalid") {
+		t.Errorf("Expected every value to be rejected, got: %v", err)
+	}
+	if strings.Count(output, "\tversion\t") != 3 {
+		t.Errorf("Expected three version failures, got %q", output)
+	}
+}
+
+func TestCorruptSeed(t *testing.T) {
+	first, err := executeCommand(t, "", "--corrupt", "random", "-n", "10", "--seed", "42")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := executeCommand(t, "", "--corrupt", "random", "-n", "10", "--seed", "42")
+	if first != second {
+		t.Errorf("Expected identical output for the same seed:\n%s\n%s", first, second)
+	}
+	other, _ := executeCommand(t, "", "--corrupt", "random", "-n", "10", "--seed", "43")
+	if first == other {
+		t.Error("Expected different output for a different seed")
+	}
+}
+
+func TestCorruptErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"unknown kind", []string{"--corrupt", "spaces"}, "unknown corruption 'spaces'"},
+		{"with version", []string{"--corrupt", "hex", "-7"}, "--corrupt cannot be combined with -7"},
+		{"with json", []string{"--corrupt", "hex", "--json"}, "--corrupt cannot be combined with --json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
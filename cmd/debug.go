@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// debugLogger receives the CLI's own debug records; it discards them unless
+// --debug is given
+var debugLogger = slog.New(slog.DiscardHandler)
+
+// applyDebugLogging installs a stderr slog handler for the CLI and the
+// generator package when --debug is given. Stdout is never written to.
+func applyDebugLogging(cmd *cobra.Command) error {
+	enabled, _ := cmd.Flags().GetBool("debug")
+	format, _ := cmd.Flags().GetString("debug-format")
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown debug format '%s'. Available formats: text, json", format)
+	}
+	if !enabled {
+		return nil
+	}
+
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handler slog.Handler = slog.NewTextHandler(cmd.ErrOrStderr(), options)
+	if format == "json" {
+		handler = slog.NewJSONHandler(cmd.ErrOrStderr(), options)
+	}
+	debugLogger = slog.New(handler)
+	generator.SetLogger(debugLogger)
+	return nil
+}
+
+func init() {
+	cobra.OnFinalize(func() {
+		debugLogger = slog.New(slog.DiscardHandler)
+		generator.SetLogger(nil)
+	})
+}
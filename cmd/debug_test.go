@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDebugLogsToStderrOnly(t *testing.T) {
+	stdout, stderr, err := executeCommandSplit(t, "", "--debug", "--debug-format", "json", "-7", "-t", "2023-06-14")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !uuidRegex.MatchString(strings.TrimSpace(stdout)) {
+		t.Errorf("Stdout should contain only the UUID, got: %q", stdout)
+	}
+
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(stderr), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Debug output should be JSON lines, got %q", line)
+		}
+		messages = append(messages, record["msg"].(string))
+		if record["msg"] == "selected generator" && (record["version"] != float64(7) || record["timestamp_source"] != "flag") {
+			t.Errorf("Unexpected generator selection record: %v", record)
+		}
+	}
+	if got := strings.Join(messages, ","); got != "selected generator,read clock,read entropy,generated UUID" {
+		t.Errorf("Unexpected debug records: %s", got)
+	}
+}
+
+func TestDebugDisabledByDefault(t *testing.T) {
+	// A previous --debug run must not leave logging enabled
+	if _, _, err := executeCommandSplit(t, "", "--debug"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, stderr, err := executeCommandSplit(t, "", "-7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stderr != "" {
+		t.Errorf("Expected no stderr output without --debug, got: %q", stderr)
+	}
+}
+
+func TestDebugFormatInvalid(t *testing.T) {
+	_, err := executeCommand(t, "", "--debug", "--debug-format", "xml")
+	if err == nil || !strings.Contains(err.Error(), "unknown debug format 'xml'") {
+		t.Errorf("Expected an unknown debug format error, got: %v", err)
+	}
+}
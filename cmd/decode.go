@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// decodeCmd converts alternate UUID encodings back to the canonical form
+var decodeCmd = &cobra.Command{
+	Use:   "decode [value...]",
+	Short: "Convert an encoded UUID back to canonical form",
+	Long: `Convert UUIDs from an alternate encoding back to the canonical hyphenated form.
+
+Values are read from the arguments, or one per line from each --file in
+turn ('-' is stdin), or from stdin when no arguments are given. Use --from to name the encoding, or --detect to try
+every encoding and report which one matched. Detection fails when a value
+is valid in more than one encoding (for example, many 22-character values
+are valid base64, base58, and base57).
+
+Examples:
+  uuid decode --from base64 2UKIiBIrEeG4XGHNPLsyEA
+  uuid decode --from decimal 288787935866349040041796580581842825744
+  uuid decode --detect 2UKIiBIrEeG4XGHNPLsyEA
+  cat ids.txt | uuid decode --from base58`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		detect, _ := cmd.Flags().GetBool("detect")
+
+		if from == "" && !detect {
+			return fmt.Errorf("one of --from or --detect is required. Available encodings: %s", strings.Join(generator.EncodingNames(), ", "))
+		}
+
+		var decode func(string) (string, error)
+		if detect {
+			decode = detectValue
+		} else {
+			encoding, err := generator.LookupEncoding(from)
+			if err != nil {
+				return err
+			}
+			decode = func(value string) (string, error) {
+				uuid, err := encoding.Decode(value)
+				if err != nil {
+					return "", err
+				}
+				return generator.FormatUUID(uuid), nil
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		return eachInputValue(cmd, args, func(value string) error {
+			return decodeLine(out, decode, value)
+		})
+	},
+}
+
+func decodeLine(out io.Writer, decode func(string) (string, error), value string) error {
+	result, err := decode(value)
+	if err != nil {
+		return fmt.Errorf("cannot decode '%s': %w", value, err)
+	}
+	fmt.Fprintln(out, result)
+	return nil
+}
+
+// detectValue decodes value with whichever encoding accepts it, appending the
+// encoding name after a tab
+func detectValue(value string) (string, error) {
+	uuid, matches := generator.DetectEncoding(value)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("value does not match any encoding (%s)", strings.Join(generator.EncodingNames(), ", "))
+	case 1:
+		return generator.FormatUUID(uuid) + "\t" + matches[0], nil
+	default:
+		return "", fmt.Errorf("ambiguous value matches %s; use --from to choose", strings.Join(matches, ", "))
+	}
+}
+
+func init() {
+	decodeCmd.Flags().String("from", "", "Encoding of the input values ("+strings.Join(generator.EncodingNames(), ", ")+")")
+	decodeCmd.Flags().Bool("detect", false, "Detect the encoding of each value and report it")
+	decodeCmd.MarkFlagsMutuallyExclusive("from", "detect")
+
+	addInputFlags(decodeCmd)
+	rootCmd.AddCommand(decodeCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  string
+		value string
+	}{
+		{"hex32", "hex32", "d9428888122b11e1b85c61cd3cbb3210"},
+		{"base64", "base64", "2UKIiBIrEeG4XGHNPLsyEA"},
+		{"base32", "base32", "3fbircasfmi6doc4mhgtzozsca"},
+		{"base58", "base58", "Tq2zVsESD1552ggtR5CuCB"},
+		{"base57", "base57", "gfMWVuhTWjYTSbx44Pdeqx"},
+		{"decimal", "decimal", "288787935866349040041796580581842825744"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(t, "", "decode", "--from", tt.from, tt.value)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != "d9428888-122b-11e1-b85c-61cd3cbb3210\n" {
+				t.Errorf("Unexpected output: %q", output)
+			}
+		})
+	}
+}
+
+func TestDecodeCommandStdin(t *testing.T) {
+	input := "2UKIiBIrEeG4XGHNPLsyEA\n\nAAAAAAAAAAAAAAAAAAAAAA\n"
+	output, err := executeCommand(t, input, "decode", "--from", "base64")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "d9428888-122b-11e1-b85c-61cd3cbb3210\n00000000-0000-0000-0000-000000000000\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+}
+
+func TestDecodeCommandStdinReportsLine(t *testing.T) {
+	_, err := executeCommand(t, "2UKIiBIrEeG4XGHNPLsyEA\nbad\n", "decode", "--from", "base64")
+	if err == nil {
+		t.Fatal("Expected error for invalid line")
+	}
+	if !strings.Contains(err.Error(), "line 2") || !strings.Contains(err.Error(), "invalid length 3") {
+		t.Errorf("Error should name the line and length problem, got: %v", err)
+	}
+}
+
+func TestDecodeCommandDetect(t *testing.T) {
+	output, err := executeCommand(t, "", "decode", "--detect", "2UKIiBIrEeG4XGHNPLsyEA")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "d9428888-122b-11e1-b85c-61cd3cbb3210\tbase64\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	_, err = executeCommand(t, "", "decode", "--detect", "J2jvCWcuVkoXcNvTKsJ7AA")
+	if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("Expected ambiguity error, got: %v", err)
+	}
+}
+
+func TestDecodeCommandErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"no mode", []string{"decode", "abc"}, "one of --from or --detect is required"},
+		{"unknown encoding", []string{"decode", "--from", "base99", "abc"}, "unknown encoding 'base99'"},
+		{"bad character", []string{"decode", "--from", "base58", "Tq2zVsESD1552ggtR5CuC0"}, "invalid character '0' at position 22"},
+		{"both modes", []string{"decode", "--from", "base64", "--detect", "abc"}, "none of the others can be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
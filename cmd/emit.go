@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// emitter renders each generated UUID as one or more columns drawn from the
+// generator's encoding registry
+type emitter struct {
+	columns []generator.Encoding
+	header  bool
+	json    bool
+	pretty  bool
+	group   int
+	upper   bool
+}
+
+// displayConflicts are the flags that select structured or alternate output,
+// which --group and --upper cannot reshape
+var displayConflicts = []string{"emit", "format-name", "oid", "check-digit", "header", "json", "phonetic"}
+
+// newEmitter builds an emitter from the --emit, --format-name, --oid,
+// --check-digit, --header, --json, --group, and --upper flags. Without any of
+// the first four the only column is the canonical form. JSON output is never
+// decorated, even when the pretty renderer is selected.
+func newEmitter(cmd *cobra.Command) (*emitter, error) {
+	spec, _ := cmd.Flags().GetString("emit")
+	formatName, _ := cmd.Flags().GetString("format-name")
+	header, _ := cmd.Flags().GetBool("header")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	if oid, _ := cmd.Flags().GetBool("oid"); oid {
+		formatName = "oid"
+	}
+	if checkDigit, _ := cmd.Flags().GetBool("check-digit"); checkDigit {
+		formatName = "damm"
+	}
+
+	if formatName != "" {
+		if _, err := generator.LookupEncoding(formatName); err != nil {
+			return nil, fmt.Errorf("unknown format '%s'. Run 'uuid list-formats' to see the available formats", formatName)
+		}
+		spec = formatName
+	}
+	if spec == "" {
+		spec = "canonical"
+	}
+
+	group, err := groupFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	upper, _ := cmd.Flags().GetBool("upper")
+	if group > 0 {
+		if err := rejectFlags(cmd, "--group", displayConflicts); err != nil {
+			return nil, err
+		}
+	}
+	if upper {
+		if err := rejectFlags(cmd, "--upper", displayConflicts); err != nil {
+			return nil, err
+		}
+	}
+
+	e := &emitter{header: header, json: asJSON, pretty: usePrettyOutput(cmd) && !asJSON, group: group, upper: upper}
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		encoding, err := generator.LookupEncoding(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown column '%s'. Available columns: %s", name, strings.Join(generator.EncodingNames(), ", "))
+		}
+		e.columns = append(e.columns, encoding)
+	}
+	return e, nil
+}
+
+// write generates count UUIDs and prints one record per line. Output is
+// buffered, so a single UUID takes a single write.
+func (e *emitter) write(out io.Writer, generate func() string, count int) error {
+	w := bufio.NewWriter(out)
+	e.writeHeader(w)
+
+	for i := 0; i < count; i++ {
+		record, err := e.record(generate())
+		if err != nil {
+			w.Flush()
+			return err
+		}
+		fmt.Fprintln(w, record)
+	}
+
+	if e.pretty && count > 1 {
+		fmt.Fprintln(w, dim(fmt.Sprintf("%d UUIDs generated", count)))
+	}
+	return w.Flush()
+}
+
+// writePerLine generates one UUID for every line of in, including empty
+// lines unless skipEmpty is set. With join, each record is followed by a tab
+// and the original line. Lines are handled as they arrive, so arbitrarily
+// large inputs are streamed rather than buffered.
+func (e *emitter) writePerLine(in io.Reader, out io.Writer, generate func() string, join, skipEmpty bool) error {
+	if join {
+		e.writeHeader(out, "input")
+	} else {
+		e.writeHeader(out)
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if line == "" && err == io.EOF {
+			return nil
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		if !skipEmpty || strings.TrimSpace(line) != "" {
+			record, recordErr := e.record(generate())
+			if recordErr != nil {
+				return recordErr
+			}
+			if join {
+				record += "\t" + line
+			}
+			fmt.Fprintln(out, record)
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// writeQR renders a single record as a QR code. The record is built without
+// terminal colours, since escape codes would end up in the payload.
+func (e *emitter) writeQR(cmd *cobra.Command, value string) error {
+	plain := *e
+	plain.pretty = false
+	record, err := plain.record(value)
+	if err != nil {
+		return err
+	}
+	return writeQR(cmd, record)
+}
+
+// groupFlag returns the --group size, or 0 when the flag is not set
+func groupFlag(cmd *cobra.Command) (int, error) {
+	if !cmd.Flags().Changed("group") {
+		return 0, nil
+	}
+	group, _ := cmd.Flags().GetInt("group")
+	if group < 1 || group > 32 {
+		return 0, fmt.Errorf("--group must be between 1 and 32, got %d", group)
+	}
+	return group, nil
+}
+
+// writeHeader prints the column names when --header is set, followed by any
+// extra column names
+func (e *emitter) writeHeader(out io.Writer, extra ...string) {
+	if !e.header {
+		return
+	}
+	names := make([]string, 0, len(e.columns)+len(extra))
+	for _, c := range e.columns {
+		names = append(names, c.Name)
+	}
+	fmt.Fprintln(out, strings.Join(append(names, extra...), "\t"))
+}
+
+// record renders a single UUID as a tab-separated line or a JSON object
+func (e *emitter) record(value string) (string, error) {
+	uuid, err := generator.ParseUUID(value)
+	if err != nil {
+		return "", err
+	}
+
+	fields := make([]string, len(e.columns))
+	for i, c := range e.columns {
+		rendered := c.Encode(uuid)
+		if e.group > 0 {
+			rendered = generator.FormatGrouped(uuid, e.group)
+		}
+		if e.upper {
+			rendered = strings.ToUpper(rendered)
+		}
+		if e.pretty && c.Name == "canonical" && e.group == 0 {
+			rendered = highlightVersion(rendered)
+		}
+		if e.json {
+			// Marshal keys and values individually so columns keep the requested order
+			key, _ := json.Marshal(c.Name)
+			val, _ := json.Marshal(rendered)
+			rendered = string(key) + ":" + string(val)
+		}
+		fields[i] = rendered
+	}
+
+	if e.json {
+		return "{" + strings.Join(fields, ",") + "}", nil
+	}
+	return strings.Join(fields, "\t"), nil
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestEmitColumnOrder(t *testing.T) {
+	output, err := executeCommand(t, "", "-7", "-n", "5", "--emit", "decimal,canonical,ulid,base64")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines, got %d: %q", len(lines), output)
+	}
+
+	order := []string{"decimal", "canonical", "ulid", "base64"}
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != len(order) {
+			t.Fatalf("Expected %d columns, got %d: %q", len(order), len(fields), line)
+		}
+
+		// Every column must decode to the same underlying bytes as the canonical column
+		canonical := fields[1]
+		if !uuidRegex.MatchString(canonical) {
+			t.Fatalf("Second column should be canonical, got: %s", canonical)
+		}
+		for i, name := range order {
+			encoding, err := generator.LookupEncoding(name)
+			if err != nil {
+				t.Fatalf("Failed to look up %s: %v", name, err)
+			}
+			decoded, err := encoding.Decode(fields[i])
+			if err != nil {
+				t.Fatalf("Column %s value %s does not decode: %v", name, fields[i], err)
+			}
+			if generator.FormatUUID(decoded) != canonical {
+				t.Errorf("Column %s renders %s, expected %s", name, generator.FormatUUID(decoded), canonical)
+			}
+		}
+	}
+}
+
+func TestEmitHeader(t *testing.T) {
+	output, err := executeCommand(t, "", "-n", "2", "--emit", "base58,canonical", "--header")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header plus 2 lines, got %d: %q", len(lines), output)
+	}
+	if lines[0] != "base58\tcanonical" {
+		t.Errorf("Unexpected header: %q", lines[0])
+	}
+
+	output, err = executeCommand(t, "", "-n", "2", "--emit", "base58,canonical")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(output, "base58") {
+		t.Errorf("Header should only be printed with --header, got: %q", output)
+	}
+}
+
+func TestEmitJSON(t *testing.T) {
+	output, err := executeCommand(t, "", "-n", "3", "--emit", "ulid,canonical", "--json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 JSON records, got %d: %q", len(lines), output)
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, `{"ulid":`) {
+			t.Errorf("JSON keys should follow the requested column order, got: %s", line)
+		}
+
+		var record map[string]string
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid JSON record %s: %v", line, err)
+		}
+		ulid, err := generator.LookupEncoding("ulid")
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := ulid.Decode(record["ulid"])
+		if err != nil || generator.FormatUUID(decoded) != record["canonical"] {
+			t.Errorf("ULID %s does not match canonical %s", record["ulid"], record["canonical"])
+		}
+	}
+}
+
+func TestEmitDefaultIsPlain(t *testing.T) {
+	output, err := executeCommand(t, "", "-n", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !uuidRegex.MatchString(line) {
+			t.Errorf("Default output should be a bare canonical UUID, got: %q", line)
+		}
+	}
+}
+
+func TestEmitGroup(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--group", "4"}, "d942 8888 122b 41e1 b85c 61cd 3cbb 3210\n"},
+		{[]string{"--group", "5", "--upper"}, "D9428 88812 2B41E 1B85C 61CD3 CBB32 10\n"},
+		{[]string{"--group", "6"}, "d94288 88122b 41e1b8 5c61cd 3cbb32 10\n"},
+		{[]string{"--upper"}, "D9428888-122B-" + ansiHighlight + "4" + ansiReset + "1E1-B85C-61CD3CBB3210\n"},
+	}
+
+	// Grouped output is never highlighted, since the version digit moves
+	for _, tt := range tests {
+		args := append([]string{"--from-hex", "d9428888122b41e1b85c61cd3cbb3210", "--pretty"}, tt.args...)
+		output, err := executeCommand(t, "", args...)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", tt.args, err)
+		}
+		if output != tt.expected {
+			t.Errorf("%v: expected %q, got %q", tt.args, tt.expected, output)
+		}
+	}
+}
+
+func TestEmitErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"unknown column", []string{"--emit", "canonical,base99"}, "unknown column 'base99'. Available columns: canonical"},
+		{"zero count", []string{"-n", "0"}, "count must be at least 1"},
+		{"header with json", []string{"--header", "--json"}, "none of the others can be"},
+		{"group too small", []string{"--group", "0"}, "--group must be between 1 and 32, got 0"},
+		{"group too large", []string{"--group", "33"}, "--group must be between 1 and 32, got 33"},
+		{"group with json", []string{"--group", "4", "--json"}, "--group cannot be combined with --json"},
+		{"group with emit", []string{"--group", "4", "--emit", "base64"}, "--group cannot be combined with --emit"},
+		{"upper with format", []string{"--upper", "--format-name", "base64"}, "--upper cannot be combined with --format-name"},
+		{"group with nanoid", []string{"--nanoid", "--group", "4"}, "--nanoid cannot be combined with --group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestPerLineMatchesInputLineCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		args      []string
+		wantLines int
+	}{
+		{"trailing newline", "a\nb\n\nc\n", nil, 4},
+		{"no trailing newline", "a\nb\n\nc", nil, 4},
+		{"only empty lines", "\n\n", nil, 2},
+		{"empty input", "", nil, 0},
+		{"skip empty", "a\n\n  \nb", []string{"--skip-empty"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--per-line", "-7"}, tt.args...)
+			output, err := executeCommand(t, tt.input, args...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+			if output == "" {
+				lines = nil
+			}
+			if len(lines) != tt.wantLines {
+				t.Fatalf("Expected %d UUIDs, got %d: %q", tt.wantLines, len(lines), output)
+			}
+			for _, line := range lines {
+				if !uuidRegex.MatchString(line) || line[14] != '7' {
+					t.Errorf("Expected a UUIDv7 per line, got %q", line)
+				}
+			}
+		})
+	}
+}
+
+func TestPerLineJoin(t *testing.T) {
+	output, err := executeCommand(t, "alice\r\n\nbob smith", "--per-line", "--join", "--emit", "canonical,base64", "--header")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 4 || lines[0] != "canonical\tbase64\tinput" {
+		t.Fatalf("Expected a header and three records, got: %q", output)
+	}
+	for i, want := range []string{"alice", "", "bob smith"} {
+		fields := strings.Split(lines[i+1], "\t")
+		if len(fields) != 3 || fields[2] != want {
+			t.Errorf("Record %d should end with the input line %q, got %q", i, want, lines[i+1])
+		}
+	}
+}
+
+func TestPerLineErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"join without per-line", []string{"--join"}, "--join and --skip-empty require --per-line"},
+		{"per-line with count", []string{"--per-line", "-n", "3"}, "none of the others can be"},
+		{"join with json", []string{"--per-line", "--join", "--json"}, "none of the others can be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
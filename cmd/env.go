@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// envName matches the variable names env accepts: the portable shell subset
+var envName = sync.OnceValue(func() *regexp.Regexp {
+	return regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+})
+
+// envAssignment matches a NAME= line in a .env file, with an optional
+// export prefix
+var envAssignment = sync.OnceValue(func() *regexp.Regexp {
+	return regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=`)
+})
+
+// envCmd writes NAME=<uuid> lines for new services
+var envCmd = &cobra.Command{
+	Use:   "env NAME...",
+	Short: "Write a UUID for each named variable to a .env file",
+	Long: `Generate a UUID for each variable name and print NAME=<uuid> lines, or
+merge them into the .env file given by --out.
+
+Names must be letters, digits, and underscores, not starting with a digit.
+The version flags and --format-name apply to every variable. With --export
+each line starts with 'export ', so the file can be sourced by a shell.
+
+When --out already exists, its other lines, comments, and line endings are
+kept and new variables are appended. A variable the file already sets is an
+error unless --overwrite is given, in which case its line is replaced where
+it stands. Nothing is written when any name is rejected. New files are
+created readable only by their owner.
+
+Examples:
+  uuid env SERVICE_ID DEPLOY_KEY WEBHOOK_SECRET --out .env
+  uuid env SERVICE_ID -7 --export
+  uuid env WEBHOOK_SECRET --out .env --overwrite`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outPath, _ := cmd.Flags().GetString("out")
+		overwrite, _ := cmd.Flags().GetBool("overwrite")
+		export, _ := cmd.Flags().GetBool("export")
+		formatName, _ := cmd.Flags().GetString("format-name")
+		v6, _ := cmd.Flags().GetBool("6")
+		v7, _ := cmd.Flags().GetBool("7")
+
+		seen := map[string]bool{}
+		for _, name := range args {
+			if !envName().MatchString(name) {
+				return fmt.Errorf("invalid variable name '%s': use letters, digits, and '_', not starting with a digit", name)
+			}
+			if seen[name] {
+				return fmt.Errorf("variable '%s' is given more than once", name)
+			}
+			seen[name] = true
+		}
+		if overwrite && outPath == "" {
+			return fmt.Errorf("--overwrite requires --out")
+		}
+		encoding, err := generator.LookupEncoding(formatName)
+		if err != nil {
+			return fmt.Errorf("unknown format '%s'. Run 'uuid list-formats' to see the available formats", formatName)
+		}
+
+		generate := generator.GenerateUUIDv4
+		if v6 {
+			generate = generator.GenerateUUIDv6
+		} else if v7 {
+			if err := checkClock(cmd); err != nil {
+				return err
+			}
+			generate = generator.GenerateUUIDv7
+		}
+		prefix := ""
+		if export {
+			prefix = "export "
+		}
+		values := map[string]string{}
+		for _, name := range args {
+			uuid, _ := generator.ParseUUID(generate())
+			values[name] = prefix + name + "=" + encoding.Encode(uuid)
+		}
+
+		if outPath == "" {
+			for _, name := range args {
+				fmt.Fprintln(cmd.OutOrStdout(), values[name])
+			}
+			return nil
+		}
+
+		existing, err := os.ReadFile(outPath)
+		mode := fs.FileMode(0o600)
+		if err == nil {
+			if info, err := os.Stat(outPath); err == nil {
+				mode = info.Mode().Perm()
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		merged, err := mergeEnvFile(string(existing), args, values, overwrite)
+		if err != nil {
+			return fmt.Errorf("%s: %w", outPath, err)
+		}
+		return os.WriteFile(outPath, []byte(merged), mode)
+	},
+}
+
+// mergeEnvFile adds the lines in values, keyed by variable name, to the
+// contents of a .env file. Lines setting one of names are replaced in place
+// when overwrite is set and are an error otherwise; the remaining names are
+// appended in order, using the file's line ending.
+func mergeEnvFile(existing string, names []string, values map[string]string, overwrite bool) (string, error) {
+	newline := "\n"
+	if strings.Contains(existing, "\r\n") {
+		newline = "\r\n"
+	}
+
+	var lines []string
+	if existing != "" {
+		lines = strings.SplitAfter(existing, "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+	}
+	present := map[string]bool{}
+	var conflicts []string
+	for i, line := range lines {
+		m := envAssignment().FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value, ok := values[m[1]]
+		if !ok {
+			continue
+		}
+		if !present[m[1]] && !overwrite {
+			conflicts = append(conflicts, m[1])
+		}
+		present[m[1]] = true
+		ending := line[len(strings.TrimRight(line, "\r\n")):]
+		lines[i] = value + ending
+	}
+	if len(conflicts) > 0 {
+		return "", fmt.Errorf("already sets %s; use --overwrite to replace", strings.Join(conflicts, ", "))
+	}
+
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		lines[n-1] += newline
+	}
+	for _, name := range names {
+		if !present[name] {
+			lines = append(lines, values[name]+newline)
+		}
+	}
+	return strings.Join(lines, ""), nil
+}
+
+func init() {
+	envCmd.Flags().StringP("out", "o", "", "Merge the variables into this .env file instead of printing them")
+	envCmd.Flags().Bool("overwrite", false, "Replace variables the --out file already sets")
+	envCmd.Flags().Bool("export", false, "Prefix each line with 'export ' for shell sourcing")
+	envCmd.Flags().String("format-name", "canonical", "Write each UUID in the named format (see 'uuid list-formats')")
+	envCmd.Flags().BoolP("4", "4", false, "Generate UUIDv4 (default)")
+	envCmd.Flags().BoolP("6", "6", false, "Generate UUIDv6")
+	envCmd.Flags().BoolP("7", "7", false, "Generate UUIDv7 (contains timestamp)")
+	envCmd.MarkFlagsMutuallyExclusive("4", "6", "7")
+
+	rootCmd.AddCommand(envCmd)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// envLine matches a NAME=<canonical uuid> line
+var envLine = regexp.MustCompile(`^(export )?([A-Z_]+)=([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+
+func TestEnvCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	output, err := executeCommand(t, "", "env", "SERVICE_ID", "DEPLOY_KEY", "WEBHOOK_SECRET", "--out", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("Expected no output, got %q", output)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	names := []string{"SERVICE_ID", "DEPLOY_KEY", "WEBHOOK_SECRET"}
+	if len(lines) != len(names) {
+		t.Fatalf("Expected %d lines, got:\n%s", len(names), data)
+	}
+	values := map[string]bool{}
+	for i, line := range lines {
+		m := envLine.FindStringSubmatch(line)
+		if m == nil || m[2] != names[i] {
+			t.Errorf("Expected %s=<uuid>, got %q", names[i], line)
+			continue
+		}
+		values[m[3]] = true
+	}
+	if len(values) != len(names) {
+		t.Errorf("Expected a distinct UUID per variable, got:\n%s", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected a new file to be private, got %v", info.Mode().Perm())
+	}
+}
+
+func TestEnvStdoutAndFlags(t *testing.T) {
+	output, err := executeCommand(t, "", "env", "A_ID", "B_ID", "-7", "--export")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i, name := range []string{"A_ID", "B_ID"} {
+		m := envLine.FindStringSubmatch(lines[i])
+		if m == nil || m[1] != "export " || m[2] != name || m[3][14] != '7' {
+			t.Errorf("Expected an exported UUIDv7 for %s, got %q", name, lines[i])
+		}
+	}
+
+	output, err = executeCommand(t, "", "env", "A_ID", "--format-name", "hex32")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(`^A_ID=[0-9a-f]{32}\n$`).MatchString(output) {
+		t.Errorf("Expected a hex32 value, got %q", output)
+	}
+}
+
+func TestEnvMergesExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	original := "# service settings\r\nDATABASE_URL=postgres://localhost/app\r\n\r\nexport LOG_LEVEL=debug"
+	if err := os.WriteFile(path, []byte(original), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := executeCommand(t, "", "env", "SERVICE_ID", "--out", path, "--export"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	merged := string(data)
+	if !strings.HasPrefix(merged, original+"\r\n") {
+		t.Fatalf("Expected the existing lines to be kept, got %q", merged)
+	}
+	added := strings.TrimPrefix(merged, original+"\r\n")
+	if !strings.HasSuffix(added, "\r\n") || envLine.FindStringSubmatch(strings.TrimSuffix(added, "\r\n")) == nil {
+		t.Errorf("Expected one appended line with the file's line ending, got %q", added)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+		t.Errorf("Expected the file mode to be kept, got %v", info.Mode().Perm())
+	}
+}
+
+func TestEnvOverwriteGuard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	original := "SERVICE_ID=keep-me\nOTHER=1\nexport DEPLOY_KEY = old\n"
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := executeCommand(t, "", "env", "SERVICE_ID", "DEPLOY_KEY", "NEW_ID", "--out", path)
+	if err == nil || !strings.Contains(err.Error(), "already sets SERVICE_ID, DEPLOY_KEY; use --overwrite to replace") {
+		t.Fatalf("Expected an overwrite error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Fatalf("Expected the file to be untouched, got %q", data)
+	}
+
+	if _, err := executeCommand(t, "", "env", "SERVICE_ID", "DEPLOY_KEY", "NEW_ID", "--out", path, "--overwrite"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 4 || lines[1] != "OTHER=1" {
+		t.Fatalf("Expected variables replaced in place, got:\n%s", data)
+	}
+	for i, name := range map[int]string{0: "SERVICE_ID", 2: "DEPLOY_KEY", 3: "NEW_ID"} {
+		if m := envLine.FindStringSubmatch(lines[i]); m == nil || m[2] != name {
+			t.Errorf("Expected line %d to set %s, got %q", i+1, name, lines[i])
+		}
+	}
+}
+
+func TestEnvErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"no names", []string{"env"}, "requires at least 1 arg(s)"},
+		{"leading digit", []string{"env", "1ID"}, "invalid variable name '1ID'"},
+		{"hyphen", []string{"env", "SERVICE-ID"}, "invalid variable name 'SERVICE-ID'"},
+		{"duplicate", []string{"env", "ID", "ID"}, "variable 'ID' is given more than once"},
+		{"overwrite without out", []string{"env", "ID", "--overwrite"}, "--overwrite requires --out"},
+		{"unknown format", []string{"env", "ID", "--format-name", "nope"}, "unknown format 'nope'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
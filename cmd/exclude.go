@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// defaultExcludeRetries is the --exclude-retries default. A fresh random UUID
+// is essentially never excluded twice in a row, so hitting it means the
+// generator cannot produce anything outside the set.
+const defaultExcludeRetries = 100
+
+// exclusionLimitError is raised, as a panic inside generate, when every
+// attempt produced an excluded UUID; catchExclusionLimit turns it back into
+// an error
+type exclusionLimitError struct {
+	attempts int
+	unique   bool
+}
+
+func (e *exclusionLimitError) Error() string {
+	if e.unique {
+		return fmt.Sprintf("%d UUIDs in a row were all %s; giving up (the generator cannot produce a new one, or --exclude-retries is too low)", e.attempts, excludedSet(e.unique))
+	}
+	return fmt.Sprintf("%d UUIDs in a row were all in --exclude-file; giving up (the generator cannot avoid the excluded set, or --exclude-retries is too low)", e.attempts)
+}
+
+// excludedSet describes the UUIDs a regenerated UUID was among
+func excludedSet(unique bool) string {
+	if unique {
+		return "in --exclude-file or already generated"
+	}
+	return "in --exclude-file"
+}
+
+// uuidExclusions is the set of UUIDs generated values must avoid, held
+// exactly or, with --approx, in a Bloom filter. With --unique every UUID
+// handed out joins the set, so none repeats within the run.
+type uuidExclusions struct {
+	exact       map[[16]byte]bool
+	filter      *bloomFilter
+	count       int
+	regenerated int
+	unique      bool
+}
+
+func (x *uuidExclusions) contains(key [16]byte) bool {
+	if x.filter != nil {
+		return x.filter.contains(key)
+	}
+	return x.exact[key]
+}
+
+func (x *uuidExclusions) add(key [16]byte) {
+	if x.filter != nil {
+		x.filter.add(key)
+	} else {
+		x.exact[key] = true
+	}
+}
+
+// loadExclusions reads every UUID in paths. With approx, the Bloom filter
+// is sized for them and for extra more UUIDs added later.
+func loadExclusions(paths []string, approx bool, extra int) (*uuidExclusions, error) {
+	x := &uuidExclusions{exact: map[[16]byte]bool{}}
+	if approx {
+		size := int64(extra) * 33
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+			size += info.Size()
+		}
+		// As in setop --approx, every line holds at least 33 bytes
+		x.filter = newBloomFilter(int(size/33) + 1)
+	}
+	for _, path := range paths {
+		err := eachUUID(path, func(key [16]byte, text string) error {
+			x.add(key)
+			x.count++
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return x, nil
+}
+
+// withExclusions wraps generate so it never returns a UUID listed in
+// --exclude-file or, with --unique, one it already returned, regenerating
+// up to --exclude-retries times. With --approx a Bloom filter false
+// positive (about 1%) costs one extra regeneration. The returned exclusions
+// count the regenerations.
+func withExclusions(cmd *cobra.Command, generate func() string) (func() string, *uuidExclusions, error) {
+	paths, _ := cmd.Flags().GetStringArray("exclude-file")
+	unique, _ := cmd.Flags().GetBool("unique")
+	approx, _ := cmd.Flags().GetBool("approx")
+	retries, _ := cmd.Flags().GetInt("exclude-retries")
+	if len(paths) == 0 && !unique {
+		return generate, nil, nil
+	}
+	if retries < 1 {
+		return nil, nil, fmt.Errorf("--exclude-retries must be at least 1, got %d", retries)
+	}
+	extra := 0
+	if unique {
+		extra, _ = cmd.Flags().GetInt("count")
+	}
+	x, err := loadExclusions(paths, approx, extra)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read --exclude-file: %w", err)
+	}
+	x.unique = unique
+	debugLogger.Debug("loaded exclusions", "files", len(paths), "approx", approx, "uuids", x.count, "unique", unique)
+
+	return func() string {
+		for range retries {
+			value := generate()
+			key, _ := generator.ParseUUID(value)
+			if !x.contains(key) {
+				if unique {
+					x.add(key)
+				}
+				return value
+			}
+			x.regenerated++
+			debugLogger.Debug("regenerating excluded UUID", "uuid", value)
+		}
+		panic(&exclusionLimitError{attempts: retries, unique: unique})
+	}, x, nil
+}
+
+// catchExclusionLimit runs write, returning the exclusionLimitError that
+// stops generation as an ordinary error. Generators cannot return errors,
+// so the limit is reported by panicking out of the output loop.
+func catchExclusionLimit(write func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			limit, ok := r.(*exclusionLimitError)
+			if !ok {
+				panic(r)
+			}
+			err = limit
+		}
+	}()
+	return write()
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// riggedEntropy makes the generators read blocks, in order, as their random
+// bytes
+func riggedEntropy(t *testing.T, blocks ...[16]byte) {
+	t.Helper()
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, block[:]...)
+	}
+	generator.SetEntropySource(bytes.NewReader(data))
+	t.Cleanup(func() { generator.SetEntropySource(nil) })
+}
+
+// repeatingEntropy returns the same block on every read
+type repeatingEntropy [16]byte
+
+func (r repeatingEntropy) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r[i%16]
+	}
+	return len(p), nil
+}
+
+// writeExcludeFile writes uuids to a file, one per line
+func writeExcludeFile(t *testing.T, uuids ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "existing.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(uuids, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExcludeFileRegenerates(t *testing.T) {
+	taken := [16]byte{0: 0x11, 15: 0x11}
+	free := [16]byte{0: 0x22, 15: 0x22}
+	other := [16]byte{0: 0x33, 15: 0x33}
+	excluded := generator.NewV4FromBytes(taken)
+	path := writeExcludeFile(t, "ffffffff-ffff-4fff-bfff-ffffffffffff", strings.ToUpper(excluded))
+
+	for _, approx := range []bool{false, true} {
+		riggedEntropy(t, taken, free, taken, taken, other)
+		args := []string{"-n", "2", "--exclude-file", path, "--verbose"}
+		if approx {
+			args = append(args, "--approx")
+		}
+		output, errOut, err := executeCommandSplit(t, "", args...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := generator.NewV4FromBytes(free) + "\n" + generator.NewV4FromBytes(other) + "\n"
+		if output != want {
+			t.Errorf("approx=%v: expected the excluded UUID to be skipped, got:\n%s", approx, output)
+		}
+		if errOut != "regenerated 3 UUIDs that were in --exclude-file\n" {
+			t.Errorf("approx=%v: expected the regeneration count, got %q", approx, errOut)
+		}
+	}
+}
+
+func TestExcludeFileSilentWithoutVerbose(t *testing.T) {
+	path := writeExcludeFile(t, "ffffffff-ffff-4fff-bfff-ffffffffffff")
+	output, errOut, err := executeCommandSplit(t, "", "-7", "-n", "50", "--exclude-file", path, "--exclude-file", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(output), "\n"); len(lines) != 50 {
+		t.Errorf("Expected 50 UUIDs, got %d", len(lines))
+	}
+	if errOut != "" {
+		t.Errorf("Expected nothing on stderr, got %q", errOut)
+	}
+}
+
+func TestExcludeFileRetryCap(t *testing.T) {
+	stuck := [16]byte{0: 0x44}
+	path := writeExcludeFile(t, generator.NewV4FromBytes(stuck))
+	generator.SetEntropySource(repeatingEntropy(stuck))
+	t.Cleanup(func() { generator.SetEntropySource(nil) })
+
+	output, _, err := executeCommandSplit(t, "", "-n", "3", "--exclude-file", path, "--exclude-retries", "5")
+	if err == nil || err.Error() != "5 UUIDs in a row were all in --exclude-file; giving up (the generator cannot avoid the excluded set, or --exclude-retries is too low)" {
+		t.Fatalf("Expected the retry cap error, got %v", err)
+	}
+	if output != "" {
+		t.Errorf("Expected no UUIDs, got %q", output)
+	}
+
+	// The cap applies on every output path
+	_, _, err = executeCommandSplit(t, "", "--pg-copy", "--exclude-file", path)
+	if err == nil || !strings.Contains(err.Error(), "100 UUIDs in a row") {
+		t.Errorf("Expected the default cap with --pg-copy, got %v", err)
+	}
+	_, _, err = executeCommandSplit(t, "", "--idempotent", "--key", "k", "--exclude-file", path)
+	if err == nil || !strings.Contains(err.Error(), "were all in --exclude-file") {
+		t.Errorf("Expected the cap error with --idempotent, got %v", err)
+	}
+}
+
+func TestExcludeFileErrors(t *testing.T) {
+	valid := writeExcludeFile(t, "ffffffff-ffff-4fff-bfff-ffffffffffff")
+	invalid := writeExcludeFile(t, "ffffffff-ffff-4fff-bfff-ffffffffffff", "not-a-uuid")
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"approx alone", []string{"--approx"}, "--approx requires --exclude-file or --unique"},
+		{"retries alone", []string{"--exclude-retries", "3"}, "--exclude-retries requires --exclude-file or --unique"},
+		{"verbose alone", []string{"--verbose"}, "--verbose requires --exclude-file or --unique"},
+		{"zero retries", []string{"--exclude-file", valid, "--exclude-retries", "0"}, "--exclude-retries must be at least 1, got 0"},
+		{"missing file", []string{"--exclude-file", "missing.txt"}, "cannot read --exclude-file: open missing.txt"},
+		{"invalid line", []string{"--exclude-file", invalid}, ":2: invalid UUID 'not-a-uuid'"},
+		{"with nanoid", []string{"--nanoid", "--exclude-file", valid}, "--nanoid cannot be combined with --exclude-file"},
+		{"unique with v5", []string{"-5", "--name", "a", "--unique"}, "cannot be combined with --unique"},
+		{"unique with ulid", []string{"--ulid", "--unique"}, "--ulid cannot be combined with --unique"},
+		{"unique with zero retries", []string{"--unique", "--exclude-retries", "0"}, "--exclude-retries must be at least 1, got 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestUniqueRegeneratesDuplicates(t *testing.T) {
+	first := [16]byte{0: 0x55, 15: 0x55}
+	second := [16]byte{0: 0x66, 15: 0x66}
+	for _, approx := range []bool{false, true} {
+		riggedEntropy(t, first, first, first, second)
+		args := []string{"-n", "2", "--unique", "--verbose"}
+		if approx {
+			args = append(args, "--approx")
+		}
+		output, errOut, err := executeCommandSplit(t, "", args...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := generator.NewV4FromBytes(first) + "\n" + generator.NewV4FromBytes(second) + "\n"
+		if output != want {
+			t.Errorf("approx=%v: expected the duplicate to be skipped, got:\n%s", approx, output)
+		}
+		if errOut != "regenerated 2 UUIDs that were in --exclude-file or already generated\n" {
+			t.Errorf("approx=%v: expected the regeneration count, got %q", approx, errOut)
+		}
+	}
+}
+
+func TestUniqueWithFixedTimestamp(t *testing.T) {
+	output, err := executeCommand(t, "", "-7", "-t", "2023-06-14T10:30:00Z", "-n", "2000", "--unique")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if seen[line] {
+			t.Fatalf("Duplicate UUID %s", line)
+		}
+		seen[line] = true
+	}
+	if len(seen) != 2000 {
+		t.Errorf("Expected 2000 UUIDs, got %d", len(seen))
+	}
+}
+
+func TestUniqueRetryCap(t *testing.T) {
+	stuck := [16]byte{0: 0x77}
+	generator.SetEntropySource(repeatingEntropy(stuck))
+	t.Cleanup(func() { generator.SetEntropySource(nil) })
+
+	output, _, err := executeCommandSplit(t, "", "-n", "3", "--unique", "--exclude-retries", "4")
+	if err == nil || err.Error() != "4 UUIDs in a row were all in --exclude-file or already generated; giving up (the generator cannot produce a new one, or --exclude-retries is too low)" {
+		t.Fatalf("Expected the retry cap error, got %v", err)
+	}
+	if strings.Contains(output, generator.NewV4FromBytes(stuck)+"\n"+generator.NewV4FromBytes(stuck)) {
+		t.Errorf("Expected no repeated UUID, got %q", output)
+	}
+}
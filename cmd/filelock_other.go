@@ -0,0 +1,13 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package cmd
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile fails: this platform has no file locking the state files can use
+func lockFile(f *os.File) error {
+	return errors.New("file locking is not supported on this platform")
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// filterBufferSize is how much of its input filter-tokens reads at a time
+	filterBufferSize = 32 * 1024
+
+	// maxTokenName bounds the name in a named token, which also bounds how
+	// much input is held back while a token is incomplete
+	maxTokenName = 64
+)
+
+// filterTokensCmd replaces placeholder tokens in a stream with UUIDs
+var filterTokensCmd = &cobra.Command{
+	Use:   "filter-tokens",
+	Short: "Copy files or stdin to stdout, replacing tokens with UUIDs",
+	Long: `Copy each --file in order, or stdin when none is given, to stdout,
+replacing every %UUID% with a new UUIDv4.
+
+A named token, %UUID:name%, is replaced with one UUID per distinct name, so
+every occurrence of %UUID:db% in the stream, across every --file, gets the
+same value. Names are
+up to 64 letters, digits, '_', '-', and '.'. --token sets another token; its
+named form puts ':name' before the token's last character, so --token '@ID@'
+also matches '@ID:name@'.
+
+Everything other than tokens is copied byte for byte, binary data included.
+The input is streamed rather than buffered, and output is written as soon
+as each read is processed, so the filter works in long-running pipelines.
+Only the start of a possible token is held back until the next read shows
+whether it is one.
+
+Examples:
+  envsubst < app.conf.tmpl | uuid filter-tokens > app.conf
+  uuid filter-tokens --token '@ID@' < seed.sql
+  uuid filter-tokens -f schema.sql -f seed.sql > init.sql`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+
+		// Every new UUID is passed to the current input's fn, so --verbose
+		// counts them per file
+		var fn func(string) error
+		var fnErr error
+		filter, err := newTokenFilter(token, func() string {
+			uuid := generator.GenerateUUIDv4()
+			if fnErr == nil {
+				fnErr = fn(uuid)
+			}
+			return uuid
+		})
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		return eachInput(cmd, nil, func(r io.Reader, each func(string) error) error {
+			fn, fnErr = each, nil
+			if err := filter.copy(out, r, filterBufferSize); err != nil {
+				return err
+			}
+			return fnErr
+		}, func(string) error { return nil })
+	},
+}
+
+// tokenFilter finds tokens split as open + [":" name] + close
+type tokenFilter struct {
+	open  []byte
+	close byte
+	named map[string]string
+	next  func() string
+}
+
+// tokenMatch is the result of checking the input at a possible token start
+type tokenMatch int
+
+const (
+	tokenNone tokenMatch = iota
+	tokenFound
+	tokenPartial
+)
+
+// newTokenFilter validates token and returns a filter replacing it with
+// values from next
+func newTokenFilter(token string, next func() string) (*tokenFilter, error) {
+	if len(token) < 2 || isTokenNameByte(token[len(token)-1]) || token[len(token)-1] == ':' {
+		return nil, fmt.Errorf("invalid --token '%s': it must be at least 2 characters and end in a character other than a letter, digit, ':', '_', '-', or '.'", token)
+	}
+	return &tokenFilter{
+		open:  []byte(token[:len(token)-1]),
+		close: token[len(token)-1],
+		named: make(map[string]string),
+		next:  next,
+	}, nil
+}
+
+// copy streams in to out, reading bufSize bytes at a time and writing the
+// filtered output of every read before the next
+func (f *tokenFilter) copy(out io.Writer, in io.Reader, bufSize int) error {
+	chunk := make([]byte, bufSize)
+	var pending, filtered []byte
+	for {
+		n, readErr := in.Read(chunk)
+		eof := errors.Is(readErr, io.EOF)
+		if readErr != nil && !eof {
+			return readErr
+		}
+
+		pending = append(pending, chunk[:n]...)
+		var rest []byte
+		filtered, rest = f.replace(filtered[:0], pending, eof)
+		if len(filtered) > 0 {
+			if _, err := out.Write(filtered); err != nil {
+				return err
+			}
+		}
+		pending = pending[:copy(pending, rest)]
+
+		if eof {
+			return nil
+		}
+	}
+}
+
+// replace appends b to out with tokens replaced. Unless eof is set, a
+// possible token cut off by the end of b is returned as rest instead.
+func (f *tokenFilter) replace(out, b []byte, eof bool) (_ []byte, rest []byte) {
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, f.open[0])
+		if i < 0 {
+			return append(out, b...), nil
+		}
+		out = append(out, b[:i]...)
+		b = b[i:]
+
+		n, name, match := f.match(b, eof)
+		switch match {
+		case tokenPartial:
+			return out, b
+		case tokenFound:
+			out = append(out, f.value(name)...)
+			b = b[n:]
+		default:
+			out = append(out, b[0])
+			b = b[1:]
+		}
+	}
+	return out, nil
+}
+
+// match checks for a token at the start of b and returns its length and name
+func (f *tokenFilter) match(b []byte, eof bool) (int, string, tokenMatch) {
+	partial := tokenPartial
+	if eof {
+		partial = tokenNone
+	}
+
+	if k := min(len(b), len(f.open)); !bytes.Equal(b[:k], f.open[:k]) {
+		return 0, "", tokenNone
+	}
+	if len(b) <= len(f.open) {
+		return 0, "", partial
+	}
+
+	rest := b[len(f.open):]
+	if rest[0] == f.close {
+		return len(f.open) + 1, "", tokenFound
+	}
+	if rest[0] != ':' {
+		return 0, "", tokenNone
+	}
+	for j := 1; j < len(rest); j++ {
+		switch {
+		case rest[j] == f.close && j > 1:
+			return len(f.open) + j + 1, string(rest[1:j]), tokenFound
+		case !isTokenNameByte(rest[j]) || j > maxTokenName:
+			return 0, "", tokenNone
+		}
+	}
+	return 0, "", partial
+}
+
+// value returns a new UUID, or the UUID already given to a named token
+func (f *tokenFilter) value(name string) string {
+	if name == "" {
+		return f.next()
+	}
+	id, ok := f.named[name]
+	if !ok {
+		id = f.next()
+		f.named[name] = id
+	}
+	return id
+}
+
+// isTokenNameByte reports whether c may appear in a token name
+func isTokenNameByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c == '-' || c == '.'
+}
+
+func init() {
+	filterTokensCmd.Flags().String("token", "%UUID%", "Token to replace; its named form inserts ':name' before the last character")
+	addInputFlags(filterTokensCmd)
+	rootCmd.AddCommand(filterTokensCmd)
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// sequentialIDs returns a generator of predictable values: id1, id2, ...
+func sequentialIDs() func() string {
+	n := 0
+	return func() string {
+		n++
+		return fmt.Sprintf("id%d", n)
+	}
+}
+
+func TestFilterTokensReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		input    string
+		expected string
+	}{
+		{"no tokens", "%UUID%", "plain text\n", "plain text\n"},
+		{"unnamed tokens differ", "%UUID%", "a=%UUID% b=%UUID%\n", "a=id1 b=id2\n"},
+		{"named tokens share a value", "%UUID%", "%UUID:db% %UUID% %UUID:db% %UUID:web%", "id1 id2 id1 id3"},
+		{"near misses", "%UUID%", "%%UUID% %UUID %UUID:% %UUID:a b% %uuid% 100%", "%id1 %UUID %UUID:% %UUID:a b% %uuid% 100%"},
+		{"truncated at end", "%UUID%", "tail %UUID:name", "tail %UUID:name"},
+		{"custom token", "@ID@", "@ID@ @ID:x@ @ID:x@ %UUID%", "id1 id2 id2 %UUID%"},
+		{"empty input", "%UUID%", "", ""},
+	}
+
+	for _, tt := range tests {
+		for _, size := range []int{1, 2, 3, 7, filterBufferSize} {
+			t.Run(fmt.Sprintf("%s/buffer %d", tt.name, size), func(t *testing.T) {
+				filter, err := newTokenFilter(tt.token, sequentialIDs())
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				var out bytes.Buffer
+				if err := filter.copy(&out, strings.NewReader(tt.input), size); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if out.String() != tt.expected {
+					t.Errorf("Expected %q, got %q", tt.expected, out.String())
+				}
+			})
+		}
+	}
+}
+
+func TestFilterTokensTokenAtEveryBoundary(t *testing.T) {
+	// Slide the token across the read boundary one byte at a time
+	for offset := 0; offset < 16; offset++ {
+		input := strings.Repeat("x", offset) + "%UUID:key%|%UUID:key%"
+		filter, _ := newTokenFilter("%UUID%", sequentialIDs())
+		var out bytes.Buffer
+		if err := filter.copy(&out, strings.NewReader(input), 8); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if expected := strings.Repeat("x", offset) + "id1|id1"; out.String() != expected {
+			t.Errorf("Offset %d: expected %q, got %q", offset, expected, out.String())
+		}
+	}
+}
+
+func TestFilterTokensBinaryPassthrough(t *testing.T) {
+	var input []byte
+	for i := 0; i < 4096; i++ {
+		input = append(input, byte(i*31+7))
+	}
+	input = append(input, "\x00%UUID\xff%UUI\r\n%"...)
+
+	filter, _ := newTokenFilter("%UUID%", sequentialIDs())
+	var out bytes.Buffer
+	if err := filter.copy(&out, bytes.NewReader(input), 5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), input) {
+		t.Errorf("Expected input without tokens to pass through unchanged")
+	}
+}
+
+func TestFilterTokensStreams(t *testing.T) {
+	in, producer := io.Pipe()
+	consumer, out := io.Pipe()
+	filter, _ := newTokenFilter("%UUID%", sequentialIDs())
+	done := make(chan error, 1)
+	go func() {
+		done <- filter.copy(out, in, filterBufferSize)
+		out.Close()
+	}()
+
+	// Each line must come out before the next is written
+	lines := bufio.NewReader(consumer)
+	for i, expected := range []string{"first id1\n", "second id1 id2\n"} {
+		input := []string{"first %UUID:a%\n", "second %UUID:a% %UUID%\n"}[i]
+		go producer.Write([]byte(input))
+		line, err := lines.ReadString('\n')
+		if err != nil || line != expected {
+			t.Fatalf("Expected %q, got %q, %v", expected, line, err)
+		}
+	}
+	producer.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestFilterTokensCommand(t *testing.T) {
+	output, err := executeCommand(t, "id: %UUID%\nref: %UUID:a%\nalso: %UUID:a%\n", "filter-tokens")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	matches := regexp.MustCompile(`^id: (` + uuidV4 + `)\nref: (` + uuidV4 + `)\nalso: (` + uuidV4 + `)\n$`).FindStringSubmatch(output)
+	if matches == nil {
+		t.Fatalf("Unexpected output %q", output)
+	}
+	if matches[1] == matches[2] || matches[2] != matches[3] {
+		t.Errorf("Expected only the named tokens to share a UUID, got %q", output)
+	}
+}
+
+func TestFilterTokensFiles(t *testing.T) {
+	files := writeInputFiles(t, "a=%UUID:db%\n", "c=%UUID:db% d=%UUID%\n")
+	output, errOut, err := executeCommandSplit(t, "b=%UUID%\n", "filter-tokens", "--verbose", "-f", files[0], "-f", "-", "-f", files[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	matches := regexp.MustCompile(`^a=(` + uuidV4 + `)\nb=` + uuidV4 + `\nc=(` + uuidV4 + `) d=` + uuidV4 + `\n$`).FindStringSubmatch(output)
+	if matches == nil || matches[1] != matches[2] {
+		t.Fatalf("Expected the inputs in order, sharing named tokens, got %q", output)
+	}
+	want := files[0] + ": 1 values, ok\nstdin: 1 values, ok\n" + files[1] + ": 1 values, ok\n"
+	if errOut != want {
+		t.Errorf("Expected a count of new UUIDs per input, got %q", errOut)
+	}
+}
+
+func TestFilterTokensInvalidToken(t *testing.T) {
+	for _, token := range []string{"", "%", "%UUID", "%UUID:", "__ID__"} {
+		_, err := executeCommand(t, "", "filter-tokens", "--token", token)
+		if err == nil || !strings.Contains(err.Error(), "invalid --token") {
+			t.Errorf("Token %q: expected an invalid token error, got %v", token, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+const sampleFixtureSpec = `entities:
+  - name: users
+    count: 5
+    timestamps:
+      from: 2024-01-01
+      to: 2024-01-05
+    timestamp_field: created_at
+    fields:
+      role: member
+      nickname: "o'brien"
+  - name: orders
+    count: 20
+    refs:
+      user_id: users
+    fields:
+      paid: true
+`
+
+// writeSpec writes a spec file into a temporary directory and returns its path
+func writeSpec(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixtures.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFixturesJSON(t *testing.T) {
+	spec := writeSpec(t, sampleFixtureSpec)
+	output, err := executeCommand(t, "", "fixtures", "--spec", spec, "--seed", "42")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var data map[string][]map[string]any
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(data["users"]) != 5 || len(data["orders"]) != 20 {
+		t.Fatalf("Expected 5 users and 20 orders, got %d and %d", len(data["users"]), len(data["orders"]))
+	}
+
+	userIDs := make(map[string]bool)
+	for i, user := range data["users"] {
+		id, _ := user["id"].(string)
+		uuid, err := generator.ParseUUID(id)
+		if err != nil {
+			t.Fatalf("User %d has invalid id %q", i, id)
+		}
+		if details := generator.Inspect(uuid); details.Version != 7 {
+			t.Errorf("User %d should have a v7 id, got v%d", i, details.Version)
+		}
+		if user["role"] != "member" || user["nickname"] != "o'brien" {
+			t.Errorf("User %d is missing static fields: %v", i, user)
+		}
+		userIDs[id] = true
+	}
+
+	// Timestamps are spread evenly across the range, one day apart
+	if got := data["users"][0]["created_at"]; got != "2024-01-01T00:00:00.000Z" {
+		t.Errorf("First timestamp should be the start of the range, got %v", got)
+	}
+	if got := data["users"][4]["created_at"]; got != "2024-01-05T00:00:00.000Z" {
+		t.Errorf("Last timestamp should be the end of the range, got %v", got)
+	}
+	if !strings.HasPrefix(data["users"][0]["id"].(string), "018cc251-f400-7") {
+		t.Errorf("First user id should embed 2024-01-01, got %v", data["users"][0]["id"])
+	}
+
+	for i, order := range data["orders"] {
+		if !userIDs[order["user_id"].(string)] {
+			t.Errorf("Order %d references unknown user %v", i, order["user_id"])
+		}
+		if order["paid"] != true {
+			t.Errorf("Order %d is missing static field paid: %v", i, order)
+		}
+	}
+
+	if !strings.HasPrefix(output, "{\n  \"users\": [\n    {\n      \"id\": ") {
+		t.Errorf("Output should keep spec entity order and put the id first:\n%s", output[:80])
+	}
+}
+
+func TestFixturesSeedIsReproducible(t *testing.T) {
+	spec := writeSpec(t, sampleFixtureSpec)
+
+	first, err := executeCommand(t, "", "fixtures", "--spec", spec, "--seed", "7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := executeCommand(t, "", "fixtures", "--spec", spec, "--seed", "7")
+	other, _ := executeCommand(t, "", "fixtures", "--spec", spec, "--seed", "8")
+
+	if first != second {
+		t.Error("Output with the same --seed should be identical")
+	}
+	if first == other {
+		t.Error("Output with different seeds should differ")
+	}
+}
+
+func TestFixturesSQL(t *testing.T) {
+	spec := writeSpec(t, sampleFixtureSpec)
+	out := filepath.Join(t.TempDir(), "seed.sql")
+
+	if _, err := executeCommand(t, "", "fixtures", "--spec", spec, "--out", out, "--seed", "1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 25 {
+		t.Fatalf("Expected 25 INSERT statements, got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "INSERT INTO users (id, created_at, nickname, role) VALUES ('") ||
+		!strings.HasSuffix(lines[0], "'2024-01-01T00:00:00.000Z', 'o''brien', 'member');") {
+		t.Errorf("Unexpected users statement: %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[5], "INSERT INTO orders (id, user_id, paid) VALUES ('") ||
+		!strings.HasSuffix(lines[5], ", TRUE);") {
+		t.Errorf("Unexpected orders statement: %s", lines[5])
+	}
+}
+
+func TestFixturesSpecErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		args     []string
+		contains string
+	}{
+		{
+			name:     "unknown key",
+			spec:     "entities:\n  - name: users\n    count: 1\n    colour: blue\n",
+			contains: "line 4: field colour not found",
+		},
+		{
+			name:     "reference to later entity",
+			spec:     "entities:\n  - name: orders\n    count: 1\n    refs:\n      user_id: users\n  - name: users\n    count: 1\n",
+			contains: "line 2: entity 'orders': refs.user_id refers to 'users', which is not defined before it",
+		},
+		{
+			name:     "bad version",
+			spec:     "entities:\n  - name: users\n    count: 1\n  - name: things\n    count: 1\n    version: 5\n",
+			contains: "line 4: entity 'things': version must be 4 or 7",
+		},
+		{
+			name:     "column collision",
+			spec:     "entities:\n  - name: users\n    count: 1\n    fields:\n      id: 3\n",
+			contains: "column 'id' is defined more than once",
+		},
+		{
+			name:     "unseeded v7 with seed",
+			spec:     "entities:\n  - name: users\n    count: 1\n    version: 7\n",
+			args:     []string{"--seed", "1"},
+			contains: "needs a timestamps range to be reproducible",
+		},
+		{
+			name:     "empty",
+			spec:     "",
+			contains: "spec is empty",
+		},
+		{
+			name:     "bad format",
+			spec:     sampleFixtureSpec,
+			args:     []string{"--format", "csv"},
+			contains: "unknown format 'csv'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"fixtures", "--spec", writeSpec(t, tt.spec)}, tt.args...)
+			_, err := executeCommand(t, "", args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
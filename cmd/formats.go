@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// formatSample is the UUID rendered as the example in the format listing
+// (the UUIDv7 test vector from RFC 9562)
+const formatSample = "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"
+
+// listFormatsCmd prints every registered output format
+var listFormatsCmd = &cobra.Command{
+	Use:   "list-formats",
+	Short: "List the output formats accepted by --format-name and --emit",
+	Long: `List every registered output format with a description and an example
+rendering of a fixed sample UUID (` + formatSample + `).
+
+Any listed name or alias can be passed to --format-name, used as an --emit
+column, or given to 'uuid decode --from'.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sample, err := generator.ParseUUID(formatSample)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tDESCRIPTION\tEXAMPLE")
+		for _, e := range generator.Encodings() {
+			name := e.Name
+			if len(e.Aliases) > 0 {
+				name += " (" + strings.Join(e.Aliases, ", ") + ")"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", name, e.Description, e.Encode(sample))
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listFormatsCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestFormatNameRoundTrips(t *testing.T) {
+	for _, e := range generator.Encodings() {
+		for _, name := range append([]string{e.Name}, e.Aliases...) {
+			t.Run(name, func(t *testing.T) {
+				output, err := executeCommand(t, "", "-t", "2022-02-22T19:22:22Z", "--format-name", name)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				uuid, err := e.Decode(strings.TrimSpace(output))
+				if err != nil {
+					t.Fatalf("Output %q does not decode as %s: %v", output, e.Name, err)
+				}
+				if details := generator.Inspect(uuid); details.Version != 7 || details.Timestamp.UnixMilli() != 1645557742000 {
+					t.Errorf("Round trip through %s lost data: %+v", name, details)
+				}
+			})
+		}
+	}
+}
+
+func TestOIDOutputRoundTrips(t *testing.T) {
+	output, err := executeCommand(t, "", "--oid", "-n", "20")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if !strings.HasPrefix(line, "2.25.") {
+			t.Fatalf("Expected an OID under 2.25, got %q", line)
+		}
+		decoded, err := executeCommand(t, "", "decode", "--from", "oid", line)
+		if err != nil {
+			t.Fatalf("decode %s: %v", line, err)
+		}
+		uuid, err := generator.ParseUUID(line)
+		if err != nil {
+			t.Fatalf("ParseUUID %s: %v", line, err)
+		}
+		if strings.TrimSpace(decoded) != generator.FormatUUID(uuid) {
+			t.Errorf("decode and ParseUUID disagree for %s: %q vs %s", line, decoded, generator.FormatUUID(uuid))
+		}
+		if generator.Inspect(uuid).Version != 4 {
+			t.Errorf("Round trip of %s lost the version", line)
+		}
+	}
+}
+
+func TestListFormatsMatchesRegistry(t *testing.T) {
+	output, err := executeCommand(t, "", "list-formats")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	encodings := generator.Encodings()
+	if len(lines) != len(encodings)+1 {
+		t.Fatalf("Expected a header and %d formats, got %d lines:\n%s", len(encodings), len(lines), output)
+	}
+
+	sample, _ := generator.ParseUUID(formatSample)
+	for i, e := range encodings {
+		line := lines[i+1]
+		if !strings.HasPrefix(line, e.Name) || !strings.Contains(line, e.Description) || !strings.HasSuffix(line, e.Encode(sample)) {
+			t.Errorf("Listing line %d does not describe %s: %q", i+1, e.Name, line)
+		}
+		for _, alias := range e.Aliases {
+			if !strings.Contains(line, alias) {
+				t.Errorf("Listing for %s should mention alias %s: %q", e.Name, alias, line)
+			}
+		}
+	}
+}
+
+func TestFormatNameErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"unknown", []string{"--format-name", "base99"}, "unknown format 'base99'. Run 'uuid list-formats'"},
+		{"with emit", []string{"--format-name", "urn", "--emit", "canonical"}, "none of the others can be"},
+		{"oid with format-name", []string{"--oid", "--format-name", "urn"}, "none of the others can be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// frontMatterExtensions are the file types the frontmatter command edits
+var frontMatterExtensions = []string{".md", ".markdown"}
+
+// plainYAMLKey matches field names that can be written as a plain YAML key
+var plainYAMLKey = sync.OnceValue(func() *regexp.Regexp {
+	return regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+})
+
+// errNoFrontMatter marks files without front matter when --create is not set
+var errNoFrontMatter = errors.New("no front matter")
+
+// frontmatterCmd adds a UUID field to the YAML front matter of Markdown files
+var frontmatterCmd = &cobra.Command{
+	Use:   "frontmatter",
+	Short: "Add a UUID to the front matter of Markdown files that lack one",
+	Long: `Add a UUIDv4 field to the YAML front matter of every Markdown file
+(.md, .markdown) under --dir that does not already have it.
+
+The field is appended as the last line of the front matter. Everything else
+in the file, including its line endings, is left byte-for-byte unchanged, so
+running the command again modifies nothing. Files without front matter are
+skipped with a warning unless --create is given, which adds a block holding
+only the field. TOML front matter (+++) is rejected.
+
+Every file is checked before any is written, so a file with broken front
+matter leaves the whole tree untouched. The path of each changed file is
+printed; with --dry-run the paths are printed and nothing is written.
+
+Examples:
+  uuid frontmatter --dir content/
+  uuid frontmatter --dir content/ --field uid --create --dry-run`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+		field, _ := cmd.Flags().GetString("field")
+		create, _ := cmd.Flags().GetBool("create")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if !plainYAMLKey().MatchString(field) {
+			return fmt.Errorf("invalid --field '%s': use letters, digits, '_', and '-', starting with a letter or '_'", field)
+		}
+
+		type update struct {
+			path    string
+			content []byte
+			mode    fs.FileMode
+		}
+		var updates []update
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if entry.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !entry.Type().IsRegular() || !isMarkdown(path) {
+				return nil
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			updated, changed, err := injectFrontMatter(content, field, generator.GenerateUUIDv4(), create)
+			if errors.Is(err, errNoFrontMatter) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: %s has no front matter; use --create to add one.\n", path)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if changed {
+				info, err := entry.Info()
+				if err != nil {
+					return err
+				}
+				updates = append(updates, update{path, updated, info.Mode().Perm()})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		for _, u := range updates {
+			if !dryRun {
+				if err := os.WriteFile(u.path, u.content, u.mode); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintln(out, u.path)
+		}
+		return nil
+	},
+}
+
+// isMarkdown reports whether path has a Markdown extension
+func isMarkdown(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range frontMatterExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// injectFrontMatter adds "field: id" to the YAML front matter of content
+// unless the field is already present. Files without front matter get a new
+// block when create is set and errNoFrontMatter otherwise.
+func injectFrontMatter(content []byte, field, id string, create bool) ([]byte, bool, error) {
+	first, rest := splitLine(content)
+	eol := lineEnding(first)
+	entry := field + ": " + id + eol
+
+	switch delimiter := string(bytes.TrimRight(first, "\r\n")); delimiter {
+	case "+++":
+		return nil, false, fmt.Errorf("TOML front matter (+++) is not supported; only YAML (---) is")
+	case "---":
+	default:
+		if !create {
+			return nil, false, errNoFrontMatter
+		}
+		if eol == "" {
+			eol = "\n"
+			entry += eol
+		}
+		block := "---" + eol + entry + "---" + eol
+		return append([]byte(block), content...), true, nil
+	}
+
+	// Find the closing delimiter, which YAML also allows to be "..."
+	offset := len(first)
+	for len(rest) > 0 {
+		line, remaining := splitLine(rest)
+		if closing := string(bytes.TrimRight(line, "\r\n")); closing == "---" || closing == "..." {
+			var fields map[string]any
+			if err := yaml.Unmarshal(content[len(first):offset], &fields); err != nil {
+				return nil, false, fmt.Errorf("front matter is not a YAML mapping: %w", err)
+			}
+			if _, ok := fields[field]; ok {
+				return content, false, nil
+			}
+			updated := make([]byte, 0, len(content)+len(entry))
+			updated = append(updated, content[:offset]...)
+			updated = append(updated, entry...)
+			updated = append(updated, content[offset:]...)
+			return updated, true, nil
+		}
+		offset += len(line)
+		rest = remaining
+	}
+	return nil, false, fmt.Errorf("front matter is not closed by a '---' line")
+}
+
+// splitLine returns the first line of b, including its line ending, and the
+// remainder
+func splitLine(b []byte) (line, rest []byte) {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		return b[:i+1], b[i+1:]
+	}
+	return b, nil
+}
+
+// lineEnding returns the ending of line: "\r\n", "\n", or "" for a final
+// line without one
+func lineEnding(line []byte) string {
+	switch {
+	case bytes.HasSuffix(line, []byte("\r\n")):
+		return "\r\n"
+	case bytes.HasSuffix(line, []byte("\n")):
+		return "\n"
+	}
+	return ""
+}
+
+func init() {
+	frontmatterCmd.Flags().String("dir", ".", "Directory to scan for Markdown files")
+	frontmatterCmd.Flags().String("field", "id", "Front matter field to hold the UUID")
+	frontmatterCmd.Flags().Bool("create", false, "Add a front matter block to files that have none")
+	frontmatterCmd.Flags().Bool("dry-run", false, "List the files that would change without writing them")
+	rootCmd.AddCommand(frontmatterCmd)
+}
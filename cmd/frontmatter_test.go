@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// idLine matches an inserted field line and captures its line ending
+var idLine = regexp.MustCompile(`id: [0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}(\r?\n)`)
+
+func writeContentTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func readContent(t *testing.T, dir, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestFrontMatterInjection(t *testing.T) {
+	files := map[string]string{
+		"has-id.md":          "---\ntitle: Kept\nid: existing\n---\nBody\n",
+		"null-id.md":         "---\nid:\n---\nBody\n",
+		"missing.md":         "---\ntitle: Hello\ntags: [a, b]\n---\n\n# Hello\n",
+		"crlf.md":            "---\r\ntitle: Windows\r\n---\r\nLine one\r\nLine two\r\n",
+		"dots.markdown":      "---\ntitle: Dots\n...\nBody\n",
+		"empty-block.md":     "---\n---\nBody\n",
+		"nested/deep.md":     "---\nparent:\n  id: not-top-level\n---\n",
+		"none.md":            "# No front matter\n",
+		"notes.txt":          "---\ntitle: Not Markdown\n---\n",
+		".git/ignored.md":    "---\ntitle: Ignored\n---\n",
+		"horizontal-rule.md": "Intro\n\n---\n\nMore\n",
+	}
+	dir := writeContentTree(t, files)
+
+	output, stderr, err := executeCommandSplit(t, "", "frontmatter", "--dir", dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	changed := []string{"missing.md", "crlf.md", "dots.markdown", "empty-block.md", "nested/deep.md"}
+	for _, name := range changed {
+		if !strings.Contains(output, filepath.Join(dir, name)+"\n") {
+			t.Errorf("Expected %s to be listed as changed, got:\n%s", name, output)
+		}
+	}
+	if lines := strings.Count(output, "\n"); lines != len(changed) {
+		t.Errorf("Expected %d changed files, got:\n%s", len(changed), output)
+	}
+	for _, name := range []string{"none.md", "horizontal-rule.md"} {
+		if !strings.Contains(stderr, filepath.Join(dir, name)+" has no front matter") {
+			t.Errorf("Expected a warning for %s, got %q", name, stderr)
+		}
+	}
+
+	// Untouched files are byte-for-byte identical
+	for _, name := range []string{"has-id.md", "null-id.md", "none.md", "notes.txt", ".git/ignored.md", "horizontal-rule.md"} {
+		if got := readContent(t, dir, name); got != files[name] {
+			t.Errorf("Expected %s unchanged, got %q", name, got)
+		}
+	}
+
+	// Changed files differ only by the inserted line, in the file's line ending
+	for _, name := range changed {
+		got := readContent(t, dir, name)
+		match := idLine.FindStringSubmatch(got)
+		if match == nil {
+			t.Fatalf("Expected an id line in %s, got %q", name, got)
+		}
+		if strings.Replace(got, match[0], "", 1) != files[name] {
+			t.Errorf("Expected only the id line added to %s, got %q", name, got)
+		}
+		wantEOL := "\n"
+		if name == "crlf.md" {
+			wantEOL = "\r\n"
+		}
+		if match[1] != wantEOL {
+			t.Errorf("Expected %q line ending in %s, got %q", wantEOL, name, match[1])
+		}
+	}
+	if got := readContent(t, dir, "missing.md"); !strings.HasPrefix(got, "---\ntitle: Hello\ntags: [a, b]\nid: ") {
+		t.Errorf("Expected the id as the last front matter line, got %q", got)
+	}
+
+	// A second run changes nothing
+	before := map[string]string{}
+	for name := range files {
+		before[name] = readContent(t, dir, name)
+	}
+	output, _, err = executeCommandSplit(t, "", "frontmatter", "--dir", dir)
+	if err != nil || output != "" {
+		t.Errorf("Expected no changes on the second run, got %q, %v", output, err)
+	}
+	for name, content := range before {
+		if readContent(t, dir, name) != content {
+			t.Errorf("Second run modified %s", name)
+		}
+	}
+}
+
+func TestFrontMatterCreate(t *testing.T) {
+	files := map[string]string{
+		"plain.md": "# Title\n\nBody\n",
+		"crlf.md":  "# Title\r\nBody\r\n",
+		"empty.md": "",
+	}
+	dir := writeContentTree(t, files)
+
+	if _, err := executeCommand(t, "", "frontmatter", "--dir", dir, "--create", "--field", "uid"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for name, eol := range map[string]string{"plain.md": "\n", "crlf.md": "\r\n", "empty.md": "\n"} {
+		got := readContent(t, dir, name)
+		prefix := "---" + eol + "uid: "
+		suffix := eol + "---" + eol + files[name]
+		if !strings.HasPrefix(got, prefix) || !strings.HasSuffix(got, suffix) || len(got) != len(prefix)+36+len(suffix) {
+			t.Errorf("Unexpected created block in %s: %q", name, got)
+		}
+	}
+
+	output, _ := executeCommand(t, "", "frontmatter", "--dir", dir, "--create", "--field", "uid")
+	if output != "" {
+		t.Errorf("Expected no changes on the second run, got %q", output)
+	}
+}
+
+func TestFrontMatterDryRun(t *testing.T) {
+	files := map[string]string{"a.md": "---\ntitle: A\n---\n", "b.md": "---\nid: b\n---\n"}
+	dir := writeContentTree(t, files)
+
+	output, err := executeCommand(t, "", "frontmatter", "--dir", dir, "--dry-run")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != filepath.Join(dir, "a.md")+"\n" {
+		t.Errorf("Expected only a.md listed, got %q", output)
+	}
+	if readContent(t, dir, "a.md") != files["a.md"] {
+		t.Error("--dry-run must not write files")
+	}
+}
+
+func TestFrontMatterErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		args     []string
+		contains string
+	}{
+		{"toml", map[string]string{"toml.md": "+++\ntitle = \"TOML\"\n+++\n"}, nil, "TOML front matter (+++) is not supported"},
+		{"unterminated", map[string]string{"open.md": "---\ntitle: Open\n"}, nil, "front matter is not closed"},
+		{"not a mapping", map[string]string{"list.md": "---\n- a\n- b\n---\n"}, nil, "front matter is not a YAML mapping"},
+		{"bad field", map[string]string{}, []string{"--field", "my id"}, "invalid --field 'my id'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A valid file alongside must not be written when another fails
+			tt.files["valid.md"] = "---\ntitle: Valid\n---\n"
+			dir := writeContentTree(t, tt.files)
+
+			_, err := executeCommand(t, "", append([]string{"frontmatter", "--dir", dir}, tt.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+			if readContent(t, dir, "valid.md") != tt.files["valid.md"] {
+				t.Error("Expected no file to be written after an error")
+			}
+		})
+	}
+}
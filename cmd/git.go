@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// gitNamespace is the namespace for commit-derived UUIDs: the UUIDv5 of
+// "https://github.com/scottbrown/uuid/git" in the URL namespace
+var gitNamespace = generator.NewV5(generator.NamespaceURL, "https://github.com/scottbrown/uuid/git")
+
+// gitDirtySuffix is appended to the commit hash before hashing when
+// --allow-dirty mixes uncommitted changes into the UUID
+const gitDirtySuffix = "-dirty"
+
+// gitCmd derives a build identifier from a git commit
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Derive a deterministic UUID from a git commit",
+	Long: `Derive a deterministic UUID from a git commit, for build identifiers.
+
+--ref (default HEAD) is resolved to its full commit hash with git, and the
+lowercase hash is the name of a name-based UUID in the namespace
+` + generator.FormatUUID(gitNamespace) + ` (the UUIDv5 of
+"https://github.com/scottbrown/uuid/git" in the URL namespace). --hash
+sha256 (default) gives UUIDv8 per RFC 9562 appendix B.2; --hash sha1 gives
+UUIDv5.
+
+When the ref is the checked-out commit and the working tree has uncommitted
+changes, the command fails, since the build would not match the commit. With
+--allow-dirty the name becomes the hash followed by "` + gitDirtySuffix + `", so dirty
+builds get a different UUID. --verbose prints the hashed name and the UUID
+separated by a tab.
+
+Examples:
+  uuid git
+  uuid git --ref v1.2.0 --repo ../service
+  uuid git --allow-dirty --verbose`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, _ := cmd.Flags().GetString("ref")
+		repo, _ := cmd.Flags().GetString("repo")
+		allowDirty, _ := cmd.Flags().GetBool("allow-dirty")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		hashName, _ := cmd.Flags().GetString("hash")
+
+		version, ok := manifestHashes[hashName]
+		if !ok {
+			return fmt.Errorf("unknown hash '%s'. Available hashes: sha256, sha1", hashName)
+		}
+
+		if _, err := runGit(repo, "rev-parse", "--git-dir"); err != nil {
+			return fmt.Errorf("'%s' is not inside a git repository: %w", repo, err)
+		}
+		commit, err := runGit(repo, "rev-parse", "--verify", "--end-of-options", ref+"^{commit}")
+		if err != nil {
+			return fmt.Errorf("cannot resolve ref '%s' to a commit: %w", ref, err)
+		}
+
+		name := commit
+		if head, err := runGit(repo, "rev-parse", "--verify", "HEAD"); err == nil && head == commit {
+			status, err := runGit(repo, "status", "--porcelain")
+			if err != nil {
+				return fmt.Errorf("cannot check for uncommitted changes: %w", err)
+			}
+			if status != "" {
+				if !allowDirty {
+					return fmt.Errorf("working tree has uncommitted changes, so %s does not describe it; commit them or use --allow-dirty", commit)
+				}
+				name += gitDirtySuffix
+			}
+		}
+
+		uuid, _ := generator.NewNameBasedFromReader(version, gitNamespace, strings.NewReader(name))
+		debugLogger.Debug("derived git UUID", "ref", ref, "name", name, "version", version)
+		if verbose {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", name, generator.FormatUUID(uuid))
+			return nil
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), generator.FormatUUID(uuid))
+		return nil
+	},
+}
+
+// runGit runs git in repo and returns its trimmed stdout. Failures carry
+// git's own message. Tests may replace it.
+var runGit = func(repo string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	c.Stdout, c.Stderr = &stdout, &stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", errors.New(strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func init() {
+	gitCmd.Flags().String("ref", "HEAD", "Branch, tag, or commit to derive the UUID from")
+	gitCmd.Flags().String("repo", ".", "Path inside the git repository")
+	gitCmd.Flags().Bool("allow-dirty", false, "Mark uncommitted changes in the UUID instead of failing")
+	gitCmd.Flags().Bool("verbose", false, "Print the hashed commit name before the UUID")
+	gitCmd.Flags().String("hash", "sha256", "Hash for the UUID: sha256 (UUIDv8) or sha1 (UUIDv5)")
+	rootCmd.AddCommand(gitCmd)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// newGitRepo creates a repository with one commit and returns its path and
+// commit hash
+func newGitRepo(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "first"},
+		{"tag", "v1"},
+	} {
+		if _, err := runGit(dir, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	commit, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, commit
+}
+
+func TestGitDerivationPinned(t *testing.T) {
+	if got := generator.FormatUUID(gitNamespace); got != "fa15abe7-c871-5f77-ab2d-4f72cbbe6d42" {
+		t.Errorf("The documented git namespace changed: %s", got)
+	}
+
+	// A synthetic hash, with git replaced so the derivation alone is tested
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	dirty := ""
+	original := runGit
+	runGit = func(repo string, args ...string) (string, error) {
+		switch args[0] {
+		case "status":
+			return dirty, nil
+		case "rev-parse":
+			if args[1] == "--git-dir" {
+				return ".git", nil
+			}
+			return commit, nil
+		}
+		t.Fatalf("unexpected git %v", args)
+		return "", nil
+	}
+	t.Cleanup(func() { runGit = original })
+
+	tests := []struct {
+		name     string
+		dirty    string
+		args     []string
+		expected string
+	}{
+		{"sha256", "", nil, "246c5373-0797-8140-afd1-f85b88c1ffd9\n"},
+		{"sha1", "", []string{"--hash", "sha1"}, "aba13822-8915-52c4-9014-3c33738a5c1d\n"},
+		{"verbose", "", []string{"--verbose"}, commit + "\t246c5373-0797-8140-afd1-f85b88c1ffd9\n"},
+		{"dirty", " M main.go", []string{"--allow-dirty", "--verbose"}, commit + "-dirty\ta202701e-8ca2-8a4f-a929-631bf831ece4\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dirty = tt.dirty
+			output, err := executeCommand(t, "", append([]string{"git"}, tt.args...)...)
+			if err != nil || output != tt.expected {
+				t.Errorf("Expected %q, got %q, %v", tt.expected, output, err)
+			}
+		})
+	}
+}
+
+func TestGitRepository(t *testing.T) {
+	repo, commit := newGitRepo(t)
+
+	output, err := executeCommand(t, "", "git", "--repo", repo, "--verbose")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	name, uuid, _ := strings.Cut(strings.TrimSpace(output), "\t")
+	if name != commit {
+		t.Errorf("Expected commit %s, got %s", commit, name)
+	}
+	if expected := generator.FormatUUID(generator.NewV8SHA256(gitNamespace, commit)); uuid != expected {
+		t.Errorf("Expected %s, got %s", expected, uuid)
+	}
+
+	// Refs resolve to the same commit, and a subdirectory finds the repository
+	os.Mkdir(filepath.Join(repo, "sub"), 0o755)
+	for _, args := range [][]string{{"--ref", "v1"}, {"--repo", filepath.Join(repo, "sub")}} {
+		output, err := executeCommand(t, "", append([]string{"git", "--repo", repo}, args...)...)
+		if err != nil || strings.TrimSpace(output) != uuid {
+			t.Errorf("Expected %s for %v, got %q, %v", uuid, args, output, err)
+		}
+	}
+
+	// Uncommitted changes fail unless they are marked in the UUID
+	os.WriteFile(filepath.Join(repo, "new.txt"), []byte("change"), 0o644)
+	_, err = executeCommand(t, "", "git", "--repo", repo)
+	if err == nil || !strings.Contains(err.Error(), "working tree has uncommitted changes") {
+		t.Errorf("Expected a dirty tree error, got: %v", err)
+	}
+	output, err = executeCommand(t, "", "git", "--repo", repo, "--allow-dirty")
+	if err != nil || strings.TrimSpace(output) == uuid {
+		t.Errorf("Expected a different UUID for a dirty tree, got %q, %v", output, err)
+	}
+
+	// A ref other than the checkout is not affected by the working tree
+	if _, err := runGit(repo, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "second"); err != nil {
+		t.Fatal(err)
+	}
+	output, err = executeCommand(t, "", "git", "--repo", repo, "--ref", "v1")
+	if err != nil || strings.TrimSpace(output) != uuid {
+		t.Errorf("Expected %s for the tagged commit, got %q, %v", uuid, output, err)
+	}
+}
+
+func TestGitErrors(t *testing.T) {
+	repo, _ := newGitRepo(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"outside a repository", []string{"git", "--repo", t.TempDir()}, "is not inside a git repository"},
+		{"unknown ref", []string{"git", "--repo", repo, "--ref", "nope"}, "cannot resolve ref 'nope' to a commit"},
+		{"unknown hash", []string{"git", "--repo", repo, "--hash", "md5"}, "unknown hash 'md5'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
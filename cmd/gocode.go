@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// gocodeCmd emits a Go source file declaring freshly generated UUIDs
+var gocodeCmd = &cobra.Command{
+	Use:   "gocode",
+	Short: "Generate a Go file declaring well-known UUIDs",
+	Long: `Generate a complete, gofmt-clean Go source file that declares freshly
+generated UUIDs, for services that need a stable set of well-known IDs.
+
+By default the UUIDs are declared as string constants, so the file imports
+nothing. With --typed they are declared as uuid.UUID variables using
+github.com/google/uuid's MustParse, and the file imports that package; the
+consuming module must require it.
+
+Names are exported identifiers built from --var-prefix and either the
+--names list or a 1-based index. Each name in --names is converted to
+CamelCase, so "feature-flag" becomes "FeatureFlag".
+
+Doc comments record when each UUID was generated; --reproducible omits the
+timestamp so regenerating the file only changes the UUIDs themselves.
+
+Examples:
+  uuid gocode --package ids --var-prefix Widget -n 5
+  uuid gocode --package ids --var-prefix Flag --names checkout,search -7 --typed`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pkg, _ := cmd.Flags().GetString("package")
+		prefix, _ := cmd.Flags().GetString("var-prefix")
+		names, _ := cmd.Flags().GetStringSlice("names")
+		count, _ := cmd.Flags().GetInt("count")
+		typed, _ := cmd.Flags().GetBool("typed")
+		reproducible, _ := cmd.Flags().GetBool("reproducible")
+		v6, _ := cmd.Flags().GetBool("6")
+		v7, _ := cmd.Flags().GetBool("7")
+
+		if !token.IsIdentifier(pkg) {
+			return fmt.Errorf("invalid package name '%s'", pkg)
+		}
+		if len(names) > 0 {
+			if !cmd.Flags().Changed("count") {
+				count = len(names)
+			} else if len(names) != count {
+				return fmt.Errorf("--names has %d entries but -n is %d", len(names), count)
+			}
+		}
+		if count < 1 {
+			return fmt.Errorf("count must be at least 1, got %d", count)
+		}
+
+		identifiers, err := gocodeNames(prefix, names, count)
+		if err != nil {
+			return err
+		}
+
+		versionNumber, generate := 4, generator.GenerateUUIDv4
+		if v6 {
+			versionNumber, generate = 6, generator.GenerateUUIDv6
+		} else if v7 {
+			versionNumber, generate = 7, generator.GenerateUUIDv7
+		}
+		if v6 || v7 {
+			if err := checkClock(cmd); err != nil {
+				return err
+			}
+		}
+
+		var generatedAt string
+		if !reproducible {
+			generatedAt = now().UTC().Format(time.RFC3339)
+		}
+
+		source, err := renderGocode(pkg, identifiers, generate, versionNumber, typed, generatedAt)
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(source)
+		return err
+	},
+}
+
+// gocodeNames builds one exported identifier per UUID from the prefix and
+// either the given names or a 1-based index
+func gocodeNames(prefix string, names []string, count int) ([]string, error) {
+	identifiers := make([]string, count)
+	seen := make(map[string]bool)
+	for i := range identifiers {
+		suffix := fmt.Sprint(i + 1)
+		if len(names) > 0 {
+			suffix = camelCase(names[i])
+		}
+		base := camelCase(prefix)
+		if base == "" && len(names) == 0 {
+			base = "ID"
+		}
+		identifier := base + suffix
+
+		if !token.IsIdentifier(identifier) || !token.IsExported(identifier) {
+			return nil, fmt.Errorf("name '%s' does not form a valid exported Go identifier", identifier)
+		}
+		if seen[identifier] {
+			return nil, fmt.Errorf("name '%s' is used more than once", identifier)
+		}
+		seen[identifier] = true
+		identifiers[i] = identifier
+	}
+	return identifiers, nil
+}
+
+// camelCase joins the letter and digit runs of s, upper-casing the first
+// letter of each run
+func camelCase(s string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// renderGocode writes the Go file and runs it through gofmt, so the output is
+// always formatted exactly as go fmt would leave it
+func renderGocode(pkg string, identifiers []string, generate func() string, versionNumber int, typed bool, generatedAt string) ([]byte, error) {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Package %s declares well-known UUIDs generated by uuid gocode.\n", pkg)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	keyword := "const"
+	if typed {
+		keyword = "var"
+		b.WriteString("import \"github.com/google/uuid\"\n\n")
+	}
+
+	fmt.Fprintf(&b, "%s (\n", keyword)
+	for i, identifier := range identifiers {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if generatedAt != "" {
+			fmt.Fprintf(&b, "// %s is a UUIDv%d generated at %s.\n", identifier, versionNumber, generatedAt)
+		} else {
+			fmt.Fprintf(&b, "// %s is a UUIDv%d.\n", identifier, versionNumber)
+		}
+		if typed {
+			fmt.Fprintf(&b, "%s = uuid.MustParse(%q)\n", identifier, generate())
+		} else {
+			fmt.Fprintf(&b, "%s = %q\n", identifier, generate())
+		}
+	}
+	b.WriteString(")\n")
+
+	formatted, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not compile: %w", err)
+	}
+	return formatted, nil
+}
+
+func init() {
+	gocodeCmd.Flags().String("package", "ids", "Package name for the generated file")
+	gocodeCmd.Flags().String("var-prefix", "", "Prefix for every declared name")
+	gocodeCmd.Flags().StringSlice("names", nil, "Comma-separated names, one per UUID (default: 1-based index)")
+	gocodeCmd.Flags().IntP("count", "n", 1, "Number of UUIDs to declare")
+	gocodeCmd.Flags().Bool("typed", false, "Declare uuid.UUID variables using github.com/google/uuid instead of string constants")
+	gocodeCmd.Flags().Bool("reproducible", false, "Omit the generation timestamp from doc comments")
+	gocodeCmd.Flags().BoolP("4", "4", false, "Generate UUIDv4 (default)")
+	gocodeCmd.Flags().BoolP("6", "6", false, "Generate UUIDv6")
+	gocodeCmd.Flags().BoolP("7", "7", false, "Generate UUIDv7 (contains timestamp)")
+	gocodeCmd.MarkFlagsMutuallyExclusive("4", "6", "7")
+	addClockFlags(gocodeCmd)
+
+	rootCmd.AddCommand(gocodeCmd)
+}
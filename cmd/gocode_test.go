@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseGocode checks that output is gofmt-clean Go and returns its syntax tree
+func parseGocode(t *testing.T, output string) *ast.File {
+	t.Helper()
+	formatted, err := format.Source([]byte(output))
+	if err != nil {
+		t.Fatalf("Output does not parse: %v\n%s", err, output)
+	}
+	if string(formatted) != output {
+		t.Errorf("Output is not gofmt-clean:\n%s", output)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "ids.go", output, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Output does not parse: %v", err)
+	}
+	return file
+}
+
+// declaredNames returns the names of every const or var declared in file
+func declaredNames(file *ast.File) []string {
+	var names []string
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok {
+			for _, spec := range gen.Specs {
+				if value, ok := spec.(*ast.ValueSpec); ok {
+					for _, name := range value.Names {
+						names = append(names, name.Name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+func TestGocodeConstants(t *testing.T) {
+	withClock(t, "", time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC))
+
+	output, err := executeCommand(t, "", "gocode", "--package", "ids", "--var-prefix", "Widget", "-n", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	file := parseGocode(t, output)
+
+	if file.Name.Name != "ids" {
+		t.Errorf("Expected package ids, got %s", file.Name.Name)
+	}
+	if len(file.Imports) != 0 {
+		t.Errorf("String constants should not import anything")
+	}
+	if got := strings.Join(declaredNames(file), ","); got != "Widget1,Widget2,Widget3" {
+		t.Errorf("Unexpected names: %s", got)
+	}
+	if !strings.Contains(output, "// Widget1 is a UUIDv4 generated at 2025-03-04T05:06:07Z.") {
+		t.Errorf("Doc comments should include the generation time:\n%s", output)
+	}
+}
+
+func TestGocodeNamesReproducible(t *testing.T) {
+	output, err := executeCommand(t, "", "gocode", "--var-prefix", "flag", "--names", "checkout,search-v2", "-7", "--reproducible")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	file := parseGocode(t, output)
+
+	if got := strings.Join(declaredNames(file), ","); got != "FlagCheckout,FlagSearchV2" {
+		t.Errorf("Unexpected names: %s", got)
+	}
+	if !strings.Contains(output, "// FlagCheckout is a UUIDv7.\n") || strings.Contains(output, "generated at") {
+		t.Errorf("--reproducible should omit the timestamp:\n%s", output)
+	}
+	if len(regexp.MustCompile(`= "[0-9a-f]{8}-[0-9a-f]{4}-7`).FindAllString(output, -1)) != 2 {
+		t.Errorf("Expected two UUIDv7 constants:\n%s", output)
+	}
+}
+
+func TestGocodeTypedNamesReproducible(t *testing.T) {
+	output, err := executeCommand(t, "", "gocode", "--var-prefix", "flag", "--names", "checkout,search-v2", "-7", "--typed", "--reproducible")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	file := parseGocode(t, output)
+
+	if len(file.Imports) != 1 || file.Imports[0].Path.Value != `"github.com/google/uuid"` {
+		t.Errorf("Typed output should import github.com/google/uuid")
+	}
+	if got := strings.Join(declaredNames(file), ","); got != "FlagCheckout,FlagSearchV2" {
+		t.Errorf("Unexpected names: %s", got)
+	}
+	if !strings.Contains(output, "// FlagCheckout is a UUIDv7.\n") || strings.Contains(output, "generated at") {
+		t.Errorf("--reproducible should omit the timestamp:\n%s", output)
+	}
+	if len(regexp.MustCompile(`uuid\.MustParse\("[0-9a-f]{8}-[0-9a-f]{4}-7`).FindAllString(output, -1)) != 2 {
+		t.Errorf("Expected two MustParse UUIDv7 declarations:\n%s", output)
+	}
+}
+
+func TestGocodeClockCheck(t *testing.T) {
+	withClock(t, "2025-01-01T00:00:00Z", time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	for _, version := range []string{"-6", "-7"} {
+		_, err := executeCommand(t, "", "gocode", version, "--strict-clock")
+		if err == nil || !strings.Contains(err.Error(), "refusing to generate time-based UUIDs (--strict-clock)") {
+			t.Errorf("%s: expected the strict clock check to fail, got %v", version, err)
+		}
+	}
+	output, err := executeCommand(t, "", "gocode", "-7")
+	if err != nil || !strings.Contains(output, "WARNING: system clock") {
+		t.Errorf("Expected a clock warning, got %q, %v", output, err)
+	}
+	// UUIDv4 embeds no time, so there is nothing to check
+	output, err = executeCommand(t, "", "gocode", "-4", "--strict-clock")
+	if err != nil || strings.Contains(output, "WARNING") {
+		t.Errorf("Expected no clock check for -4, got %q, %v", output, err)
+	}
+}
+
+func TestGocodeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"names count mismatch", []string{"gocode", "--names", "a,b", "-n", "3"}, "--names has 2 entries but -n is 3"},
+		{"unexported name", []string{"gocode", "--names", "1st"}, "name '1st' does not form a valid exported Go identifier"},
+		{"duplicate names", []string{"gocode", "--names", "a-b,a_b"}, "name 'AB' is used more than once"},
+		{"bad package", []string{"gocode", "--package", "my-ids"}, "invalid package name 'my-ids'"},
+		{"zero count", []string{"gocode", "-n", "0"}, "count must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
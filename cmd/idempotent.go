@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// idempotentStateFile is the name of the file caching --idempotent keys
+const idempotentStateFile = "idempotent.json"
+
+// idempotentEntry is the UUID cached for one --key
+type idempotentEntry struct {
+	UUID      string    `json:"uuid"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// idempotentUUID returns the UUID cached for key when it is younger than ttl
+// (a ttl of zero never expires), and otherwise stores and returns a new one
+// from generate. The cache is locked for the whole lookup, so concurrent
+// invocations for the same key agree on one UUID. A corrupt cache is
+// reported on warn and replaced.
+func idempotentUUID(warn io.Writer, key string, ttl time.Duration, generate func() string) (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, idempotentStateFile)
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	entries, err := loadIdempotentEntries(path)
+	if err != nil {
+		fmt.Fprintf(warn, "WARNING: %v; starting a new cache.\n", err)
+		entries = map[string]idempotentEntry{}
+	}
+
+	current := now()
+	if entry, ok := entries[key]; ok && (ttl == 0 || current.Sub(entry.CreatedAt) < ttl) {
+		debugLogger.Debug("idempotent key", "key", key, "source", "cache", "created_at", entry.CreatedAt)
+		return entry.UUID, nil
+	}
+
+	entry := idempotentEntry{UUID: generate(), CreatedAt: current.UTC()}
+	entries[key] = entry
+	debugLogger.Debug("idempotent key", "key", key, "source", "generated")
+	return entry.UUID, saveIdempotentEntries(path, entries)
+}
+
+// forgetIdempotentKey removes key from the cache, reporting whether it was
+// there
+func forgetIdempotentKey(warn io.Writer, key string) (bool, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return false, err
+	}
+	path := filepath.Join(dir, idempotentStateFile)
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	entries, err := loadIdempotentEntries(path)
+	if err != nil {
+		fmt.Fprintf(warn, "WARNING: %v; starting a new cache.\n", err)
+		return false, saveIdempotentEntries(path, map[string]idempotentEntry{})
+	}
+	if _, ok := entries[key]; !ok {
+		return false, nil
+	}
+	delete(entries, key)
+	return true, saveIdempotentEntries(path, entries)
+}
+
+// loadIdempotentEntries reads the cache; a missing file is an empty cache
+func loadIdempotentEntries(path string) (map[string]idempotentEntry, error) {
+	entries := map[string]idempotentEntry{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("corrupt state file %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// saveIdempotentEntries replaces the cache with replaceFile while the caller
+// holds its lock, so readers never see a partial file, even after a crash
+func saveIdempotentEntries(path string, entries map[string]idempotentEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, append(data, '\n'))
+}
+
+// lockStateFile takes an exclusive advisory lock on path.lock with
+// lockFile, waiting while another process holds it. The state file itself
+// is not locked because it is replaced by renaming. The lock file is left
+// in place: the lock belongs to the open file, so the operating system
+// releases it when unlock closes the file or the process exits, and a
+// crashed run never leaves it held.
+func lockStateFile(path string) (unlock func(), err error) {
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() { f.Close() }, nil
+}
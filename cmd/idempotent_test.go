@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// fakeIdempotentState points the cache at a temporary state directory and
+// the clock at *current
+func fakeIdempotentState(t *testing.T, current *time.Time) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "state")
+	originalDir, originalNow := stateDir, now
+	stateDir = func() (string, error) { return dir, nil }
+	now = func() time.Time { return *current }
+	t.Cleanup(func() { stateDir, now = originalDir, originalNow })
+	return dir
+}
+
+func TestIdempotentReturnsCachedUUID(t *testing.T) {
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fakeIdempotentState(t, &current)
+
+	first, _, err := executeCommandSplit(t, "", "--idempotent", "--key", "db-migration-42", "--ttl", "1h")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !uuidRegex.MatchString(strings.TrimSpace(first)) {
+		t.Fatalf("Expected a UUID, got %q", first)
+	}
+
+	current = current.Add(59 * time.Minute)
+	second, _, err := executeCommandSplit(t, "", "--idempotent", "--key", "db-migration-42", "--ttl", "1h")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if second != first {
+		t.Errorf("Expected the cached UUID %q, got %q", first, second)
+	}
+
+	other, _, _ := executeCommandSplit(t, "", "--idempotent", "--key", "other", "--ttl", "1h")
+	if other == first {
+		t.Errorf("Expected a different key to get a different UUID")
+	}
+
+	// Once expired, a new UUID replaces the cached one
+	current = current.Add(time.Minute)
+	third, _, _ := executeCommandSplit(t, "", "--idempotent", "--key", "db-migration-42", "--ttl", "1h")
+	if third == first {
+		t.Errorf("Expected a new UUID after the TTL, got the cached %q", third)
+	}
+	fourth, _, _ := executeCommandSplit(t, "", "--idempotent", "--key", "db-migration-42", "--ttl", "1h")
+	if fourth != third {
+		t.Errorf("Expected the new UUID %q to be cached, got %q", third, fourth)
+	}
+}
+
+func TestIdempotentWithoutTTLNeverExpires(t *testing.T) {
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fakeIdempotentState(t, &current)
+
+	first, _, err := executeCommandSplit(t, "", "-7", "--idempotent", "--key", "k")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	current = current.AddDate(10, 0, 0)
+	second, _, _ := executeCommandSplit(t, "", "-7", "--idempotent", "--key", "k")
+	if second != first {
+		t.Errorf("Expected the cached UUID %q, got %q", first, second)
+	}
+	if first[14] != '7' {
+		t.Errorf("Expected the version flags to apply to the first UUID, got %s", first)
+	}
+}
+
+func TestIdempotentConcurrent(t *testing.T) {
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fakeIdempotentState(t, &current)
+
+	results := make([]string, 16)
+	errs := make([]error, len(results))
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = idempotentUUID(io.Discard, "shared", time.Hour, generator.GenerateUUIDv4)
+		}()
+	}
+	wg.Wait()
+
+	for i, uuid := range results {
+		if errs[i] != nil {
+			t.Fatalf("Unexpected error: %v", errs[i])
+		}
+		if uuid != results[0] {
+			t.Fatalf("Expected every invocation to agree, got %q and %q", results[0], uuid)
+		}
+	}
+}
+
+func TestIdempotentForget(t *testing.T) {
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fakeIdempotentState(t, &current)
+
+	first, _, _ := executeCommandSplit(t, "", "--idempotent", "--key", "k")
+	kept, _, _ := executeCommandSplit(t, "", "--idempotent", "--key", "kept")
+
+	output, errOut, err := executeCommandSplit(t, "", "--forget", "k")
+	if err != nil || output != "" || errOut != "" {
+		t.Fatalf("Expected a silent forget, got %q, %q, %v", output, errOut, err)
+	}
+	second, _, _ := executeCommandSplit(t, "", "--idempotent", "--key", "k")
+	if second == first {
+		t.Errorf("Expected a new UUID after --forget")
+	}
+	if again, _, _ := executeCommandSplit(t, "", "--idempotent", "--key", "kept"); again != kept {
+		t.Errorf("Expected other keys to stay cached")
+	}
+
+	_, errOut, err = executeCommandSplit(t, "", "--forget", "missing")
+	if err != nil || !strings.Contains(errOut, "WARNING: no UUID is cached for key 'missing'.") {
+		t.Errorf("Expected a warning for an unknown key, got %q, %v", errOut, err)
+	}
+}
+
+func TestIdempotentCorruptCache(t *testing.T) {
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	dir := fakeIdempotentState(t, &current)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, idempotentStateFile), []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	first, errOut, err := executeCommandSplit(t, "", "--idempotent", "--key", "k")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut, "WARNING: corrupt state file") || !strings.Contains(errOut, "starting a new cache.") {
+		t.Errorf("Expected a corruption warning, got %q", errOut)
+	}
+	second, errOut, _ := executeCommandSplit(t, "", "--idempotent", "--key", "k")
+	if second != first || errOut != "" {
+		t.Errorf("Expected the rebuilt cache to be used silently, got %q, %q", second, errOut)
+	}
+}
+
+func TestIdempotentLeftoverLockFile(t *testing.T) {
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	dir := fakeIdempotentState(t, &current)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	// A lock file left by a run that died holds no lock
+	lock := filepath.Join(dir, idempotentStateFile+".lock")
+	if err := os.WriteFile(lock, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := executeCommandSplit(t, "", "--idempotent", "--key", "k"); err != nil {
+		t.Fatalf("Expected a leftover lock file not to block, got %v", err)
+	}
+}
+
+func TestLockStateFileWaits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	acquired := make(chan error)
+	go func() {
+		second, err := lockStateFile(path)
+		if err == nil {
+			second()
+		}
+		acquired <- err
+	}()
+	select {
+	case err := <-acquired:
+		t.Fatalf("Expected the second lock to wait, got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// However long the first holder takes, the second gets the lock only
+	// once it is released
+	unlock()
+	if err := <-acquired; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestIdempotentErrors(t *testing.T) {
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fakeIdempotentState(t, &current)
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"without key", []string{"--idempotent"}, "--idempotent and --key must be given together"},
+		{"key alone", []string{"--key", "k"}, "--idempotent and --key must be given together"},
+		{"ttl alone", []string{"--ttl", "1h"}, "--ttl requires --idempotent"},
+		{"negative ttl", []string{"--idempotent", "--key", "k", "--ttl", "-1h"}, "--ttl must not be negative, got -1h0m0s"},
+		{"with count", []string{"--idempotent", "--key", "k", "-n", "2"}, "--idempotent cannot be combined with -n"},
+		{"forget with key", []string{"--forget", "k", "--key", "k"}, "--forget cannot be combined with --key"},
+		{"with nanoid", []string{"--nanoid", "--idempotent", "--key", "k"}, "--nanoid cannot be combined with --idempotent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestXDGStateDir(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/srv/state")
+	if dir, err := xdgStateDir(); err != nil || dir != filepath.Join("/srv/state", "uuid") {
+		t.Errorf("Expected $XDG_STATE_HOME/uuid, got %q, %v", dir, err)
+	}
+
+	// A relative $XDG_STATE_HOME is ignored, as the XDG spec requires
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", "relative")
+	if dir, err := xdgStateDir(); err != nil || dir != filepath.Join(home, ".local", "state", "uuid") {
+		t.Errorf("Expected ~/.local/state/uuid, got %q, %v", dir, err)
+	}
+}
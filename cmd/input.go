@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// stdinFile is the --file value that reads stdin
+const stdinFile = "-"
+
+// addInputFlags registers the flags shared by the commands that read values
+// one per line: --file, --keep-going, and --verbose
+func addInputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("file", "f", nil, "Read values from this file, one per line; repeat for several, '-' for stdin")
+	cmd.Flags().Bool("keep-going", false, "Continue with the next --file when one cannot be read or fails")
+	cmd.Flags().Bool("verbose", false, "Report how many values each --file held on stderr")
+}
+
+// eachInputValue calls fn for every value given to a stream command: the
+// arguments when there are any, with @name aliases resolved, otherwise
+// every non-blank, trimmed line of each --file in order, or of stdin when no
+// --file is given.
+//
+// Errors from a file are prefixed with its name and line number. With
+// --keep-going a file that is missing or fails is reported on stderr and
+// the remaining files are still processed; the error returned then counts
+// the failed files.
+func eachInputValue(cmd *cobra.Command, args []string, fn func(string) error) error {
+	return eachInput(cmd, args, eachInputLine, fn)
+}
+
+// lineReader calls fn for the values in r, one per line
+type lineReader func(r io.Reader, fn func(string) error) error
+
+// eachInput is eachInputValue with the lines of each input split into
+// values by read
+func eachInput(cmd *cobra.Command, args []string, read lineReader, fn func(string) error) error {
+	files, _ := cmd.Flags().GetStringArray("file")
+	keepGoing, _ := cmd.Flags().GetBool("keep-going")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	if len(args) > 0 {
+		if len(files) > 0 {
+			return fmt.Errorf("--file cannot be combined with values given as arguments")
+		}
+		args, err := resolveAliases(args)
+		if err != nil {
+			return err
+		}
+		for _, value := range args {
+			if err := fn(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(files) == 0 {
+		return read(cmd.InOrStdin(), fn)
+	}
+
+	failed := 0
+	for _, name := range files {
+		count := 0
+		err := eachInputFile(cmd, name, read, func(value string) error {
+			count++
+			return fn(value)
+		})
+		if name == stdinFile {
+			name = "stdin"
+		}
+		if err != nil {
+			err = fmt.Errorf("%s: %w", name, err)
+			if !keepGoing {
+				return err
+			}
+			failed++
+			fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: %v; continuing with the next file.\n", err)
+		}
+		if verbose {
+			status := "ok"
+			if err != nil {
+				status = "failed"
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: %d values, %s\n", name, count, status)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(files))
+	}
+	return nil
+}
+
+// eachInputFile calls fn for every value read from the named file, or stdin
+// for "-"
+func eachInputFile(cmd *cobra.Command, name string, read lineReader, fn func(string) error) error {
+	if name == stdinFile {
+		return read(cmd.InOrStdin(), fn)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		// The caller adds the file name
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			return pathErr.Err
+		}
+		return err
+	}
+	defer f.Close()
+	return read(f, fn)
+}
+
+// eachInputLine calls fn with every non-blank, trimmed line of r, prefixing
+// any error with the line number
+func eachInputLine(r io.Reader, fn func(string) error) error {
+	return eachRawInputLine(r, func(line string) error {
+		return fn(strings.TrimSpace(line))
+	})
+}
+
+// eachRawInputLine is eachInputLine for commands that print lines exactly as
+// read: blank lines are still skipped, but the others keep their whitespace
+func eachRawInputLine(r io.Reader, fn func(string) error) error {
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		value := scanner.Text()
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		if err := fn(value); err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// eachExactInputLine is eachInputLine for commands that copy their input
+// byte for byte: every line is passed on as read, blank or not, with its
+// line ending, and a final line without one is passed on too
+func eachExactInputLine(r io.Reader, fn func(string) error) error {
+	in := bufio.NewReader(r)
+	lineNum := 0
+	for {
+		line, readErr := in.ReadString('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return readErr
+		}
+		if line != "" {
+			lineNum++
+			if err := fn(line); err != nil {
+				return fmt.Errorf("line %d: %w", lineNum, err)
+			}
+		}
+		if readErr != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeInputFiles writes each named file into a temporary directory and
+// returns their paths in the same order
+func writeInputFiles(t *testing.T, contents ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, len(contents))
+	for i, content := range contents {
+		paths[i] = filepath.Join(dir, string(rune('a'+i))+".txt")
+		if err := os.WriteFile(paths[i], []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+const (
+	inputUUID1 = "00000000-0000-4000-8000-000000000001"
+	inputUUID2 = "00000000-0000-4000-8000-000000000002"
+	inputUUID3 = "00000000-0000-4000-8000-000000000003"
+)
+
+func TestInputFilesInOrder(t *testing.T) {
+	files := writeInputFiles(t, inputUUID1+"\n\n", inputUUID3+"\n")
+
+	output, err := executeCommand(t, inputUUID2+"\n", "validate", "-f", files[0], "-f", "-", "--file", files[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := inputUUID1 + "\tok\n" + inputUUID2 + "\tok\n" + inputUUID3 + "\tok\n"
+	if output != expected {
+		t.Errorf("Expected files then stdin in the order given, got:\n%s", output)
+	}
+}
+
+func TestInputFileErrorNamesFile(t *testing.T) {
+	files := writeInputFiles(t, inputUUID1+"\n", inputUUID2+"\nnot-a-uuid\n"+inputUUID3+"\n")
+
+	output, err := executeCommand(t, "", "say", "-f", files[0], "-f", files[1])
+	if err == nil || !strings.Contains(err.Error(), files[1]+": line 2: invalid UUID 'not-a-uuid'") {
+		t.Fatalf("Expected the error to name the file and line, got %v", err)
+	}
+	if strings.Contains(output, "three") {
+		t.Errorf("Expected processing to stop at the error, got:\n%s", output)
+	}
+
+	_, err = executeCommand(t, "x\n", "inspect", "-f", "-")
+	if err == nil || !strings.Contains(err.Error(), "stdin: line 1: invalid UUID 'x'") {
+		t.Errorf("Expected stdin to be named in the error, got %v", err)
+	}
+}
+
+func TestInputKeepGoing(t *testing.T) {
+	files := writeInputFiles(t, inputUUID1+"\n", "bad\n"+inputUUID2+"\n", inputUUID3+"\n")
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	args := []string{"bucket", "--n", "4", "-f", files[0], "-f", missing, "-f", files[1], "-f", files[2]}
+
+	// Without --keep-going the missing file aborts the run
+	stdout, _, err := executeCommandSplit(t, "", args...)
+	if err == nil || !strings.Contains(err.Error(), missing+": no such file or directory") {
+		t.Fatalf("Expected a missing file error, got %v", err)
+	}
+	if strings.Contains(stdout, inputUUID3) {
+		t.Errorf("Expected no output after the missing file, got:\n%s", stdout)
+	}
+
+	stdout, stderr, err := executeCommandSplit(t, "", append(args, "--keep-going", "--verbose")...)
+	if err == nil || err.Error() != "2 of 4 files failed" {
+		t.Fatalf("Expected a combined failure, got %v", err)
+	}
+	if !strings.Contains(stdout, inputUUID1) || !strings.Contains(stdout, inputUUID3) || strings.Contains(stdout, inputUUID2) {
+		t.Errorf("Expected the good files to be processed and the rest of a failed file skipped, got:\n%s", stdout)
+	}
+	for _, want := range []string{
+		"WARNING: " + missing + ": no such file or directory; continuing with the next file.",
+		"WARNING: " + files[1] + ": line 1: invalid UUID 'bad'",
+		files[0] + ": 1 values, ok",
+		missing + ": 0 values, failed",
+		files[2] + ": 1 values, ok",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected stderr to contain %q, got:\n%s", want, stderr)
+		}
+	}
+}
+
+func TestInputValidateCombinesFailures(t *testing.T) {
+	files := writeInputFiles(t, "bad\n")
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+
+	_, err := executeCommand(t, "", "validate", "-f", files[0], "-f", missing, "--keep-going")
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 files failed") || !strings.Contains(err.Error(), "1 of 1 values are invalid") {
+		t.Errorf("Expected both the failed file and the invalid value to be reported, got %v", err)
+	}
+}
+
+func TestInputFileWithArguments(t *testing.T) {
+	_, err := executeCommand(t, "", "decode", "--from", "hex32", "-f", "ids.txt", "00000000000040008000000000000001")
+	if err == nil || !strings.Contains(err.Error(), "--file cannot be combined with values given as arguments") {
+		t.Errorf("Expected --file to be rejected alongside arguments, got %v", err)
+	}
+}
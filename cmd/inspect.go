@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// inspectCmd decodes the fields embedded in existing UUIDs
+var inspectCmd = &cobra.Command{
+	Use:   "inspect [uuid...]",
+	Short: "Decode the version, variant, and timestamp of UUIDs",
+	Long: `Decode the fields embedded in one or more UUIDs.
+
+UUIDs are read from the arguments, or one per line from each --file in
+turn ('-' is stdin), or from stdin when no arguments are given. Canonical, upper case, braced, urn:uuid:, and
+hyphenless forms are accepted.
+
+With --json the report uses a stable schema:
+
+  uuid          canonical form
+  version       version number (int)
+  variant       one of "ncs", "rfc", "microsoft", "future"
+  timestamp     embedded time in RFC3339 (null when absent)
+  timestamp_ms  embedded time in Unix milliseconds (null when absent)
+  node          node field as colon-separated hex (v1/v6, otherwise null)
+  clock_seq     clock sequence (v1/v6, otherwise null)
+  counter       sequence counter when decodable (otherwise null)
+  encodings     object with "simple", "urn", and "base64" renderings
+
+A single UUID produces one JSON object and several produce a JSON array.
+Use --jsonl to print one compact object per line instead.
+
+--group N echoes each UUID as its hex digits in space-separated groups of N,
+as 'uuid --group' prints them; such grouped input is accepted too.
+
+--porcelain prints one tab-separated line per UUID with the fields of the
+JSON schema, except encodings, in the order above; absent fields are "-".
+This format is frozen.
+
+Use --order to report UUIDs sorted rather than in input order: "bytes"
+sorts by the 16 bytes as written, and "sqlserver" ranks them the way SQL
+Server orders uniqueidentifier columns (last group first, then the fourth,
+third, second, and first groups).
+
+With --snowflake, each UUIDv7 is also decoded as a Snowflake ID embedded by
+'uuid convert --from snowflake', reporting the recovered ID, worker, and
+sequence relative to --epoch.
+
+With --v8-layout, each UUIDv8 is also decoded field by field with the layout
+it was generated with by 'uuid -8 --v8-layout'. Node fields report the value
+found in the UUID, and ms fields of 48 bits or more are shown as a time.
+
+--v7-layout says how each UUIDv7 uses the bits after its timestamp, which
+the UUID itself does not record:
+
+  counter    the 16-bit counter of the monotonic UUIDv7 generators in
+             this project's generator package (rand_a and 4 bits of rand_b)
+  counter12  a 12-bit counter filling rand_a (RFC 9562 method 1)
+  none       no counter; rand_a is reported as raw bits
+
+The counter is reported in the counter field. Any UUIDv7 decodes with any
+layout, so a counter is only meaningful when the layout matches the
+generator that produced the UUID.
+
+Examples:
+  uuid inspect 0188b733-b800-7079-9ce7-7022b2ba0185
+  uuid inspect --json 0188b733-b800-7079-9ce7-7022b2ba0185
+  cat ids.txt | uuid inspect --jsonl
+  cat ids.txt | uuid inspect --jsonl --order sqlserver
+  uuid inspect --v8-layout ms:48,node:10=0,seq:12,rand:52 0188b733-b800-80a8-8006-deb1c2bce195
+  cat ids.txt | uuid inspect --jsonl --v7-layout counter`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		asJSONL, _ := cmd.Flags().GetBool("jsonl")
+		order, _ := cmd.Flags().GetString("order")
+		snowflake, _ := cmd.Flags().GetBool("snowflake")
+		porcelain, err := porcelainFlag(cmd, []string{"json", "jsonl", "snowflake", "group", "v8-layout"})
+		if err != nil {
+			return err
+		}
+		group, err := groupFlag(cmd)
+		if err != nil {
+			return err
+		}
+		if group > 0 && (asJSON || asJSONL) {
+			return fmt.Errorf("--group cannot be combined with --json or --jsonl")
+		}
+		epoch, err := snowflakeEpoch(cmd)
+		if err != nil {
+			return err
+		}
+		var layout *generator.V8Layout
+		if spec, _ := cmd.Flags().GetString("v8-layout"); spec != "" {
+			if layout, err = generator.ParseV8Layout(spec); err != nil {
+				return fmt.Errorf("invalid --v8-layout: %w", err)
+			}
+		}
+
+		v7Layout := generator.V7LayoutNone
+		decodeV7 := cmd.Flags().Changed("v7-layout")
+		if decodeV7 {
+			name, _ := cmd.Flags().GetString("v7-layout")
+			if v7Layout, err = generator.ParseV7Layout(name); err != nil {
+				return err
+			}
+		}
+
+		var compare func(a, b [16]byte) int
+		switch order {
+		case "input":
+		case "bytes":
+			compare = func(a, b [16]byte) int { return bytes.Compare(a[:], b[:]) }
+		case "sqlserver":
+			compare = generator.CompareSQLServer
+		default:
+			return fmt.Errorf("unknown order '%s'. Available orders: input, bytes, sqlserver", order)
+		}
+
+		var details []generator.Details
+		inputErr := eachInputValue(cmd, args, func(value string) error {
+			uuid, err := generator.ParseUUID(value)
+			if err != nil {
+				return fmt.Errorf("invalid UUID '%s': %w", value, err)
+			}
+			details = append(details, generator.Inspect(uuid))
+			return nil
+		})
+		// With --keep-going, report the values that were read before failing
+		if keepGoing, _ := cmd.Flags().GetBool("keep-going"); inputErr != nil && !keepGoing {
+			return inputErr
+		}
+		if compare != nil {
+			slices.SortStableFunc(details, func(a, b generator.Details) int { return compare(a.UUID, b.UUID) })
+		}
+
+		snowflakes := make([]*generator.Snowflake, len(details))
+		for i, d := range details {
+			if !snowflake {
+				break
+			}
+			s, err := generator.SnowflakeFromUUID(d.UUID, epoch)
+			if err != nil {
+				return fmt.Errorf("cannot recover snowflake from '%s': %w", generator.FormatUUID(d.UUID), err)
+			}
+			snowflakes[i] = &s
+		}
+
+		fields := make([][]generator.V8FieldValue, len(details))
+		for i, d := range details {
+			if layout == nil {
+				break
+			}
+			if fields[i], err = layout.Decode(d.UUID); err != nil {
+				return fmt.Errorf("cannot decode '%s' with --v8-layout: %w", generator.FormatUUID(d.UUID), err)
+			}
+		}
+
+		// Only UUIDv7s are decoded; RandA fails for everything else
+		randAs := make([]*int, len(details))
+		for i := range details {
+			if !decodeV7 {
+				break
+			}
+			uuid := generator.UUID(details[i].UUID)
+			randA, err := uuid.RandA()
+			if err != nil {
+				continue
+			}
+			if v7Layout == generator.V7LayoutNone {
+				randAs[i] = &randA
+				continue
+			}
+			counter, _ := uuid.V7Counter(v7Layout)
+			details[i].Counter = &counter
+		}
+
+		out := cmd.OutOrStdout()
+		switch {
+		case porcelain != "":
+			for _, d := range details {
+				fmt.Fprintln(out, inspectPorcelain(d))
+			}
+		case asJSONL:
+			for i, d := range details {
+				line, err := json.Marshal(newInspectRecord(d, snowflakes[i], fields[i], randAs[i]))
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(out, string(line))
+			}
+		case asJSON:
+			var v any
+			if len(details) == 1 {
+				v = newInspectRecord(details[0], snowflakes[0], fields[0], randAs[0])
+			} else {
+				records := make([]inspectRecord, len(details))
+				for i, d := range details {
+					records[i] = newInspectRecord(d, snowflakes[i], fields[i], randAs[i])
+				}
+				v = records
+			}
+			doc, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, string(doc))
+		default:
+			for i, d := range details {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				writeInspectReport(out, d, snowflakes[i], fields[i], randAs[i], group)
+			}
+		}
+		return inputErr
+	},
+}
+
+// inspectRecord is the stable JSON schema for inspect --json. Renaming or
+// removing fields breaks consumers, so only add new ones.
+type inspectRecord struct {
+	UUID        string            `json:"uuid"`
+	Version     int               `json:"version"`
+	Variant     string            `json:"variant"`
+	Timestamp   *string           `json:"timestamp"`
+	TimestampMs *int64            `json:"timestamp_ms"`
+	Node        *string           `json:"node"`
+	ClockSeq    *int              `json:"clock_seq"`
+	Counter     *int              `json:"counter"`
+	Encodings   inspectEncodings  `json:"encodings"`
+	Snowflake   *inspectSnowflake `json:"snowflake,omitempty"`
+	V8Fields    []inspectV8Field  `json:"v8_fields,omitempty"`
+	RandA       *string           `json:"rand_a,omitempty"`
+}
+
+// inspectSnowflake is only present with --snowflake
+type inspectSnowflake struct {
+	ID       string `json:"id"`
+	Worker   uint16 `json:"worker"`
+	Sequence uint16 `json:"sequence"`
+}
+
+// inspectV8Field is a field decoded with --v8-layout; value is a string so
+// 64-bit fields survive JSON parsers that read numbers as doubles
+type inspectV8Field struct {
+	Fill      string  `json:"fill"`
+	Width     int     `json:"width"`
+	Value     string  `json:"value"`
+	Timestamp *string `json:"timestamp,omitempty"`
+}
+
+type inspectEncodings struct {
+	Simple string `json:"simple"`
+	URN    string `json:"urn"`
+	Base64 string `json:"base64"`
+}
+
+func newInspectRecord(d generator.Details, snowflake *generator.Snowflake, fields []generator.V8FieldValue, randA *int) inspectRecord {
+	canonical := generator.FormatUUID(d.UUID)
+	record := inspectRecord{
+		UUID:     canonical,
+		Version:  d.Version,
+		Variant:  d.Variant,
+		ClockSeq: d.ClockSeq,
+		Counter:  d.Counter,
+		Encodings: inspectEncodings{
+			Simple: strings.ReplaceAll(canonical, "-", ""),
+			URN:    "urn:uuid:" + canonical,
+			Base64: encodeWith("base64", d.UUID),
+		},
+	}
+	if d.Timestamp != nil {
+		ts := d.Timestamp.Format(time.RFC3339Nano)
+		ms := d.Timestamp.UnixMilli()
+		record.Timestamp = &ts
+		record.TimestampMs = &ms
+	}
+	if d.Node != nil {
+		node := formatNode(*d.Node)
+		record.Node = &node
+	}
+	if snowflake != nil {
+		record.Snowflake = &inspectSnowflake{
+			ID:       strconv.FormatUint(snowflake.ID, 10),
+			Worker:   snowflake.Worker,
+			Sequence: snowflake.Sequence,
+		}
+	}
+	if randA != nil {
+		bits := fmt.Sprintf("0x%03x", *randA)
+		record.RandA = &bits
+	}
+	for _, f := range fields {
+		field := inspectV8Field{Fill: f.Fill, Width: f.Width, Value: strconv.FormatUint(f.Value, 10)}
+		if f.Timestamp != nil {
+			ts := f.Timestamp.Format(time.RFC3339Nano)
+			field.Timestamp = &ts
+		}
+		record.V8Fields = append(record.V8Fields, field)
+	}
+	return record
+}
+
+// inspectPorcelain renders one UUID in the porcelain v1 format: the fields
+// of the --json schema except encodings, in schema order
+func inspectPorcelain(d generator.Details) string {
+	r := newInspectRecord(d, nil, nil, nil)
+	version := strconv.Itoa(r.Version)
+	return porcelainFields(&r.UUID, &version, &r.Variant, r.Timestamp, porcelainInt(r.TimestampMs), r.Node, porcelainInt(r.ClockSeq), porcelainInt(r.Counter))
+}
+
+// writeInspectReport prints the human-readable report for one UUID, showing
+// it grouped when group is set
+func writeInspectReport(out io.Writer, d generator.Details, snowflake *generator.Snowflake, fields []generator.V8FieldValue, randA *int, group int) {
+	if group > 0 {
+		fmt.Fprintf(out, "UUID:       %s\n", generator.FormatGrouped(d.UUID, group))
+	} else {
+		fmt.Fprintf(out, "UUID:       %s\n", generator.FormatUUID(d.UUID))
+	}
+	fmt.Fprintf(out, "Version:    %d (%s)\n", d.Version, generator.VersionName(d.Version))
+	fmt.Fprintf(out, "Variant:    %s\n", d.Variant)
+	if d.Timestamp != nil {
+		fmt.Fprintf(out, "Timestamp:  %s (%d ms)\n", d.Timestamp.Format(time.RFC3339Nano), d.Timestamp.UnixMilli())
+	}
+	if d.ClockSeq != nil {
+		fmt.Fprintf(out, "Clock seq:  %d\n", *d.ClockSeq)
+	}
+	if d.Node != nil {
+		fmt.Fprintf(out, "Node:       %s\n", formatNode(*d.Node))
+	}
+	if d.Counter != nil {
+		fmt.Fprintf(out, "Counter:    %d\n", *d.Counter)
+	}
+	if randA != nil {
+		fmt.Fprintf(out, "Rand A:     0x%03x (raw bits, no counter layout)\n", *randA)
+	}
+	if snowflake != nil {
+		fmt.Fprintf(out, "Snowflake:  %d (worker %d, sequence %d)\n", snowflake.ID, snowflake.Worker, snowflake.Sequence)
+	}
+	for i, f := range fields {
+		label := ""
+		if i == 0 {
+			label = "V8 fields:"
+		}
+		value := strconv.FormatUint(f.Value, 10)
+		if f.Timestamp != nil {
+			value += " (" + f.Timestamp.Format(time.RFC3339Nano) + ")"
+		}
+		fmt.Fprintf(out, "%-11s %-8s %s\n", label, fmt.Sprintf("%s:%d", f.Fill, f.Width), value)
+	}
+}
+
+// formatNode renders a 48-bit node as colon-separated hex octets
+func formatNode(node [6]byte) string {
+	return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", node[0], node[1], node[2], node[3], node[4], node[5])
+}
+
+// encodeWith renders uuid with a registered encoding
+func encodeWith(name string, uuid [16]byte) string {
+	encoding, err := generator.LookupEncoding(name)
+	if err != nil {
+		panic(err)
+	}
+	return encoding.Encode(uuid)
+}
+
+func init() {
+	inspectCmd.Flags().Bool("json", false, "Print a JSON object (or array for several UUIDs)")
+	inspectCmd.Flags().Bool("jsonl", false, "Print one compact JSON object per line")
+	inspectCmd.MarkFlagsMutuallyExclusive("json", "jsonl")
+	inspectCmd.Flags().Bool("snowflake", false, "Recover the Snowflake ID embedded in each UUIDv7")
+	inspectCmd.Flags().String("epoch", twitterEpoch, "Custom epoch of Snowflake IDs, with --snowflake")
+	inspectCmd.Flags().String("v8-layout", "", "Decode each UUIDv8 with this 'uuid -8 --v8-layout' layout")
+	inspectCmd.Flags().String("v7-layout", "none", "Decode the counter of each UUIDv7 with this layout: counter, counter12, or none")
+	inspectCmd.Flags().String("order", "input", "Report order: input, bytes, or sqlserver")
+	inspectCmd.Flags().Int("group", 0, "Show each UUID as hex digits in space-separated groups of this size")
+	addInputFlags(inspectCmd)
+	addPorcelainFlag(inspectCmd)
+
+	rootCmd.AddCommand(inspectCmd)
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// inspectGoldenUUIDs are the RFC 9562 appendix examples, one per version.
+// The golden files pin the inspect --json schema; update them only when the
+// schema change is intentional.
+var inspectGoldenUUIDs = map[string]string{
+	"v1": "c232ab00-9414-11ec-b3c8-9f6bdeced846",
+	"v3": "5df41881-3aed-3515-88a7-2f4a814cf09e",
+	"v4": "919108f7-52d1-4320-9bac-f847db4148a8",
+	"v5": "2ed6657d-e927-568b-95e1-2665a8aea6a2",
+	"v6": "1ec9414c-232a-6b00-b3c8-9f6bdeced846",
+	"v7": "017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+	"v8": "2489e9ad-2ee2-8e00-8ec9-32d5f69181c0",
+}
+
+func TestInspectJSONGolden(t *testing.T) {
+	for version, uuid := range inspectGoldenUUIDs {
+		t.Run(version, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join("testdata", "inspect_"+version+".json"))
+			if err != nil {
+				t.Fatalf("Failed to read golden file: %v", err)
+			}
+
+			output, err := executeCommand(t, "", "inspect", "--json", uuid)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != string(golden) {
+				t.Errorf("inspect --json output differs from golden file\ngot:\n%s\nwant:\n%s", output, golden)
+			}
+		})
+	}
+}
+
+func TestInspectHumanReport(t *testing.T) {
+	output, err := executeCommand(t, "", "inspect", "C232AB00-9414-11EC-B3C8-9F6BDECED846")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `UUID:       c232ab00-9414-11ec-b3c8-9f6bdeced846
+Version:    1 (Gregorian time-based)
+Variant:    rfc
+Timestamp:  2022-02-22T19:22:22Z (1645557742000 ms)
+Clock seq:  13256
+Node:       9f:6b:de:ce:d8:46
+`
+	if output != expected {
+		t.Errorf("Unexpected report:\n%s", output)
+	}
+}
+
+func TestInspectGroup(t *testing.T) {
+	output, err := executeCommand(t, "C232 AB00 9414 11EC B3C8 9F6B DECE D846\n", "inspect", "--group", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(output, "UUID:       c23 2ab 009 414 11e cb3 c89 f6b dec ed8 46\nVersion:    1 ") {
+		t.Errorf("Expected the grouped UUID to be echoed, got:\n%s", output)
+	}
+
+	_, err = executeCommand(t, "", "inspect", "--group", "4", "--jsonl", "c232ab00-9414-11ec-b3c8-9f6bdeced846")
+	if err == nil || !strings.Contains(err.Error(), "--group cannot be combined with --json or --jsonl") {
+		t.Errorf("Expected --group to be rejected with --jsonl, got %v", err)
+	}
+}
+
+func TestInspectJSONMultiple(t *testing.T) {
+	output, err := executeCommand(t, "", "inspect", "--json", inspectGoldenUUIDs["v4"], inspectGoldenUUIDs["v7"])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var records []map[string]any
+	if err := json.Unmarshal([]byte(output), &records); err != nil {
+		t.Fatalf("Multiple UUIDs should produce a JSON array: %v", err)
+	}
+	if len(records) != 2 || records[0]["version"] != float64(4) || records[1]["version"] != float64(7) {
+		t.Errorf("Unexpected records: %v", records)
+	}
+}
+
+func TestInspectJSONLStdin(t *testing.T) {
+	input := inspectGoldenUUIDs["v6"] + "\n\n" + inspectGoldenUUIDs["v7"] + "\n"
+	output, err := executeCommand(t, input, "inspect", "--jsonl")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 JSON lines, got %d: %q", len(lines), output)
+	}
+	for _, line := range lines {
+		var record inspectRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid JSON line %s: %v", line, err)
+		}
+		if record.Timestamp == nil || *record.Timestamp != "2022-02-22T19:22:22Z" {
+			t.Errorf("Unexpected timestamp in %s", line)
+		}
+	}
+}
+
+func TestInspectInvalidUUID(t *testing.T) {
+	_, err := executeCommand(t, "", "inspect", "not-a-uuid")
+	if err == nil || !strings.Contains(err.Error(), "invalid UUID 'not-a-uuid'") {
+		t.Errorf("Expected invalid UUID error, got: %v", err)
+	}
+}
+
+func TestInspectOrder(t *testing.T) {
+	input := strings.Join([]string{
+		"ffffffff-0000-0000-0000-000000000002",
+		"00000000-0000-0000-0000-010000000000",
+		"11111111-0000-0000-0000-000000000002",
+		"00000000-ffff-0000-0000-000000000001",
+	}, "\n")
+
+	tests := []struct {
+		order    string
+		expected []string
+	}{
+		{"input", []string{"ffffffff", "00000000", "11111111", "00000000-ffff"}},
+		{"bytes", []string{"00000000-0000", "00000000-ffff", "11111111", "ffffffff"}},
+		{"sqlserver", []string{"00000000-ffff", "11111111", "ffffffff", "00000000-0000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			output, err := executeCommand(t, input, "inspect", "--jsonl", "--order", tt.order)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(output), "\n")
+			if len(lines) != len(tt.expected) {
+				t.Fatalf("Expected %d lines, got %q", len(tt.expected), output)
+			}
+			for i, prefix := range tt.expected {
+				if !strings.HasPrefix(lines[i], `{"uuid":"`+prefix) {
+					t.Errorf("Line %d: expected UUID starting %s, got %s", i, prefix, lines[i])
+				}
+			}
+		})
+	}
+
+	if _, err := executeCommand(t, input, "inspect", "--order", "oracle"); err == nil || !strings.Contains(err.Error(), "unknown order 'oracle'") {
+		t.Errorf("Expected unknown order error, got: %v", err)
+	}
+}
+
+func TestInspectV8Layout(t *testing.T) {
+	const layout = "node:8=7,ms:48,seq:12,rand:54"
+	generated, err := executeCommand(t, "", "-8", "--v8-layout", layout, "-t", "2023-06-14", "-n", "2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	output, err := executeCommand(t, generated, "inspect", "--v8-layout", layout)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"V8 fields:  node:8   7\n",
+		"            ms:48    1686700800000 (2023-06-14T00:00:00Z)\n",
+		"            seq:12   0\n",
+		"            seq:12   1\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in:\n%s", want, output)
+		}
+	}
+
+	output, err = executeCommand(t, strings.SplitN(generated, "\n", 2)[0], "inspect", "--v8-layout", layout, "--jsonl")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `"v8_fields":[{"fill":"node","width":8,"value":"7"},{"fill":"ms","width":48,"value":"1686700800000","timestamp":"2023-06-14T00:00:00Z"},{"fill":"seq","width":12,"value":"0"},`) {
+		t.Errorf("Expected decoded fields, got: %s", output)
+	}
+
+	_, err = executeCommand(t, "", "inspect", "--v8-layout", layout, inspectGoldenUUIDs["v4"])
+	if err == nil || !strings.Contains(err.Error(), "only UUIDv8 has a custom layout, got version 4") {
+		t.Errorf("Expected a version error, got: %v", err)
+	}
+	_, err = executeCommand(t, "", "inspect", "--v8-layout", "ms:48", inspectGoldenUUIDs["v4"])
+	if err == nil || !strings.Contains(err.Error(), "invalid --v8-layout: layout widths add up to 48 bits") {
+		t.Errorf("Expected a layout error, got: %v", err)
+	}
+}
+
+func TestInspectSnowflake(t *testing.T) {
+	converted, err := executeCommand(t, "", "convert", "--from", "snowflake", "1050118621198921728")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	output, err := executeCommand(t, converted, "inspect", "--snowflake")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "Snowflake:  1050118621198921728 (worker 347, sequence 0)") {
+		t.Errorf("Expected recovered snowflake fields, got:\n%s", output)
+	}
+
+	output, err = executeCommand(t, converted, "inspect", "--snowflake", "--jsonl")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `"snowflake":{"id":"1050118621198921728","worker":347,"sequence":0}`) {
+		t.Errorf("Expected a snowflake object, got: %s", output)
+	}
+
+	_, err = executeCommand(t, "", "inspect", "--snowflake", inspectGoldenUUIDs["v4"])
+	if err == nil || !strings.Contains(err.Error(), "only UUIDv7 can carry a snowflake") {
+		t.Errorf("Expected a version error, got: %v", err)
+	}
+}
+
+func TestInspectV7Layout(t *testing.T) {
+	// A monotonic burst within one millisecond, so the counters run on
+	var g generator.MonotonicV7
+	generator.SetClock(func() time.Time { return time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC) })
+	var burst []string
+	for range 20 {
+		burst = append(burst, g.Generate())
+	}
+	generator.SetClock(nil)
+
+	output, err := executeCommand(t, strings.Join(burst, "\n"), "inspect", "--jsonl", "--v7-layout", "counter")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var last int
+	for i, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var record struct {
+			Counter *int `json:"counter"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil || record.Counter == nil {
+			t.Fatalf("Expected a counter in %s: %v", line, err)
+		}
+		if i > 0 && *record.Counter != last+1 {
+			t.Errorf("UUID %d: expected counter %d, got %d", i, last+1, *record.Counter)
+		}
+		last = *record.Counter
+	}
+
+	output, err = executeCommand(t, "", "inspect", "--v7-layout", "counter12", inspectGoldenUUIDs["v7"])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "Counter:    3267\n") {
+		t.Errorf("Expected a 12-bit counter, got:\n%s", output)
+	}
+
+	// A v7 from another implementation is shown as raw bits; other versions
+	// are left alone
+	output, err = executeCommand(t, "", "inspect", "--v7-layout", "none", "0188b733-b800-7079-9ce7-7022b2ba0185", inspectGoldenUUIDs["v4"])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Count(output, "Rand A:     0x079 (raw bits, no counter layout)\n") != 1 || strings.Contains(output, "Counter:") {
+		t.Errorf("Expected raw rand_a for the v7 only, got:\n%s", output)
+	}
+	output, _ = executeCommand(t, "", "inspect", "--jsonl", "--v7-layout", "none", "0188b733-b800-7079-9ce7-7022b2ba0185")
+	if !strings.Contains(output, `"counter":null`) || !strings.Contains(output, `"rand_a":"0x079"`) {
+		t.Errorf("Expected raw rand_a in JSON, got: %s", output)
+	}
+
+	_, err = executeCommand(t, "", "inspect", "--v7-layout", "counter8", inspectGoldenUUIDs["v7"])
+	if err == nil || !strings.Contains(err.Error(), "unknown v7 layout 'counter8'. Available layouts: counter12, counter, none") {
+		t.Errorf("Expected a layout error, got: %v", err)
+	}
+}
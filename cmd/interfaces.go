@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// interfacesCmd lists the candidates for the UUIDv6 node
+var interfacesCmd = &cobra.Command{
+	Use:   "interfaces",
+	Short: "List network interfaces and the node each --node mode would use",
+	Long: `List network interfaces as candidates for the UUIDv6 node.
+
+Each interface is shown with its hardware address and whether it is up or a
+loopback. The one marked "selected" is the interface --node mac uses; the
+choice comes from the same function the generator calls. The per-boot random
+node used by --node per-boot is shown when one has been persisted for the
+current boot.
+
+With --json the listing uses a stable schema:
+
+  interfaces     array of {name, hardware_addr, up, loopback, selected}
+  mac_node       the --node mac node as 12 hex digits, or null
+  per_boot_node  the persisted --node per-boot node, or null
+
+Examples:
+  uuid interfaces
+  uuid interfaces --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		ifaces, err := listInterfaces()
+		if err != nil {
+			return fmt.Errorf("cannot list network interfaces: %w", err)
+		}
+		selected, ok := selectMACInterface(ifaces)
+
+		report := interfacesReport{Interfaces: []interfaceRecord{}}
+		for _, iface := range ifaces {
+			report.Interfaces = append(report.Interfaces, interfaceRecord{
+				Name:         iface.Name,
+				HardwareAddr: iface.HardwareAddr.String(),
+				Up:           iface.Flags&net.FlagUp != 0,
+				Loopback:     iface.Flags&net.FlagLoopback != 0,
+				Selected:     ok && iface.Index == selected.Index && iface.Name == selected.Name,
+			})
+		}
+		if ok {
+			node := hex.EncodeToString(selected.HardwareAddr)
+			report.MACNode = &node
+		}
+		node, found, err := loadPerBootNode()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: cannot read the per-boot node (%v).\n", err)
+		}
+		if found {
+			perBoot := hex.EncodeToString(node[:])
+			report.PerBootNode = &perBoot
+		}
+
+		out := cmd.OutOrStdout()
+		if asJSON {
+			doc, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, string(doc))
+			return nil
+		}
+
+		if len(report.Interfaces) == 0 {
+			fmt.Fprintln(out, "No network interfaces found.")
+		} else {
+			w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tHARDWARE ADDRESS\tFLAGS\tNODE")
+			for _, r := range report.Interfaces {
+				mark := ""
+				if r.Selected {
+					mark = "selected"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, orDash(r.HardwareAddr), r.flags(), mark)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+		if report.MACNode == nil {
+			fmt.Fprintln(out, "--node mac: no usable interface")
+		} else {
+			fmt.Fprintf(out, "--node mac: %s\n", *report.MACNode)
+		}
+		if report.PerBootNode == nil {
+			fmt.Fprintln(out, "--node per-boot: none persisted for this boot")
+		} else {
+			fmt.Fprintf(out, "--node per-boot: %s\n", *report.PerBootNode)
+		}
+		return nil
+	},
+}
+
+// interfacesReport is the stable JSON schema for interfaces --json
+type interfacesReport struct {
+	Interfaces  []interfaceRecord `json:"interfaces"`
+	MACNode     *string           `json:"mac_node"`
+	PerBootNode *string           `json:"per_boot_node"`
+}
+
+// interfaceRecord describes one network interface
+type interfaceRecord struct {
+	Name         string `json:"name"`
+	HardwareAddr string `json:"hardware_addr"`
+	Up           bool   `json:"up"`
+	Loopback     bool   `json:"loopback"`
+	Selected     bool   `json:"selected"`
+}
+
+// flags summarises the interface state for the table
+func (r interfaceRecord) flags() string {
+	flags := "down"
+	if r.Up {
+		flags = "up"
+	}
+	if r.Loopback {
+		flags += ",loopback"
+	}
+	return flags
+}
+
+// orDash stands in for empty table cells
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	interfacesCmd.Flags().Bool("json", false, "Print the listing as JSON")
+	rootCmd.AddCommand(interfacesCmd)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeInterfaces replaces the interface lister for the duration of a test
+func fakeInterfaces(t *testing.T, ifaces []net.Interface) {
+	t.Helper()
+	original := listInterfaces
+	listInterfaces = func() ([]net.Interface, error) { return ifaces, nil }
+	t.Cleanup(func() { listInterfaces = original })
+}
+
+func mustMAC(t *testing.T, value string) net.HardwareAddr {
+	t.Helper()
+	mac, err := net.ParseMAC(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mac
+}
+
+func TestInterfacesSelection(t *testing.T) {
+	loopback := net.Interface{Index: 1, Name: "lo", Flags: net.FlagUp | net.FlagLoopback}
+	down := net.Interface{Index: 2, Name: "eth0", HardwareAddr: mustMAC(t, "00:11:22:33:44:55")}
+	zero := net.Interface{Index: 3, Name: "dummy0", Flags: net.FlagUp, HardwareAddr: mustMAC(t, "00:00:00:00:00:00")}
+	tunnel := net.Interface{Index: 4, Name: "tun0", Flags: net.FlagUp}
+	eth1 := net.Interface{Index: 5, Name: "eth1", Flags: net.FlagUp, HardwareAddr: mustMAC(t, "02:42:ac:11:00:02")}
+	eth2 := net.Interface{Index: 6, Name: "eth2", Flags: net.FlagUp, HardwareAddr: mustMAC(t, "02:42:ac:11:00:03")}
+
+	tests := []struct {
+		name     string
+		ifaces   []net.Interface
+		selected string
+		macNode  string
+	}{
+		{"multi-NIC", []net.Interface{loopback, down, zero, tunnel, eth1, eth2}, "eth1", "0242ac110002"},
+		{"loopback only", []net.Interface{loopback}, "", ""},
+		{"empty", nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boot := "boot-1"
+			fakeNodeState(t, &boot)
+			fakeInterfaces(t, tt.ifaces)
+
+			output, err := executeCommand(t, "", "interfaces", "--json")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var report interfacesReport
+			if err := json.Unmarshal([]byte(output), &report); err != nil {
+				t.Fatalf("Invalid JSON %q: %v", output, err)
+			}
+			if len(report.Interfaces) != len(tt.ifaces) {
+				t.Errorf("Expected %d interfaces, got %d", len(tt.ifaces), len(report.Interfaces))
+			}
+			selected := ""
+			for _, r := range report.Interfaces {
+				if r.Selected {
+					selected = r.Name
+				}
+			}
+			if selected != tt.selected {
+				t.Errorf("Expected %q to be selected, got %q", tt.selected, selected)
+			}
+			if got := derefOr(report.MACNode); got != tt.macNode {
+				t.Errorf("Expected mac_node %q, got %q", tt.macNode, got)
+			}
+
+			// The generator must use the interface the listing marks
+			uuid, err := executeCommand(t, "", "-6", "--node", "mac")
+			if tt.macNode == "" {
+				if err == nil || !strings.Contains(err.Error(), "no network interface with a MAC address is up") {
+					t.Errorf("Expected a missing interface error, got: %v", err)
+				}
+				return
+			}
+			if err != nil || nodeOf(strings.TrimSpace(uuid)) != tt.macNode {
+				t.Errorf("Expected node %s, got %q, %v", tt.macNode, uuid, err)
+			}
+		})
+	}
+}
+
+func TestInterfacesTable(t *testing.T) {
+	boot := "boot-1"
+	fakeNodeState(t, &boot)
+	fakeInterfaces(t, []net.Interface{
+		{Index: 1, Name: "lo", Flags: net.FlagUp | net.FlagLoopback},
+		{Index: 2, Name: "eth0", Flags: net.FlagUp, HardwareAddr: mustMAC(t, "02:42:ac:11:00:02")},
+	})
+
+	output, err := executeCommand(t, "", "interfaces")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"up,loopback", "02:42:ac:11:00:02", "selected", "--node mac: 0242ac110002", "--node per-boot: none persisted for this boot"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+
+	// Once a per-boot node exists it is listed
+	generated, err := executeCommand(t, "", "-6", "--node", "per-boot")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output, _ = executeCommand(t, "", "interfaces")
+	if !strings.Contains(output, "--node per-boot: "+nodeOf(strings.TrimSpace(generated))) {
+		t.Errorf("Expected the per-boot node in output:\n%s", output)
+	}
+}
+
+func TestInterfacesEmpty(t *testing.T) {
+	boot := "boot-1"
+	fakeNodeState(t, &boot)
+	fakeInterfaces(t, nil)
+
+	output, err := executeCommand(t, "", "interfaces")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "No network interfaces found.") || !strings.Contains(output, "--node mac: no usable interface") {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+
+	output, _ = executeCommand(t, "", "interfaces", "--json")
+	if !strings.Contains(output, `"interfaces": []`) || !strings.Contains(output, `"mac_node": null`) {
+		t.Errorf("Expected empty JSON fields, got:\n%s", output)
+	}
+}
+
+func derefOr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// timestampLayoutsFile names the file of extra timestamp layouts, a JSON
+// object mapping format names to Go reference layouts
+const timestampLayoutsFile = "timestamp-layouts.json"
+
+// timestampLayoutsLoaded records that loadTimestampLayouts ran during this
+// execution. The file is only read once a timestamp is parsed, so commands
+// that never parse one skip the configuration lookup.
+var timestampLayoutsLoaded bool
+
+// parseTimestamp is generator.ParseTimestamp with the configured layouts
+// registered
+func parseTimestamp(value string) (time.Time, error) {
+	if err := ensureTimestampLayouts(); err != nil {
+		return time.Time{}, err
+	}
+	return generator.ParseTimestamp(value)
+}
+
+// explainTimestamp is generator.ExplainTimestamp with the configured layouts
+// registered
+func explainTimestamp(value string) (generator.TimestampExplanation, error) {
+	if err := ensureTimestampLayouts(); err != nil {
+		return generator.TimestampExplanation{Input: value}, err
+	}
+	return generator.ExplainTimestamp(value)
+}
+
+// ensureTimestampLayouts loads the configured layouts on first use
+func ensureTimestampLayouts() error {
+	if timestampLayoutsLoaded {
+		return nil
+	}
+	timestampLayoutsLoaded = true
+	return loadTimestampLayouts()
+}
+
+// loadTimestampLayouts registers the layouts in timestampLayoutsFile under the
+// user configuration directory, in name order. A missing file, or no
+// configuration directory at all, registers nothing.
+func loadTimestampLayouts() error {
+	dir, err := configDir()
+	if err != nil {
+		return nil
+	}
+	path := filepath.Join(dir, timestampLayoutsFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var layouts map[string]string
+	if err := json.Unmarshal(data, &layouts); err != nil {
+		return fmt.Errorf("invalid timestamp layouts in %s: %w", path, err)
+	}
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := generator.RegisterTimestampLayout(name, layouts[name]); err != nil {
+			return fmt.Errorf("invalid timestamp layouts in %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	cobra.OnFinalize(func() { timestampLayoutsLoaded = false })
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTimestampLayoutsFromConfig(t *testing.T) {
+	dir := fakeConfigDir(t)
+	os.MkdirAll(dir, 0o700)
+	layouts := `{"test-dmy": "02/01/2006 15.04.05", "test-compact": "20060102T1504"}`
+	if err := os.WriteFile(filepath.Join(dir, timestampLayoutsFile), []byte(layouts), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := executeCommand(t, "", "parse-timestamp", "14/06/2023 10.30.45")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "Format:   test-dmy\nUTC:      2023-06-14T10:30:45Z\n") {
+		t.Errorf("Expected the configured layout to match, got:\n%s", output)
+	}
+
+	// -t uses the same parser
+	generated, err := executeCommand(t, "", "-t", "20220222T1922")
+	if err != nil || !strings.HasPrefix(generated, "017f22e2-23c0-7") {
+		t.Errorf("Expected -t to accept the configured layout, got %q (%v)", generated, err)
+	}
+}
+
+func TestTimestampLayoutsConfigErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		contains string
+	}{
+		{"not json", "{", "invalid timestamp layouts in"},
+		{"built-in name", `{"iso-date": "2006/01/02"}`, "'iso-date' is a built-in timestamp format"},
+		{"empty layout", `{"nothing": ""}`, "timestamp layout 'nothing': '' has no elements"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := fakeConfigDir(t)
+			os.MkdirAll(dir, 0o700)
+			os.WriteFile(filepath.Join(dir, timestampLayoutsFile), []byte(tt.content), 0o600)
+
+			_, err := executeCommand(t, "", "-t", "2023-06-14")
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+
+			// The file is only read when a timestamp is parsed
+			if _, err := executeCommand(t, "", "-4"); err != nil {
+				t.Errorf("Expected generation without -t to ignore the file, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// lintCmd scans source trees for malformed or duplicated hardcoded UUIDs
+var lintCmd = &cobra.Command{
+	Use:   "lint [path...]",
+	Short: "Find malformed or duplicate hardcoded UUIDs in source files",
+	Long: `Scan files for hardcoded UUIDs and report problems with each occurrence.
+
+Every text file under the given paths (default ".") is scanned line by line
+for anything shaped like a UUID, which covers string literals and comments
+in any language. A trailing "/..." on a path is accepted and ignored, since
+directories are always scanned recursively. Binary files and .git
+directories are skipped; use --exclude to skip more.
+
+Findings:
+
+  malformed  hex groups of the wrong length, e.g. one digit short
+  version    RFC 9562 variant with a version outside 1-8
+  variant    the reserved (future) variant bits
+  duplicate  the same UUID appearing more than once across the tree
+
+The nil and max UUIDs are placeholders and are never reported. The command
+fails when the number of findings exceeds --max-findings (default 0).
+
+Examples:
+  uuid lint ./...
+  uuid lint --exclude 'vendor' --exclude '*.lock' src
+  uuid lint --json . > findings.json`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		excludes, _ := cmd.Flags().GetStringSlice("exclude")
+		maxFindings, _ := cmd.Flags().GetInt("max-findings")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		for _, pattern := range excludes {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid --exclude pattern '%s': %w", pattern, err)
+			}
+		}
+
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+
+		var candidates []lintFinding
+		for _, root := range args {
+			root = strings.TrimSuffix(strings.TrimSuffix(root, "..."), "/")
+			if root == "" {
+				root = "."
+			}
+			found, err := lintTree(root, excludes)
+			if err != nil {
+				return err
+			}
+			candidates = append(candidates, found...)
+		}
+		findings := lintFindings(candidates)
+
+		out := cmd.OutOrStdout()
+		if asJSON {
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(findings); err != nil {
+				return err
+			}
+		} else {
+			for _, f := range findings {
+				fmt.Fprintf(out, "%s:%d:%d: %s: %s\n", f.File, f.Line, f.Column, f.Kind, f.Message)
+			}
+		}
+
+		if len(findings) > maxFindings {
+			return fmt.Errorf("%d findings exceed --max-findings %d", len(findings), maxFindings)
+		}
+		return nil
+	},
+}
+
+// lintFinding is one reported problem; the JSON field names are stable for
+// CI annotation tooling
+type lintFinding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Value   string `json:"value"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// lintCandidate matches text shaped like a UUID, loosely enough to catch hex
+// groups that are a digit or two too short or too long
+var lintCandidate = sync.OnceValue(func() *regexp.Regexp {
+	return regexp.MustCompile(`\b[0-9a-fA-F]{6,10}-[0-9a-fA-F]{2,6}-[0-9a-fA-F]{2,6}-[0-9a-fA-F]{2,6}-[0-9a-fA-F]{10,14}\b`)
+})
+
+// lintGroupLengths is the hex digit count of each group of a canonical UUID
+var lintGroupLengths = []int{8, 4, 4, 4, 12}
+
+// lintTree scans every text file under root and returns each UUID-shaped
+// occurrence, with Kind set for problems found in isolation
+func lintTree(root string, excludes []string) ([]lintFinding, error) {
+	var found []lintFinding
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && lintExcluded(root, path, excludes) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		occurrences, err := lintFile(path)
+		if err != nil {
+			return err
+		}
+		found = append(found, occurrences...)
+		return nil
+	})
+	return found, err
+}
+
+// lintExcluded reports whether path matches an --exclude glob, either by its
+// base name or by its slash-separated path relative to root
+func lintExcluded(root, path string, excludes []string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range excludes {
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// lintFile returns the UUID-shaped occurrences in one file, skipping files
+// that look binary
+func lintFile(path string) ([]lintFinding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	head, err := reader.Peek(8000)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+
+	var found []lintFinding
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		for _, loc := range lintCandidate().FindAllStringIndex(line, -1) {
+			value := line[loc[0]:loc[1]]
+			finding := lintFinding{File: path, Line: lineNum, Column: loc[0] + 1, Value: value}
+			finding.Kind, finding.Message = lintCheck(value)
+			found = append(found, finding)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return found, nil
+}
+
+// lintCheck classifies a single candidate, returning an empty kind when it is
+// a well-formed UUID
+func lintCheck(value string) (kind, message string) {
+	groups := strings.Split(value, "-")
+	for i, group := range groups {
+		if len(group) != lintGroupLengths[i] {
+			return "malformed", fmt.Sprintf("'%s' has %d hex digits in group %d, expected %d", value, len(group), i+1, lintGroupLengths[i])
+		}
+	}
+
+	uuid, err := generator.ParseUUID(value)
+	if err != nil {
+		return "malformed", fmt.Sprintf("'%s' is not a valid UUID: %v", value, err)
+	}
+	if lintSentinel(value) {
+		return "", ""
+	}
+
+	details := generator.Inspect(uuid)
+	switch {
+	case details.Variant == generator.VariantFuture:
+		return "variant", fmt.Sprintf("'%s' uses the reserved variant bits", value)
+	case details.Variant == generator.VariantRFC && (details.Version < 1 || details.Version > 8):
+		return "version", fmt.Sprintf("'%s' has version %d, which RFC 9562 does not define", value, details.Version)
+	}
+	return "", ""
+}
+
+// lintSentinel reports whether value is the nil or max UUID, which are
+// placeholders that legitimately repeat and carry no version
+func lintSentinel(value string) bool {
+	value = strings.ToLower(value)
+	return value == "00000000-0000-0000-0000-000000000000" || value == "ffffffff-ffff-ffff-ffff-ffffffffffff"
+}
+
+// lintFindings keeps the candidates that have problems and adds a duplicate
+// finding for every occurrence of a well-formed UUID seen more than once
+func lintFindings(candidates []lintFinding) []lintFinding {
+	locations := make(map[string][]string)
+	for _, c := range candidates {
+		if c.Kind != "malformed" && !lintSentinel(c.Value) {
+			key := strings.ToLower(c.Value)
+			locations[key] = append(locations[key], fmt.Sprintf("%s:%d", c.File, c.Line))
+		}
+	}
+
+	findings := []lintFinding{}
+	for _, c := range candidates {
+		if c.Kind != "" {
+			findings = append(findings, c)
+		}
+		if c.Kind == "malformed" || lintSentinel(c.Value) {
+			continue
+		}
+		seen := locations[strings.ToLower(c.Value)]
+		if len(seen) < 2 {
+			continue
+		}
+		here := fmt.Sprintf("%s:%d", c.File, c.Line)
+		others := make([]string, 0, len(seen)-1)
+		skipped := false
+		for _, location := range seen {
+			if location == here && !skipped {
+				skipped = true
+				continue
+			}
+			others = append(others, location)
+		}
+		duplicate := c
+		duplicate.Kind = "duplicate"
+		duplicate.Message = fmt.Sprintf("'%s' appears %d times (also at %s)", c.Value, len(seen), strings.Join(others, ", "))
+		findings = append(findings, duplicate)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return findings
+}
+
+func init() {
+	lintCmd.Flags().StringSlice("exclude", nil, "Glob of file or directory names to skip (repeatable)")
+	lintCmd.Flags().Int("max-findings", 0, "Fail when there are more findings than this")
+	lintCmd.Flags().Bool("json", false, "Print findings as a JSON array")
+
+	rootCmd.AddCommand(lintCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var lintFixtures = filepath.Join("testdata", "lint")
+
+func TestLintFindsEachProblemClass(t *testing.T) {
+	output, err := executeCommand(t, "", "lint", lintFixtures+"/...")
+	if err == nil || !strings.Contains(err.Error(), "6 findings exceed --max-findings 0") {
+		t.Errorf("Expected findings to fail the command, got: %v", err)
+	}
+
+	flags := filepath.Join(lintFixtures, "flags.go")
+	config := filepath.Join(lintFixtures, "nested", "config.yaml")
+	expected := []string{
+		flags + ":5:18: duplicate: '0188b733-b800-7079-9ce7-7022b2ba0185' appears 2 times (also at " + config + ":2)",
+		flags + ":6:18: malformed: '0188b733-b800-7079-9ce7-7022b2ba018' has 11 hex digits in group 5, expected 12",
+		config + ":2:18: duplicate: '0188B733-B800-7079-9CE7-7022B2BA0185' appears 2 times (also at " + flags + ":5)",
+		config + ":3:9: version: '919108f7-52d1-0320-9bac-f847db4148a8' has version 0, which RFC 9562 does not define",
+		config + ":4:11: variant: '919108f7-52d1-4320-eb0c-f847db4148a8' uses the reserved variant bits",
+		filepath.Join(lintFixtures, "vendor", "dep.go") + ":3:17: malformed: '1234567-1234-4234-8234-123456789abc' has 7 hex digits in group 1, expected 8",
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("Missing finding %q in:\n%s", line, output)
+		}
+	}
+	if strings.Contains(output, "blob.bin") {
+		t.Errorf("Binary files should be skipped:\n%s", output)
+	}
+	if strings.Contains(output, "00000000-0000") {
+		t.Errorf("The nil UUID should never be reported:\n%s", output)
+	}
+}
+
+func TestLintExcludeAndMaxFindings(t *testing.T) {
+	output, err := executeCommand(t, "", "lint", "--exclude", "vendor", "--exclude", "*.yaml", "--max-findings", "1", lintFixtures)
+	if err != nil {
+		t.Fatalf("One finding should be within --max-findings 1, got: %v", err)
+	}
+	if strings.Count(output, "\n") != 1 || !strings.Contains(output, "malformed") {
+		t.Errorf("Expected only the malformed flag, got:\n%s", output)
+	}
+}
+
+func TestLintJSON(t *testing.T) {
+	output, _ := executeCommand(t, "", "lint", "--json", "--max-findings", "100", lintFixtures)
+
+	var findings []lintFinding
+	if err := json.Unmarshal([]byte(output), &findings); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(findings) != 6 {
+		t.Fatalf("Expected 6 findings, got %d", len(findings))
+	}
+	first := findings[0]
+	if first.File != filepath.Join(lintFixtures, "flags.go") || first.Line != 5 || first.Column != 18 || first.Kind != "duplicate" {
+		t.Errorf("Unexpected first finding: %+v", first)
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	output, err := executeCommand(t, "", "lint", "--json", filepath.Join(lintFixtures, "vendor"), "--exclude", "*.go")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.TrimSpace(output) != "[]" {
+		t.Errorf("A clean tree should produce an empty JSON array, got: %s", output)
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// manifestFixture is a small build tree; hello.txt is pinned against an
+// independent computation of the derivation
+var manifestFixture = map[string]string{
+	"hello.txt":        "hello\n",
+	"bin/app":          "binary\x00contents",
+	"assets/style.css": "body {}\n",
+	"assets/copy.txt":  "hello\n",
+	"tmp/scratch.tmp":  "scratch",
+	"cache.tmp":        "cache",
+}
+
+func runManifest(t *testing.T, args ...string) map[string]manifestEntry {
+	t.Helper()
+	output, err := executeCommand(t, "", append([]string{"manifest"}, args...)...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var manifest map[string]manifestEntry
+	if err := json.Unmarshal([]byte(output), &manifest); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", output, err)
+	}
+	return manifest
+}
+
+func TestManifestDerivation(t *testing.T) {
+	if got := generator.FormatUUID(manifestNamespace); got != "c04ca54a-145d-559c-a12f-4db86c5b2746" {
+		t.Errorf("The documented manifest namespace changed: %s", got)
+	}
+
+	dir := writeContentTree(t, manifestFixture)
+	manifest := runManifest(t, dir)
+	if len(manifest) != len(manifestFixture) {
+		t.Errorf("Expected %d files, got %v", len(manifestFixture), manifest)
+	}
+	hello := manifest["hello.txt"]
+	if hello.UUID != "e2c5cc95-9944-843e-852d-e7559e1e4270" || hello.Size != 6 || hello.MTime == "" {
+		t.Errorf("Unexpected entry for hello.txt: %+v", hello)
+	}
+	if manifest["assets/copy.txt"].UUID != hello.UUID {
+		t.Error("Identical contents should get the same UUID without --include-path")
+	}
+
+	if got := runManifest(t, dir, "--hash", "sha1")["hello.txt"].UUID; got != "e238fda3-ac0e-5bfc-b7f0-3fcd62410867" {
+		t.Errorf("Unexpected UUIDv5 for hello.txt: %s", got)
+	}
+
+	withPath := runManifest(t, dir, "--include-path")
+	if withPath["assets/copy.txt"].UUID == withPath["hello.txt"].UUID {
+		t.Error("--include-path should separate identical files at different paths")
+	}
+}
+
+func TestManifestDeterministic(t *testing.T) {
+	dir := writeContentTree(t, manifestFixture)
+	out := filepath.Join(dir, "manifest.json")
+
+	if _, err := executeCommand(t, "", "manifest", dir, "--out", out, "--workers", "3"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(first), "manifest.json") {
+		t.Error("The manifest must not list itself")
+	}
+
+	if _, err := executeCommand(t, "", "manifest", dir, "--out", out, "--workers", "1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := os.ReadFile(out)
+	if string(first) != string(second) {
+		t.Errorf("Expected identical manifests:\n%s\n%s", first, second)
+	}
+}
+
+func TestManifestByteFlip(t *testing.T) {
+	dir := writeContentTree(t, manifestFixture)
+	before := runManifest(t, dir)
+
+	// Flip one byte without changing the size or mtime
+	path := filepath.Join(dir, "assets", "style.css")
+	info, _ := os.Stat(path)
+	os.WriteFile(path, []byte("body {}\t"), 0o644)
+	os.Chtimes(path, info.ModTime(), info.ModTime())
+
+	after := runManifest(t, dir)
+	for rel, entry := range before {
+		if changed := after[rel].UUID != entry.UUID; changed != (rel == "assets/style.css") {
+			t.Errorf("Unexpected change for %s: %s -> %s", rel, entry.UUID, after[rel].UUID)
+		}
+	}
+}
+
+func TestManifestExclude(t *testing.T) {
+	dir := writeContentTree(t, manifestFixture)
+	manifest := runManifest(t, dir, "--exclude", "*.tmp", "--exclude", "bin")
+	for _, rel := range []string{"tmp/scratch.tmp", "cache.tmp", "bin/app"} {
+		if _, ok := manifest[rel]; ok {
+			t.Errorf("Expected %s to be excluded", rel)
+		}
+	}
+	if len(manifest) != 3 {
+		t.Errorf("Expected 3 files, got %v", manifest)
+	}
+}
+
+func TestManifestErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"unknown hash", []string{"manifest", "--hash", "md5"}, "unknown hash 'md5'"},
+		{"no workers", []string{"manifest", "--workers", "0"}, "workers must be at least 1"},
+		{"bad exclude", []string{"manifest", "--exclude", "["}, "invalid --exclude pattern '['"},
+		{"missing dir", []string{"manifest", filepath.Join(t.TempDir(), "missing")}, "no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// monotonicStateConflicts are the flags that choose UUIDv7 timestamps
+// other than the clock, which a counter continued across runs cannot follow
+var monotonicStateConflicts = []string{"timestamp", "time-granularity", "spread", "spread-from", "from-hex", "idempotent"}
+
+// monotonicV7 is the generator -7 draws from under --monotonic-state, or nil
+var monotonicV7 *generator.MonotonicV7
+
+// monotonicState is the --monotonic-state file: the timestamp and counter
+// of the last UUIDv7 any run generated with it
+type monotonicState struct {
+	LastTimestamp time.Time `json:"last_timestamp"`
+	Counter       uint16    `json:"counter"`
+}
+
+// withMonotonicState wraps run so that, with --monotonic-state, -7 draws
+// from a monotonic generator continuing the counter saved in that file.
+// The file is locked for the whole run, so concurrent runs take turns and
+// their UUIDs strictly increase in the order the runs held the lock. A
+// state file that cannot be locked, read, or saved is an error: falling
+// back would silently give up the ordering.
+func withMonotonicState(run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("monotonic-state")
+		if path == "" {
+			return run(cmd, args)
+		}
+		selected, err := versionFlag(cmd)
+		if err != nil {
+			return err
+		}
+		if selected != 7 {
+			return fmt.Errorf("--monotonic-state requires -7")
+		}
+		if err := rejectFlags(cmd, "--monotonic-state", monotonicStateConflicts); err != nil {
+			return err
+		}
+
+		return withMonotonicStateFile(path, func(g *generator.MonotonicV7) error {
+			monotonicV7 = g
+			defer func() { monotonicV7 = nil }()
+			return run(cmd, args)
+		})
+	}
+}
+
+// withMonotonicStateFile locks the state file at path with lockStateFile
+// and calls fn with a generator restored from it, or a fresh one when the
+// file does not exist yet or is empty. The generator's state is saved back
+// when fn succeeds, by renaming a synced temporary file over path, so a
+// crash leaves the previous state rather than a partial one.
+func withMonotonicStateFile(path string, fn func(*generator.MonotonicV7) error) error {
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot lock --monotonic-state '%s': %w", path, err)
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cannot read --monotonic-state '%s': %w", path, err)
+	}
+	g := new(generator.MonotonicV7)
+	if len(bytes.TrimSpace(data)) > 0 {
+		var state monotonicState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("corrupt --monotonic-state '%s': %w", path, err)
+		}
+		g.Restore(state.LastTimestamp, state.Counter)
+	}
+	debugLogger.Debug("loaded monotonic state", "path", path)
+
+	if err := fn(g); err != nil {
+		return err
+	}
+
+	last, counter := g.State()
+	data, _ = json.Marshal(monotonicState{LastTimestamp: last, Counter: counter})
+	if err := replaceFile(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("cannot save --monotonic-state '%s': %w", path, err)
+	}
+	return nil
+}
+
+// replaceFile writes data to a temporary file beside path, syncs it, and
+// renames it over path. The temporary file is always path.tmp, so callers
+// hold path's lockStateFile lock.
+func replaceFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
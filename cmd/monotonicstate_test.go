@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestMonotonicStateAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monotonic.json")
+	var all []string
+	// Every run falls in the same pinned millisecond, which only the saved
+	// counter can keep in order
+	for range 3 {
+		output, err := executeCommand(t, "", "-7", "-n", "2", "--monotonic-state", path, "--now", "2024-01-01T00:00:00Z")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		all = append(all, strings.Fields(output)...)
+	}
+	if len(all) != 6 {
+		t.Fatalf("Expected 6 UUIDs, got %q", all)
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i] <= all[i-1] {
+			t.Errorf("Expected %s > %s across runs", all[i], all[i-1])
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), `"last_timestamp":"2024-01-01T00:00:00Z"`) {
+		t.Errorf("Expected the state file to record the last timestamp, got %s, %v", data, err)
+	}
+}
+
+func TestMonotonicStateConcurrent(t *testing.T) {
+	pinned := time.UnixMilli(1700000000000)
+	generator.SetClock(func() time.Time { return pinned })
+	t.Cleanup(func() { generator.SetClock(nil) })
+
+	// Each goroutine stands in for a separate invocation with its own file
+	// descriptor; UUIDs are recorded while the lock is held, so their
+	// combined order is the order the runs took turns in
+	path := filepath.Join(t.TempDir(), "monotonic.json")
+	const runs, perRun = 16, 50
+	var mu sync.Mutex
+	var combined []string
+	var wg sync.WaitGroup
+	errs := make(chan error, runs)
+	for range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- withMonotonicStateFile(path, func(g *generator.MonotonicV7) error {
+				for range perRun {
+					uuid := g.Generate()
+					mu.Lock()
+					combined = append(combined, uuid)
+					mu.Unlock()
+				}
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(combined) != runs*perRun {
+		t.Fatalf("Expected %d UUIDs, got %d", runs*perRun, len(combined))
+	}
+	for i := 1; i < len(combined); i++ {
+		if combined[i] <= combined[i-1] {
+			t.Fatalf("Expected global ordering, got %s after %s at %d", combined[i], combined[i-1], i)
+		}
+	}
+}
+
+func TestMonotonicStateReplacedAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "monotonic.json")
+	// An empty file, as mktemp leaves, starts a fresh counter
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executeCommand(t, "", "-7", "--monotonic-state", path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(path + ".tmp"); err == nil {
+		t.Error("Expected the temporary file to be renamed over the state file")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), `"counter":`) {
+		t.Errorf("Expected the saved state, got %s, %v", data, err)
+	}
+}
+
+func TestMonotonicStateErrors(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"without v7", []string{"-4", "--monotonic-state", filepath.Join(dir, "a.json")}, "--monotonic-state requires -7"},
+		{"default version", []string{"--monotonic-state", filepath.Join(dir, "a.json")}, "--monotonic-state requires -7"},
+		{"with timestamp", []string{"-7", "-t", "2024-01-01", "--monotonic-state", filepath.Join(dir, "a.json")}, "--monotonic-state cannot be combined with -t"},
+		{"unsupported version", []string{"--uuid-version", "3", "--monotonic-state", filepath.Join(dir, "a.json")}, "unsupported --uuid-version 3"},
+		{"unwritable", []string{"-7", "--monotonic-state", filepath.Join(dir, "missing", "a.json")}, "cannot lock --monotonic-state"},
+		{"corrupt", []string{"-7", "--monotonic-state", corrupt}, fmt.Sprintf("corrupt --monotonic-state '%s'", corrupt)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, _, err := executeCommandSplit(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+			if output != "" {
+				t.Errorf("Expected no UUIDs, got %q", output)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.json")); err == nil {
+		t.Error("Expected no state file to be created for a rejected run")
+	}
+}
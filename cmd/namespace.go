@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// namespaceRegistryFile is the name of the file holding project namespaces
+const namespaceRegistryFile = "namespaces.json"
+
+// configDir returns the directory for user configuration; tests replace it
+// with a temporary directory
+var configDir = func() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "uuid"), nil
+}
+
+// namespaceCmd groups the namespace registry subcommands
+var namespaceCmd = &cobra.Command{
+	Use:   "namespace",
+	Short: "Create and manage named project namespaces for name-based UUIDs",
+	Long: `Create and manage named project namespaces for name-based UUIDs.
+
+Namespaces are stored in ` + namespaceRegistryFile + ` under the user configuration
+directory. Wherever a namespace is accepted, a registered name is looked up
+first, then the well-known keywords (` + strings.Join(generator.NamespaceKeywords(), ", ") + `), then a literal UUID.
+
+Examples:
+  uuid namespace create myproject
+  uuid namespace create scratch --random
+  uuid namespace list
+  uuid namespace show myproject
+  uuid namespace rm scratch`,
+	Args: cobra.NoArgs,
+}
+
+// namespaceCreateCmd registers a new namespace
+var namespaceCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Register a namespace under a name",
+	Long: `Register a namespace under a name and print it.
+
+By default the namespace is derived from the name as its UUIDv5 in the DNS
+namespace, so every team that creates the same name gets the same UUID. Use
+--random for a random UUIDv4 instead. Existing names are only replaced with
+--force.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		random, _ := cmd.Flags().GetBool("random")
+		force, _ := cmd.Flags().GetBool("force")
+		if name == "" || strings.ContainsFunc(name, func(r rune) bool { return r <= ' ' }) {
+			return fmt.Errorf("invalid namespace name '%s': names must be non-empty and contain no whitespace", name)
+		}
+
+		registry, err := loadNamespaces()
+		if err != nil {
+			return err
+		}
+		if existing, ok := registry[name]; ok && !force {
+			return fmt.Errorf("namespace '%s' already exists (%s); use --force to replace it", name, existing)
+		}
+
+		namespace := generator.FormatUUID(generator.NewV5(generator.NamespaceDNS, name))
+		if random {
+			namespace = generator.GenerateUUIDv4()
+		}
+		registry[name] = namespace
+		if err := saveNamespaces(registry); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), namespace)
+		return nil
+	},
+}
+
+// namespaceListCmd prints every registered namespace
+var namespaceListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List registered namespaces",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registry, err := loadNamespaces()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tNAMESPACE")
+		names := make([]string, 0, len(registry))
+		for name := range registry {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s\t%s\n", name, registry[name])
+		}
+		return w.Flush()
+	},
+}
+
+// namespaceShowCmd prints the namespace a name resolves to
+var namespaceShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print the namespace UUID a name resolves to",
+	Long: `Print the namespace UUID a name resolves to, following the same order as
+--namespace: registered names, then keywords, then literal UUIDs.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		namespace, err := resolveNamespace(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), generator.FormatUUID(namespace))
+		return nil
+	},
+}
+
+// namespaceRmCmd removes a registered namespace
+var namespaceRmCmd = &cobra.Command{
+	Use:          "rm <name>",
+	Short:        "Remove a registered namespace",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registry, err := loadNamespaces()
+		if err != nil {
+			return err
+		}
+		if _, ok := registry[args[0]]; !ok {
+			return fmt.Errorf("no namespace named '%s'", args[0])
+		}
+		delete(registry, args[0])
+		return saveNamespaces(registry)
+	},
+}
+
+// resolveNamespace turns a --namespace value into a namespace UUID, trying
+// registered names, then well-known keywords, then a literal UUID
+func resolveNamespace(value string) ([16]byte, error) {
+	registry, err := loadNamespaces()
+	if err != nil {
+		return [16]byte{}, err
+	}
+	return lookupNamespace(registry, value)
+}
+
+// lookupNamespace resolves value as resolveNamespace does, against an
+// already loaded registry
+func lookupNamespace(registry map[string]string, value string) ([16]byte, error) {
+	if namespace, ok := registry[value]; ok {
+		return generator.ParseUUID(namespace)
+	}
+	namespace, err := generator.ParseNamespace(value)
+	if err != nil {
+		return [16]byte{}, fmt.Errorf("unknown namespace '%s'. Use a name from 'uuid namespace list', a keyword (%s), or a UUID in the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx such as 6ba7b810-9dad-11d1-80b4-00c04fd430c8", value, strings.Join(generator.NamespaceKeywords(), ", "))
+	}
+	return namespace, nil
+}
+
+// loadNamespaces reads the registry, which is empty until the first create
+func loadNamespaces() (map[string]string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, namespaceRegistryFile)
+	registry := map[string]string{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("corrupt namespace registry %s: %w", path, err)
+	}
+	return registry, nil
+}
+
+// saveNamespaces writes the registry, creating the directory if needed
+func saveNamespaces(registry map[string]string) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, namespaceRegistryFile), append(data, '\n'), 0o600)
+}
+
+func init() {
+	namespaceCreateCmd.Flags().Bool("random", false, "Generate a random namespace instead of deriving it from the name")
+	namespaceCreateCmd.Flags().Bool("force", false, "Replace an existing namespace with the same name")
+	namespaceCmd.AddCommand(namespaceCreateCmd, namespaceListCmd, namespaceShowCmd, namespaceRmCmd)
+	rootCmd.AddCommand(namespaceCmd)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// fakeConfigDir points the namespace registry at a temporary directory
+func fakeConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "config")
+	original := configDir
+	configDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { configDir = original })
+	return dir
+}
+
+func TestNamespaceRoundTrip(t *testing.T) {
+	fakeConfigDir(t)
+
+	// Derived namespaces are the UUIDv5 of the name in the DNS namespace
+	output, err := executeCommand(t, "", "namespace", "create", "myproject")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "a30e6ea9-befa-54b6-ae26-f26260cf6a3f\n" {
+		t.Errorf("Unexpected derived namespace %q", output)
+	}
+
+	random, err := executeCommand(t, "", "namespace", "create", "scratch", "--random")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	random = strings.TrimSpace(random)
+	if !uuidRegex.MatchString(random) || random[14] != '4' {
+		t.Errorf("Expected a random UUIDv4, got %q", random)
+	}
+
+	output, err = executeCommand(t, "", "namespace", "show", "scratch")
+	if err != nil || output != random+"\n" {
+		t.Errorf("Expected %s, got %q, %v", random, output, err)
+	}
+
+	output, err = executeCommand(t, "", "namespace", "list")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "myproject") || !strings.HasPrefix(lines[2], "scratch") {
+		t.Errorf("Expected both namespaces sorted by name, got:\n%s", output)
+	}
+
+	if _, err := executeCommand(t, "", "namespace", "rm", "scratch"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output, _ = executeCommand(t, "", "namespace", "list")
+	if strings.Contains(output, "scratch") {
+		t.Errorf("Expected scratch to be removed, got:\n%s", output)
+	}
+}
+
+func TestNamespaceCollision(t *testing.T) {
+	fakeConfigDir(t)
+
+	first, err := executeCommand(t, "", "namespace", "create", "team", "--random")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err = executeCommand(t, "", "namespace", "create", "team")
+	if err == nil || !strings.Contains(err.Error(), "namespace 'team' already exists") {
+		t.Errorf("Expected a collision error, got: %v", err)
+	}
+	output, _ := executeCommand(t, "", "namespace", "show", "team")
+	if output != first {
+		t.Errorf("Collision must not replace the namespace, got %q", output)
+	}
+
+	output, err = executeCommand(t, "", "namespace", "create", "team", "--force")
+	if err != nil || output == first {
+		t.Errorf("Expected --force to replace the namespace, got %q, %v", output, err)
+	}
+}
+
+func TestResolveNamespacePrecedence(t *testing.T) {
+	fakeConfigDir(t)
+	literal := "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"keyword", "dns", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{"literal", literal, literal},
+		{"literal in another form", "urn:uuid:" + literal, literal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace, err := resolveNamespace(tt.value)
+			if err != nil || generator.FormatUUID(namespace) != tt.expected {
+				t.Errorf("Expected %s, got %s, %v", tt.expected, generator.FormatUUID(namespace), err)
+			}
+		})
+	}
+
+	// Registered names shadow keywords and literal UUIDs
+	for _, name := range []string{"dns", literal} {
+		created, err := executeCommand(t, "", "namespace", "create", name, "--random")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		namespace, err := resolveNamespace(name)
+		if err != nil || generator.FormatUUID(namespace) != strings.TrimSpace(created) {
+			t.Errorf("Expected the registered %s for %s, got %s, %v", created, name, generator.FormatUUID(namespace), err)
+		}
+	}
+}
+
+func TestNamespaceErrors(t *testing.T) {
+	dir := fakeConfigDir(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"unknown name", []string{"namespace", "show", "nothing"}, "unknown namespace 'nothing'. Use a name from 'uuid namespace list', a keyword (dns, url, oid, x500)"},
+		{"rm missing", []string{"namespace", "rm", "nothing"}, "no namespace named 'nothing'"},
+		{"whitespace", []string{"namespace", "create", "my project"}, "invalid namespace name 'my project'"},
+		{"missing name", []string{"namespace", "create"}, "accepts 1 arg(s)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+
+	os.MkdirAll(dir, 0o700)
+	os.WriteFile(filepath.Join(dir, namespaceRegistryFile), []byte("[1, 2"), 0o600)
+	_, err := executeCommand(t, "", "namespace", "list")
+	if err == nil || !strings.Contains(err.Error(), "corrupt namespace registry") {
+		t.Errorf("Expected a corrupt registry error, got: %v", err)
+	}
+}
+
+func TestV5Flag(t *testing.T) {
+	fakeConfigDir(t)
+	if _, err := executeCommand(t, "", "namespace", "create", "myproject"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expected values from Python's uuid.uuid5
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"keyword", []string{"-5", "--namespace", "dns", "--name", "example.com"}, "cfbff0d1-9375-5685-968c-48ce8b15ae17"},
+		{"literal", []string{"-5", "--namespace", "6ba7b811-9dad-11d1-80b4-00c04fd430c8", "--name", "https://example.com/a"}, "6639460f-3425-5329-8097-a58f06127860"},
+		{"private", []string{"-5", "--namespace", "919108f7-52d1-4320-9bac-f847db4148a8", "--name", "h\u00e9llo"}, "117b709e-9f17-51d9-9689-1021e492bab9"},
+		{"registered", []string{"--uuid-version", "5", "--namespace", "myproject", "--name", "orders"}, generator.FormatUUID(generator.NewV5(generator.NewV5(generator.NamespaceDNS, "myproject"), "orders"))},
+		{"upper", []string{"-5", "--namespace", "dns", "--name", "example.com", "--upper"}, "CFBFF0D1-9375-5685-968C-48CE8B15AE17"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(t, "", tt.args...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != tt.expected+"\n" {
+				t.Errorf("Expected %s, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestV5FlagErrors(t *testing.T) {
+	fakeConfigDir(t)
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"missing name", []string{"-5", "--namespace", "dns"}, "-5 requires --name"},
+		{"missing namespace", []string{"-5", "--name", "example.com"}, "-5 requires --namespace"},
+		{"name without -5", []string{"--name", "example.com"}, "--name requires -5 or -8 --hmac-key-file"},
+		{"namespace with -7", []string{"-7", "--namespace", "dns"}, "--namespace requires -5"},
+		{"with timestamp", []string{"-5", "--namespace", "dns", "--name", "x", "-t", "2023-06-14"}, "-5 cannot be combined with -t"},
+		{"with per-line", []string{"-5", "--namespace", "dns", "--name", "x", "--per-line"}, "-5 cannot be combined with --per-line"},
+		{"with -4", []string{"-5", "-4", "--namespace", "dns", "--name", "x"}, "none of the others can be"},
+		{"unknown namespace", []string{"-5", "--namespace", "nope", "--name", "x"}, "unknown namespace 'nope'. Use a name from 'uuid namespace list', a keyword (dns, url, oid, x500), or a UUID in the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
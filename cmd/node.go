@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// perBootStateFile is the name of the file persisting the per-boot node
+const perBootStateFile = "node.json"
+
+// cacheDir returns the directory for state that only guards against
+// repeats, such as the per-boot node and the UUIDv6 clock sequence, and can
+// be lost without breaking anything; tests replace it with a temporary
+// directory
+var cacheDir = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "uuid"), nil
+}
+
+// stateDir returns the directory for state users rely on between runs, such
+// as idempotency keys and aliases: $XDG_STATE_HOME/uuid, or
+// ~/.local/state/uuid. Unlike a cache directory it is not cleared behind
+// the user's back. Tests replace it with a temporary directory.
+var stateDir = xdgStateDir
+
+// xdgStateDir is the default stateDir
+func xdgStateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "uuid"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "uuid"), nil
+}
+
+// readBootID identifies the current boot session. Only Linux exposes one;
+// elsewhere it fails and the per-boot node lasts until its state file is
+// removed. Tests replace it to simulate reboots.
+var readBootID = func() (string, error) {
+	b, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// listInterfaces enumerates network interfaces; tests replace it
+var listInterfaces = net.Interfaces
+
+// selectMACInterface picks the interface whose hardware address --node mac
+// uses: the first, in index order, that is up, is not a loopback, and has a
+// non-zero 48-bit MAC. The interfaces command shows its choice too.
+func selectMACInterface(ifaces []net.Interface) (net.Interface, bool) {
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if len(iface.HardwareAddr) != 6 || slices.Equal(iface.HardwareAddr, make(net.HardwareAddr, 6)) {
+			continue
+		}
+		return iface, true
+	}
+	return net.Interface{}, false
+}
+
+// perBootState is the persisted per-boot node and the boot it belongs to
+type perBootState struct {
+	BootID string `json:"boot_id"`
+	Node   string `json:"node"`
+}
+
+// resolveNode returns the node that --node asks for, or nil when every UUID
+// should get a fresh random node
+func resolveNode(cmd *cobra.Command) (*[6]byte, error) {
+	mode, _ := cmd.Flags().GetString("node")
+	switch mode {
+	case "random":
+		return nil, nil
+	case "per-process":
+		node := generator.ProcessNode()
+		debugLogger.Debug("selected node", "node_source", "per-process", "node", hex.EncodeToString(node[:]))
+		return &node, nil
+	case "per-boot":
+		node, err := perBootNode()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: cannot use the per-boot node (%v); using a random node instead.\n", err)
+			return nil, nil
+		}
+		debugLogger.Debug("selected node", "node_source", "per-boot", "node", hex.EncodeToString(node[:]))
+		return &node, nil
+	case "mac":
+		ifaces, err := listInterfaces()
+		if err != nil {
+			return nil, fmt.Errorf("cannot list network interfaces: %w", err)
+		}
+		iface, ok := selectMACInterface(ifaces)
+		if !ok {
+			return nil, fmt.Errorf("no network interface with a MAC address is up; use --node random or per-boot (see 'uuid interfaces')")
+		}
+		var node [6]byte
+		copy(node[:], iface.HardwareAddr)
+		debugLogger.Debug("selected node", "node_source", "mac", "interface", iface.Name, "node", hex.EncodeToString(node[:]))
+		return &node, nil
+	}
+	node, err := generator.ParseNode(mode)
+	if err != nil {
+		return nil, fmt.Errorf("unknown node mode '%s'. Available modes: random, per-process, per-boot, mac, or a node as 12 hex digits", mode)
+	}
+	debugLogger.Debug("selected node", "node_source", "flag", "node", hex.EncodeToString(node[:]))
+	return &node, nil
+}
+
+// perBootNode returns the node persisted for the current boot, creating and
+// persisting a random one on first use
+func perBootNode() ([6]byte, error) {
+	node, found, err := loadPerBootNode()
+	if err != nil || found {
+		return node, err
+	}
+
+	dir, err := cacheDir()
+	if err != nil {
+		return [6]byte{}, err
+	}
+	boot, _ := readBootID()
+	node = generator.RandomNode()
+	data, _ := json.Marshal(perBootState{BootID: boot, Node: hex.EncodeToString(node[:])})
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return [6]byte{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, perBootStateFile), append(data, '\n'), 0o600); err != nil {
+		return [6]byte{}, err
+	}
+	return node, nil
+}
+
+// loadPerBootNode reads the persisted node. found is false when there is no
+// state file or it belongs to an earlier boot.
+func loadPerBootNode() (node [6]byte, found bool, err error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return node, false, err
+	}
+	path := filepath.Join(dir, perBootStateFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return node, false, nil
+	}
+	if err != nil {
+		return node, false, err
+	}
+
+	var state perBootState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return node, false, fmt.Errorf("corrupt state file %s: %w", path, err)
+	}
+	b, err := hex.DecodeString(state.Node)
+	if err != nil || len(b) != len(node) {
+		return node, false, fmt.Errorf("corrupt state file %s: node '%s' is not 12 hex digits", path, state.Node)
+	}
+	if boot, _ := readBootID(); boot != state.BootID {
+		return node, false, nil
+	}
+	copy(node[:], b)
+	return node, true, nil
+}
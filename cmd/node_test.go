@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeNodeState points the per-boot node at a temporary cache directory and
+// a boot ID the test controls
+func fakeNodeState(t *testing.T, boot *string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "state")
+	originalDir, originalBoot := cacheDir, readBootID
+	cacheDir = func() (string, error) { return dir, nil }
+	readBootID = func() (string, error) {
+		if *boot == "" {
+			return "", errors.New("no boot ID")
+		}
+		return *boot, nil
+	}
+	t.Cleanup(func() { cacheDir, readBootID = originalDir, originalBoot })
+	return dir
+}
+
+// nodeOf returns the node field of a canonical UUID
+func nodeOf(uuid string) string {
+	return uuid[24:]
+}
+
+func TestNodePerBootCreateAndReuse(t *testing.T) {
+	boot := "boot-1"
+	dir := fakeNodeState(t, &boot)
+
+	output, _, err := executeCommandSplit(t, "", "-6", "--node", "per-boot", "-n", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Fields(output)
+	node := nodeOf(lines[0])
+	for _, uuid := range lines {
+		if nodeOf(uuid) != node {
+			t.Errorf("Expected every UUID to share node %s, got %s", node, uuid)
+		}
+	}
+	if first, _ := strconv.ParseUint(node[:2], 16, 8); first&0x01 == 0 {
+		t.Errorf("Expected the multicast bit in node %s", node)
+	}
+	state, err := os.ReadFile(filepath.Join(dir, perBootStateFile))
+	if err != nil || !strings.Contains(string(state), `"boot_id":"boot-1"`) || !strings.Contains(string(state), node) {
+		t.Fatalf("Expected the node persisted for boot-1, got %q, %v", state, err)
+	}
+
+	// A second run in the same boot reuses the node
+	output, _, err = executeCommandSplit(t, "", "-6", "--node", "per-boot")
+	if err != nil || nodeOf(strings.TrimSpace(output)) != node {
+		t.Errorf("Expected node %s to be reused, got %q, %v", node, output, err)
+	}
+
+	// After a reboot a new node replaces it
+	boot = "boot-2"
+	output, _, err = executeCommandSplit(t, "", "-6", "--node", "per-boot")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if nodeOf(strings.TrimSpace(output)) == node {
+		t.Errorf("Expected a new node after reboot, got %s again", node)
+	}
+}
+
+func TestNodePerBootWithoutBootID(t *testing.T) {
+	boot := ""
+	fakeNodeState(t, &boot)
+
+	first, _, err := executeCommandSplit(t, "", "-6", "--node", "per-boot")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _, _ := executeCommandSplit(t, "", "-6", "--node", "per-boot")
+	if nodeOf(strings.TrimSpace(first)) != nodeOf(strings.TrimSpace(second)) {
+		t.Errorf("Expected the node to persist without a boot ID, got %s and %s", first, second)
+	}
+}
+
+func TestNodePerBootFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, dir string)
+		contains string
+	}{
+		{
+			name: "corrupt json",
+			setup: func(t *testing.T, dir string) {
+				os.MkdirAll(dir, 0o700)
+				os.WriteFile(filepath.Join(dir, perBootStateFile), []byte("{not json"), 0o600)
+			},
+			contains: "corrupt state file",
+		},
+		{
+			name: "corrupt node",
+			setup: func(t *testing.T, dir string) {
+				os.MkdirAll(dir, 0o700)
+				os.WriteFile(filepath.Join(dir, perBootStateFile), []byte(`{"boot_id":"boot-1","node":"xyz"}`), 0o600)
+			},
+			contains: "node 'xyz' is not 12 hex digits",
+		},
+		{
+			name: "unwritable directory",
+			setup: func(t *testing.T, dir string) {
+				// A file where the directory should be cannot be created into
+				os.WriteFile(dir, nil, 0o600)
+			},
+			contains: "cannot use the per-boot node",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boot := "boot-1"
+			dir := fakeNodeState(t, &boot)
+			tt.setup(t, dir)
+
+			first, stderr, err := executeCommandSplit(t, "", "-6", "--node", "per-boot")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !uuidRegex.MatchString(strings.TrimSpace(first)) {
+				t.Errorf("Expected a UUID, got %q", first)
+			}
+			if !strings.Contains(stderr, "WARNING") || !strings.Contains(stderr, tt.contains) {
+				t.Errorf("Expected a warning containing %q, got %q", tt.contains, stderr)
+			}
+
+			// Each run falls back to a fresh random node
+			second, _, _ := executeCommandSplit(t, "", "-6", "--node", "per-boot")
+			if nodeOf(strings.TrimSpace(first)) == nodeOf(strings.TrimSpace(second)) {
+				t.Errorf("Expected random nodes, got %s twice", nodeOf(first))
+			}
+		})
+	}
+}
+
+func TestNodeStableModes(t *testing.T) {
+	tests := []struct {
+		name, node, want string
+	}{
+		{"hex", "02005e100001", "02005e100001"},
+		{"mac notation", "02:00:5E:10:00:01", "02005e100001"},
+		{"per-process", "per-process", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, version := range []string{"-1", "-6"} {
+				output, err := executeCommand(t, "", version, "--node", tt.node, "-n", "3")
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", version, err)
+				}
+				lines := strings.Fields(output)
+				want := tt.want
+				if want == "" {
+					want = nodeOf(lines[0])
+				}
+				for _, uuid := range lines {
+					if nodeOf(uuid) != want {
+						t.Errorf("%s: expected every UUID to have node %s, got %s", version, want, uuid)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNodeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"without v6", []string{"--node", "per-boot"}, "--node requires -1 or -6"},
+		{"with v7", []string{"-7", "--node", "per-boot"}, "--node requires -1 or -6"},
+		{"unknown mode", []string{"-6", "--node", "eth0"}, "unknown node mode 'eth0'. Available modes: random, per-process, per-boot, mac, or a node as 12 hex digits"},
+		{"short hex", []string{"-6", "--node", "02005e1000"}, "unknown node mode '02005e1000'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// ANSI escape sequences used by the pretty renderer
+const (
+	ansiReset     = "\x1b[0m"
+	ansiHighlight = "\x1b[1;36m"
+	ansiDim       = "\x1b[2m"
+)
+
+// isTerminal reports whether w is a character device such as an interactive terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// usePrettyOutput decides between the pretty and plain renderers. --porcelain
+// is always plain, then the --plain and --pretty flags win; otherwise pretty
+// output is used only when stdout is a terminal, so piped output stays
+// byte-identical to plain mode.
+func usePrettyOutput(cmd *cobra.Command) bool {
+	if f := cmd.Flags().Lookup("porcelain"); f != nil && f.Changed {
+		return false
+	}
+	if plain, _ := cmd.Flags().GetBool("plain"); plain {
+		return false
+	}
+	if pretty, _ := cmd.Flags().GetBool("pretty"); pretty {
+		return true
+	}
+	return isTerminal(cmd.OutOrStdout())
+}
+
+// colorEnabled reports whether ANSI escapes may be written. They are left
+// out when NO_COLOR is set to anything (https://no-color.org) or the
+// terminal is TERM=dumb, which prints them literally; the pretty layout is
+// kept either way.
+func colorEnabled() bool {
+	return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+}
+
+// highlightVersion emphasises the version digit of a canonical UUID
+func highlightVersion(canonical string) string {
+	if len(canonical) != 36 || !colorEnabled() {
+		return canonical
+	}
+	return canonical[:14] + ansiHighlight + canonical[14:15] + ansiReset + canonical[15:]
+}
+
+// dim renders text in the terminal's faint style
+func dim(text string) string {
+	if !colorEnabled() {
+		return text
+	}
+	return ansiDim + text + ansiReset
+}
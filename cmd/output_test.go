@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPlainOutputHasNoDecoration(t *testing.T) {
+	// Both the default (a non-terminal buffer) and --plain must be undecorated
+	for _, args := range [][]string{{"-n", "3"}, {"-n", "3", "--plain"}, {"-7", "-n", "3", "--emit", "canonical,base64"}} {
+		output, err := executeCommand(t, "", args...)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", args, err)
+		}
+		if strings.Contains(output, "\x1b") {
+			t.Errorf("Plain output for %v contains escape sequences: %q", args, output)
+		}
+		if lines := strings.Count(output, "\n"); lines != 3 {
+			t.Errorf("Plain output for %v should have exactly 3 lines, got %d", args, lines)
+		}
+	}
+}
+
+func TestPrettyOutput(t *testing.T) {
+	output, err := executeCommand(t, "", "-7", "-n", "2", "--pretty")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 2 UUIDs and a summary, got %d lines: %q", len(lines), output)
+	}
+	if !strings.Contains(lines[0], ansiHighlight+"7"+ansiReset) {
+		t.Errorf("Pretty output should highlight the version digit, got: %q", lines[0])
+	}
+	if lines[2] != dim("2 UUIDs generated") {
+		t.Errorf("Unexpected summary line: %q", lines[2])
+	}
+}
+
+func TestPrettyOutputWithoutColor(t *testing.T) {
+	tests := []struct {
+		name, noColor, term string
+	}{
+		{"NO_COLOR", "1", "xterm-256color"},
+		{"TERM=dumb", "", "dumb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("TERM", tt.term)
+
+			output, err := executeCommand(t, "", "-7", "-n", "2", "--pretty")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Contains(output, "\x1b") {
+				t.Errorf("Expected no escape sequences, got: %q", output)
+			}
+			// The layout is still pretty, just uncoloured
+			if !strings.HasSuffix(output, "\n2 UUIDs generated\n") {
+				t.Errorf("Expected the summary line without colour, got: %q", output)
+			}
+		})
+	}
+}
+
+func TestPrettyOutputSingleUUIDHasNoSummary(t *testing.T) {
+	output, err := executeCommand(t, "", "--pretty")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Count(output, "\n") != 1 {
+		t.Errorf("A single UUID should not get a summary line, got: %q", output)
+	}
+}
+
+func TestPrettyOutputLeavesJSONAlone(t *testing.T) {
+	output, err := executeCommand(t, "", "-n", "2", "--json", "--pretty")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(output, "\x1b") || strings.Count(output, "\n") != 2 {
+		t.Errorf("JSON output should never be decorated, got: %q", output)
+	}
+}
+
+func TestPlainAndPrettyConflict(t *testing.T) {
+	_, err := executeCommand(t, "", "--plain", "--pretty")
+	if err == nil {
+		t.Error("Expected error when both --plain and --pretty are given")
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if isTerminal(f) {
+		t.Error("A regular file should not be detected as a terminal")
+	}
+	if isTerminal(&strings.Builder{}) {
+		t.Error("A non-file writer should not be detected as a terminal")
+	}
+}
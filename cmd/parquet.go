@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/scottbrown/uuid/internal/parquet"
+	"github.com/spf13/cobra"
+)
+
+// defaultParquetRowGroup is the --parquet-row-group default: about 16 MB of
+// UUIDs per row group, which Spark and pyarrow read comfortably
+const defaultParquetRowGroup = 1_000_000
+
+// parquetTypes maps --parquet-type values to the UUID column's kind
+var parquetTypes = map[string]parquet.ColumnKind{
+	"uuid":   parquet.UUIDColumn,
+	"string": parquet.StringColumn,
+}
+
+// parquetConflicts are the flags that shape the text output or pick another
+// file format
+var parquetConflicts = append([]string{"pg-copy", "pg-copy-binary", "pg-copy-columns"}, pgCopyConflicts...)
+
+// writeParquet writes count UUIDs from generate to the --output Parquet
+// file, with a timestamp column alongside when withTimestamp is set. Rows
+// are streamed, so only one row group is held in memory.
+func writeParquet(cmd *cobra.Command, generate func() string, count int, withTimestamp bool) error {
+	typeName, _ := cmd.Flags().GetString("parquet-type")
+	rowGroup, _ := cmd.Flags().GetInt("parquet-row-group")
+	path, _ := cmd.Flags().GetString("output")
+
+	kind, ok := parquetTypes[typeName]
+	if !ok {
+		return fmt.Errorf("unknown --parquet-type '%s'. Available types: uuid, string", typeName)
+	}
+	if rowGroup < 1 {
+		return fmt.Errorf("--parquet-row-group must be at least 1, got %d", rowGroup)
+	}
+	columns := []parquet.Column{{Name: "uuid", Kind: kind}}
+	if withTimestamp {
+		columns = append(columns, parquet.Column{Name: "timestamp", Kind: parquet.TimestampMillisColumn})
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	buffered := bufio.NewWriter(file)
+	w, err := parquet.NewWriter(buffered, columns, rowGroup)
+	if err != nil {
+		return err
+	}
+
+	row := make([]any, len(columns))
+	for range count {
+		value := generate()
+		uuid, err := generator.ParseUUID(value)
+		if err != nil {
+			return err
+		}
+		row[0] = uuid
+		if kind == parquet.StringColumn {
+			row[0] = value
+		}
+		if withTimestamp {
+			row[1] = v7Time(uuid)
+		}
+		if err := w.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	debugLogger.Debug("wrote parquet file", "path", path, "rows", count, "type", typeName, "row_group", rowGroup)
+	return file.Close()
+}
+
+// v7Time returns the Unix millisecond timestamp in the first 48 bits of a
+// UUIDv7. Unlike generator.Inspect it does not check the variant bits, which
+// --variant --force may have rewritten.
+func v7Time(uuid [16]byte) time.Time {
+	var ms [8]byte
+	copy(ms[2:], uuid[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:]))).UTC()
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/scottbrown/uuid/internal/parquet"
+)
+
+// readParquetFile reads back a file written by --parquet
+func readParquetFile(t *testing.T, path string) *parquet.File {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.Read(data)
+	if err != nil {
+		t.Fatalf("Cannot read %s: %v", path, err)
+	}
+	return f
+}
+
+func TestParquetV7WithTimestamps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.parquet")
+	before := time.Now().Truncate(time.Millisecond)
+	output, err := executeCommand(t, "", "-7", "-n", "2500", "--parquet", "-o", path, "--parquet-row-group", "1000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("Expected no output, got %q", output)
+	}
+
+	f := readParquetFile(t, path)
+	want := []parquet.Column{{Name: "uuid", Kind: parquet.UUIDColumn}, {Name: "timestamp", Kind: parquet.TimestampMillisColumn}}
+	if len(f.Columns) != 2 || f.Columns[0] != want[0] || f.Columns[1] != want[1] {
+		t.Fatalf("Expected columns %v, got %v", want, f.Columns)
+	}
+	if f.NumRows() != 2500 || len(f.RowGroups) != 3 {
+		t.Fatalf("Expected 2500 rows in 3 row groups, got %v", f.RowGroups)
+	}
+	seen := map[[16]byte]bool{}
+	for i, value := range f.Values[0] {
+		uuid := value.([16]byte)
+		d := generator.Inspect(uuid)
+		if d.Version != 7 {
+			t.Fatalf("Expected UUIDv7 values, got %s", generator.FormatUUID(uuid))
+		}
+		ts := f.Values[1][i].(time.Time)
+		if !ts.Equal(*d.Timestamp) || ts.Before(before) {
+			t.Fatalf("Expected row %d's timestamp %v to match its UUID's %v", i, ts, *d.Timestamp)
+		}
+		seen[uuid] = true
+	}
+	if len(seen) != 2500 {
+		t.Errorf("Expected 2500 distinct UUIDs, got %d", len(seen))
+	}
+}
+
+func TestParquetStringColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.parquet")
+	if _, err := executeCommand(t, "", "-n", "10", "--parquet", "--parquet-type", "string", "-o", path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	f := readParquetFile(t, path)
+	if len(f.Columns) != 1 || f.Columns[0].Kind != parquet.StringColumn {
+		t.Fatalf("Expected a single string column for UUIDv4, got %v", f.Columns)
+	}
+	for _, value := range f.Values[0] {
+		if s := value.(string); !uuidRegex.MatchString(s) || s[14] != '4' {
+			t.Errorf("Expected a canonical UUIDv4, got %q", s)
+		}
+	}
+}
+
+func TestParquetFixedTimestamp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.parquet")
+	if _, err := executeCommand(t, "", "-t", "2023-06-14T15:30:45.123Z", "-n", "3", "--parquet", "-o", path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	f := readParquetFile(t, path)
+	want := time.Date(2023, 6, 14, 15, 30, 45, 123000000, time.UTC)
+	for _, value := range f.Values[1] {
+		if ts := value.(time.Time); !ts.Equal(want) {
+			t.Errorf("Expected %v, got %v", want, ts)
+		}
+	}
+}
+
+func TestParquetErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.parquet")
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"without output", []string{"--parquet"}, "--parquet writes a binary file and requires -o/--output"},
+		{"type alone", []string{"--parquet-type", "string"}, "--parquet-type and --parquet-row-group require --parquet"},
+		{"unknown type", []string{"--parquet", "-o", path, "--parquet-type", "binary"}, "unknown --parquet-type 'binary'. Available types: uuid, string"},
+		{"bad row group", []string{"--parquet", "-o", path, "--parquet-row-group", "0"}, "--parquet-row-group must be at least 1, got 0"},
+		{"with pg-copy", []string{"--parquet", "-o", path, "--pg-copy"}, "--parquet cannot be combined with --pg-copy"},
+		{"with emit", []string{"--parquet", "-o", path, "--emit", "canonical"}, "--parquet cannot be combined with --emit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
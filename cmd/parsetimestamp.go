@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// parseTimestampCmd shows how -t would read a timestamp
+var parseTimestampCmd = &cobra.Command{
+	Use:   "parse-timestamp <value>",
+	Short: "Show how a -t timestamp is interpreted",
+	Long: `Show how a timestamp given to -t is interpreted, without generating a UUID.
+
+The value goes through exactly the parser that -t, --now, and the other
+timestamp flags use. The report names the format that matched, the
+resulting time in UTC, and its Unix milliseconds, followed by the formats
+tried before it and why each did not match. When no format matches, every
+format is listed with its reason and the command exits non-zero.
+
+Formats, in the order they are tried:
+
+  unix-seconds        exactly 10 digits
+  unix-milliseconds   exactly 13 digits
+  rfc3339             2006-01-02T15:04:05Z or with an offset
+  rfc3339-no-offset   2006-01-02T15:04:05, as UTC
+  iso-date            2006-01-02, as midnight UTC
+  date-time           2006-01-02 15:04:05, as UTC
+  iso-week-date       2006-W01-1, ISO 8601 week and weekday, as midnight UTC
+  iso-week            2006-W01, the Monday of that ISO 8601 week
+  unix-integer        any other integer: milliseconds above 1e12, else seconds
+
+Formats registered in ` + timestampLayoutsFile + ` under the user configuration
+directory, a JSON object mapping names to Go reference layouts such as
+{"dmy": "02/01/2006 15:04"}, are tried after these. With --layout, as with
+-t, only that Go reference layout is used.
+
+Examples:
+  uuid parse-timestamp 1686742245
+  uuid parse-timestamp "2023-06-14 10:30"`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var explanation generator.TimestampExplanation
+		var err error
+		if layout, _ := cmd.Flags().GetString("layout"); layout != "" {
+			explanation = generator.TimestampExplanation{Input: args[0], Format: "layout '" + layout + "'"}
+			explanation.Time, err = generator.ParseTimestampLayout(args[0], layout)
+		} else {
+			explanation, err = explainTimestamp(args[0])
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "Input:\t%s\n", explanation.Input)
+		if err == nil {
+			fmt.Fprintf(w, "Format:\t%s\n", explanation.Format)
+			fmt.Fprintf(w, "UTC:\t%s\n", explanation.Time.Format(time.RFC3339Nano))
+			fmt.Fprintf(w, "Unix ms:\t%d\n", explanation.Time.UnixMilli())
+		}
+		for i, attempt := range explanation.Attempts {
+			label := ""
+			if i == 0 {
+				label = "Tried:"
+			}
+			fmt.Fprintf(w, "%s\t%s: %v\n", label, attempt.Format, attempt.Err)
+		}
+		if flushErr := w.Flush(); flushErr != nil {
+			return flushErr
+		}
+		return err
+	},
+}
+
+func init() {
+	parseTimestampCmd.Flags().String("layout", "", "Parse with this Go reference layout instead of trying every format, as -t --layout does")
+	rootCmd.AddCommand(parseTimestampCmd)
+}
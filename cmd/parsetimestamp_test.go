@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTimestampReport(t *testing.T) {
+	output, err := executeCommand(t, "", "parse-timestamp", "2023-06-14")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `Input:    2023-06-14
+Format:   iso-date
+UTC:      2023-06-14T00:00:00Z
+Unix ms:  1686700800000
+Tried:    unix-seconds: not an integer
+          unix-milliseconds: not 13 characters long
+          rfc3339: cannot parse "" as "T"
+          rfc3339-no-offset: cannot parse "" as "T"
+`
+	if output != expected {
+		t.Errorf("Unexpected report:\n%s", output)
+	}
+}
+
+func TestParseTimestampMatchesFlag(t *testing.T) {
+	// The report and -t must agree, since both use the same parser
+	output, err := executeCommand(t, "", "parse-timestamp", "1645557742000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "Format:   unix-milliseconds\nUTC:      2022-02-22T19:22:22Z\n") {
+		t.Errorf("Unexpected report:\n%s", output)
+	}
+
+	generated, err := executeCommand(t, "", "-t", "1645557742000")
+	if err != nil || !strings.HasPrefix(generated, "017f22e2-79b0-7") {
+		t.Errorf("Expected -t to embed the same time, got %q (%v)", generated, err)
+	}
+}
+
+func TestParseTimestampFailure(t *testing.T) {
+	stdout, _, err := executeCommandSplit(t, "", "parse-timestamp", "2023-06-14 10:30")
+	if err == nil || !strings.Contains(err.Error(), "unable to parse timestamp '2023-06-14 10:30'") {
+		t.Fatalf("Expected a parse error, got %v", err)
+	}
+	if strings.Contains(stdout, "Format:") {
+		t.Errorf("Expected no matched format, got:\n%s", stdout)
+	}
+	for _, reason := range []string{
+		"Tried:  unix-seconds: not 10 characters long\n",
+		"date-time: cannot parse \"\" as \":\"\n",
+		"unix-integer: not an integer\n",
+	} {
+		if !strings.Contains(stdout, reason) {
+			t.Errorf("Expected %q in the report, got:\n%s", reason, stdout)
+		}
+	}
+}
+
+func TestParseTimestampLayout(t *testing.T) {
+	output, err := executeCommand(t, "", "parse-timestamp", "14/06/2023", "--layout", "02/01/2006")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `Input:    14/06/2023
+Format:   layout '02/01/2006'
+UTC:      2023-06-14T00:00:00Z
+Unix ms:  1686700800000
+`
+	if output != expected {
+		t.Errorf("Unexpected report:\n%s", output)
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// pgCopySignature starts every COPY binary file: "PGCOPY\n\377\r\n\0"
+var pgCopySignature = []byte("PGCOPY\n\xff\r\n\x00")
+
+// Column kinds accepted by --pg-copy-columns
+const (
+	pgCopyUUID = "uuid"
+	pgCopyNull = "null"
+)
+
+// pgCopyConflicts are the flags that shape the text output, which COPY
+// formats replace
+var pgCopyConflicts = []string{"emit", "format-name", "oid", "check-digit", "header", "json", "per-line", "qr", "phonetic", "group", "upper", "porcelain", "nanoid", "ulid", "corrupt"}
+
+// pgCopyColumns parses --pg-copy-columns, one kind per table column
+func pgCopyColumns(value string) ([]string, error) {
+	columns := strings.Split(value, ",")
+	for i, column := range columns {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column != pgCopyUUID && column != pgCopyNull {
+			return nil, fmt.Errorf("unknown --pg-copy-columns kind '%s': use uuid or null", column)
+		}
+		columns[i] = column
+	}
+	return columns, nil
+}
+
+// writePGCopy writes count rows for PostgreSQL's COPY ... FROM, in the
+// binary format when binaryFormat is set and the text format otherwise. Each
+// uuid column gets a new UUID from generate and each null column is NULL.
+// Output goes to --output when it is set, and to stdout otherwise.
+func writePGCopy(cmd *cobra.Command, generate func() string, count int, binaryFormat bool) error {
+	value, _ := cmd.Flags().GetString("pg-copy-columns")
+	columns, err := pgCopyColumns(value)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	var file *os.File
+	if path, _ := cmd.Flags().GetString("output"); path != "" {
+		if file, err = os.Create(path); err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	w := bufio.NewWriter(out)
+	if binaryFormat {
+		err = writePGCopyBinary(w, generate, count, columns)
+	} else {
+		err = writePGCopyText(w, generate, count, columns)
+	}
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if file != nil {
+		return file.Close()
+	}
+	return nil
+}
+
+// writePGCopyText writes the COPY text format: tab-separated columns, \N for
+// NULL, one row per line. Canonical UUIDs need no escaping.
+func writePGCopyText(w io.Writer, generate func() string, count int, columns []string) error {
+	fields := make([]string, len(columns))
+	for range count {
+		for i, column := range columns {
+			if column == pgCopyNull {
+				fields[i] = `\N`
+			} else {
+				fields[i] = generate()
+			}
+		}
+		if _, err := io.WriteString(w, strings.Join(fields, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePGCopyBinary writes the COPY binary format: the signature, a zero
+// flags field and header extension length, then per row an int16 field count
+// and each field as an int32 length (-1 for NULL) and its bytes, ending with
+// an int16 -1 trailer. All integers are big-endian.
+func writePGCopyBinary(w io.Writer, generate func() string, count int, columns []string) error {
+	header := binary.BigEndian.AppendUint32(append([]byte(nil), pgCopySignature...), 0)
+	header = binary.BigEndian.AppendUint32(header, 0)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]byte, 0, 2+len(columns)*20)
+	for range count {
+		row = binary.BigEndian.AppendUint16(row[:0], uint16(len(columns)))
+		for _, column := range columns {
+			if column == pgCopyNull {
+				row = binary.BigEndian.AppendUint32(row, 0xffffffff)
+				continue
+			}
+			uuid, err := generator.ParseUUID(generate())
+			if err != nil {
+				return err
+			}
+			row = binary.BigEndian.AppendUint32(row, 16)
+			row = append(row, uuid[:]...)
+		}
+		if _, err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write([]byte{0xff, 0xff})
+	return err
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// readPGCopyBinary parses a COPY binary file the way PostgreSQL's COPY FROM
+// does, returning each row's fields with nil for NULL. It rejects anything the
+// server would: a bad signature, unknown critical flag bits, a row whose
+// field count differs from the first, truncation, or data after the trailer.
+func readPGCopyBinary(data []byte) ([][][]byte, error) {
+	r := bytes.NewReader(data)
+	signature := make([]byte, 11)
+	if _, err := io.ReadFull(r, signature); err != nil || !bytes.Equal(signature, []byte("PGCOPY\n\xff\r\n\x00")) {
+		return nil, errors.New("missing COPY binary signature")
+	}
+	var flags, extension uint32
+	if err := binary.Read(r, binary.BigEndian, &flags); err != nil {
+		return nil, err
+	}
+	if flags&0xffff0000 != 0 {
+		return nil, fmt.Errorf("unrecognised critical flags %#x", flags)
+	}
+	if err := binary.Read(r, binary.BigEndian, &extension); err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(int64(extension), io.SeekCurrent); err != nil {
+		return nil, err
+	}
+
+	var rows [][][]byte
+	for {
+		var fieldCount int16
+		if err := binary.Read(r, binary.BigEndian, &fieldCount); err != nil {
+			return nil, fmt.Errorf("missing trailer: %w", err)
+		}
+		if fieldCount == -1 {
+			break
+		}
+		if len(rows) > 0 && int(fieldCount) != len(rows[0]) {
+			return nil, fmt.Errorf("row %d has %d fields, expected %d", len(rows)+1, fieldCount, len(rows[0]))
+		}
+		row := make([][]byte, fieldCount)
+		for i := range row {
+			var length int32
+			if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+				return nil, err
+			}
+			if length == -1 {
+				continue
+			}
+			row[i] = make([]byte, length)
+			if _, err := io.ReadFull(r, row[i]); err != nil {
+				return nil, fmt.Errorf("truncated field: %w", err)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d bytes after the trailer", r.Len())
+	}
+	return rows, nil
+}
+
+func TestPGCopyBinaryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.copy")
+	output, err := executeCommand(t, "", "-7", "-n", "50", "--pg-copy-binary", "-o", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("Expected nothing on stdout with -o, got %q", output)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := []byte("PGCOPY\n\xff\r\n\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+	if !bytes.HasPrefix(data, header) {
+		t.Fatalf("Expected the 19-byte COPY header, got % x", data[:min(len(data), 19)])
+	}
+	if !bytes.HasSuffix(data, []byte{0xff, 0xff}) {
+		t.Fatalf("Expected the int16 -1 trailer, got % x", data[len(data)-2:])
+	}
+	if len(data) != 19+50*(2+4+16)+2 {
+		t.Errorf("Expected %d bytes, got %d", 19+50*(2+4+16)+2, len(data))
+	}
+
+	rows, err := readPGCopyBinary(data)
+	if err != nil {
+		t.Fatalf("Cannot read COPY data: %v", err)
+	}
+	if len(rows) != 50 {
+		t.Fatalf("Expected 50 rows, got %d", len(rows))
+	}
+	seen := map[string]bool{}
+	for _, row := range rows {
+		if len(row) != 1 || len(row[0]) != 16 {
+			t.Fatalf("Expected one 16-byte field per row, got %v", row)
+		}
+		uuid := generator.FormatUUID([16]byte(row[0]))
+		if uuid[14] != '7' || generator.Validate(uuid) != nil {
+			t.Errorf("Expected a valid UUIDv7, got %s", uuid)
+		}
+		seen[uuid] = true
+	}
+	if len(seen) != len(rows) {
+		t.Errorf("Expected distinct UUIDs, got %d of %d", len(seen), len(rows))
+	}
+}
+
+func TestPGCopyBinaryColumns(t *testing.T) {
+	output, _, err := executeCommandSplit(t, "", "-n", "3", "--pg-copy-binary", "--pg-copy-columns", "uuid,null,uuid")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows, err := readPGCopyBinary([]byte(output))
+	if err != nil {
+		t.Fatalf("Cannot read COPY data: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+	for _, row := range rows {
+		if len(row) != 3 || len(row[0]) != 16 || row[1] != nil || len(row[2]) != 16 {
+			t.Errorf("Expected uuid, NULL, uuid fields, got %v", row)
+		}
+		if bytes.Equal(row[0], row[2]) {
+			t.Errorf("Expected each uuid column to get its own UUID")
+		}
+	}
+}
+
+func TestPGCopyBinaryReaderRejectsBadFraming(t *testing.T) {
+	output, _, err := executeCommandSplit(t, "", "-n", "2", "--pg-copy-binary")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	good := []byte(output)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"signature", append([]byte("PGCOPY\n\xff\r\n\x01"), good[11:]...)},
+		{"truncated field", good[:19+2+4+8]},
+		{"no trailer", good[:len(good)-2]},
+		{"data after trailer", append(append([]byte(nil), good...), 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readPGCopyBinary(tt.data); err == nil {
+				t.Errorf("Expected the reader to reject the data")
+			}
+		})
+	}
+}
+
+func TestPGCopyText(t *testing.T) {
+	output, err := executeCommand(t, "", "-n", "4", "--pg-copy", "--pg-copy-columns", "uuid, NULL")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 rows, got %q", output)
+	}
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 || !uuidRegex.MatchString(fields[0]) || fields[1] != `\N` {
+			t.Errorf("Expected a UUID and \\N, got %q", line)
+		}
+	}
+
+	output, err = executeCommand(t, "", "--pg-copy")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !uuidRegex.MatchString(strings.TrimSuffix(output, "\n")) {
+		t.Errorf("Expected one UUID per row by default, got %q", output)
+	}
+}
+
+func TestPGCopyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"both formats", []string{"--pg-copy", "--pg-copy-binary"}, "[pg-copy pg-copy-binary] were all set"},
+		{"columns alone", []string{"--pg-copy-columns", "uuid"}, "--pg-copy-columns requires --pg-copy or --pg-copy-binary"},
+		{"unknown column", []string{"--pg-copy", "--pg-copy-columns", "uuid,text"}, "unknown --pg-copy-columns kind 'text': use uuid or null"},
+		{"with emit", []string{"--pg-copy", "--emit", "canonical"}, "--pg-copy cannot be combined with --emit"},
+		{"binary with json", []string{"--pg-copy-binary", "--json"}, "--pg-copy-binary cannot be combined with --json"},
+		{"with nanoid", []string{"--pg-copy", "--nanoid"}, "--pg-copy cannot be combined with --nanoid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// poolMagic and poolFormat start the header line of every pool file
+const (
+	poolMagic  = "uuid-pool"
+	poolFormat = "1"
+)
+
+// uuidPool is a pool file: UUIDs minted up front and handed out in order by
+// take. The first consumed values are spent; the rest remain.
+type uuidPool struct {
+	version  int
+	created  time.Time
+	consumed int
+	values   []string
+}
+
+func (p *uuidPool) remaining() int {
+	return len(p.values) - p.consumed
+}
+
+// header returns the header line without its checksum field
+func (p *uuidPool) header() string {
+	return fmt.Sprintf("%s %s version=%d size=%d created=%s consumed=%d",
+		poolMagic, poolFormat, p.version, len(p.values), p.created.UTC().Format(time.RFC3339), p.consumed)
+}
+
+// encode renders the pool file. The checksum covers the rest of the header
+// and every value, so a damaged or hand-edited pool is refused.
+func (p *uuidPool) encode() []byte {
+	var body bytes.Buffer
+	for _, value := range p.values {
+		body.WriteString(value)
+		body.WriteByte('\n')
+	}
+	header := p.header()
+	sum := sha256.Sum256(append([]byte(header+"\n"), body.Bytes()...))
+	return append([]byte(fmt.Sprintf("%s sha256=%s\n", header, hex.EncodeToString(sum[:]))), body.Bytes()...)
+}
+
+// decodePool parses and verifies a pool file
+func decodePool(path string, data []byte) (*uuidPool, error) {
+	corrupt := func(format string, args ...any) error {
+		return fmt.Errorf("pool %s is corrupt: %s", path, fmt.Sprintf(format, args...))
+	}
+	headerLine, body, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, corrupt("missing header")
+	}
+	fields := strings.Fields(string(headerLine))
+	if len(fields) < 2 || fields[0] != poolMagic {
+		return nil, fmt.Errorf("%s is not a uuid pool file", path)
+	}
+	if fields[1] != poolFormat {
+		return nil, fmt.Errorf("pool %s has format %s; this uuid reads format %s", path, fields[1], poolFormat)
+	}
+	header := map[string]string{}
+	for _, field := range fields[2:] {
+		key, value, _ := strings.Cut(field, "=")
+		header[key] = value
+	}
+
+	checksum := header["sha256"]
+	unsigned, _, _ := strings.Cut(string(headerLine), " sha256=")
+	sum := sha256.Sum256(append([]byte(unsigned+"\n"), body...))
+	if checksum != hex.EncodeToString(sum[:]) {
+		return nil, corrupt("checksum mismatch")
+	}
+
+	p := &uuidPool{}
+	var size int
+	var err error
+	if p.version, err = strconv.Atoi(header["version"]); err != nil {
+		return nil, corrupt("invalid version '%s'", header["version"])
+	}
+	if size, err = strconv.Atoi(header["size"]); err != nil {
+		return nil, corrupt("invalid size '%s'", header["size"])
+	}
+	if p.consumed, err = strconv.Atoi(header["consumed"]); err != nil || p.consumed < 0 || p.consumed > size {
+		return nil, corrupt("invalid consumed count '%s'", header["consumed"])
+	}
+	if p.created, err = time.Parse(time.RFC3339, header["created"]); err != nil {
+		return nil, corrupt("invalid created time '%s'", header["created"])
+	}
+	p.values = strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	if len(body) == 0 {
+		p.values = nil
+	}
+	if len(p.values) != size {
+		return nil, corrupt("header says %d UUIDs, file holds %d", size, len(p.values))
+	}
+	return p, nil
+}
+
+// readPool reads and verifies the pool at path
+func readPool(path string) (*uuidPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodePool(path, data)
+}
+
+// writePool replaces the pool at path with replaceFile, which syncs it
+// before the rename, so a crash never leaves a partial pool behind. The
+// temporary file beside path has a fixed name, so the caller must hold the
+// pool's lock.
+func writePool(path string, p *uuidPool) error {
+	return replaceFile(path, p.encode())
+}
+
+// lockPool locks the pool at path for a read-modify-write with
+// lockStateFile. A missing pool is an error, reported before anything,
+// including the lock file, is created beside it.
+func lockPool(path string) (unlock func(), err error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no such pool %s", path)
+	} else if err != nil {
+		return nil, err
+	}
+	return lockStateFile(path)
+}
+
+// createPool writes a pool of size UUIDs from generate to path
+func createPool(path string, size, version int, generate func() string, force bool) error {
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("pool %s already exists; use --force to replace it", path)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	p := &uuidPool{version: version, created: now(), values: make([]string, size)}
+	for i := range p.values {
+		p.values[i] = generate()
+	}
+	debugLogger.Debug("created pool", "path", path, "size", size, "version", version)
+	return writePool(path, p)
+}
+
+// takeFromPool removes the next n UUIDs from the pool at path and returns
+// them. The pool is locked for the whole read-modify-write and the values
+// are marked consumed before they are returned, so concurrent takers never
+// receive the same UUID. Nothing is taken when fewer than n remain.
+func takeFromPool(path string, n int) ([]string, error) {
+	unlock, err := lockPool(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	p, err := readPool(path)
+	if err != nil {
+		return nil, err
+	}
+	if remaining := p.remaining(); remaining < n {
+		if remaining == 0 {
+			return nil, fmt.Errorf("pool %s is exhausted: 0 of %d UUIDs remaining", path, len(p.values))
+		}
+		return nil, fmt.Errorf("pool %s has only %d UUIDs remaining, cannot take %d", path, remaining, n)
+	}
+	taken := p.values[p.consumed : p.consumed+n]
+	p.consumed += n
+	if err := writePool(path, p); err != nil {
+		return nil, err
+	}
+	debugLogger.Debug("took from pool", "path", path, "taken", n, "remaining", p.remaining())
+	return taken, nil
+}
+
+// poolCmd groups the pool subcommands
+var poolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Create and inspect pools of pre-generated UUIDs",
+	Long: `Create and inspect pools of pre-generated UUIDs for 'uuid take'.
+
+A pool lets IDs be minted centrally, carried to an air-gapped system, and
+consumed there one at a time with no reuse. The pool file is text: a header
+line recording the format, UUID version, size, creation time, consumed count,
+and a SHA-256 checksum, then one UUID per line. A pool whose checksum does not
+match is refused.
+
+Examples:
+  uuid pool create --size 10000 --out pool.db
+  uuid pool create --size 500 --out pool.db -7 --force
+  uuid pool status --pool pool.db
+  uuid take --pool pool.db -n 5`,
+	Args: cobra.NoArgs,
+}
+
+// poolCreateCmd writes a new pool file
+var poolCreateCmd = &cobra.Command{
+	Use:          "create --size N --out FILE",
+	Short:        "Generate a pool file",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		size, _ := cmd.Flags().GetInt("size")
+		out, _ := cmd.Flags().GetString("out")
+		force, _ := cmd.Flags().GetBool("force")
+		v6, _ := cmd.Flags().GetBool("6")
+		v7, _ := cmd.Flags().GetBool("7")
+
+		if size < 1 {
+			return fmt.Errorf("--size must be at least 1, got %d", size)
+		}
+		version, generate := 4, generator.GenerateUUIDv4
+		if v6 {
+			version, generate = 6, generator.GenerateUUIDv6
+		} else if v7 {
+			version, generate = 7, generator.GenerateUUIDv7
+		}
+		if err := createPool(out, size, version, generate, force); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "created %s with %d UUIDv%d values\n", out, size, version)
+		return nil
+	},
+}
+
+// poolStatusCmd reports how much of a pool is left
+var poolStatusCmd = &cobra.Command{
+	Use:          "status --pool FILE",
+	Short:        "Report remaining and consumed UUIDs in a pool",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("pool")
+		unlock, err := lockPool(path)
+		if err != nil {
+			return err
+		}
+		p, err := readPool(path)
+		unlock()
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "version:   %d\n", p.version)
+		fmt.Fprintf(out, "created:   %s\n", p.created.UTC().Format(time.RFC3339))
+		fmt.Fprintf(out, "size:      %d\n", len(p.values))
+		fmt.Fprintf(out, "consumed:  %d\n", p.consumed)
+		fmt.Fprintf(out, "remaining: %d\n", p.remaining())
+		return nil
+	},
+}
+
+// takeCmd hands out UUIDs from a pool
+var takeCmd = &cobra.Command{
+	Use:   "take --pool FILE",
+	Short: "Take unused UUIDs from a pool",
+	Long: `Take the next unused UUIDs from a pool created by 'uuid pool create',
+printing them one per line and marking them consumed.
+
+The pool is locked while it is updated, so concurrent takers never receive
+the same UUID, and the values are recorded as consumed before they are
+printed. When fewer than -n remain nothing is taken and the remaining count
+is reported.
+
+Examples:
+  uuid take --pool pool.db
+  uuid take --pool pool.db -n 5`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("pool")
+		count, _ := cmd.Flags().GetInt("count")
+		if count < 1 {
+			return fmt.Errorf("count must be at least 1, got %d", count)
+		}
+		taken, err := takeFromPool(path, count)
+		if err != nil {
+			return err
+		}
+		for _, value := range taken {
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+		}
+		return nil
+	},
+}
+
+func init() {
+	poolCreateCmd.Flags().Int("size", 0, "Number of UUIDs in the pool")
+	poolCreateCmd.Flags().String("out", "", "Pool file to write")
+	poolCreateCmd.Flags().Bool("force", false, "Replace an existing pool file")
+	poolCreateCmd.Flags().BoolP("4", "4", false, "Generate UUIDv4 (default)")
+	poolCreateCmd.Flags().BoolP("6", "6", false, "Generate UUIDv6")
+	poolCreateCmd.Flags().BoolP("7", "7", false, "Generate UUIDv7 (contains timestamp)")
+	poolCreateCmd.MarkFlagsMutuallyExclusive("4", "6", "7")
+	_ = poolCreateCmd.MarkFlagRequired("size")
+	_ = poolCreateCmd.MarkFlagRequired("out")
+
+	poolStatusCmd.Flags().String("pool", "", "Pool file to inspect")
+	_ = poolStatusCmd.MarkFlagRequired("pool")
+
+	takeCmd.Flags().String("pool", "", "Pool file to take from")
+	takeCmd.Flags().IntP("count", "n", 1, "Number of UUIDs to take")
+	_ = takeCmd.MarkFlagRequired("pool")
+
+	poolCmd.AddCommand(poolCreateCmd, poolStatusCmd)
+	rootCmd.AddCommand(poolCmd, takeCmd)
+}
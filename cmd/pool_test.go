@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// createTestPool writes a pool of size UUIDs and returns its path
+func createTestPool(t *testing.T, size string, extra ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pool.db")
+	args := append([]string{"pool", "create", "--size", size, "--out", path}, extra...)
+	if _, err := executeCommand(t, "", args...); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return path
+}
+
+func TestPoolTakeAndStatus(t *testing.T) {
+	path := createTestPool(t, "10", "-7")
+
+	first, err := executeCommand(t, "", "take", "--pool", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	next, err := executeCommand(t, "", "take", "--pool", path, "-n", "5")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	taken := strings.Split(strings.TrimSpace(first+next), "\n")
+	if len(taken) != 6 {
+		t.Fatalf("Expected 6 UUIDs, got %d", len(taken))
+	}
+	for _, value := range taken {
+		if !uuidRegex.MatchString(value) || value[14] != '7' {
+			t.Errorf("Expected a UUIDv7, got %q", value)
+		}
+	}
+
+	// Values come out in pool order
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(string(data), "\n")
+	if strings.Join(lines[1:7], "\n") != strings.Join(taken, "\n") {
+		t.Errorf("Expected the first six pool values in order")
+	}
+
+	output, err := executeCommand(t, "", "pool", "status", "--pool", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"version:   7\n", "size:      10\n", "consumed:  6\n", "remaining: 4\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in status, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestPoolExhaustion(t *testing.T) {
+	path := createTestPool(t, "3")
+
+	_, err := executeCommand(t, "", "take", "--pool", path, "-n", "5")
+	if err == nil || err.Error() != "pool "+path+" has only 3 UUIDs remaining, cannot take 5" {
+		t.Errorf("Expected a remaining count error, got: %v", err)
+	}
+	// A failed take consumes nothing
+	output, err := executeCommand(t, "", "take", "--pool", path, "-n", "3")
+	if err != nil || len(strings.Fields(output)) != 3 {
+		t.Fatalf("Expected all 3 UUIDs, got %q, %v", output, err)
+	}
+	_, err = executeCommand(t, "", "take", "--pool", path)
+	if err == nil || err.Error() != "pool "+path+" is exhausted: 0 of 3 UUIDs remaining" {
+		t.Errorf("Expected an exhausted error, got: %v", err)
+	}
+}
+
+func TestPoolConcurrentTakers(t *testing.T) {
+	const size, takers = 300, 12
+	path := createTestPool(t, "300")
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	exhausted := 0
+	var wg sync.WaitGroup
+	for i := range takers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				taken, err := takeFromPool(path, 1+i%3)
+				mu.Lock()
+				if err != nil {
+					if strings.Contains(err.Error(), "remaining") {
+						exhausted++
+					} else {
+						t.Errorf("Unexpected error: %v", err)
+					}
+					mu.Unlock()
+					return
+				}
+				for _, value := range taken {
+					seen[value]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for value, n := range seen {
+		if n > 1 {
+			t.Errorf("%s was taken %d times", value, n)
+		}
+	}
+	if exhausted != takers {
+		t.Errorf("Expected every taker to stop on exhaustion, got %d of %d", exhausted, takers)
+	}
+	// Takers of 2 or 3 may stop with a value or two left over
+	p, err := readPool(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen)+p.remaining() != size || p.remaining() > 2 {
+		t.Errorf("Expected %d UUIDs taken or left, got %d taken and %d left", size, len(seen), p.remaining())
+	}
+}
+
+func TestPoolCorruption(t *testing.T) {
+	tests := []struct {
+		name     string
+		damage   func(string) string
+		contains string
+	}{
+		{"value edited", func(s string) string {
+			lines := strings.Split(s, "\n")
+			lines[2] = strings.Replace(lines[2], lines[2][:1], "x", 1)
+			return strings.Join(lines, "\n")
+		}, "checksum mismatch"},
+		{"consumed reset", func(s string) string { return strings.Replace(s, "consumed=1", "consumed=0", 1) }, "checksum mismatch"},
+		{"truncated", func(s string) string { return s[:len(s)-37] }, "checksum mismatch"},
+		{"not a pool", func(s string) string { return "hello\n" }, "is not a uuid pool file"},
+		{"newer format", func(s string) string { return strings.Replace(s, "uuid-pool 1", "uuid-pool 2", 1) }, "has format 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := createTestPool(t, "4")
+			if _, err := executeCommand(t, "", "take", "--pool", path); err != nil {
+				t.Fatal(err)
+			}
+			data, _ := os.ReadFile(path)
+			if err := os.WriteFile(path, []byte(tt.damage(string(data))), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			for _, args := range [][]string{{"take", "--pool", path}, {"pool", "status", "--pool", path}} {
+				_, err := executeCommand(t, "", args...)
+				if err == nil || !strings.Contains(err.Error(), tt.contains) {
+					t.Errorf("%s: expected error containing %q, got: %v", args[0], tt.contains, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPoolErrors(t *testing.T) {
+	existing := createTestPool(t, "2")
+	missing := filepath.Join(t.TempDir(), "missing.db")
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"zero size", []string{"pool", "create", "--size", "0", "--out", filepath.Join(t.TempDir(), "p")}, "--size must be at least 1, got 0"},
+		{"missing out", []string{"pool", "create", "--size", "5"}, `required flag(s) "out" not set`},
+		{"existing pool", []string{"pool", "create", "--size", "5", "--out", existing}, "already exists; use --force"},
+		{"two versions", []string{"pool", "create", "--size", "5", "--out", existing, "-6", "-7"}, "none of the others can be"},
+		{"missing pool", []string{"take", "--pool", missing}, "no such pool " + missing},
+		{"missing pool status", []string{"pool", "status", "--pool", missing}, "no such pool " + missing},
+		{"zero count", []string{"take", "--pool", existing, "-n", "0"}, "count must be at least 1, got 0"},
+		{"take without pool", []string{"take"}, `required flag(s) "pool" not set`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(missing)); len(entries) != 0 {
+		t.Errorf("Expected a missing pool to leave nothing behind, found %v", entries)
+	}
+
+	// --force replaces the pool with a fresh one
+	if _, err := executeCommand(t, "", "pool", "create", "--size", "5", "--out", existing, "--force"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p, err := readPool(existing); err != nil || p.remaining() != 5 {
+		t.Errorf("Expected a fresh pool of 5, got %v, %v", p, err)
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// porcelainGolden pins every porcelain v1 format byte for byte. DO NOT CHANGE
+// these golden files or the commands that produce them without adding a new
+// porcelain version: scripts depend on v1 never changing.
+var porcelainGolden = []struct {
+	name  string
+	stdin string
+	args  []string
+}{
+	{"generate", "", []string{"--porcelain", "--from-hex", "d9428888122b41e1b85c61cd3cbb3210"}},
+	{"generate_emit", "", []string{"--porcelain=v1", "--from-hex", "d9428888122b41e1b85c61cd3cbb3210", "--emit", "canonical,hex32,base64"}},
+	{"validate", "c232ab00-9414-11ec-b3c8-9f6bdeced846\nnot-a-uuid\nc232ab00-9414-01ec-b3c8-9f6bdeced846\n", []string{"validate", "--porcelain"}},
+	{"inspect", "", append([]string{"inspect", "--porcelain=v1"}, porcelainInspectUUIDs()...)},
+}
+
+// porcelainInspectUUIDs are the inspect golden UUIDs in version order
+func porcelainInspectUUIDs() []string {
+	var uuids []string
+	for _, version := range []string{"v1", "v3", "v4", "v5", "v6", "v7", "v8"} {
+		uuids = append(uuids, inspectGoldenUUIDs[version])
+	}
+	return uuids
+}
+
+func TestPorcelainV1Golden(t *testing.T) {
+	for _, tt := range porcelainGolden {
+		t.Run(tt.name, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join("testdata", "porcelain_v1_"+tt.name+".txt"))
+			if err != nil {
+				t.Fatalf("Failed to read golden file: %v", err)
+			}
+
+			// Pretty output must never leak into porcelain
+			output, _, _ := executeCommandSplit(t, tt.stdin, append(tt.args, "--plain")...)
+			if output != string(golden) {
+				t.Errorf("porcelain v1 output differs from golden file\ngot:\n%q\nwant:\n%q", output, golden)
+			}
+		})
+	}
+}
+
+func TestPorcelainIsNeverPretty(t *testing.T) {
+	output, err := executeCommand(t, "", "-7", "-n", "3", "--porcelain")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(output, "\x1b[") || strings.Contains(output, "generated") {
+		t.Errorf("Expected plain lines only, got %q", output)
+	}
+}
+
+func TestPorcelainErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"unknown version", []string{"--porcelain=v9"}, "unknown porcelain version 'v9'. Available versions: v1"},
+		{"generate with json", []string{"--porcelain", "--json"}, "--porcelain cannot be combined with --json"},
+		{"generate with pretty", []string{"--porcelain", "--pretty"}, "--porcelain cannot be combined with --pretty"},
+		{"generate with group", []string{"--porcelain", "--group", "4"}, "--porcelain cannot be combined with --group"},
+		{"inspect with jsonl", []string{"inspect", "--porcelain", "--jsonl", inspectGoldenUUIDs["v4"]}, "--porcelain cannot be combined with --jsonl"},
+		{"validate unknown version", []string{"validate", "--porcelain=v0", inspectGoldenUUIDs["v4"]}, "unknown porcelain version 'v0'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
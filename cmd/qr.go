@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/qr"
+	"github.com/spf13/cobra"
+)
+
+// qrLevels maps --qr-level values to error-correction levels
+var qrLevels = map[string]qr.Level{
+	"L": qr.Low,
+	"M": qr.Medium,
+	"Q": qr.Quartile,
+	"H": qr.High,
+}
+
+const (
+	// qrModuleSize is the PNG size of one QR module in pixels
+	qrModuleSize = 8
+	// qrQuietZone is the light border around a QR code, in modules
+	qrQuietZone = 4
+)
+
+// writeQR renders value as a QR code: a PNG when --output is set, otherwise
+// Unicode half-blocks on stdout followed by the value itself so it can still
+// be copied. Dark modules are drawn as spaces, which suits dark terminals.
+func writeQR(cmd *cobra.Command, value string) error {
+	levelName, _ := cmd.Flags().GetString("qr-level")
+	output, _ := cmd.Flags().GetString("output")
+
+	level, ok := qrLevels[strings.ToUpper(levelName)]
+	if !ok {
+		return fmt.Errorf("unknown QR error-correction level '%s'. Available levels: L, M, Q, H", levelName)
+	}
+	modules, err := qr.Encode(value, level)
+	if err != nil {
+		return err
+	}
+	modules = withQuietZone(modules)
+
+	if output != "" {
+		if err := writeQRPNG(modules, output); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), value)
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprint(out, qrHalfBlocks(modules))
+	fmt.Fprintln(out, value)
+	return nil
+}
+
+// withQuietZone surrounds modules with qrQuietZone light modules
+func withQuietZone(modules [][]bool) [][]bool {
+	size := len(modules) + 2*qrQuietZone
+	framed := make([][]bool, size)
+	for y := range framed {
+		framed[y] = make([]bool, size)
+		if y >= qrQuietZone && y < size-qrQuietZone {
+			copy(framed[y][qrQuietZone:], modules[y-qrQuietZone])
+		}
+	}
+	return framed
+}
+
+// qrHalfBlocks draws two rows of modules per line of text, inverted so
+// that dark modules are blank
+func qrHalfBlocks(modules [][]bool) string {
+	var b strings.Builder
+	for y := 0; y < len(modules); y += 2 {
+		for x := range modules[y] {
+			top := modules[y][x]
+			bottom := y+1 < len(modules) && modules[y+1][x]
+			switch {
+			case top && bottom:
+				b.WriteString(" ")
+			case top:
+				b.WriteString("▄")
+			case bottom:
+				b.WriteString("▀")
+			default:
+				b.WriteString("█")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// writeQRPNG writes modules to path as a black-on-white PNG
+func writeQRPNG(modules [][]bool, path string) error {
+	size := len(modules) * qrModuleSize
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dy := 0; dy < qrModuleSize; dy++ {
+				for dx := 0; dx < qrModuleSize; dx++ {
+					img.SetColorIndex(x*qrModuleSize+dx, y*qrModuleSize+dy, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQRTerminalRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"canonical", []string{"--qr", "--plain"}},
+		{"urn", []string{"--qr", "--format-name", "urn", "--qr-level", "H"}},
+		{"base64", []string{"--qr", "--format-name", "base64", "--qr-level", "l"}},
+		{"oid", []string{"--qr", "--oid", "--qr-level", "Q"}},
+		{"from hex", []string{"--qr", "--from-hex", "919108f752d143209bacf847db4148a8"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(t, "", tt.args...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+			value := lines[len(lines)-1]
+
+			decoded, err := decodeQR(parseHalfBlocks(lines[:len(lines)-1]))
+			if err != nil {
+				t.Fatalf("Cannot decode QR code: %v\n%s", err, output)
+			}
+			if decoded != value {
+				t.Errorf("QR payload %q does not match the printed value %q", decoded, value)
+			}
+		})
+	}
+}
+
+func TestQRPNGRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uuid.png")
+	output, err := executeCommand(t, "", "--qr", "-7", "-o", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	value := strings.TrimSpace(output)
+	if !uuidRegex.MatchString(value) {
+		t.Fatalf("Expected only the UUID on stdout, got %q", output)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("Output is not a PNG: %v", err)
+	}
+
+	bounds := img.Bounds()
+	modules := make([][]bool, bounds.Dy()/qrModuleSize)
+	for y := range modules {
+		modules[y] = make([]bool, bounds.Dx()/qrModuleSize)
+		for x := range modules[y] {
+			r, _, _, _ := img.At(x*qrModuleSize+qrModuleSize/2, y*qrModuleSize+qrModuleSize/2).RGBA()
+			modules[y][x] = r < 0x8000
+		}
+	}
+
+	decoded, err := decodeQR(modules)
+	if err != nil {
+		t.Fatalf("Cannot decode PNG QR code: %v", err)
+	}
+	if decoded != value {
+		t.Errorf("QR payload %q does not match %q", decoded, value)
+	}
+}
+
+func TestQRErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"with count", []string{"--qr", "-n", "2"}, "none of the others can be"},
+		{"with per-line", []string{"--qr", "--per-line"}, "none of the others can be"},
+		{"unknown level", []string{"--qr", "--qr-level", "X"}, "unknown QR error-correction level 'X'"},
+		{"output without qr", []string{"-o", "uuid.png"}, "--output requires --qr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+// parseHalfBlocks turns terminal output back into modules (true is dark).
+// Each character covers two rows; dark modules are drawn as spaces.
+func parseHalfBlocks(lines []string) [][]bool {
+	var rows [][]bool
+	for _, line := range lines {
+		var top, bottom []bool
+		for _, r := range line {
+			switch r {
+			case ' ':
+				top, bottom = append(top, true), append(bottom, true)
+			case '█':
+				top, bottom = append(top, false), append(bottom, false)
+			case '▀':
+				top, bottom = append(top, false), append(bottom, true)
+			case '▄':
+				top, bottom = append(top, true), append(bottom, false)
+			}
+		}
+		rows = append(rows, top, bottom)
+	}
+	// The symbol has an odd number of rows, so the last line is half empty
+	return rows[:len(rows)-1]
+}
+
+// qrBlocks gives the data codewords per error-correction block for versions
+// 1-6, from ISO/IEC 18004 table 9
+var qrBlocks = map[int]map[string][]int{
+	1: {"L": {19}, "M": {16}, "Q": {13}, "H": {9}},
+	2: {"L": {34}, "M": {28}, "Q": {22}, "H": {16}},
+	3: {"L": {55}, "M": {44}, "Q": {17, 17}, "H": {13, 13}},
+	4: {"L": {80}, "M": {32, 32}, "Q": {24, 24}, "H": {9, 9, 9, 9}},
+	5: {"L": {108}, "M": {43, 43}, "Q": {15, 15, 16, 16}, "H": {11, 11, 12, 12}},
+	6: {"L": {68, 68}, "M": {27, 27, 27, 27}, "Q": {19, 19, 19, 19}, "H": {15, 15, 15, 15}},
+}
+
+// decodeQR is a minimal QR decoder for undamaged version 1-6 symbols, written
+// independently of the encoder so tests can confirm what a scanner would read.
+// It does no error correction.
+func decodeQR(modules [][]bool) (string, error) {
+	// Crop the quiet zone to the bounding box of dark modules
+	top, left, bottom, right := len(modules), len(modules[0]), -1, -1
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				top, left, bottom, right = min(top, y), min(left, x), max(bottom, y), max(right, x)
+			}
+		}
+	}
+	size := bottom - top + 1
+	if size != right-left+1 || (size-17)%4 != 0 {
+		return "", fmt.Errorf("symbol is %dx%d modules", size, right-left+1)
+	}
+	version := (size - 17) / 4
+	blocks, ok := qrBlocks[version]
+	if !ok {
+		return "", fmt.Errorf("unsupported version %d", version)
+	}
+	get := func(row, col int) bool { return modules[top+row][left+col] }
+
+	// Format information next to the top-left finder pattern
+	format := 0
+	for _, p := range [][2]int{{8, 0}, {8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {8, 7}, {8, 8}, {7, 8}, {5, 8}, {4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}} {
+		format <<= 1
+		if get(p[0], p[1]) {
+			format |= 1
+		}
+	}
+	level, mask := "", -1
+	for data := 0; data < 32; data++ {
+		code := data << 10
+		for bit := 14; bit >= 10; bit-- {
+			if code&(1<<bit) != 0 {
+				code ^= 0x537 << (bit - 10)
+			}
+		}
+		if (data<<10|code)^0x5412 == format {
+			level, mask = [4]string{"M", "L", "H", "Q"}[data>>3], data&7
+		}
+	}
+	if mask < 0 {
+		return "", fmt.Errorf("unreadable format information %015b", format)
+	}
+
+	isFunction := func(row, col int) bool {
+		switch {
+		case row == 6 || col == 6:
+			return true
+		case row <= 8 && (col <= 8 || col >= size-8):
+			return true
+		case row >= size-8 && col <= 8:
+			return true
+		case version > 1 && row >= size-9 && row <= size-5 && col >= size-9 && col <= size-5:
+			return true
+		}
+		return false
+	}
+	masks := []func(i, j int) bool{
+		func(i, j int) bool { return (i+j)%2 == 0 },
+		func(i, j int) bool { return i%2 == 0 },
+		func(i, j int) bool { return j%3 == 0 },
+		func(i, j int) bool { return (i+j)%3 == 0 },
+		func(i, j int) bool { return (i/2+j/3)%2 == 0 },
+		func(i, j int) bool { return (i*j)%2+(i*j)%3 == 0 },
+		func(i, j int) bool { return ((i*j)%2+(i*j)%3)%2 == 0 },
+		func(i, j int) bool { return ((i+j)%2+(i*j)%3)%2 == 0 },
+	}
+
+	// Read the codeword bits in the two-column zigzag from the bottom right
+	var bits []bool
+	upward := true
+	for col := size - 1; col > 0; col -= 2 {
+		if col == 6 {
+			col--
+		}
+		for i := 0; i < size; i++ {
+			row := i
+			if upward {
+				row = size - 1 - i
+			}
+			for _, c := range []int{col, col - 1} {
+				if !isFunction(row, c) {
+					bits = append(bits, get(row, c) != masks[mask](row, c))
+				}
+			}
+		}
+		upward = !upward
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i := range codewords {
+		for _, bit := range bits[i*8 : i*8+8] {
+			codewords[i] <<= 1
+			if bit {
+				codewords[i] |= 1
+			}
+		}
+	}
+
+	// De-interleave the data codewords; error-correction codewords follow
+	sizes := blocks[level]
+	data := make([][]byte, len(sizes))
+	next := 0
+	for i := 0; i < sizes[len(sizes)-1]; i++ {
+		for b, n := range sizes {
+			if i < n {
+				data[b] = append(data[b], codewords[next])
+				next++
+			}
+		}
+	}
+	var stream []bool
+	for _, block := range data {
+		for _, c := range block {
+			for bit := 7; bit >= 0; bit-- {
+				stream = append(stream, c&(1<<bit) != 0)
+			}
+		}
+	}
+
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n && len(stream) > 0; i++ {
+			v <<= 1
+			if stream[0] {
+				v |= 1
+			}
+			stream = stream[1:]
+		}
+		return v
+	}
+
+	// Decode numeric, alphanumeric, and byte segments up to the terminator
+	const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+	var payload strings.Builder
+	for len(stream) >= 4 {
+		switch mode := read(4); mode {
+		case 0:
+			return payload.String(), nil
+		case 1:
+			for n := read(10); n > 0; n -= 3 {
+				digits := min(n, 3)
+				fmt.Fprintf(&payload, "%0*d", digits, read([4]int{0, 4, 7, 10}[digits]))
+			}
+		case 2:
+			for n := read(9); n > 0; n -= 2 {
+				if n == 1 {
+					payload.WriteByte(alphanumeric[read(6)])
+				} else {
+					v := read(11)
+					payload.WriteByte(alphanumeric[v/45])
+					payload.WriteByte(alphanumeric[v%45])
+				}
+			}
+		case 4:
+			for n := read(8); n > 0; n-- {
+				payload.WriteByte(byte(read(8)))
+			}
+		default:
+			return "", fmt.Errorf("unsupported mode %04b", mode)
+		}
+	}
+	return payload.String(), nil
+}
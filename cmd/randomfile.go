@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// withRandomFile wraps run so that, with --random-file, every random byte
+// comes from that file instead of crypto/rand. Generators have no error
+// return and panic when the source runs dry or fails; that panic is turned
+// back into an error, so the command fails rather than falling back.
+func withRandomFile(run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		path, _ := cmd.Flags().GetString("random-file")
+		if path == "" {
+			return run(cmd, args)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("cannot open --random-file: %w", err)
+		}
+		defer f.Close()
+
+		debugLogger.Debug("reading entropy from file", "path", path)
+		generator.SetEntropySource(f)
+		defer generator.SetEntropySource(nil)
+		defer func() {
+			if r := recover(); r != nil {
+				failure, ok := r.(error)
+				if !ok || !errors.Is(failure, generator.ErrEntropyUnavailable) {
+					panic(r)
+				}
+				err = fmt.Errorf("cannot read random bytes from --random-file '%s': %w", path, failure)
+			}
+		}()
+		return run(cmd, args)
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeRandomFile writes content to a file for --random-file
+func writeRandomFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "random.bin")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRandomFile(t *testing.T) {
+	// Every byte of the stream survives except the version and variant bits
+	path := writeRandomFile(t, strings.Repeat("\xff", 16)+strings.Repeat("\x00", 16))
+	output, err := executeCommand(t, "", "-n", "2", "--random-file", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := "ffffffff-ffff-4fff-bfff-ffffffffffff\n00000000-0000-4000-8000-000000000000\n"; output != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, output)
+	}
+
+	path = writeRandomFile(t, "0123456789abcdef")
+	output, err = executeCommand(t, "", "-7", "--random-file", path, "--now", "2024-03-01T10:04:12.345Z")
+	if err != nil || output != "018df978-aeb9-7637-b839-616263646566\n" {
+		t.Errorf("Expected a UUIDv7 whose random bits come from the file, got %q, %v", output, err)
+	}
+}
+
+func TestRandomFileErrors(t *testing.T) {
+	short := writeRandomFile(t, strings.Repeat("\x00", 20))
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"runs short", []string{"-n", "2", "--random-file", short}, "cannot read random bytes from --random-file '" + short + "': entropy source failed after 3 attempts"},
+		{"missing", []string{"--random-file", filepath.Join(t.TempDir(), "none")}, "cannot open --random-file"},
+		{"with seed", []string{"--random-file", short, "--seed", "1"}, "none of the others can be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+
+	// crypto/rand is back once the command returns
+	if _, err := executeCommand(t, "", "-n", "3"); err != nil {
+		t.Errorf("Expected generation to recover after a failed --random-file, got: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket per client: each holds up to burst tokens,
+// refilled at rate tokens a second, and a request spends one
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// parseRate reads a --rate-limit of the form N/s, N/m, or N/h as requests
+// per second, also returning N
+func parseRate(value string) (perSecond float64, n int, err error) {
+	count, unit, ok := strings.Cut(value, "/")
+	n, convErr := strconv.Atoi(count)
+	per := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}[unit]
+	if !ok || convErr != nil || n < 1 || per == 0 {
+		return 0, 0, fmt.Errorf("invalid --rate-limit '%s': use N/s, N/m, or N/h with N at least 1", value)
+	}
+	return float64(n) / per.Seconds(), n, nil
+}
+
+// allow spends a token of client's bucket, or reports how long until one is
+// available
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops the buckets that have refilled completely, at most once per
+// refill period. A full bucket behaves exactly like a new one, so this
+// bounds memory by the clients seen in one period without changing limits.
+func (l *rateLimiter) sweep(now time.Time) {
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+	l.lastSweep = now
+	for client, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// limit answers 429 with Retry-After once the client of a request has spent
+// its tokens, counting allowed and throttled requests under endpoint
+func (s *server) limit(endpoint string, next http.HandlerFunc) http.HandlerFunc {
+	if s.limiter == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ok, wait := s.limiter.allow(s.clientIP(r))
+		if !ok {
+			s.metrics.add("uuid_ratelimit_throttled_total", 1, "endpoint", endpoint)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeServeError(w, http.StatusTooManyRequests, "rate_limited", fmt.Sprintf("rate limit exceeded; retry in %s", wait.Round(time.Millisecond)))
+			return
+		}
+		s.metrics.add("uuid_ratelimit_allowed_total", 1, "endpoint", endpoint)
+		next(w, r)
+	}
+}
+
+// clientIP is the address a request is limited under: the peer address, or
+// with --trust-proxy the last X-Forwarded-For entry, which is the one the
+// nearest proxy recorded and so cannot be forged by the client
+func (s *server) clientIP(r *http.Request) string {
+	if s.trustProxy {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			entries := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeRateLimitPerClient(t *testing.T) {
+	s := newTestServer(t, "--rate-limit", "10/s", "--burst", "5")
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.limiter.now = func() time.Time { return current }
+
+	// The first client exhausts its burst while the second is unaffected
+	for i := 0; i < 5; i++ {
+		if w := s.request("/uuid", "192.0.2.1:40000", nil); w.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected 200, got %d", i+1, w.Code)
+		}
+	}
+	w := s.request("/uuid", "192.0.2.1:40001", nil)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 after the burst, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Expected Retry-After: 1, got %q", got)
+	}
+	if !strings.Contains(w.Body.String(), `"code":"rate_limited"`) {
+		t.Errorf("Expected a rate_limited error, got %s", w.Body)
+	}
+	for i := 0; i < 5; i++ {
+		if w := s.request("/uuid", "198.51.100.7:40000", nil); w.Code != http.StatusOK {
+			t.Fatalf("Other client request %d: expected 200, got %d", i+1, w.Code)
+		}
+	}
+
+	// The health endpoint is never limited
+	if w := s.request("/healthz", "192.0.2.1:40000", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected /healthz to stay open, got %d", w.Code)
+	}
+
+	// Tokens refill at the configured rate
+	current = current.Add(100 * time.Millisecond)
+	if w := s.request("/uuid", "192.0.2.1:40000", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected a refilled token after 100ms, got %d", w.Code)
+	}
+	if w := s.request("/uuid", "192.0.2.1:40000", nil); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected one refilled token only, got %d", w.Code)
+	}
+
+	metrics := s.request("/metrics", "203.0.113.9:1", nil).Body.String()
+	for _, want := range []string{
+		`uuid_ratelimit_allowed_total{endpoint="/uuid"} 11`,
+		`uuid_ratelimit_throttled_total{endpoint="/uuid"} 2`,
+		`uuid_requests_total{endpoint="/uuid",code="429"} 2`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("Expected /metrics to contain %q, got:\n%s", want, metrics)
+		}
+	}
+}
+
+func TestServeRateLimitRetryAfterRoundsUp(t *testing.T) {
+	s := newTestServer(t, "--rate-limit", "1/m")
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.limiter.now = func() time.Time { return current }
+
+	s.request("/uuid", "192.0.2.1:1", nil)
+	current = current.Add(500 * time.Millisecond)
+	w := s.request("/uuid", "192.0.2.1:1", nil)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "60" {
+		t.Errorf("Expected 429 with Retry-After: 60, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+}
+
+func TestServeRateLimitTrustProxy(t *testing.T) {
+	for _, trust := range []bool{false, true} {
+		args := []string{"--rate-limit", "1/h"}
+		if trust {
+			args = append(args, "--trust-proxy")
+		}
+		s := newTestServer(t, args...)
+
+		// Two clients behind the same proxy
+		first := http.Header{"X-Forwarded-For": {"10.0.0.1, 192.0.2.1"}}
+		second := http.Header{"X-Forwarded-For": {"192.0.2.2"}}
+		s.request("/uuid", "203.0.113.1:1", first)
+		w := s.request("/uuid", "203.0.113.1:2", second)
+		if trust && w.Code != http.StatusOK {
+			t.Errorf("With --trust-proxy, expected the second forwarded client to pass, got %d", w.Code)
+		}
+		if !trust && w.Code != http.StatusTooManyRequests {
+			t.Errorf("Without --trust-proxy, expected the shared peer to be limited, got %d", w.Code)
+		}
+		resetFlags(serveCmd)
+	}
+}
+
+func TestRateLimiterEvictsIdleClients(t *testing.T) {
+	l := newRateLimiter(10, 5)
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return current }
+
+	for i := 0; i < 1000; i++ {
+		l.allow(fmt.Sprintf("client-%d", i))
+	}
+	if len(l.buckets) != 1000 {
+		t.Fatalf("Expected 1000 buckets, got %d", len(l.buckets))
+	}
+
+	// Half a second refills a burst of 5 at 10/s, so every bucket is idle
+	current = current.Add(500 * time.Millisecond)
+	l.allow("198.51.100.1")
+	if len(l.buckets) != 1 {
+		t.Errorf("Expected idle buckets to be evicted, %d remain", len(l.buckets))
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		value string
+		rate  float64
+		n     int
+	}{
+		{"100/s", 100, 100},
+		{"60/m", 1, 60},
+		{"3600/h", 1, 3600},
+	}
+	for _, tt := range tests {
+		rate, n, err := parseRate(tt.value)
+		if err != nil || rate != tt.rate || n != tt.n {
+			t.Errorf("parseRate(%q) = %v, %d, %v; want %v, %d", tt.value, rate, n, err, tt.rate, tt.n)
+		}
+	}
+	for _, value := range []string{"100", "0/s", "-1/s", "10/d", "x/s", ""} {
+		if _, _, err := parseRate(value); err == nil {
+			t.Errorf("parseRate(%q): expected an error", value)
+		}
+	}
+}
+
+func TestServeRateLimitFlagErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"bad rate", []string{"serve", "--rate-limit", "fast"}, "invalid --rate-limit 'fast'"},
+		{"burst alone", []string{"serve", "--burst", "5"}, "--burst requires --rate-limit"},
+		{"trust proxy alone", []string{"serve", "--trust-proxy"}, "--trust-proxy requires --rate-limit"},
+		{"zero burst", []string{"serve", "--rate-limit", "1/s", "--burst", "0"}, "--burst must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// retimeCmd replaces the timestamp of a UUIDv7, keeping its random bits
+var retimeCmd = &cobra.Command{
+	Use:   "retime <uuid> -t <timestamp>",
+	Short: "Give a UUIDv7 a corrected timestamp, keeping its random bits",
+	Long: `Print the UUIDv7 with the random bits of <uuid> and the timestamp of -t,
+for when an event's true time is corrected after its ID was issued.
+
+Only the first 48 bits, the millisecond timestamp, change; rand_a, rand_b,
+and the version and variant bits are kept, so the new UUID stays related to
+the original for deduplication. -t takes any timestamp form (see uuid
+parse-timestamp) and is truncated to the millisecond. UUIDs of other
+versions, and timestamps a UUIDv7 cannot hold, are refused.
+
+Examples:
+  uuid retime 0188b733-b800-7061-aaef-88392620e0e3 -t 2023-06-13T22:15:30.250Z`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, _ := cmd.Flags().GetString("timestamp")
+
+		uuid, err := generator.ParseUUID(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid UUID '%s': %w", args[0], err)
+		}
+		timestamp, err := parseTimestamp(value)
+		if err != nil {
+			return err
+		}
+		retimed, err := generator.RetimeUUIDv7(uuid, timestamp)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), generator.FormatUUID(retimed))
+		return nil
+	},
+}
+
+func init() {
+	retimeCmd.Flags().StringP("timestamp", "t", "", "Corrected timestamp for the UUID")
+	_ = retimeCmd.MarkFlagRequired("timestamp")
+
+	rootCmd.AddCommand(retimeCmd)
+}
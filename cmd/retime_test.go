@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestRetime(t *testing.T) {
+	original := uuidV7At(t, time.Date(2023, 6, 14, 10, 30, 0, 0, time.UTC))
+	output, err := executeCommand(t, "", "retime", original, "-t", "2023-06-13T22:15:30.250Z")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	before, _ := generator.ParseUUID(original)
+	after, err := generator.ParseUUID(strings.TrimSpace(output))
+	if err != nil {
+		t.Fatalf("Expected a UUID, got %q", output)
+	}
+	if !bytes.Equal(after[6:], before[6:]) || bytes.Equal(after[:6], before[:6]) {
+		t.Errorf("Expected only the first six bytes to differ: %x -> %x", before, after)
+	}
+	want := time.Date(2023, 6, 13, 22, 15, 30, 250_000_000, time.UTC)
+	if details := generator.Inspect(after); details.Version != 7 || !details.Timestamp.Equal(want) {
+		t.Errorf("Expected a UUIDv7 at %s, got %+v", want, details)
+	}
+}
+
+func TestRetimeErrors(t *testing.T) {
+	v7 := uuidV7At(t, time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"v4", []string{"retime", "a1b2c3d4-e5f6-4789-8abc-def012345678", "-t", "2023-06-14"}, "only UUIDv7s can be retimed, got version 4"},
+		{"invalid uuid", []string{"retime", "not-a-uuid", "-t", "2023-06-14"}, "invalid UUID 'not-a-uuid'"},
+		{"before epoch", []string{"retime", v7, "-t", "1969-12-31"}, "is before the Unix epoch"},
+		{"bad timestamp", []string{"retime", v7, "-t", "yesterday"}, "unable to parse timestamp 'yesterday'"},
+		{"missing timestamp", []string{"retime", v7}, `required flag(s) "timestamp" not set`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncryptTimeReveal(t *testing.T) {
+	key := writeKeyFile(t, "0123456789abcdef0123456789abcdef\n")
+
+	plain, err := executeCommand(t, "", "-t", "1718361000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output, err := executeCommand(t, "", "-t", "1718361000", "-n", "2", "--encrypt-time", key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	encrypted := strings.Fields(output)
+	if len(encrypted) != 2 {
+		t.Fatalf("Expected 2 UUIDs, got %q", output)
+	}
+	for _, value := range encrypted {
+		if !uuidRegex.MatchString(value) || value[14] != '7' {
+			t.Errorf("Expected a v7-shaped UUID, got %s", value)
+		}
+		if value[:13] == plain[:13] {
+			t.Errorf("Expected the timestamp of %s to be encrypted", value)
+		}
+	}
+	// Same millisecond, same encrypted timestamp
+	if encrypted[0][:13] != encrypted[1][:13] {
+		t.Errorf("Expected one encrypted timestamp, got %s and %s", encrypted[0], encrypted[1])
+	}
+
+	revealed, err := executeCommand(t, output, "reveal", "--key-file", key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(revealed), "\n")
+	for i, line := range lines {
+		uuid, ts, _ := strings.Cut(line, "\t")
+		if ts != "2024-06-14T10:30:00Z" {
+			t.Errorf("Expected the true time, got %q", line)
+		}
+		if uuid[:13] != plain[:13] || uuid[13:] != encrypted[i][13:] {
+			t.Errorf("Expected the UUID as generated, got %s from %s", uuid, encrypted[i])
+		}
+	}
+}
+
+func TestEncryptTimeErrors(t *testing.T) {
+	key := writeKeyFile(t, "0123456789abcdef0123456789abcdef")
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"default version", []string{"--encrypt-time", key}, "--encrypt-time requires UUIDv7 (-7 or -t)"},
+		{"v6", []string{"-6", "--encrypt-time", key}, "--encrypt-time requires UUIDv7"},
+		{"sqlserver", []string{"--sqlserver-sequential", "--encrypt-time", key}, "--encrypt-time requires UUIDv7"},
+		{"missing key", []string{"-7", "--encrypt-time", "missing.key"}, "cannot read --encrypt-time"},
+		{"short key", []string{"-7", "--encrypt-time", writeKeyFile(t, "short")}, "is 5 bytes; at least 16 are required"},
+		{"reveal v4", []string{"reveal", "--key-file", key, "919108f7-52d1-4320-9bac-f847db4148a8"}, "is a version 4 UUID; only UUIDv7 timestamps are encrypted"},
+		{"reveal invalid", []string{"reveal", "--key-file", key, "nope"}, "invalid UUID 'nope'"},
+		{"reveal without key", []string{"reveal", "x"}, `required flag(s) "key-file" not set`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
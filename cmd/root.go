@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"encoding/hex"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/scottbrown/uuid/internal/generator"
 	"github.com/spf13/cobra"
@@ -15,8 +20,9 @@ var (
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "uuid",
-	Short: "Generate UUIDs from the command line",
+	Use:          "uuid",
+	Short:        "Generate UUIDs from the command line",
+	SilenceUsage: true,
 	Long: `A simple CLI tool for generating UUIDs.
 	
 By default, generates UUIDv4. Use version flags to generate other UUID versions.
@@ -28,54 +34,738 @@ Use UUIDv4 when privacy is important.
 Examples:
   uuid                        # Generate UUIDv4 (default)
   uuid -4                     # Generate UUIDv4 (explicit)
+  uuid -1 --node mac          # UUIDv1 for Cassandra timeuuid columns
+  uuid -5 --namespace dns --name example.com  # Name-based UUIDv5
+  uuid -5 --namespace dns --stdin < hosts.txt  # UUIDv5 of every hostname in a file
   uuid -6                     # Generate UUIDv6
+  uuid -6 --node per-boot     # UUIDv6 sharing one node until reboot
   uuid -7                     # Generate UUIDv7 (contains timestamp)
+  uuid --uuid-version "$VER"  # Version from a variable; --v4/--v6/--v7 also work
   uuid -t 1234567890          # Generate UUIDv7 from Unix timestamp
   uuid -t 2023-06-14          # Generate UUIDv7 from date
-  uuid -t "2023-06-14 10:30"  # Generate UUIDv7 from date-time`,
-	Run: func(cmd *cobra.Command, args []string) {
+  uuid -t "2023-06-14 10:30"  # Generate UUIDv7 from date-time
+  uuid -t 14/06/2023 --layout 02/01/2006      # UUIDv7 from a custom date format
+  uuid -t 1330560000 --epoch 1980-01-06       # UUIDv7 from seconds since the GPS epoch
+  uuid -8 --v8-layout ms:48,node:10=42,seq:12,rand:52  # UUIDv8 in a custom layout
+  uuid -7 -n 5 --emit canonical,base64,ulid  # Several representations per UUID
+  uuid --group 4 --upper                      # D942 8888 122B ... for reading aloud
+  uuid --per-line --join < names.txt          # One UUID per input line
+  uuid --nanoid -n 3                          # NanoIDs instead of UUIDs
+  uuid --idempotent --key db-migration-42 --ttl 1h  # Same UUID on re-runs for an hour
+  uuid -7 -n 10000000 --pg-copy-binary -o keys.copy  # Bulk load file for PostgreSQL COPY
+  uuid -7 -n 1000000 --parquet -o ids.parquet  # UUID and timestamp columns for Spark
+  uuid -n 500 --exclude-file existing.txt     # New IDs that avoid an existing set
+  uuid -7 -t 2023-06-14 -n 1000 --unique      # No repeats, even within one millisecond
+  uuid -7 --encrypt-time time.key             # UUIDv7 with a keyed, opaque timestamp
+  uuid -7 --time-granularity 1h               # UUIDv7 that reveals only the hour
+  uuid --ulid -t 2023-06-14                   # ULID with a historical timestamp
+  uuid -8 --hmac-key-file ids.key --name c-42 # Keyed pseudonym for an identifier
+  uuid -7 -n 100000 --spread 30d              # Sorted UUIDv7s created over the last 30 days`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyDebugLogging(cmd); err != nil {
+			return err
+		}
+		return applyNowOverride(cmd)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check which version flag was used
-		v4, _ := cmd.Flags().GetBool("4")
-		v6, _ := cmd.Flags().GetBool("6")
-		v7, _ := cmd.Flags().GetBool("7")
+		selected, err := versionFlag(cmd)
+		if err != nil {
+			return err
+		}
+		v1, v4, v5, v6, v7, v8 := selected == 1, selected == 4, selected == 5, selected == 6, selected == 7, selected == 8
 		timestamp, _ := cmd.Flags().GetString("timestamp")
+		count, _ := cmd.Flags().GetInt("count")
+		perLine, _ := cmd.Flags().GetBool("per-line")
+		join, _ := cmd.Flags().GetBool("join")
+		skipEmpty, _ := cmd.Flags().GetBool("skip-empty")
+		fromHex, _ := cmd.Flags().GetString("from-hex")
+		sqlServerSequential, _ := cmd.Flags().GetBool("sqlserver-sequential")
+		nanoid, _ := cmd.Flags().GetBool("nanoid")
+		ulid, _ := cmd.Flags().GetBool("ulid")
+		all, _ := cmd.Flags().GetBool("all")
+		qr, _ := cmd.Flags().GetBool("qr")
+		phonetic, _ := cmd.Flags().GetBool("phonetic")
+
+		if count < 1 {
+			return fmt.Errorf("count must be at least 1, got %d", count)
+		}
+		if cmd.Flags().Changed("forget") {
+			if err := rejectFlags(cmd, "--forget", []string{"idempotent", "key", "ttl"}); err != nil {
+				return err
+			}
+			key, _ := cmd.Flags().GetString("forget")
+			found, err := forgetIdempotentKey(cmd.ErrOrStderr(), key)
+			if err != nil {
+				return fmt.Errorf("cannot use the idempotency cache: %w", err)
+			}
+			if !found {
+				fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: no UUID is cached for key '%s'.\n", key)
+			}
+			return nil
+		}
+		idempotent, _ := cmd.Flags().GetBool("idempotent")
+		if idempotent != cmd.Flags().Changed("key") {
+			return fmt.Errorf("--idempotent and --key must be given together")
+		}
+		if cmd.Flags().Changed("ttl") && !idempotent {
+			return fmt.Errorf("--ttl requires --idempotent")
+		}
+		if ttl, _ := cmd.Flags().GetDuration("ttl"); ttl < 0 {
+			return fmt.Errorf("--ttl must not be negative, got %s", ttl)
+		}
+		if idempotent {
+			if err := rejectFlags(cmd, "--idempotent", []string{"count", "per-line"}); err != nil {
+				return err
+			}
+		}
+		if (join || skipEmpty) && !perLine {
+			return fmt.Errorf("--join and --skip-empty require --per-line")
+		}
+		if _, err := porcelainFlag(cmd, generatePorcelainConflicts); err != nil {
+			return err
+		}
+
+		pgCopy, _ := cmd.Flags().GetBool("pg-copy")
+		pgCopyBinary, _ := cmd.Flags().GetBool("pg-copy-binary")
+		parquetOut, _ := cmd.Flags().GetBool("parquet")
+		if cmd.Flags().Changed("output") && !qr && !pgCopy && !pgCopyBinary && !parquetOut {
+			return fmt.Errorf("--output requires --qr, --pg-copy, --pg-copy-binary, or --parquet")
+		}
+		if (cmd.Flags().Changed("parquet-type") || cmd.Flags().Changed("parquet-row-group")) && !parquetOut {
+			return fmt.Errorf("--parquet-type and --parquet-row-group require --parquet")
+		}
+		if parquetOut {
+			if err := rejectFlags(cmd, "--parquet", parquetConflicts); err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("output") {
+				return fmt.Errorf("--parquet writes a binary file and requires -o/--output")
+			}
+		}
+		if cmd.Flags().Changed("pg-copy-columns") && !pgCopy && !pgCopyBinary {
+			return fmt.Errorf("--pg-copy-columns requires --pg-copy or --pg-copy-binary")
+		}
+		if pgCopy || pgCopyBinary {
+			mode := "--pg-copy"
+			if pgCopyBinary {
+				mode = "--pg-copy-binary"
+			}
+			if err := rejectFlags(cmd, mode, pgCopyConflicts); err != nil {
+				return err
+			}
+		}
+		if !cmd.Flags().Changed("exclude-file") && !cmd.Flags().Changed("unique") {
+			for _, name := range []string{"approx", "exclude-retries", "verbose"} {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("--%s requires --exclude-file or --unique", name)
+				}
+			}
+		}
+		spreading := cmd.Flags().Changed("spread") || cmd.Flags().Changed("spread-from")
+		if !spreading {
+			for _, name := range []string{"spread-to", "distribution", "shuffle"} {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("--%s requires --spread or --spread-from", name)
+				}
+			}
+		} else if v1 || v4 || v5 || v6 || v8 || sqlServerSequential {
+			return fmt.Errorf("--spread generates UUIDv7 and cannot be combined with -1, -4, -5, -6, -8, or --sqlserver-sequential")
+		} else if err := rejectFlags(cmd, "--spread", spreadConflicts); err != nil {
+			return err
+		}
+		isV7 := !v6 && !v8 && !sqlServerSequential && (v7 || timestamp != "" || spreading)
+		if cmd.Flags().Changed("encrypt-time") && !isV7 {
+			return fmt.Errorf("--encrypt-time requires UUIDv7 (-7 or -t)")
+		}
+		if cmd.Flags().Changed("time-granularity") {
+			if !v7 {
+				return fmt.Errorf("--time-granularity requires -7")
+			}
+			if err := rejectFlags(cmd, "--time-granularity", granularityConflicts); err != nil {
+				return err
+			}
+			if granularity, _ := cmd.Flags().GetDuration("time-granularity"); granularity < time.Millisecond {
+				return fmt.Errorf("--time-granularity must be at least 1ms, got %s", granularity)
+			}
+		}
+		if (cmd.Flags().Changed("length") || cmd.Flags().Changed("alphabet")) && !nanoid {
+			return fmt.Errorf("--length and --alphabet require --nanoid")
+		}
+		if cmd.Flags().Changed("layout") && timestamp == "" {
+			return fmt.Errorf("--layout requires -t")
+		}
+		if cmd.Flags().Changed("epoch") {
+			if timestamp == "" {
+				return fmt.Errorf("--epoch requires -t")
+			}
+			if err := rejectFlags(cmd, "--epoch", []string{"layout"}); err != nil {
+				return err
+			}
+		}
+		hmacKeyFile, _ := cmd.Flags().GetString("hmac-key-file")
+		if v8 && !cmd.Flags().Changed("v8-layout") && !cmd.Flags().Changed("payload") && hmacKeyFile == "" {
+			return fmt.Errorf("-8 requires --v8-layout, --payload, or --hmac-key-file")
+		}
+		for _, name := range []string{"v8-layout", "payload", "hmac-key-file"} {
+			if cmd.Flags().Changed(name) && !v8 {
+				return fmt.Errorf("--%s requires -8", name)
+			}
+		}
+		stdinNames, _ := cmd.Flags().GetBool("stdin")
+		if stdinNames && !v5 {
+			return fmt.Errorf("--stdin requires -5")
+		}
+		if cmd.Flags().Changed("strict") && !stdinNames {
+			return fmt.Errorf("--strict requires --stdin")
+		}
+		if v5 {
+			if err := rejectFlags(cmd, "-5", v5Conflicts); err != nil {
+				return err
+			}
+			if stdinNames {
+				if err := rejectFlags(cmd, "--stdin", stdinConflicts); err != nil {
+					return err
+				}
+			} else if !cmd.Flags().Changed("name") {
+				return fmt.Errorf("-5 requires --name or --stdin")
+			}
+			if !cmd.Flags().Changed("namespace") {
+				return fmt.Errorf("-5 requires --namespace")
+			}
+		} else if cmd.Flags().Changed("namespace") {
+			return fmt.Errorf("--namespace requires -5")
+		} else if hmacKeyFile != "" && !cmd.Flags().Changed("name") {
+			return fmt.Errorf("--hmac-key-file requires --name")
+		} else if cmd.Flags().Changed("name") && hmacKeyFile == "" {
+			return fmt.Errorf("--name requires -5 or -8 --hmac-key-file")
+		}
+		if cmd.Flags().Changed("node") && !v1 && !v6 {
+			return fmt.Errorf("--node requires -1 or -6")
+		}
+		for _, name := range []string{"stateless", "clock-state"} {
+			if cmd.Flags().Changed(name) && (!v6 || timestamp != "") {
+				return fmt.Errorf("--%s requires -6 without -t", name)
+			}
+		}
+		if cmd.Flags().Changed("seed") && !cmd.Flags().Changed("corrupt") && !spreading {
+			// --corrupt and --spread draw from their own seeded streams
+			seed, _ := cmd.Flags().GetUint64("seed")
+			fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: --seed makes every generated value predictable from the seed; use it only for test fixtures.\n")
+			debugLogger.Debug("seeding entropy", "seed", seed)
+			generator.SetEntropySource(generator.NewDeterministicSource(seed))
+			defer generator.SetEntropySource(nil)
+		}
+		if all {
+			if err := rejectFlags(cmd, "--all", allConflicts); err != nil {
+				return err
+			}
+			return writeAllVersions(cmd, timestamp)
+		}
+		if nanoid {
+			if err := rejectFlags(cmd, "--nanoid", nanoidConflicts); err != nil {
+				return err
+			}
+			return writeNanoIDs(cmd, count)
+		}
+		if ulid {
+			if err := rejectFlags(cmd, "--ulid", ulidConflicts); err != nil {
+				return err
+			}
+			return writeULIDs(cmd, timestamp, count)
+		}
+		if cmd.Flags().Changed("corrupt") {
+			if err := rejectFlags(cmd, "--corrupt", corruptConflicts); err != nil {
+				return err
+			}
+			corrupt, _ := cmd.Flags().GetString("corrupt")
+			return writeCorrupt(cmd, corrupt, count)
+		}
 
-		// Handle timestamp flag
-		if timestamp != "" {
-			// Validate that timestamp is only used with UUIDv7 (or no version specified)
-			if v4 || v6 {
-				fmt.Fprintf(os.Stderr, "Error: Timestamp flag (-t) is only supported with UUIDv7. Use 'uuid -t %s' or 'uuid -7 -t %s'.\n", timestamp, timestamp)
-				os.Exit(1)
+		emit, err := newEmitter(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Build a single UUIDv4 from caller-supplied bytes
+		if cmd.Flags().Changed("from-hex") {
+			if v1 || v6 || v7 || v8 || timestamp != "" {
+				return fmt.Errorf("--from-hex only constructs UUIDv4 and cannot be combined with -1, -6, -7, -8, or -t")
+			}
+			value, err := uuidFromHex(cmd, fromHex)
+			if err != nil {
+				return err
+			}
+			generate, err := withVariant(cmd, func() string { return value }, false)
+			if err != nil {
+				return err
+			}
+			value = generate()
+			if qr {
+				return emit.writeQR(cmd, value)
+			}
+			if phonetic {
+				writePhonetic(cmd.OutOrStdout(), value)
+				return nil
+			}
+			return emit.write(cmd.OutOrStdout(), func() string { return value }, 1)
+		}
+
+		var generate func() string
+		var stdinNamespace *[16]byte
+
+		if v8 {
+			mode, conflicts := "-8", []string{"1", "4", "5", "6", "7", "v4", "v6", "v7", "uuid-version", "sqlserver-sequential"}
+			if !cmd.Flags().Changed("8") {
+				mode, conflicts = "--uuid-version 8", []string{"sqlserver-sequential"}
+			}
+			if err := rejectFlags(cmd, mode, conflicts); err != nil {
+				return err
+			}
+			if hmacKeyFile != "" {
+				if err := rejectFlags(cmd, "--hmac-key-file", hmacConflicts); err != nil {
+					return err
+				}
+				key, err := readKeyFile("hmac-key-file", hmacKeyFile)
+				if err != nil {
+					return err
+				}
+				name, _ := cmd.Flags().GetString("name")
+				value, err := generator.GenerateUUIDv8HMAC(key, name)
+				if err != nil {
+					return err
+				}
+				debugLogger.Debug("selected generator", "version", 8, "layout", "hmac-sha256")
+				generate = func() string { return value }
+			} else if cmd.Flags().Changed("payload") {
+				if err := rejectFlags(cmd, "--payload", payloadConflicts); err != nil {
+					return err
+				}
+				payload, _ := cmd.Flags().GetString("payload")
+				value, err := uuidFromPayload(cmd, payload)
+				if err != nil {
+					return err
+				}
+				debugLogger.Debug("selected generator", "version", 8, "layout", "payload")
+				generate = func() string { return value }
+			} else {
+				spec, _ := cmd.Flags().GetString("v8-layout")
+				layout, err := generator.ParseV8Layout(spec)
+				if err != nil {
+					return fmt.Errorf("invalid --v8-layout: %w", err)
+				}
+				var parsedTime time.Time
+				if timestamp != "" {
+					if parsedTime, err = parseTimestampFlag(cmd, timestamp); err != nil {
+						return err
+					}
+					if err := layout.CheckTimestamp(parsedTime); err != nil {
+						return err
+					}
+				} else if err := checkClock(cmd); err != nil {
+					return err
+				}
+				debugLogger.Debug("selected generator", "version", 8, "layout", layout.String(), "count", count)
+				generate = layout.Generator(parsedTime)
+			}
+		} else if sqlServerSequential {
+			// A UUIDv8 layout, so it cannot be combined with the RFC version flags
+			if v1 || v4 || v5 || v6 || v7 {
+				return fmt.Errorf("--sqlserver-sequential generates a UUIDv8 layout and cannot be combined with -1, -4, -5, -6, or -7")
+			}
+			if timestamp != "" {
+				parsedTime, err := parseTimestampFlag(cmd, timestamp)
+				if err != nil {
+					return err
+				}
+				if err := generator.CheckUUIDv7Timestamp(parsedTime); err != nil {
+					return err
+				}
+				debugLogger.Debug("selected generator", "version", 8, "layout", "sqlserver-sequential", "timestamp_source", "flag", "timestamp", parsedTime)
+				generate = func() string { return generator.GenerateSQLServerSequentialWithTimestamp(parsedTime) }
+			} else {
+				if err := checkClock(cmd); err != nil {
+					return err
+				}
+				debugLogger.Debug("selected generator", "version", 8, "layout", "sqlserver-sequential", "timestamp_source", "clock", "count", count)
+				generate = generator.GenerateSQLServerSequential
+			}
+		} else if v5 {
+			namespaceValue, _ := cmd.Flags().GetString("namespace")
+			name, _ := cmd.Flags().GetString("name")
+			namespace, err := resolveNamespace(namespaceValue)
+			if err != nil {
+				return err
+			}
+			if stdinNames {
+				debugLogger.Debug("selected generator", "version", 5, "namespace", generator.FormatUUID(namespace), "name_source", "stdin")
+				stdinNamespace = &namespace
+			} else {
+				value := generator.GenerateUUIDv5WithNamespace(namespace, name)
+				debugLogger.Debug("selected generator", "version", 5, "namespace", generator.FormatUUID(namespace), "name", name)
+				generate = func() string { return value }
+			}
+		} else if spreading {
+			if generate, err = spreadGenerator(cmd, count); err != nil {
+				return err
+			}
+		} else if timestamp != "" {
+			// Handle timestamp flag
+			// Validate that timestamp is only used with UUIDv6 or UUIDv7 (or no version specified)
+			if v1 || v4 {
+				return fmt.Errorf("Timestamp flag (-t) is only supported with UUIDv6 and UUIDv7. Use 'uuid -t %s', 'uuid -7 -t %s', or 'uuid -6 -t %s'.", timestamp, timestamp, timestamp)
 			}
 
 			// Parse the timestamp
-			parsedTime, err := generator.ParseTimestamp(timestamp)
+			parsedTime, err := parseTimestampFlag(cmd, timestamp)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
+				return err
 			}
 
-			// Generate UUIDv7 with the specified timestamp
-			fmt.Println(generator.GenerateUUIDv7WithTimestamp(parsedTime))
-			return
+			if v6 {
+				// The node is random, as for every UUIDv6 without --node
+				if err := rejectFlags(cmd, "-6 -t", []string{"node"}); err != nil {
+					return err
+				}
+				if err := generator.CheckUUIDv6Timestamp(parsedTime); err != nil {
+					return err
+				}
+				debugLogger.Debug("selected generator", "version", 6, "timestamp_source", "flag", "timestamp", parsedTime)
+				generate = func() string {
+					// The timestamp was checked above, so this cannot fail
+					uuid, _ := generator.GenerateUUIDv6WithTimestamp(parsedTime)
+					return uuid
+				}
+			} else {
+				// Generate UUIDv7 with the specified timestamp
+				if err := generator.CheckUUIDv7Timestamp(parsedTime); err != nil {
+					return err
+				}
+				debugLogger.Debug("selected generator", "version", 7, "timestamp_source", "flag", "timestamp", parsedTime)
+				generate = func() string {
+					// The timestamp was checked above, so this cannot fail
+					uuid, _ := generator.GenerateUUIDv7WithTimestamp(parsedTime)
+					return uuid
+				}
+			}
+		} else {
+			// Default to UUIDv4 if no version flag is specified
+			if !v1 && !v4 && !v6 && !v7 {
+				v4 = true
+			}
+
+			// Time-based versions embed the system clock, so sanity check it first
+			if v1 || v6 || v7 {
+				if err := checkClock(cmd); err != nil {
+					return err
+				}
+			}
+
+			// Select the appropriate UUID generator
+			selected := 4
+			if v7 {
+				selected, generate = 7, generator.GenerateUUIDv7
+				if monotonicV7 != nil {
+					debugLogger.Debug("continuing monotonic counter", "source", "monotonic-state")
+					generate = monotonicV7.Generate
+				}
+				if cmd.Flags().Changed("time-granularity") {
+					granularity, _ := cmd.Flags().GetDuration("time-granularity")
+					debugLogger.Debug("truncating timestamps", "granularity", granularity)
+					generate = func() string { return generator.GenerateUUIDv7Truncated(granularity) }
+				}
+			} else if v6 {
+				selected = 6
+				node, err := resolveNode(cmd)
+				if err != nil {
+					return err
+				}
+				var saveClockSequence func()
+				generate, saveClockSequence = uuidV6Generator(cmd, node)
+				defer saveClockSequence()
+			} else if v1 {
+				selected, generate = 1, generator.GenerateUUIDv1
+				node, err := resolveNode(cmd)
+				if err != nil {
+					return err
+				}
+				if node != nil {
+					generate = func() string { return generator.GenerateUUIDv1WithNode(*node) }
+				}
+			} else if v4 {
+				generate = generator.GenerateUUIDv4
+			}
+			debugLogger.Debug("selected generator", "version", selected, "timestamp_source", "clock", "count", count)
 		}
 
-		// Default to UUIDv4 if no version flag is specified
-		if !v4 && !v6 && !v7 {
-			v4 = true
+		if generate, err = withTimeEncryption(cmd, generate); err != nil {
+			return err
 		}
+		timeBased := v1 || v6 || v7 || (v8 && cmd.Flags().Changed("v8-layout")) || timestamp != "" || sqlServerSequential || spreading
+		if generate, err = withVariant(cmd, generate, timeBased); err != nil {
+			return err
+		}
+		var exclusions *uuidExclusions
+		if generate, exclusions, err = withExclusions(cmd, generate); err != nil {
+			return err
+		}
+		err = catchExclusionLimit(func() error {
+			if idempotent {
+				key, _ := cmd.Flags().GetString("key")
+				ttl, _ := cmd.Flags().GetDuration("ttl")
+				value, err := idempotentUUID(cmd.ErrOrStderr(), key, ttl, generate)
+				if err != nil {
+					return fmt.Errorf("cannot use the idempotency cache: %w", err)
+				}
+				generate = func() string { return value }
+			}
 
-		// Generate and output the appropriate UUID
-		if v7 {
-			fmt.Println(generator.GenerateUUIDv7())
-		} else if v6 {
-			fmt.Println(generator.GenerateUUIDv6())
-		} else if v4 {
-			fmt.Println(generator.GenerateUUIDv4())
+			if stdinNamespace != nil {
+				strict, _ := cmd.Flags().GetBool("strict")
+				return emit.writeNames(cmd.InOrStdin(), cmd.OutOrStdout(), *stdinNamespace, strict)
+			}
+			if pgCopy || pgCopyBinary {
+				return writePGCopy(cmd, generate, count, pgCopyBinary)
+			}
+			if parquetOut {
+				// An encrypted timestamp column would be meaningless
+				return writeParquet(cmd, generate, count, isV7 && !cmd.Flags().Changed("encrypt-time"))
+			}
+			if qr {
+				return emit.writeQR(cmd, generate())
+			}
+			if phonetic {
+				for i := 0; i < count; i++ {
+					writePhonetic(cmd.OutOrStdout(), generate())
+				}
+				return nil
+			}
+			if perLine {
+				return emit.writePerLine(cmd.InOrStdin(), cmd.OutOrStdout(), generate, join, skipEmpty)
+			}
+			return emit.write(cmd.OutOrStdout(), generate, count)
+		})
+		if verbose, _ := cmd.Flags().GetBool("verbose"); verbose && exclusions != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "regenerated %d UUIDs that were %s\n", exclusions.regenerated, excludedSet(exclusions.unique))
 		}
+		return err
 	},
 }
 
+// parseTimestampFlag parses the -t value, as a count since --epoch or with
+// the --layout reference layout when one is given and by trying every known
+// format otherwise
+func parseTimestampFlag(cmd *cobra.Command, value string) (time.Time, error) {
+	if epochValue, _ := cmd.Flags().GetString("epoch"); epochValue != "" {
+		epoch, err := parseTimestamp(epochValue)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --epoch: %w", err)
+		}
+		return generator.ParseTimestampSince(value, epoch)
+	}
+	if layout, _ := cmd.Flags().GetString("layout"); layout != "" {
+		return generator.ParseTimestampLayout(value, layout)
+	}
+	return parseTimestamp(value)
+}
+
+// uuidFromHex builds a UUIDv4 from 32 hex digits, warning on stderr when the
+// version or variant bits had to be changed
+func uuidFromHex(cmd *cobra.Command, value string) (string, error) {
+	hex32, _ := generator.LookupEncoding("hex32")
+	b, err := hex32.Decode(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid --from-hex value: %w", err)
+	}
+
+	var changed []string
+	if b[6]>>4 != 4 {
+		changed = append(changed, fmt.Sprintf("version nibble %x", b[6]>>4))
+	}
+	if b[8]&0xc0 != 0x80 {
+		changed = append(changed, fmt.Sprintf("variant bits %02b", b[8]>>6))
+	}
+	if len(changed) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: --from-hex input had %s; overwritten to make a valid UUIDv4.\n", strings.Join(changed, " and "))
+	}
+	return generator.NewV4FromBytes(b), nil
+}
+
+// uuidFromPayload builds a UUIDv8 from up to 32 hex characters, padding
+// short payloads with zeros on the right. Bits 48-51 and 64-65 become the
+// version and variant, with a warning when that overwrites payload data.
+func uuidFromPayload(cmd *cobra.Command, value string) (string, error) {
+	if len(value) > 32 {
+		return "", fmt.Errorf("invalid --payload: %d hex characters is more than the 16 bytes a UUIDv8 holds", len(value))
+	}
+	decoded, err := hex.DecodeString(value + strings.Repeat("0", 32-len(value)))
+	if err != nil {
+		return "", fmt.Errorf("invalid --payload '%s': expected hex characters", value)
+	}
+	var payload [16]byte
+	copy(payload[:], decoded)
+
+	var changed []string
+	if version := payload[6] >> 4; version != 0 && version != 8 {
+		changed = append(changed, fmt.Sprintf("version nibble %x", version))
+	}
+	if variant := payload[8] >> 6; variant != 0 && variant != 0b10 {
+		changed = append(changed, fmt.Sprintf("variant bits %02b", variant))
+	}
+	if len(changed) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: --payload had %s; overwritten to make a valid UUIDv8.\n", strings.Join(changed, " and "))
+	}
+	return generator.GenerateUUIDv8(payload), nil
+}
+
+// withVariant rewrites the variant bits of every generated UUID when --variant
+// asks for something other than the RFC 9562 default. Time-based layouts are
+// refused without --force, since their timestamps no longer decode.
+func withVariant(cmd *cobra.Command, generate func() string, timeBased bool) (func() string, error) {
+	variant, _ := cmd.Flags().GetString("variant")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if _, err := generator.SetVariant([16]byte{}, variant); err != nil {
+		return nil, err
+	}
+	if variant == generator.VariantRFC {
+		return generate, nil
+	}
+	if timeBased && !force {
+		return nil, fmt.Errorf("--variant %s makes the timestamp layout of time-based UUIDs meaningless; use --force to generate them anyway", variant)
+	}
+	debugLogger.Debug("overriding variant", "variant", variant)
+	return func() string {
+		uuid, _ := generator.ParseUUID(generate())
+		uuid, _ = generator.SetVariant(uuid, variant)
+		return generator.FormatUUID(uuid)
+	}, nil
+}
+
+// payloadConflicts are the flags that set a timestamp or ask for more than
+// the one UUID a fixed --payload describes
+var payloadConflicts = []string{"v8-layout", "timestamp", "count", "per-line", "idempotent", "exclude-file", "unique"}
+
+// hmacConflicts are the other ways to fill a UUIDv8, and the flags that set
+// a timestamp or expect a fresh UUID per value, as for -5
+var hmacConflicts = []string{"v8-layout", "payload", "timestamp", "from-hex", "per-line", "idempotent", "exclude-file", "unique"}
+
+// granularityConflicts are the flags that choose the UUIDv7 timestamp some
+// other way
+var granularityConflicts = []string{"timestamp", "spread", "spread-from", "from-hex"}
+
+// nanoidConflicts are the UUID-specific flags that make no sense for NanoIDs
+var nanoidConflicts = []string{"idempotent", "exclude-file", "unique", "1", "4", "5", "6", "7", "8", "v4", "v6", "v7", "uuid-version", "name", "namespace", "v8-layout", "payload", "hmac-key-file", "timestamp", "from-hex", "sqlserver-sequential", "emit", "format-name", "oid", "check-digit", "header", "json", "per-line", "qr", "phonetic", "variant", "corrupt", "ulid", "all", "group", "upper"}
+
+// ulidConflicts are the flags that shape UUIDs, which ULIDs are not; only
+// the timestamp plumbing (-t, --layout, --epoch) carries over
+var ulidConflicts = []string{"idempotent", "exclude-file", "unique", "1", "4", "5", "6", "7", "8", "v4", "v6", "v7", "uuid-version", "name", "namespace", "v8-layout", "payload", "hmac-key-file", "from-hex", "sqlserver-sequential", "emit", "format-name", "oid", "check-digit", "header", "json", "per-line", "qr", "phonetic", "variant", "nanoid", "corrupt", "all", "group", "upper", "node", "time-granularity", "encrypt-time"}
+
+// corruptConflicts are the flags that shape valid UUIDs, which --corrupt ignores
+var corruptConflicts = []string{"idempotent", "exclude-file", "unique", "1", "4", "5", "6", "7", "8", "v4", "v6", "v7", "uuid-version", "name", "namespace", "v8-layout", "payload", "hmac-key-file", "timestamp", "from-hex", "sqlserver-sequential", "emit", "format-name", "oid", "check-digit", "header", "json", "per-line", "qr", "phonetic", "variant", "nanoid", "ulid", "all", "group", "upper"}
+
+// generatePorcelainConflicts are the flags that decorate or reshape generated
+// output, which the porcelain format freezes
+var generatePorcelainConflicts = []string{"header", "json", "qr", "phonetic", "group", "upper", "pretty"}
+
+// supportedVersions are the UUID versions --uuid-version accepts
+var supportedVersions = []int{1, 4, 5, 6, 7, 8}
+
+// v5Conflicts are the flags that set a timestamp or expect a fresh UUID per
+// value, which a name-based UUID cannot honour
+var v5Conflicts = []string{"timestamp", "from-hex", "per-line", "idempotent", "exclude-file", "unique"}
+
+// versionFlag returns the UUID version selected with a short flag such as
+// -7, the --v4, --v6, or --v7 aliases, or --uuid-version, or 0 when none was
+// given. -8 wins so that combining it with another version is reported as a
+// -8 conflict.
+func versionFlag(cmd *cobra.Command) (int, error) {
+	if cmd.Flags().Changed("8") {
+		return 8, nil
+	}
+	if cmd.Flags().Changed("uuid-version") {
+		n, _ := cmd.Flags().GetInt("uuid-version")
+		if !slices.Contains(supportedVersions, n) {
+			names := make([]string, len(supportedVersions))
+			for i, v := range supportedVersions {
+				names[i] = strconv.Itoa(v)
+			}
+			return 0, fmt.Errorf("unsupported --uuid-version %d. Supported versions: %s", n, strings.Join(names, ", "))
+		}
+		return n, nil
+	}
+	for _, name := range []string{"1", "4", "5", "6", "7"} {
+		short, _ := cmd.Flags().GetBool(name)
+		long := false
+		if cmd.Flags().Lookup("v"+name) != nil {
+			long, _ = cmd.Flags().GetBool("v" + name)
+		}
+		if short || long {
+			return strconv.Atoi(name)
+		}
+	}
+	return 0, nil
+}
+
+// rejectFlags fails when any of the named flags was set alongside mode
+func rejectFlags(cmd *cobra.Command, mode string, names []string) error {
+	for _, name := range names {
+		if f := cmd.Flags().Lookup(name); f.Changed {
+			label := "--" + f.Name
+			if f.Shorthand != "" {
+				label = "-" + f.Shorthand
+			}
+			return fmt.Errorf("%s cannot be combined with %s", mode, label)
+		}
+	}
+	return nil
+}
+
+// writeNanoIDs prints count NanoIDs, one per line
+func writeNanoIDs(cmd *cobra.Command, count int) error {
+	length, _ := cmd.Flags().GetInt("length")
+	alphabet, _ := cmd.Flags().GetString("alphabet")
+	debugLogger.Debug("selected generator", "format", "nanoid", "length", length, "alphabet_size", len(alphabet), "count", count)
+
+	out := cmd.OutOrStdout()
+	for i := 0; i < count; i++ {
+		id, err := generator.GenerateNanoID(length, alphabet)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, id)
+	}
+	return nil
+}
+
+// writeULIDs prints count ULIDs for the -t timestamp, or for the clock
+func writeULIDs(cmd *cobra.Command, timestamp string, count int) error {
+	generate := generator.GenerateULID
+	if timestamp != "" {
+		parsedTime, err := parseTimestampFlag(cmd, timestamp)
+		if err != nil {
+			return err
+		}
+		// ULIDs share the UUIDv7 48-bit millisecond timestamp
+		if err := generator.CheckUUIDv7Timestamp(parsedTime); err != nil {
+			return err
+		}
+		debugLogger.Debug("selected generator", "format", "ulid", "timestamp_source", "flag", "timestamp", parsedTime, "count", count)
+		generate = func() string { return generator.GenerateULIDWithTimestamp(parsedTime) }
+	} else {
+		if err := checkClock(cmd); err != nil {
+			return err
+		}
+		debugLogger.Debug("selected generator", "format", "ulid", "timestamp_source", "clock", "count", count)
+	}
+
+	out := cmd.OutOrStdout()
+	for i := 0; i < count; i++ {
+		fmt.Fprintln(out, generate())
+	}
+	return nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -87,15 +777,161 @@ func Execute() {
 
 func init() {
 	// Version-specific flags
+	rootCmd.Flags().BoolP("1", "1", false, "Generate UUIDv1 (contains timestamp and node)")
 	rootCmd.Flags().BoolP("4", "4", false, "Generate UUIDv4 (default)")
+	rootCmd.Flags().BoolP("5", "5", false, "Generate the name-based UUIDv5 of --name in --namespace")
 	rootCmd.Flags().BoolP("6", "6", false, "Generate UUIDv6")
 	rootCmd.Flags().BoolP("7", "7", false, "Generate UUIDv7 (contains timestamp)")
+	rootCmd.Flags().BoolP("8", "8", false, "Generate UUIDv8 in the --v8-layout bit layout, from --payload, or keyed by --hmac-key-file")
+	rootCmd.Flags().Bool("v4", false, "Generate UUIDv4, same as -4")
+	rootCmd.Flags().Bool("v6", false, "Generate UUIDv6, same as -6")
+	rootCmd.Flags().Bool("v7", false, "Generate UUIDv7, same as -7")
+	rootCmd.Flags().Int("uuid-version", 0, "Generate this UUID version: 1, 4, 5, 6, 7, or 8")
+	rootCmd.Flags().Bool("all", false, "Print a labelled UUIDv4, UUIDv6, and UUIDv7 for the same instant (-t sets it)")
+
+	// Name-based UUIDv5
+	rootCmd.Flags().String("name", "", "Name to derive the -5 or -8 --hmac-key-file UUID from")
+	rootCmd.Flags().Bool("stdin", false, "With -5, read names from stdin, one per line, and print the UUIDv5 of each")
+	rootCmd.Flags().Bool("strict", false, "With --stdin, fail on a blank line instead of skipping it")
+	rootCmd.Flags().String("namespace", "", "Namespace for -5: a name from 'uuid namespace list', a keyword (dns, url, oid, x500), or a UUID")
+	rootCmd.Flags().String("v8-layout", "", "UUIDv8 fields as fill:width, e.g. 'ms:48,node:10=42,seq:12,rand:52' (fills: ms, node, seq, rand; 122 bits)")
+	rootCmd.Flags().String("hmac-key-file", "", "Derive the -8 UUID from --name with HMAC-SHA256 under the key in this file (at least 16 bytes)")
+	rootCmd.Flags().String("payload", "", "Hex payload of up to 16 bytes for -8, zero-padded on the right; the version and variant bits are overwritten")
 
 	// Timestamp flag for UUIDv7
 	rootCmd.Flags().StringP("timestamp", "t", "", "Generate UUIDv7 from timestamp (Unix seconds/milliseconds, RFC3339, or ISO date)")
+	rootCmd.Flags().String("layout", "", "Parse -t with this Go reference layout (e.g. '02/01/2006 15:04') instead of guessing the format")
+	rootCmd.Flags().String("epoch", "", "Read a numeric -t as seconds or milliseconds since this timestamp instead of 1970-01-01 (GPS time: 1980-01-06; leap seconds are not applied)")
+
+	// Clock sanity check for time-based versions
+	addClockFlags(rootCmd)
+
+	// Batch and output flags
+	rootCmd.Flags().IntP("count", "n", 1, "Number of UUIDs to generate")
+	rootCmd.Flags().String("emit", "", "Comma-separated columns to print for each UUID ("+strings.Join(generator.EncodingNames(), ", ")+")")
+	rootCmd.Flags().String("format-name", "", "Print each UUID in the named format (see 'uuid list-formats')")
+	rootCmd.Flags().Bool("oid", false, "Print each UUID as an ITU-T X.667 OID (2.25.<integer>)")
+	rootCmd.Flags().Bool("check-digit", false, "Append '~' and a Damm check digit to each UUID (see 'uuid verify-check')")
+	rootCmd.MarkFlagsMutuallyExclusive("emit", "format-name", "oid", "check-digit")
+	rootCmd.Flags().Bool("header", false, "Print a header row naming the --emit columns")
+	rootCmd.Flags().Bool("json", false, "Print each UUID as a JSON object keyed by column name")
+	rootCmd.MarkFlagsMutuallyExclusive("header", "json")
+	rootCmd.Flags().Int("group", 0, "Print the 32 hex digits in space-separated groups of this size, for reading aloud")
+	rootCmd.Flags().Bool("upper", false, "Print hex digits in upper case")
+
+	// Construct a UUIDv4 from exact bytes, for reproducing bugs and test vectors
+	rootCmd.Flags().String("from-hex", "", "Build a UUIDv4 from these 32 hex digits, overwriting only the version and variant bits")
+	rootCmd.MarkFlagsMutuallyExclusive("from-hex", "count")
+
+	// Per-input-line generation, where stdin drives the number of UUIDs
+	rootCmd.Flags().Bool("per-line", false, "Generate one UUID for every line read from stdin")
+	rootCmd.Flags().Bool("join", false, "With --per-line, print each UUID followed by a tab and the input line")
+	rootCmd.Flags().Bool("skip-empty", false, "With --per-line, do not generate UUIDs for blank lines")
+	rootCmd.MarkFlagsMutuallyExclusive("per-line", "count")
+	rootCmd.MarkFlagsMutuallyExclusive("join", "json")
+	rootCmd.MarkFlagsMutuallyExclusive("from-hex", "per-line")
+
+	// UUIDv8 layout whose timestamp sits where SQL Server compares first
+	rootCmd.Flags().Bool("sqlserver-sequential", false, "Generate UUIDv8 values that stay append-ordered in SQL Server uniqueidentifier indexes")
+	rootCmd.MarkFlagsMutuallyExclusive("sqlserver-sequential", "from-hex")
+
+	// NanoIDs for mixed fixtures; not UUIDs, so most other flags are rejected
+	rootCmd.Flags().Bool("nanoid", false, "Generate NanoIDs instead of UUIDs")
+	rootCmd.Flags().Int("length", generator.DefaultNanoIDLength, "Length of each NanoID, with --nanoid")
+	rootCmd.Flags().String("alphabet", generator.NanoIDAlphabet, "Characters to draw NanoIDs from, with --nanoid")
+
+	// ULIDs for services that mix them with UUIDv7; not UUIDs either
+	rootCmd.Flags().Bool("ulid", false, "Generate ULIDs (48-bit millisecond timestamp and 80 random bits in Crockford base32) instead of UUIDs")
+
+	// QR codes for moving a UUID to a device without a shared clipboard
+	rootCmd.Flags().Bool("qr", false, "Render the UUID, in the active output format, as a QR code")
+	rootCmd.Flags().String("qr-level", "M", "QR error-correction level: L, M, Q, or H")
+	rootCmd.Flags().StringP("output", "o", "", "With --qr, write a PNG to this file instead of drawing on the terminal; with --pg-copy, --pg-copy-binary, or --parquet, write the rows to this file")
+	rootCmd.MarkFlagsMutuallyExclusive("qr", "count")
+	rootCmd.MarkFlagsMutuallyExclusive("qr", "per-line")
+
+	// Bulk loading with PostgreSQL's COPY ... FROM
+	rootCmd.Flags().Bool("pg-copy", false, "Print rows in PostgreSQL COPY text format")
+	rootCmd.Flags().Bool("pg-copy-binary", false, "Write rows in PostgreSQL COPY binary format, with UUIDs as 16-byte fields")
+	rootCmd.Flags().String("pg-copy-columns", pgCopyUUID, "Comma-separated column kinds for each COPY row: uuid (a new UUID) or null")
+	rootCmd.MarkFlagsMutuallyExclusive("pg-copy", "pg-copy-binary")
+
+	// Parquet files for joining generated IDs in Spark and similar tools
+	rootCmd.Flags().Bool("parquet", false, "Write the UUIDs to the --output Parquet file, with a timestamp column for UUIDv7")
+	rootCmd.Flags().String("parquet-type", "uuid", "Parquet UUID column type: uuid (16-byte UUID logical type) or string")
+	rootCmd.Flags().Int("parquet-row-group", defaultParquetRowGroup, "Rows per Parquet row group, which bounds memory use")
+
+	// Readout for support calls; pairs each UUID with its spoken form
+	rootCmd.Flags().Bool("phonetic", false, "Follow each UUID with a NATO phonetic readout (see 'uuid say')")
+	rootCmd.MarkFlagsMutuallyExclusive("phonetic", "emit", "format-name", "oid", "check-digit")
+	rootCmd.MarkFlagsMutuallyExclusive("phonetic", "json", "header")
+	rootCmd.MarkFlagsMutuallyExclusive("phonetic", "qr")
+	rootCmd.MarkFlagsMutuallyExclusive("phonetic", "per-line")
+
+	// Test data with legacy variant bits; anything but rfc is not RFC 9562 compliant
+	rootCmd.Flags().String("variant", generator.VariantRFC, "Variant bits to set in octet 8: rfc, ncs, microsoft, or future (non-rfc values are not valid RFC 9562 UUIDs)")
+	rootCmd.Flags().Bool("force", false, "Allow --variant with time-based versions")
+
+	// Node selection for UUIDv6; only mac embeds real hardware
+	rootCmd.Flags().String("node", "random", "Node for -1 and -6: random (fresh per UUID), per-process (one random node for this run), per-boot (one random node shared until reboot), mac (the MAC address shown by 'uuid interfaces'), or 12 hex digits such as 02005e100001")
+
+	// UUIDv6 clock sequence, persisted so a clock stepping backwards between
+	// runs cannot repeat a UUID
+	rootCmd.Flags().Bool("stateless", false, "Give every UUIDv6 a random clock sequence instead of persisting one between runs")
+	rootCmd.Flags().String("clock-state", "", "File persisting the UUIDv6 clock sequence (default: "+clockSequenceStateFile+" in the user cache directory)")
+	rootCmd.MarkFlagsMutuallyExclusive("stateless", "clock-state")
+
+	// Near-miss UUIDs for negative tests
+	rootCmd.Flags().String("corrupt", "", "Print deliberately invalid UUIDs: length, hyphens, hex, version, variant, or random")
+	rootCmd.Flags().Uint64("seed", 0, "INSECURE: seed a deterministic random stream so output is reproducible, for test fixtures only")
+	rootCmd.Flags().String("random-file", "", "Read every random byte from this file or device (e.g. a hardware RNG) instead of crypto/rand; fail if it runs short")
+	rootCmd.MarkFlagsMutuallyExclusive("seed", "random-file")
+
+	// UUIDv7 ordering across separate runs, such as shell script invocations
+	rootCmd.Flags().String("monotonic-state", "", "Keep -7 strictly increasing across runs by continuing the counter saved in this locked file")
+	rootCmd.RunE = withRandomFile(withMonotonicState(rootCmd.RunE))
+
+	// Load-test data: UUIDv7s created at random times over a past window
+	rootCmd.Flags().String("spread", "", "Generate UUIDv7s with random timestamps from this long ago until now, such as 30d or 12h")
+	rootCmd.Flags().String("spread-from", "", "Generate UUIDv7s with random timestamps from this time on")
+	rootCmd.Flags().String("spread-to", "", "End of the --spread-from window (default now)")
+	rootCmd.Flags().String("distribution", "uniform", "Distribution of --spread timestamps: uniform, or business-hours (09:00-17:00 UTC, Monday to Friday)")
+	rootCmd.Flags().Bool("shuffle", false, "Emit --spread UUIDs in random order instead of sorted")
+	rootCmd.MarkFlagsMutuallyExclusive("spread", "spread-from")
+	rootCmd.MarkFlagsMutuallyExclusive("spread", "spread-to")
+
+	// Re-runnable scripts: the same key returns the same UUID until it expires
+	rootCmd.Flags().Bool("idempotent", false, "Return the UUID cached for --key in the state directory ($XDG_STATE_HOME/uuid or ~/.local/state/uuid), generating and caching it on first use")
+	rootCmd.Flags().String("key", "", "Cache key for --idempotent")
+	rootCmd.Flags().Duration("ttl", 0, "With --idempotent, generate a new UUID once the cached one is older than this (0 never expires)")
+	rootCmd.Flags().String("forget", "", "Remove this key from the --idempotent cache")
+
+	// Topping up fixtures without colliding with IDs that already exist
+	rootCmd.Flags().StringArray("exclude-file", nil, "Never generate a UUID listed in this file, one per line; repeat for several")
+	rootCmd.Flags().Bool("unique", false, "Never generate the same UUID twice in one run, regenerating duplicates")
+	rootCmd.Flags().Bool("approx", false, "With --exclude-file or --unique, index the UUIDs to avoid in a Bloom filter (about 10 bits each)")
+	rootCmd.Flags().Int("exclude-retries", defaultExcludeRetries, "With --exclude-file or --unique, how many excluded UUIDs in a row to regenerate before failing")
+	rootCmd.Flags().Bool("verbose", false, "With --exclude-file or --unique, report how many UUIDs were regenerated on stderr")
+	rootCmd.Flags().Duration("time-granularity", 0, "Round the -7 timestamp down to a multiple of this duration (e.g. 1h, 24h) so it reveals only the bucket")
+	rootCmd.Flags().String("encrypt-time", "", "Encrypt the UUIDv7 timestamp with the key in this file; recover it with 'uuid reveal'")
+
+	// Frozen output for scripts; see porcelainVersions
+	addPorcelainFlag(rootCmd)
+
+	// Renderer selection; the default depends on whether stdout is a terminal
+	rootCmd.PersistentFlags().Bool("plain", false, "Force plain output even when stdout is a terminal")
+	rootCmd.PersistentFlags().Bool("pretty", false, "Force pretty output even when stdout is not a terminal")
+	rootCmd.MarkFlagsMutuallyExclusive("plain", "pretty")
+
+	// Diagnostics go to stderr only, so stdout stays clean for pipelines
+	rootCmd.PersistentFlags().Bool("debug", false, "Log generation internals to stderr")
+	rootCmd.PersistentFlags().String("debug-format", "text", "Format of --debug logs: text or json")
+
+	// Testing facility: pin "now" for every time-based generator and calculation
+	rootCmd.PersistentFlags().String("now", "", "Pretend the current time is this timestamp (for tests and reproducible pipelines)")
 
 	// Make version flags mutually exclusive
-	rootCmd.MarkFlagsMutuallyExclusive("4", "6", "7")
+	rootCmd.MarkFlagsMutuallyExclusive("1", "4", "5", "6", "7", "v4", "v6", "v7", "uuid-version")
 
 	// Set version for --version flag (combine version and build)
 	if build != "unknown" && build != "" {
@@ -2,16 +2,39 @@ package cmd
 
 import (
 	"bytes"
+	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
 
+// TestMain keeps state files, such as the UUIDv6 clock sequence, out of the
+// user's cache and state directories; tests that inspect them set cacheDir
+// or stateDir themselves
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "uuid-state")
+	if err != nil {
+		panic(err)
+	}
+	stateDir = func() (string, error) { return filepath.Join(dir, "state"), nil }
+	cacheDir = func() (string, error) { return filepath.Join(dir, "cache"), nil }
+	// Colour depends on the environment; tests that care set it themselves
+	os.Unsetenv("NO_COLOR")
+	os.Setenv("TERM", "xterm")
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestCommandLogic(t *testing.T) {
 	// Test the actual logic behind the commands by calling the generators directly
 	// This avoids the complexity of testing cobra command state
@@ -189,7 +212,7 @@ func TestTimestampFunctionality(t *testing.T) {
 			}
 
 			if tt.shouldBeUUIDv7 {
-				uuid := generator.GenerateUUIDv7WithTimestamp(parsedTime)
+				uuid := uuidV7At(t, parsedTime)
 				if !uuidRegex.MatchString(uuid) {
 					t.Errorf("Generated UUID should be valid, got: %s", uuid)
 				}
@@ -214,7 +237,7 @@ func TestTimestampValidation(t *testing.T) {
 	}
 
 	// Valid: timestamp should work with UUIDv7
-	uuid := generator.GenerateUUIDv7WithTimestamp(parsedTime)
+	uuid := uuidV7At(t, parsedTime)
 	if !uuidRegex.MatchString(uuid) {
 		t.Errorf("UUIDv7 with timestamp should be valid, got: %s", uuid)
 	}
@@ -246,7 +269,7 @@ func TestCLITimestampIntegration(t *testing.T) {
 				t.Fatalf("Failed to parse timestamp: %v", err)
 			}
 
-			uuid := generator.GenerateUUIDv7WithTimestamp(parsedTime)
+			uuid := uuidV7At(t, parsedTime)
 
 			// Validate the generated UUID
 			if !uuidRegex.MatchString(uuid) {
@@ -261,3 +284,765 @@ func TestCLITimestampIntegration(t *testing.T) {
 		})
 	}
 }
+
+// uuidV7At generates a UUIDv7 for a timestamp the field can hold
+func uuidV7At(t *testing.T, timestamp time.Time) string {
+	t.Helper()
+	uuid, err := generator.GenerateUUIDv7WithTimestamp(timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uuid
+}
+
+// executeCommand runs the root command with args and captures its output,
+// resetting every flag afterwards so tests don't leak state into each other
+func executeCommand(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	err := runCommand(stdin, &out, &out, args...)
+	return out.String(), err
+}
+
+// executeCommandSplit runs the command like executeCommand but captures
+// stdout and stderr separately
+func executeCommandSplit(t *testing.T, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	err := runCommand(stdin, &out, &errOut, args...)
+	return out.String(), errOut.String(), err
+}
+
+// runCommand executes rootCmd with the given streams and resets its state
+func runCommand(stdin string, out, errOut io.Writer, args ...string) error {
+	rootCmd.SetArgs(args)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(errOut)
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetIn(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		resetFlags(rootCmd)
+	}()
+
+	_, err := rootCmd.ExecuteC()
+	return err
+}
+
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
+
+func TestFromHex(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		warning  string
+	}{
+		{"all zero", []string{"--from-hex", "00000000000000000000000000000000"}, "00000000-0000-4000-8000-000000000000", "version nibble 0 and variant bits 00"},
+		{"all ff", []string{"-4", "--from-hex", "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF"}, "ffffffff-ffff-4fff-bfff-ffffffffffff", "version nibble f and variant bits 11"},
+		{"already v4", []string{"--from-hex", "919108f752d143209bacf847db4148a8"}, "919108f7-52d1-4320-9bac-f847db4148a8", ""},
+		{"variant only", []string{"--from-hex", "919108f752d14320dbacf847db4148a8"}, "919108f7-52d1-4320-9bac-f847db4148a8", "had variant bits 11;"},
+		{"formatted", []string{"--from-hex", "919108f752d143209bacf847db4148a8", "--format-name", "urn"}, "urn:uuid:919108f7-52d1-4320-9bac-f847db4148a8", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, err := executeCommandSplit(t, "", tt.args...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.TrimSpace(stdout) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, stdout)
+			}
+			if tt.warning == "" && stderr != "" {
+				t.Errorf("Expected no warning, got: %s", stderr)
+			}
+			if tt.warning != "" && !strings.Contains(stderr, tt.warning) {
+				t.Errorf("Expected warning containing %q, got: %q", tt.warning, stderr)
+			}
+		})
+	}
+}
+
+func TestFromHexErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"too short", []string{"--from-hex", "919108f752d14320"}, "invalid length 16 for hex32, expected 32 characters"},
+		{"not hex", []string{"--from-hex", "919108f752d14320xbacf847db4148a8"}, "invalid character 'x' at position 17"},
+		{"with v7", []string{"-7", "--from-hex", "919108f752d143209bacf847db4148a8"}, "--from-hex only constructs UUIDv4"},
+		{"with count", []string{"-n", "2", "--from-hex", "919108f752d143209bacf847db4148a8"}, "none of the others can be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestSQLServerSequential(t *testing.T) {
+	output, err := executeCommand(t, "", "--sqlserver-sequential", "-t", "2023-06-14T15:30:45.123Z", "-n", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if !strings.HasSuffix(line, "-0188ba87d903") || line[14] != '8' {
+			t.Errorf("Expected a UUIDv8 ending in the millisecond timestamp, got %s", line)
+		}
+	}
+
+	_, err = executeCommand(t, "", "--sqlserver-sequential", "-t", "1500-01-01")
+	if err == nil || !strings.Contains(err.Error(), "timestamp 1500-01-01T00:00:00Z is before the Unix epoch") {
+		t.Errorf("Expected a range error before 1970, got: %v", err)
+	}
+
+	_, err = executeCommand(t, "", "--sqlserver-sequential", "-7")
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined with -1, -4, -5, -6, or -7") {
+		t.Errorf("Expected version flag conflict, got: %v", err)
+	}
+}
+
+func TestNanoID(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		lines   int
+		pattern *regexp.Regexp
+	}{
+		{"default", []string{"--nanoid"}, 1, regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`)},
+		{"count", []string{"--nanoid", "-n", "5"}, 5, regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`)},
+		{"custom", []string{"--nanoid", "--length", "10", "--alphabet", "0123456789"}, 1, regexp.MustCompile(`^[0-9]{10}$`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(t, "", tt.args...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(output), "\n")
+			if len(lines) != tt.lines {
+				t.Fatalf("Expected %d lines, got %q", tt.lines, output)
+			}
+			for _, line := range lines {
+				if !tt.pattern.MatchString(line) {
+					t.Errorf("Unexpected NanoID %q", line)
+				}
+			}
+		})
+	}
+}
+
+func TestNanoIDErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"with timestamp", []string{"--nanoid", "-t", "2023-06-14"}, "--nanoid cannot be combined with -t"},
+		{"with version", []string{"--nanoid", "-7"}, "--nanoid cannot be combined with -7"},
+		{"with emit", []string{"--nanoid", "--emit", "base64"}, "--nanoid cannot be combined with --emit"},
+		{"length without nanoid", []string{"--length", "5"}, "--length and --alphabet require --nanoid"},
+		{"bad alphabet", []string{"--nanoid", "--alphabet", "aa"}, "duplicate character 'a'"},
+		{"bad length", []string{"--nanoid", "--length", "0"}, "nanoid length must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestULID(t *testing.T) {
+	ulidPattern := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+	output, err := executeCommand(t, "", "--ulid", "-t", "2023-06-14", "-n", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %q", output)
+	}
+	for _, line := range lines {
+		// 2023-06-14T00:00:00Z is 1686700800000 ms, 01H2VK7E00 in Crockford base32
+		if !ulidPattern.MatchString(line) || !strings.HasPrefix(line, "01H2VK7E00") {
+			t.Errorf("Unexpected ULID %q", line)
+		}
+	}
+
+	output, err = executeCommand(t, "", "--ulid", "--now", "2023-06-14T00:00:00Z")
+	if err != nil || !strings.HasPrefix(output, "01H2VK7E00") {
+		t.Errorf("Expected a ULID at the clock time, got %q, %v", output, err)
+	}
+}
+
+func TestULIDErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"with version", []string{"--ulid", "-7"}, "--ulid cannot be combined with -7"},
+		{"with nanoid", []string{"--ulid", "--nanoid"}, "--nanoid cannot be combined with --ulid"},
+		{"with emit", []string{"--ulid", "--emit", "base64"}, "--ulid cannot be combined with --emit"},
+		{"with spread", []string{"--ulid", "--spread", "1d"}, "--spread cannot be combined with --ulid"},
+		{"bad timestamp", []string{"--ulid", "-t", "yesterday"}, "unable to parse timestamp 'yesterday'"},
+		{"timestamp before 1970", []string{"--ulid", "-t", "1500-01-01"}, "timestamp 1500-01-01T00:00:00Z is before the Unix epoch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestVariantFlag(t *testing.T) {
+	tests := []struct {
+		variant string
+		mask    byte
+		bits    byte
+	}{
+		{"rfc", 0xc0, 0x80},
+		{"ncs", 0x80, 0x00},
+		{"microsoft", 0xe0, 0xc0},
+		{"future", 0xe0, 0xe0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.variant, func(t *testing.T) {
+			output, err := executeCommand(t, "", "--variant", tt.variant, "-n", "20")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+				uuid, err := generator.ParseUUID(line)
+				if err != nil {
+					t.Fatalf("Invalid output %q: %v", line, err)
+				}
+				if uuid[8]&tt.mask != tt.bits {
+					t.Errorf("Expected octet 8 of %s to match %08b under mask %08b", line, tt.bits, tt.mask)
+				}
+				if uuid[6]>>4 != 4 {
+					t.Errorf("The version nibble of %s should be untouched", line)
+				}
+				if got := generator.Inspect(uuid).Variant; got != tt.variant {
+					t.Errorf("inspect should classify %s as %s, got %s", line, tt.variant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestVariantFlagTimeBased(t *testing.T) {
+	for _, args := range [][]string{{"-7"}, {"-6"}, {"-t", "2023-06-14"}, {"--sqlserver-sequential"}} {
+		_, err := executeCommand(t, "", append(args, "--variant", "microsoft")...)
+		if err == nil || !strings.Contains(err.Error(), "use --force") {
+			t.Errorf("%v: expected time-based versions to be refused, got: %v", args, err)
+		}
+	}
+
+	output, err := executeCommand(t, "", "-7", "--variant", "future", "--force")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	uuid, _ := generator.ParseUUID(strings.TrimSpace(output))
+	if uuid[8]&0xe0 != 0xe0 {
+		t.Errorf("Expected the future variant with --force, got %s", output)
+	}
+
+	// rfc is the default and never needs --force
+	if _, err := executeCommand(t, "", "-7", "--variant", "rfc"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := executeCommand(t, "", "--variant", "apollo"); err == nil || !strings.Contains(err.Error(), "unknown variant 'apollo'") {
+		t.Errorf("Expected an unknown variant error, got: %v", err)
+	}
+}
+
+func TestLayoutFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp string
+		layout    string
+		prefix    string
+	}{
+		{"day first", "22/02/2022 19.22.22", "02/01/2006 15.04.05", "017f22e2-79b0-7"},
+		{"literal text", "Day 22 of February 2022, 19h22", "Day 02 of January 2006, 15h04", "017f22e2-23c0-7"},
+		{"with offset", "2022.02.22 14:22:22 -0500", "2006.01.02 15:04:05 -0700", "017f22e2-79b0-7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(t, "", "-t", tt.timestamp, "--layout", tt.layout)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(output, tt.prefix) {
+				t.Errorf("Expected a UUIDv7 starting %s, got %s", tt.prefix, output)
+			}
+		})
+	}
+}
+
+func TestLayoutFlagErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"without -t", []string{"--layout", "2006-01-02"}, "--layout requires -t"},
+		{"no reference elements", []string{"-t", "x", "--layout", "dd/mm/yyyy"}, "invalid layout: 'dd/mm/yyyy' has no elements of the reference time"},
+		{"no date", []string{"-t", "10:30", "--layout", "15:04"}, "'15:04' must include the year (2006), month (01 or Jan), and day (02)"},
+		{"mismatch", []string{"-t", "2023-06-14", "--layout", "02/01/2006"}, "timestamp '2023-06-14' does not match layout '02/01/2006'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestEpochFlag(t *testing.T) {
+	// GPS week 2200 began at 2022-03-06T00:00:00Z, Unix milliseconds 1646524800000
+	output, err := executeCommand(t, "", "-t", "1330560000", "--epoch", "1980-01-06")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(output, "017f5c86-9c00-7") {
+		t.Errorf("Expected a UUIDv7 for 2022-03-06, got %s", output)
+	}
+
+	output, err = executeCommand(t, "", "-t", "1330560000", "--epoch", "1980-01-06", "--sqlserver-sequential")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reference, _ := executeCommand(t, "", "-t", "2022-03-06", "--sqlserver-sequential")
+	if output[24:] != reference[24:] {
+		t.Errorf("Expected --sqlserver-sequential to use the shifted time, got %s and %s", output, reference)
+	}
+}
+
+func TestEpochFlagErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"without -t", []string{"--epoch", "1980-01-06"}, "--epoch requires -t"},
+		{"with layout", []string{"-t", "5", "--epoch", "1980-01-06", "--layout", "2006"}, "--epoch cannot be combined with --layout"},
+		{"bad epoch", []string{"-t", "5", "--epoch", "yesterday"}, "invalid --epoch: unable to parse timestamp 'yesterday'"},
+		{"not numeric", []string{"-t", "2023-06-14", "--epoch", "1980-01-06"}, "timestamp '2023-06-14' is not a whole number of seconds or milliseconds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestVersionFlagSpellings(t *testing.T) {
+	// Pin the clock so the time fields of v6 and v7 match across spellings;
+	// only the random bits differ
+	for _, tt := range []struct {
+		version   string
+		spellings [][]string
+		prefix    int
+	}{
+		{"4", [][]string{{"-4"}, {"--v4"}, {"--uuid-version", "4"}, {"--uuid-version=4"}}, 0},
+		{"6", [][]string{{"-6"}, {"--v6"}, {"--uuid-version", "6"}}, 18},
+		{"7", [][]string{{"-7"}, {"--v7"}, {"--uuid-version", "7"}}, 13},
+	} {
+		var want string
+		for _, spelling := range tt.spellings {
+			args := append([]string{"--now", "2023-06-14T10:30:45Z", "-n", "2"}, spelling...)
+			output, err := executeCommand(t, "", args...)
+			if err != nil {
+				t.Fatalf("%v: unexpected error: %v", spelling, err)
+			}
+			for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+				if !uuidRegex.MatchString(line) || line[14:15] != tt.version {
+					t.Errorf("%v: expected a UUIDv%s, got %s", spelling, tt.version, line)
+				}
+				if want == "" {
+					want = line[:tt.prefix]
+				}
+				if line[:tt.prefix] != want {
+					t.Errorf("%v: expected the time fields %s, got %s", spelling, want, line)
+				}
+			}
+		}
+	}
+
+	output, err := executeCommand(t, "", "--uuid-version", "8", "--v8-layout", "ms:48,node:10=42,seq:12,rand:52", "-t", "2023-06-14")
+	if err != nil || !strings.HasPrefix(output, "0188b733-b800-80a8-") {
+		t.Errorf("Expected --uuid-version 8 to behave like -8, got %q, %v", output, err)
+	}
+}
+
+func TestVersionFlagTimestamp(t *testing.T) {
+	for _, spelling := range [][]string{{"-7"}, {"--v7"}, {"--uuid-version", "7"}} {
+		output, err := executeCommand(t, "", append(spelling, "-t", "2023-06-14")...)
+		if err != nil || !strings.HasPrefix(output, "0188b733-b800-7") {
+			t.Errorf("%v -t: expected a UUIDv7 at the timestamp, got %q, %v", spelling, output, err)
+		}
+	}
+	for _, spelling := range [][]string{{"-6"}, {"--v6"}, {"--uuid-version", "6"}} {
+		output, err := executeCommand(t, "", append(spelling, "-t", "2023-06-14")...)
+		if err != nil || !strings.HasPrefix(output, "1ee0a466-7c4c-6000-") {
+			t.Errorf("%v -t: expected a UUIDv6 at the timestamp, got %q, %v", spelling, output, err)
+		}
+	}
+	for _, spelling := range [][]string{{"-1"}, {"--uuid-version", "1"}, {"-4"}, {"--v4"}, {"--uuid-version", "4"}} {
+		_, err := executeCommand(t, "", append(spelling, "-t", "2023-06-14")...)
+		if err == nil || !strings.Contains(err.Error(), "Timestamp flag (-t) is only supported with UUIDv6 and UUIDv7") {
+			t.Errorf("%v -t: expected a timestamp error, got: %v", spelling, err)
+		}
+	}
+}
+
+func TestVersionFlagConflicts(t *testing.T) {
+	// Every pair of spellings for different or equal versions conflicts
+	spellings := [][]string{{"-4"}, {"-6"}, {"-7"}, {"--v4"}, {"--v6"}, {"--v7"}, {"--uuid-version", "7"}}
+	for i, a := range spellings {
+		for _, b := range spellings[i+1:] {
+			_, err := executeCommand(t, "", append(append([]string{}, a...), b...)...)
+			if err == nil || !strings.Contains(err.Error(), "none of the others can be") {
+				t.Errorf("%v %v: expected a conflict, got: %v", a, b, err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"unsupported", []string{"--uuid-version", "3"}, "unsupported --uuid-version 3. Supported versions: 1, 4, 5, 6, 7, 8"},
+		{"zero", []string{"--uuid-version", "0"}, "unsupported --uuid-version 0"},
+		{"-8 with alias", []string{"-8", "--v7", "--v8-layout", "rand:64,rand:58"}, "-8 cannot be combined with --v7"},
+		{"-8 with number", []string{"-8", "--uuid-version", "8", "--v8-layout", "rand:64,rand:58"}, "-8 cannot be combined with --uuid-version"},
+		{"8 with sqlserver", []string{"--uuid-version", "8", "--sqlserver-sequential", "--v8-layout", "rand:64,rand:58"}, "--uuid-version 8 cannot be combined with --sqlserver-sequential"},
+		{"8 without layout", []string{"--uuid-version", "8"}, "-8 requires --v8-layout, --payload, or --hmac-key-file"},
+		{"sqlserver with alias", []string{"--sqlserver-sequential", "--v6"}, "cannot be combined with -1, -4, -5, -6, or -7"},
+		{"node with alias", []string{"--v7", "--node", "random"}, "--node requires -1 or -6"},
+		{"v6 timestamp with node", []string{"-6", "-t", "2023-06-14", "--node", "random"}, "-6 -t cannot be combined with --node"},
+		{"v6 timestamp with encrypt-time", []string{"-6", "-t", "2023-06-14", "--encrypt-time", "key"}, "--encrypt-time requires UUIDv7"},
+		{"nanoid with alias", []string{"--nanoid", "--v7"}, "--nanoid cannot be combined with --v7"},
+		{"corrupt with number", []string{"--corrupt", "hex", "--uuid-version", "4"}, "--corrupt cannot be combined with --uuid-version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+
+	if _, err := executeCommand(t, "", "--v6", "--node", "random"); err != nil {
+		t.Errorf("Expected --node to accept --v6, got: %v", err)
+	}
+}
+
+func TestV1Flag(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 10, 4, 12, 0, time.UTC)
+	for _, spelling := range [][]string{{"-1"}, {"--uuid-version", "1"}} {
+		output, err := executeCommand(t, "", append(spelling, "--now", "2024-03-01T10:04:12Z")...)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", spelling, err)
+		}
+		uuid, err := generator.ParseUUID(strings.TrimSpace(output))
+		if err != nil {
+			t.Fatalf("%v: invalid UUID %q: %v", spelling, output, err)
+		}
+		d := generator.Inspect(uuid)
+		if d.Version != 1 || d.Timestamp == nil || !d.Timestamp.Equal(pinned) {
+			t.Errorf("%v: expected a UUIDv1 at %s, got %q", spelling, pinned, output)
+		}
+	}
+
+	// -1 shares --node with -6
+	boot := "boot-1"
+	fakeNodeState(t, &boot)
+	output, _, err := executeCommandSplit(t, "", "-1", "--node", "per-boot", "-n", "2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Fields(output)
+	if len(lines) != 2 || lines[0][14] != '1' || nodeOf(lines[0]) != nodeOf(lines[1]) {
+		t.Errorf("Expected two UUIDv1s sharing the per-boot node, got %q", output)
+	}
+}
+
+func TestV8Flag(t *testing.T) {
+	output, err := executeCommand(t, "", "-8", "--v8-layout", "ms:48,node:10=42,seq:12,rand:52", "-t", "2023-06-14", "-n", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 UUIDs, got:\n%s", output)
+	}
+	for _, line := range lines {
+		// The timestamp leads as in UUIDv7, then version 8 and the node (42
+		// in 10 bits is 0x0a8 after the version nibble)
+		if !strings.HasPrefix(line, "0188b733-b800-80a8-") {
+			t.Errorf("Expected a UUIDv8 in the layout, got %s", line)
+		}
+	}
+}
+
+func TestV8Payload(t *testing.T) {
+	tests := []struct {
+		name, payload, want, warning string
+	}{
+		{"full", "0123456789abcdef4123456789abcdef", "01234567-89ab-8def-8123-456789abcdef", "version nibble c and variant bits 01"},
+		{"padded", "00000042000001", "00000042-0000-8100-8000-000000000000", ""},
+		{"odd length", "abc", "abc00000-0000-8000-8000-000000000000", ""},
+		{"already v8", "ffffffffffff8fffbfffffffffffffff", "ffffffff-ffff-8fff-bfff-ffffffffffff", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, err := executeCommandSplit(t, "", "-8", "--payload", tt.payload)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := strings.TrimSpace(stdout); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+			if tt.warning == "" && stderr != "" || !strings.Contains(stderr, tt.warning) {
+				t.Errorf("Expected warning %q, got %q", tt.warning, stderr)
+			}
+		})
+	}
+}
+
+func TestV8HMAC(t *testing.T) {
+	key := writeKeyFile(t, "0123456789abcdef\n")
+	output, err := executeCommand(t, "", "-8", "--hmac-key-file", key, "--name", "customer-42")
+	if err != nil || output != "08784800-cc11-81f6-b731-49fe5cce052a\n" {
+		t.Errorf("Expected the HMAC vector, got %q, %v", output, err)
+	}
+
+	other := writeKeyFile(t, "fedcba9876543210")
+	output, err = executeCommand(t, "", "--uuid-version", "8", "--hmac-key-file", other, "--name", "customer-42")
+	if err != nil || output != "c0828610-af7d-88f9-b680-ec87c48261c0\n" {
+		t.Errorf("Expected another key to give another UUID, got %q, %v", output, err)
+	}
+}
+
+func TestV8HMACErrors(t *testing.T) {
+	key := writeKeyFile(t, "0123456789abcdef")
+	short := writeKeyFile(t, "short")
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"without name", []string{"-8", "--hmac-key-file", key}, "--hmac-key-file requires --name"},
+		{"without -8", []string{"--hmac-key-file", key, "--name", "x"}, "--hmac-key-file requires -8"},
+		{"short key", []string{"-8", "--hmac-key-file", short, "--name", "x"}, "is 5 bytes; at least 16 are required"},
+		{"missing key", []string{"-8", "--hmac-key-file", "/nonexistent/key", "--name", "x"}, "cannot read --hmac-key-file"},
+		{"with layout", []string{"-8", "--hmac-key-file", key, "--name", "x", "--v8-layout", "rand:64,rand:58"}, "--hmac-key-file cannot be combined with --v8-layout"},
+		{"with timestamp", []string{"-8", "--hmac-key-file", key, "--name", "x", "-t", "2023-06-14"}, "--hmac-key-file cannot be combined with -t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestV8FlagErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"without layout", []string{"-8"}, "-8 requires --v8-layout, --payload, or --hmac-key-file"},
+		{"without -8", []string{"--v8-layout", "rand:64,rand:58"}, "--v8-layout requires -8"},
+		{"payload without -8", []string{"--payload", "00"}, "--payload requires -8"},
+		{"payload and layout", []string{"-8", "--payload", "00", "--v8-layout", "rand:64,rand:58"}, "--payload cannot be combined with --v8-layout"},
+		{"payload with count", []string{"-8", "--payload", "00", "-n", "2"}, "--payload cannot be combined with -n"},
+		{"payload too long", []string{"-8", "--payload", strings.Repeat("ab", 17)}, "invalid --payload: 34 hex characters is more than the 16 bytes a UUIDv8 holds"},
+		{"payload not hex", []string{"-8", "--payload", "shard"}, "invalid --payload 'shard': expected hex characters"},
+		{"bad layout", []string{"-8", "--v8-layout", "ms:48,rand:64"}, "invalid --v8-layout: layout widths add up to 112 bits, but a UUIDv8 has 122 to fill"},
+		{"layout before 1970", []string{"-8", "--v8-layout", "ms:48,rand:62,rand:12", "-t", "1500-01-01"}, "timestamp 1500-01-01T00:00:00Z is before the Unix epoch"},
+		{"layout ms field too narrow", []string{"-8", "--v8-layout", "ms:40,rand:64,rand:18", "-t", "2023-06-14"}, "the last timestamp a 40-bit ms field holds"},
+		{"with -7", []string{"-8", "-7", "--v8-layout", "rand:64,rand:58"}, "-8 cannot be combined with -7"},
+		{"with sqlserver", []string{"-8", "--sqlserver-sequential", "--v8-layout", "rand:64,rand:58"}, "-8 cannot be combined with --sqlserver-sequential"},
+		{"with nanoid", []string{"-8", "--nanoid", "--v8-layout", "rand:64,rand:58"}, "--nanoid cannot be combined with -8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestTimeGranularity(t *testing.T) {
+	output, err := executeCommand(t, "", "-7", "--time-granularity", "1h", "--now", "2024-03-01T10:04:12.345Z", "-n", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, line := range strings.Fields(output) {
+		uuid, err := generator.ParseUUID(line)
+		if err != nil {
+			t.Fatalf("Invalid UUID %q: %v", line, err)
+		}
+		want := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		if ts := generator.Inspect(uuid).Timestamp; ts == nil || !ts.Equal(want) {
+			t.Errorf("Expected the timestamp rounded down to %s, got %v", want, ts)
+		}
+	}
+}
+
+func TestTimeGranularityErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"without v7", []string{"--time-granularity", "1h"}, "--time-granularity requires -7"},
+		{"with v6", []string{"-6", "--time-granularity", "1h"}, "--time-granularity requires -7"},
+		{"with timestamp", []string{"-7", "-t", "2024-03-01", "--time-granularity", "1h"}, "--time-granularity cannot be combined with -t"},
+		{"with spread", []string{"-7", "--spread", "1d", "--time-granularity", "1h"}, "--time-granularity cannot be combined with --spread"},
+		{"too fine", []string{"-7", "--time-granularity", "10us"}, "--time-granularity must be at least 1ms, got 10µs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestSeedFlag(t *testing.T) {
+	stdout, stderr, err := executeCommandSplit(t, "", "-n", "2", "--seed", "42")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Frozen: the ChaCha8 stream for seed 42 is the same on every platform
+	if want := "22301fb8-d829-48da-b007-b05614969f34\n03d00626-73f5-4944-9579-8d340c0a17e8\n"; stdout != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, stdout)
+	}
+	if !strings.Contains(stderr, "WARNING: --seed makes every generated value predictable") {
+		t.Errorf("Expected an insecurity warning, got %q", stderr)
+	}
+
+	args := []string{"-7", "-n", "3", "--seed", "7", "--now", "2024-03-01T10:04:12.345Z"}
+	first, _, _ := executeCommandSplit(t, "", args...)
+	again, _, _ := executeCommandSplit(t, "", args...)
+	if first != again {
+		t.Errorf("Expected --seed with --now to reproduce UUIDv7s, got\n%s\nthen\n%s", first, again)
+	}
+
+	// Without --seed, crypto/rand is back and nothing is printed
+	stdout, stderr, err = executeCommandSplit(t, "", "-n", "2")
+	if err != nil || stdout == "22301fb8-d829-48da-b007-b05614969f34\n03d00626-73f5-4944-9579-8d340c0a17e8\n" || stderr != "" {
+		t.Errorf("Expected random output without a warning, got %q, %q, %v", stdout, stderr, err)
+	}
+}
+
+func TestTimestampBeforeEpoch(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"v7", []string{"-7", "-t", "1969-12-31"}},
+		{"default version", []string{"-t", "1969-12-31"}},
+		{"all", []string{"--all", "-t", "1969-12-31"}},
+		{"series", []string{"series", "--from", "1969-12-31", "--to", "1970-01-02", "--step", "1d"}},
+		{"spread", []string{"-7", "-n", "3", "--spread-from", "1969-12-31", "--spread-to", "1970-01-02"}},
+		{"bounds", []string{"bounds", "-t", "1969-12-31"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, _, err := executeCommandSplit(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), "timestamp 1969-12-31T00:00:00Z is before the Unix epoch") {
+				t.Errorf("Expected a before the epoch error, got: %v", err)
+			}
+			if output != "" {
+				t.Errorf("Expected no UUIDs, got %q", output)
+			}
+		})
+	}
+
+	output, err := executeCommand(t, "", "-7", "-t", "1970-01-01")
+	if err != nil || !strings.HasPrefix(output, "00000000-0000-7") {
+		t.Errorf("Expected a UUIDv7 at the epoch itself, got %q, %v", output, err)
+	}
+}
+
+func TestTimestampBeyondField(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"v7", []string{"-7", "-t", "999999999999999999"}, "is after 10889-08-02T05:31:50.655Z, the last UUIDv7 timestamp"},
+		{"v6", []string{"-6", "-t", "5300-01-01"}, "timestamp 5300-01-01T00:00:00Z is after 5236-03-31T21:21:00.6846975Z, the last UUIDv6 timestamp"},
+		{"v6 before the Gregorian reform", []string{"-6", "-t", "1500-01-01"}, "is before 1582-10-15T00:00:00Z, the first UUIDv6 timestamp"},
+		{"all", []string{"--all", "-t", "5300-01-01"}, "the last UUIDv6 timestamp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, _, err := executeCommandSplit(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+			if output != "" {
+				t.Errorf("Expected no UUIDs, got %q", output)
+			}
+		})
+	}
+}
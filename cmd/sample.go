@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// sampleCmd draws a random sample of lines from a UUID stream
+var sampleCmd = &cobra.Command{
+	Use:   "sample (-k <n> | --percent <p>)",
+	Short: "Randomly sample UUIDs from files or stdin",
+	Long: `Randomly sample lines from a stream of UUIDs in a single pass, reading each
+--file in order, or stdin when none is given.
+
+With -k, exactly k lines are chosen uniformly using reservoir sampling (or
+every line when the input has fewer than k). With --percent, each line is
+kept independently with the given probability, so the sample size varies.
+Lines are printed exactly as read. Blank lines are ignored; other invalid
+lines are sampled like any other unless --only-valid is given.
+
+By default -k prints the sample in reservoir order; --keep-order re-emits it
+in input order. Use --seed for a reproducible sample.
+
+Examples:
+  uuid sample -k 1000 < ids.txt
+  uuid sample -k 1000 --seed 42 --keep-order < ids.txt
+  uuid sample -k 1000 -f monday.txt -f tuesday.txt
+  uuid sample --percent 0.1 < ids.txt`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, _ := cmd.Flags().GetInt("k")
+		percent, _ := cmd.Flags().GetFloat64("percent")
+		keepOrder, _ := cmd.Flags().GetBool("keep-order")
+		onlyValid, _ := cmd.Flags().GetBool("only-valid")
+
+		byCount := cmd.Flags().Changed("k")
+		byPercent := cmd.Flags().Changed("percent")
+		if !byCount && !byPercent {
+			return fmt.Errorf("one of -k or --percent is required")
+		}
+		if byCount && k < 1 {
+			return fmt.Errorf("-k must be at least 1, got %d", k)
+		}
+		if byPercent && (percent <= 0 || percent > 100) {
+			return fmt.Errorf("--percent must be greater than 0 and at most 100, got %g", percent)
+		}
+
+		seed, err := seedFlag(cmd)
+		if err != nil {
+			return err
+		}
+		rng := rand.New(rand.NewPCG(seed, seed))
+
+		out := cmd.OutOrStdout()
+		each := func(fn func(sampledLine)) error {
+			return eachSampleLine(cmd, onlyValid, fn)
+		}
+
+		if byPercent {
+			// Bernoulli sampling keeps input order naturally
+			return each(func(line sampledLine) {
+				if rng.Float64()*100 < percent {
+					fmt.Fprintln(out, line.text)
+				}
+			})
+		}
+
+		reservoir, err := reservoirSample(each, k, rng)
+		if err != nil {
+			return err
+		}
+		if keepOrder {
+			sort.Slice(reservoir, func(i, j int) bool { return reservoir[i].index < reservoir[j].index })
+		}
+		for _, line := range reservoir {
+			fmt.Fprintln(out, line.text)
+		}
+		return nil
+	},
+}
+
+// sampledLine is an input line along with its position in the stream
+type sampledLine struct {
+	index int
+	text  string
+}
+
+// eachSampleLine calls fn for every non-blank line of the --file inputs, or
+// of stdin, optionally dropping lines that are not valid UUIDs. Lines are
+// numbered across all of the inputs.
+func eachSampleLine(cmd *cobra.Command, onlyValid bool, fn func(sampledLine)) error {
+	index := 0
+	return eachInput(cmd, nil, eachRawInputLine, func(text string) error {
+		if onlyValid {
+			if _, err := generator.ParseUUID(strings.TrimSpace(text)); err != nil {
+				return nil
+			}
+		}
+		fn(sampledLine{index: index, text: text})
+		index++
+		return nil
+	})
+}
+
+// reservoirSample implements Algorithm R over the lines each produces: the
+// first k lines fill the reservoir, then line i replaces a random slot with
+// probability k/(i+1)
+func reservoirSample(each func(func(sampledLine)) error, k int, rng *rand.Rand) ([]sampledLine, error) {
+	reservoir := make([]sampledLine, 0, k)
+	err := each(func(line sampledLine) {
+		if len(reservoir) < k {
+			reservoir = append(reservoir, line)
+			return
+		}
+		if j := rng.IntN(line.index + 1); j < k {
+			reservoir[j] = line
+		}
+	})
+	return reservoir, err
+}
+
+// seedFlag returns the --seed value, or a random seed when none is given
+func seedFlag(cmd *cobra.Command) (uint64, error) {
+	if cmd.Flags().Changed("seed") {
+		seed, _ := cmd.Flags().GetUint64("seed")
+		return seed, nil
+	}
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return 0, fmt.Errorf("unable to generate seed: %w", err)
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+func init() {
+	sampleCmd.Flags().IntP("k", "k", 0, "Number of lines to sample")
+	sampleCmd.Flags().Float64("percent", 0, "Percentage of lines to keep, as an alternative to -k")
+	sampleCmd.Flags().Uint64("seed", 0, "Seed for a reproducible sample")
+	sampleCmd.Flags().Bool("keep-order", false, "Print the -k sample in input order")
+	sampleCmd.Flags().Bool("only-valid", false, "Skip lines that are not valid UUIDs")
+	addInputFlags(sampleCmd)
+	sampleCmd.MarkFlagsMutuallyExclusive("k", "percent")
+	sampleCmd.MarkFlagsMutuallyExclusive("percent", "keep-order")
+
+	rootCmd.AddCommand(sampleCmd)
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sampleInput returns n distinct UUID lines numbered from 1
+func sampleInput(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%08d-0000-4000-8000-000000000000\n", i)
+	}
+	return b.String()
+}
+
+func TestSampleSeeded(t *testing.T) {
+	output, err := executeCommand(t, sampleInput(20), "sample", "-k", "4", "--seed", "42")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "00000015-0000-4000-8000-000000000000\n" +
+		"00000016-0000-4000-8000-000000000000\n" +
+		"00000011-0000-4000-8000-000000000000\n" +
+		"00000019-0000-4000-8000-000000000000\n"
+	if output != expected {
+		t.Errorf("Seeded sample changed:\n%s", output)
+	}
+
+	output, err = executeCommand(t, sampleInput(20), "sample", "-k", "4", "--seed", "42", "--keep-order")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = "00000011-0000-4000-8000-000000000000\n" +
+		"00000015-0000-4000-8000-000000000000\n" +
+		"00000016-0000-4000-8000-000000000000\n" +
+		"00000019-0000-4000-8000-000000000000\n"
+	if output != expected {
+		t.Errorf("--keep-order should re-emit the same sample in input order:\n%s", output)
+	}
+}
+
+func TestSamplePercentSeeded(t *testing.T) {
+	output, err := executeCommand(t, sampleInput(20), "sample", "--percent", "20", "--seed", "7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var numbers []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		numbers = append(numbers, line[6:8])
+	}
+	if got := strings.Join(numbers, ","); got != "01,06,08,10,11,20" {
+		t.Errorf("Seeded percent sample changed: %s", got)
+	}
+}
+
+func TestSampleFewerLinesThanK(t *testing.T) {
+	output, err := executeCommand(t, sampleInput(3), "sample", "-k", "10", "--keep-order")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != sampleInput(3) {
+		t.Errorf("All lines should be returned when input is smaller than k, got: %q", output)
+	}
+}
+
+func TestSamplePreservesLineTextAndFiltersInvalid(t *testing.T) {
+	input := "  {0188B733-B800-7079-9CE7-7022B2BA0185}\nnot-a-uuid\n\n"
+
+	output, err := executeCommand(t, input, "sample", "-k", "5", "--keep-order")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "  {0188B733-B800-7079-9CE7-7022B2BA0185}\nnot-a-uuid\n" {
+		t.Errorf("Lines should be printed exactly as read, got: %q", output)
+	}
+
+	output, err = executeCommand(t, input, "sample", "-k", "5", "--only-valid")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(output, "not-a-uuid") {
+		t.Errorf("--only-valid should skip invalid lines, got: %q", output)
+	}
+}
+
+func TestSampleFiles(t *testing.T) {
+	files := writeInputFiles(t, inputUUID1+"\n  "+inputUUID2+"\n", "\n"+inputUUID3+"\n")
+
+	output, err := executeCommand(t, "", "sample", "-k", "5", "--keep-order", "-f", files[0], "-f", files[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := inputUUID1 + "\n  " + inputUUID2 + "\n" + inputUUID3 + "\n"
+	if output != expected {
+		t.Errorf("Expected every line of both files in order, got: %q", output)
+	}
+
+	// Lines are numbered across files, so a full -k sample spans both
+	output, err = executeCommand(t, "", "sample", "-k", "2", "--seed", "1", "-f", files[0], "-f", files[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(output), "\n"); len(lines) != 2 {
+		t.Errorf("Expected 2 sampled lines, got: %q", output)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	_, err = executeCommand(t, "", "sample", "--percent", "100", "-f", missing)
+	if err == nil || !strings.Contains(err.Error(), missing+": no such file or directory") {
+		t.Errorf("Expected the missing file to be named, got: %v", err)
+	}
+}
+
+func TestSampleUniformInclusion(t *testing.T) {
+	const n, k, runs = 20, 5, 20000
+	counts := make([]int, n)
+
+	for run := 0; run < runs; run++ {
+		rng := rand.New(rand.NewPCG(uint64(run), 1))
+		reservoir, err := reservoirSample(func(fn func(sampledLine)) error {
+			for i := 0; i < n; i++ {
+				fn(sampledLine{index: i})
+			}
+			return nil
+		}, k, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range reservoir {
+			counts[line.index]++
+		}
+	}
+
+	// Each line should be included with probability k/n = 0.25
+	expected := float64(runs) * k / n
+	for i, c := range counts {
+		if deviation := (float64(c) - expected) / expected; deviation > 0.05 || deviation < -0.05 {
+			t.Errorf("Line %d included %d times, expected about %.0f", i, c, expected)
+		}
+	}
+}
+
+func TestSampleErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"no mode", []string{"sample"}, "one of -k or --percent is required"},
+		{"zero k", []string{"sample", "-k", "0"}, "-k must be at least 1"},
+		{"bad percent", []string{"sample", "--percent", "150"}, "--percent must be greater than 0"},
+		{"both modes", []string{"sample", "-k", "3", "--percent", "5"}, "none of the others can be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
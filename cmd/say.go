@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// phoneticWords spells each hex digit with the NATO alphabet, using "niner"
+// so nine is not mistaken for the German "nein"
+var phoneticWords = map[rune]string{
+	'0': "zero", '1': "one", '2': "two", '3': "three", '4': "four",
+	'5': "five", '6': "six", '7': "seven", '8': "eight", '9': "niner",
+	'a': "alfa", 'b': "bravo", 'c': "charlie", 'd': "delta", 'e': "echo", 'f': "foxtrot",
+}
+
+// sayCmd prints the phonetic readout of existing UUIDs
+var sayCmd = &cobra.Command{
+	Use:   "say [uuid...]",
+	Short: "Spell UUIDs out with the NATO phonetic alphabet",
+	Long: `Spell UUIDs out for reading aloud.
+
+Each UUID is printed in canonical form, followed by a line that spells it
+in chunks of four characters separated by "—". Letters use the NATO alphabet,
+digits are spoken (nine is "niner"), and hyphens are read as "dash".
+
+UUIDs are read from the arguments, or one per line from each --file in
+turn ('-' is stdin), or from stdin when no arguments are given.
+
+Examples:
+  uuid say d9428888-122b-11e1-b85c-61cd3cbb3210
+  uuid -7 --phonetic`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		say := func(value string) error {
+			uuid, err := generator.ParseUUID(value)
+			if err != nil {
+				return fmt.Errorf("invalid UUID '%s': %w", value, err)
+			}
+			writePhonetic(out, generator.FormatUUID(uuid))
+			return nil
+		}
+
+		return eachInputValue(cmd, args, say)
+	},
+}
+
+// writePhonetic prints a canonical UUID followed by its phonetic readout
+func writePhonetic(out io.Writer, canonical string) {
+	fmt.Fprintln(out, canonical)
+	fmt.Fprintln(out, phonetic(canonical))
+}
+
+// phonetic spells a canonical UUID group by group, splitting each group into
+// chunks of four characters
+func phonetic(canonical string) string {
+	groups := strings.Split(canonical, "-")
+	spoken := make([]string, len(groups))
+	for i, group := range groups {
+		var chunks []string
+		for len(group) > 0 {
+			n := min(4, len(group))
+			words := make([]string, n)
+			for j, c := range group[:n] {
+				words[j] = phoneticWords[c]
+			}
+			chunks = append(chunks, strings.Join(words, " "))
+			group = group[n:]
+		}
+		spoken[i] = strings.Join(chunks, " — ")
+	}
+	return strings.Join(spoken, " dash ")
+}
+
+func init() {
+	addInputFlags(sayCmd)
+	rootCmd.AddCommand(sayCmd)
+}
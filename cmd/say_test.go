@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSayExpansion(t *testing.T) {
+	output, err := executeCommand(t, "", "say", "01234567-89AB-CDEF-0123-456789ABCDEF")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "01234567-89ab-cdef-0123-456789abcdef\n" +
+		"zero one two three — four five six seven dash " +
+		"eight niner alfa bravo dash " +
+		"charlie delta echo foxtrot dash " +
+		"zero one two three dash " +
+		"four five six seven — eight niner alfa bravo — charlie delta echo foxtrot\n"
+	if output != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestSayStdin(t *testing.T) {
+	output, err := executeCommand(t, "{d9428888-122b-11e1-b85c-61cd3cbb3210}\n\n", "say")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(output, "d9428888-122b-11e1-b85c-61cd3cbb3210\ndelta niner four two — eight eight eight eight dash one two two bravo dash") {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	_, err = executeCommand(t, "", "say", "not-a-uuid")
+	if err == nil || !strings.Contains(err.Error(), "invalid UUID 'not-a-uuid'") {
+		t.Errorf("Expected invalid UUID error, got: %v", err)
+	}
+}
+
+func TestPhoneticFlag(t *testing.T) {
+	output, err := executeCommand(t, "", "--phonetic", "-n", "2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a UUID and a readout for each of 2 UUIDs, got %q", output)
+	}
+	for i := 0; i < len(lines); i += 2 {
+		if !uuidRegex.MatchString(lines[i]) || lines[i+1] != phonetic(lines[i]) {
+			t.Errorf("Expected %q followed by its readout, got %q", lines[i], lines[i+1])
+		}
+	}
+
+	output, err = executeCommand(t, "", "--phonetic", "--from-hex", "919108f752d143209bacf847db4148a8")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(output, "919108f7-52d1-4320-9bac-f847db4148a8\nniner one niner one — zero eight foxtrot seven dash") {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	_, err = executeCommand(t, "", "--phonetic", "--format-name", "urn")
+	if err == nil || !strings.Contains(err.Error(), "none of the others can be") {
+		t.Errorf("Expected a flag conflict, got: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// defaultSeriesMaxCount is the largest series generated without raising
+// --max-count, about a year at one UUID a minute
+const defaultSeriesMaxCount = 1_000_000
+
+// seriesCmd generates UUIDv7s at even steps over a time range
+var seriesCmd = &cobra.Command{
+	Use:   "series --from <timestamp> --to <timestamp> --step <duration>",
+	Short: "Generate one UUIDv7 per step over a time range",
+	Long: `Generate one UUIDv7 for every --step from --from to --to, one per line,
+for loading time-partitioned tables with evenly spread keys.
+
+--from and --to take any timestamp -t accepts (see uuid parse-timestamp).
+The range includes --from, and --to when it falls on a step. --step is a
+Go duration such as 90s or 1h30m, or a whole number of days or weeks such
+as 1d or 2w; it must be a positive whole number of milliseconds, since
+that is the UUIDv7 timestamp's precision.
+
+The UUID for slot i embeds --from plus i steps, truncated to the
+millisecond, in its first 48 bits; the rest is random, so the output is
+sorted. UUIDs are written as they are generated rather than held in memory.
+
+A series longer than --max-count (default 1000000) is refused before
+anything is written; raise --max-count to generate it.
+
+Examples:
+  uuid series --from 2023-01-01 --to 2023-12-31 --step 1h
+  uuid series --from 2024-03-01T09:00:00Z --to 2024-03-01T17:00:00Z --step 100ms --max-count 300000`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromValue, _ := cmd.Flags().GetString("from")
+		toValue, _ := cmd.Flags().GetString("to")
+		stepValue, _ := cmd.Flags().GetString("step")
+		maxCount, _ := cmd.Flags().GetInt("max-count")
+
+		from, err := parseTimestamp(fromValue)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+		to, err := parseTimestamp(toValue)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+		if from.After(to) {
+			return fmt.Errorf("--from (%s) is after --to (%s)", from.UTC().Format(time.RFC3339Nano), to.UTC().Format(time.RFC3339Nano))
+		}
+		step, err := parseDays(stepValue)
+		if err != nil {
+			return fmt.Errorf("invalid --step '%s': %w", stepValue, err)
+		}
+		if step <= 0 {
+			return fmt.Errorf("--step must be positive, got %s", stepValue)
+		}
+		if step%time.Millisecond != 0 {
+			return fmt.Errorf("--step must be a whole number of milliseconds, got %s", stepValue)
+		}
+		for _, endpoint := range []time.Time{from, to} {
+			if err := generator.CheckUUIDv7Timestamp(endpoint); err != nil {
+				return err
+			}
+		}
+		if maxCount < 1 {
+			return fmt.Errorf("--max-count must be at least 1, got %d", maxCount)
+		}
+
+		count := int64(to.Sub(from)/step) + 1
+		if count > int64(maxCount) {
+			return fmt.Errorf("the series has %d UUIDs, more than --max-count %d; raise --max-count to generate it", count, maxCount)
+		}
+		debugLogger.Debug("selected generator", "version", 7, "timestamp_source", "series", "from", from, "to", to, "step", step, "count", count)
+
+		out := bufio.NewWriter(cmd.OutOrStdout())
+		for i := int64(0); i < count; i++ {
+			uuid, err := generator.GenerateUUIDv7WithTimestamp(from.Add(time.Duration(i) * step))
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(out, uuid); err != nil {
+				return err
+			}
+		}
+		return out.Flush()
+	},
+}
+
+func init() {
+	seriesCmd.Flags().String("from", "", "First timestamp of the series")
+	seriesCmd.Flags().String("to", "", "Last timestamp of the series, included when it falls on a step")
+	seriesCmd.Flags().String("step", "", "Time between UUIDs, such as 1h, 90s, or 1d")
+	seriesCmd.Flags().Int("max-count", defaultSeriesMaxCount, "Refuse series longer than this")
+	_ = seriesCmd.MarkFlagRequired("from")
+	_ = seriesCmd.MarkFlagRequired("to")
+	_ = seriesCmd.MarkFlagRequired("step")
+
+	rootCmd.AddCommand(seriesCmd)
+}
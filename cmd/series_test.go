@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestSeries(t *testing.T) {
+	output, err := executeCommand(t, "", "series", "--from", "2024-03-01T00:00:00Z", "--to", "2024-03-01T00:00:01Z", "--step", "250ms")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 UUIDs including both endpoints, got %q", output)
+	}
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, line := range lines {
+		uuid, err := generator.ParseUUID(line)
+		if err != nil {
+			t.Fatalf("Line %d: %v", i, err)
+		}
+		details := generator.Inspect(uuid)
+		want := from.Add(time.Duration(i) * 250 * time.Millisecond)
+		if details.Version != 7 || details.Timestamp == nil || !details.Timestamp.Equal(want) {
+			t.Errorf("Line %d: expected a UUIDv7 at %s, got %s (%+v)", i, want, line, details)
+		}
+	}
+}
+
+func TestSeriesEndBetweenSteps(t *testing.T) {
+	output, err := executeCommand(t, "", "series", "--from", "2023-01-01", "--to", "2023-01-03T12:00:00Z", "--step", "1d")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := strings.Count(output, "\n"); n != 3 {
+		t.Errorf("Expected 3 UUIDs, the last on 2023-01-03, got %d: %q", n, output)
+	}
+
+	output, err = executeCommand(t, "", "series", "--from", "2023-01-01", "--to", "2023-01-01", "--step", "1h")
+	if err != nil || strings.Count(output, "\n") != 1 {
+		t.Errorf("Expected a single UUID when --from equals --to, got %q, %v", output, err)
+	}
+}
+
+func TestSeriesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"backwards", []string{"--from", "2024-01-02", "--to", "2024-01-01", "--step", "1h"}, "--from (2024-01-02T00:00:00Z) is after --to (2024-01-01T00:00:00Z)"},
+		{"zero step", []string{"--from", "2024-01-01", "--to", "2024-01-02", "--step", "0s"}, "--step must be positive, got 0s"},
+		{"negative step", []string{"--from", "2024-01-01", "--to", "2024-01-02", "--step", "-1h"}, "--step must be positive"},
+		{"sub-millisecond step", []string{"--from", "2024-01-01", "--to", "2024-01-02", "--step", "1500us"}, "--step must be a whole number of milliseconds"},
+		{"bad step", []string{"--from", "2024-01-01", "--to", "2024-01-02", "--step", "often"}, "invalid --step 'often'"},
+		{"bad from", []string{"--from", "yesterday", "--to", "2024-01-02", "--step", "1h"}, "invalid --from"},
+		{"bad to", []string{"--from", "2024-01-01", "--to", "tomorrow", "--step", "1h"}, "invalid --to"},
+		{"too long", []string{"--from", "2023-01-01", "--to", "2023-12-31", "--step", "1s"}, "the series has 31449601 UUIDs, more than --max-count 1000000"},
+		{"custom cap", []string{"--from", "2024-01-01", "--to", "2024-01-02", "--step", "1h", "--max-count", "10"}, "the series has 25 UUIDs, more than --max-count 10"},
+		{"bad cap", []string{"--from", "2024-01-01", "--to", "2024-01-02", "--step", "1h", "--max-count", "0"}, "--max-count must be at least 1"},
+		{"missing step", []string{"--from", "2024-01-01", "--to", "2024-01-02"}, `required flag(s) "step" not set`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, _, err := executeCommandSplit(t, "", append([]string{"series"}, tt.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+			if output != "" {
+				t.Errorf("Expected nothing written before the error, got %q", output)
+			}
+		})
+	}
+}
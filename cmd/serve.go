@@ -0,0 +1,686 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// serveSignals returns the signals the server acts on: SIGINT and SIGTERM
+// stop it, and SIGHUP reloads the TLS certificate. Tests replace it to
+// deliver signals directly.
+var serveSignals = func() (<-chan os.Signal, func()) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	return signals, func() { signal.Stop(signals) }
+}
+
+// serveStarted is called with the listening addresses once the server
+// accepts connections
+var serveStarted = func(addrs []net.Addr) {}
+
+// serveCmd runs an HTTP service generating UUIDs
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve UUIDs over HTTP",
+	Long: `Run an HTTP service that generates UUIDs, for services that cannot link
+this project's generator.
+
+Endpoints:
+
+  GET /uuid?version=7&count=10  {"uuids": [...]}; version is 4 (default), 6,
+                                or 7, and count 1 (default) to --max-count
+  POST /v5                      {"uuid": ...} for {"namespace": NS, "name": NAME},
+                                or {"uuids": [...]} for {"namespace": NS,
+                                "names": [...]} of up to --max-batch names
+  GET /v5?namespace=NS&name=N   the same; repeat name for a batch
+  GET /stream?version=7&interval=1s
+                                one UUID per interval as Server-Sent Events
+  GET /inspect/{uuid}           the 'uuid inspect --json' record of a UUID
+  POST /inspect                 the same for a JSON string body, or an array
+                                of records for a JSON array of up to
+                                --max-batch strings
+  GET /healthz                  {"status": "ok"}
+  GET /metrics                  request and generation counters in the
+                                Prometheus text format
+
+Errors are JSON of the form {"error": {"code": "...", "message": "..."}}.
+/v5 derives UUIDv5s exactly as 'uuid -5' does. The namespace is a name from
+'uuid namespace list', a keyword (dns, url, oid, x500), or a UUID; an
+unknown one gets 422 with code "invalid_namespace". Answers carry an ETag
+and may be cached for a year, or 5 minutes for registry names, which can
+be changed.
+
+/stream sends "uuid" events whose data is the UUID, the first at once;
+interval defaults to 1s and may not be below --stream-min-interval. After
+--stream-max-events a final "end" event is sent and the connection closed.
+Beyond --max-streams open streams, new ones get 503.
+
+A value /inspect cannot parse gets 422 with code "invalid_uuid" and a
+"reason" from 'uuid validate': length, hyphens, or hex.
+
+--listen takes host:port, or unix:PATH for a Unix socket. With --tls-cert
+and --tls-key the service speaks HTTPS only, with TLS 1.2 or later and
+forward-secret AEAD cipher suites; SIGHUP reloads both files, keeping the
+previous certificate if they cannot be loaded.
+
+--auth-token requires an "Authorization: Bearer TOKEN" header on every
+endpoint except /healthz, answering 401 otherwise. Prefer --auth-token-file,
+which keeps the token out of the process list; surrounding whitespace in the
+file is ignored.
+
+--rate-limit N/s (or N/m, N/h) limits each client IP with a token bucket
+holding --burst requests, which defaults to N. Requests over the limit get
+429 with a Retry-After header. /healthz is not limited. Behind a reverse
+proxy, --trust-proxy limits by the last X-Forwarded-For address, the one
+the proxy recorded; only use it when every request arrives through a proxy,
+since otherwise clients can set the header themselves.
+
+Under systemd socket activation (LISTEN_PID and LISTEN_FDS), the passed
+TCP or Unix sockets are served instead of --listen. With NOTIFY_SOCKET set,
+READY=1 is sent once serving and STOPPING=1 when shutdown starts, for
+Type=notify units.
+
+SIGINT or SIGTERM stops the service gracefully: it stops accepting
+connections (removing a Unix socket), /healthz answers 503, open streams
+get a final "end" event, and in-flight requests get up to
+--shutdown-timeout to finish. The exit status is 0 when they all do. A
+second SIGINT or SIGTERM drops them and exits at once, non-zero.
+
+Examples:
+  uuid serve
+  uuid serve --listen unix:/run/uuid.sock
+  uuid serve --rate-limit 100/s --burst 200 --trust-proxy
+  uuid serve --listen :8443 --tls-cert cert.pem --tls-key key.pem --auth-token-file token
+  curl -H "Authorization: Bearer $(cat token)" "https://localhost:8443/uuid?version=7&count=5"`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := newServer(cmd)
+		if err != nil {
+			return err
+		}
+		listeners, err := activatedListeners()
+		if err != nil {
+			return err
+		}
+		if listeners != nil {
+			debugLogger.Debug("using socket-activated listeners", "count", len(listeners))
+		} else {
+			listen, _ := cmd.Flags().GetString("listen")
+			listener, err := serveListen(listen)
+			if err != nil {
+				return err
+			}
+			listeners = []net.Listener{listener}
+		}
+		return s.run(listeners, cmd.ErrOrStderr())
+	},
+}
+
+// server holds the configuration and state of a running uuid serve
+type server struct {
+	maxCount int
+	maxBatch int
+
+	streamMinInterval time.Duration
+	streamMaxEvents   int
+	maxStreams        int
+	// streams counts the open /stream connections
+	streams atomic.Int64
+
+	shutdownTimeout time.Duration
+	// draining is set once shutdown starts, failing /healthz
+	draining atomic.Bool
+	// stopping is closed once shutdown starts, ending open streams
+	stopping chan struct{}
+
+	// token is the SHA-256 of the --auth-token, or nil when none is required
+	token      []byte
+	certs      *certReloader
+	limiter    *rateLimiter
+	trustProxy bool
+	metrics    *serveMetrics
+	mux        *http.ServeMux
+}
+
+func newServer(cmd *cobra.Command) (*server, error) {
+	maxCount, _ := cmd.Flags().GetInt("max-count")
+	maxBatch, _ := cmd.Flags().GetInt("max-batch")
+	streamMinInterval, _ := cmd.Flags().GetDuration("stream-min-interval")
+	streamMaxEvents, _ := cmd.Flags().GetInt("stream-max-events")
+	maxStreams, _ := cmd.Flags().GetInt("max-streams")
+	shutdownTimeout, _ := cmd.Flags().GetDuration("shutdown-timeout")
+	certFile, _ := cmd.Flags().GetString("tls-cert")
+	keyFile, _ := cmd.Flags().GetString("tls-key")
+
+	if maxCount < 1 {
+		return nil, fmt.Errorf("--max-count must be at least 1, got %d", maxCount)
+	}
+	if maxBatch < 1 {
+		return nil, fmt.Errorf("--max-batch must be at least 1, got %d", maxBatch)
+	}
+	if streamMinInterval <= 0 {
+		return nil, fmt.Errorf("--stream-min-interval must be positive, got %s", streamMinInterval)
+	}
+	if streamMaxEvents < 1 {
+		return nil, fmt.Errorf("--stream-max-events must be at least 1, got %d", streamMaxEvents)
+	}
+	if maxStreams < 1 {
+		return nil, fmt.Errorf("--max-streams must be at least 1, got %d", maxStreams)
+	}
+	if shutdownTimeout <= 0 {
+		return nil, fmt.Errorf("--shutdown-timeout must be positive, got %s", shutdownTimeout)
+	}
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("--tls-cert and --tls-key must be given together")
+	}
+	if err := checkClock(cmd); err != nil {
+		return nil, err
+	}
+
+	s := &server{
+		maxCount: maxCount,
+		maxBatch: maxBatch,
+
+		streamMinInterval: streamMinInterval,
+		streamMaxEvents:   streamMaxEvents,
+		maxStreams:        maxStreams,
+
+		shutdownTimeout: shutdownTimeout,
+		stopping:        make(chan struct{}),
+
+		metrics: newServeMetrics(),
+		mux:     http.NewServeMux(),
+	}
+	if err := s.configureRateLimit(cmd); err != nil {
+		return nil, err
+	}
+	token, err := serveAuthToken(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		sum := sha256.Sum256([]byte(token))
+		s.token = sum[:]
+	}
+	if certFile != "" {
+		s.certs = &certReloader{certFile: certFile, keyFile: keyFile}
+		if err := s.certs.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.handle("GET /uuid", "/uuid", false, s.handleUUID)
+	s.handle("POST /v5", "/v5", false, s.handleV5)
+	s.handle("GET /v5", "/v5", false, s.handleV5Query)
+	s.handle("GET /stream", "/stream", false, s.handleStream)
+	s.handle("POST /inspect", "/inspect", false, s.handleInspect)
+	s.handle("GET /inspect/{uuid}", "/inspect", false, s.handleInspectPath)
+	s.handle("GET /healthz", "/healthz", true, s.handleHealth)
+	s.handle("GET /metrics", "/metrics", false, s.handleMetrics)
+	s.handle("/", "other", false, func(w http.ResponseWriter, r *http.Request) {
+		writeServeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("no endpoint %s %s", r.Method, r.URL.Path))
+	})
+	return s, nil
+}
+
+// configureRateLimit sets up the --rate-limit limiter, if any
+func (s *server) configureRateLimit(cmd *cobra.Command) error {
+	rateValue, _ := cmd.Flags().GetString("rate-limit")
+	burst, _ := cmd.Flags().GetInt("burst")
+	s.trustProxy, _ = cmd.Flags().GetBool("trust-proxy")
+	if rateValue == "" {
+		for _, name := range []string{"burst", "trust-proxy"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--%s requires --rate-limit", name)
+			}
+		}
+		return nil
+	}
+
+	rate, n, err := parseRate(rateValue)
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("burst") {
+		burst = n
+	} else if burst < 1 {
+		return fmt.Errorf("--burst must be at least 1, got %d", burst)
+	}
+	s.limiter = newRateLimiter(rate, burst)
+	return nil
+}
+
+// serveAuthToken returns the bearer token from --auth-token or the file
+// named by --auth-token-file, or "" when neither is set
+func serveAuthToken(cmd *cobra.Command) (string, error) {
+	token, _ := cmd.Flags().GetString("auth-token")
+	tokenFile, _ := cmd.Flags().GetString("auth-token-file")
+	if token != "" && tokenFile != "" {
+		return "", fmt.Errorf("--auth-token cannot be combined with --auth-token-file")
+	}
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("cannot read --auth-token-file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("--auth-token-file '%s' is empty", tokenFile)
+		}
+	}
+	return token, nil
+}
+
+// handle registers h for pattern, counting its requests under endpoint.
+// Unless open is set, requests also need the bearer token and are subject
+// to --rate-limit.
+func (s *server) handle(pattern, endpoint string, open bool, h http.HandlerFunc) {
+	if !open {
+		h = s.limit(endpoint, s.authorize(h))
+	}
+	s.mux.Handle(pattern, s.instrument(endpoint, h))
+}
+
+// authorize answers 401 unless the request carries the --auth-token. Both
+// sides are hashed first so the comparison takes the same time whatever the
+// length of the offered token.
+func (s *server) authorize(next http.HandlerFunc) http.HandlerFunc {
+	if s.token == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		scheme, offered, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		sum := sha256.Sum256([]byte(strings.TrimSpace(offered)))
+		if !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare(sum[:], s.token) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="uuid"`)
+			writeServeError(w, http.StatusUnauthorized, "unauthorized", "missing or invalid bearer token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// instrument counts the requests of endpoint by response status
+func (s *server) instrument(endpoint string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		s.metrics.add("uuid_requests_total", 1, "endpoint", endpoint, "code", strconv.Itoa(recorder.status))
+	})
+}
+
+// serveGenerator returns the generator for the version query parameter,
+// which defaults to 4, or answers 400 for a version that cannot be served
+func serveGenerator(w http.ResponseWriter, version string) (string, generator.ContextGenerator, bool) {
+	switch version {
+	case "", "4":
+		return "4", generator.GenerateUUIDv4Context, true
+	case "6":
+		return version, generator.GenerateUUIDv6Context, true
+	case "7":
+		return version, generator.GenerateUUIDv7Context, true
+	}
+	writeServeError(w, http.StatusBadRequest, "invalid_version", fmt.Sprintf("version must be 4, 6, or 7, got '%s'", version))
+	return "", nil, false
+}
+
+func (s *server) handleUUID(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	version, generate, ok := serveGenerator(w, query.Get("version"))
+	if !ok {
+		return
+	}
+
+	count := 1
+	if value := query.Get("count"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > s.maxCount {
+			writeServeError(w, http.StatusBadRequest, "invalid_count", fmt.Sprintf("count must be between 1 and %d, got '%s'", s.maxCount, value))
+			return
+		}
+		count = n
+	}
+
+	uuids, err := generator.GenerateBatch(r.Context(), count, generate)
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, "generation_failed", err.Error())
+		return
+	}
+	s.metrics.add("uuid_generated_total", float64(count), "version", version)
+	writeServeJSON(w, http.StatusOK, map[string][]string{"uuids": uuids})
+}
+
+// handleHealth fails once shutdown starts, so load balancers stop sending
+// new requests while in-flight ones drain
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if s.draining.Load() {
+		writeServeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "draining"})
+		return
+	}
+	writeServeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.write(w)
+}
+
+// run serves on listeners until SIGINT or SIGTERM, reloading the TLS
+// certificate on SIGHUP. It returns nil once every in-flight request has
+// finished after the signal.
+func (s *server) run(listeners []net.Listener, stderr io.Writer) error {
+	signals, stop := serveSignals()
+	defer stop()
+
+	srv := &http.Server{
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if s.certs != nil {
+		srv.TLSConfig = s.certs.config()
+	}
+	srv.RegisterOnShutdown(func() { close(s.stopping) })
+
+	errs := make(chan error, len(listeners))
+	addrs := make([]net.Addr, len(listeners))
+	for i, listener := range listeners {
+		addrs[i] = listener.Addr()
+		fmt.Fprintf(stderr, "listening on %s\n", serveURL(listener.Addr(), s.certs != nil))
+		go func() {
+			if s.certs != nil {
+				errs <- srv.ServeTLS(listener, "", "")
+			} else {
+				errs <- srv.Serve(listener)
+			}
+		}()
+	}
+	serveStarted(addrs)
+	if err := sdNotify("READY=1"); err != nil {
+		fmt.Fprintf(stderr, "WARNING: %v\n", err)
+	}
+
+	for {
+		select {
+		case err := <-errs:
+			srv.Close()
+			return err
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				s.reload(stderr)
+				continue
+			}
+			return s.shutdown(srv, signals, stderr)
+		}
+	}
+}
+
+// shutdown stops accepting connections, which also removes a Unix socket,
+// and waits up to --shutdown-timeout for in-flight requests. Open streams
+// are ended, and a second SIGINT or SIGTERM closes everything at once.
+func (s *server) shutdown(srv *http.Server, signals <-chan os.Signal, stderr io.Writer) error {
+	s.draining.Store(true)
+	if err := sdNotify("STOPPING=1"); err != nil {
+		fmt.Fprintf(stderr, "WARNING: %v\n", err)
+	}
+	fmt.Fprintf(stderr, "shutting down; draining requests for up to %s\n", s.shutdownTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- srv.Shutdown(ctx) }()
+
+	for {
+		select {
+		case err := <-done:
+			if errors.Is(err, context.DeadlineExceeded) {
+				srv.Close()
+				return fmt.Errorf("requests still running after --shutdown-timeout %s were dropped", s.shutdownTimeout)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(stderr, "shut down after %s requests\n", s.metrics.total("uuid_requests_total"))
+			return nil
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				continue
+			}
+			srv.Close()
+			return fmt.Errorf("shutdown forced by a second signal (%s); in-flight requests were dropped", sig)
+		}
+	}
+}
+
+// reload rereads the TLS certificate after a SIGHUP
+func (s *server) reload(stderr io.Writer) {
+	if s.certs == nil {
+		return
+	}
+	if err := s.certs.load(); err != nil {
+		fmt.Fprintf(stderr, "WARNING: %v; keeping the previous certificate\n", err)
+		return
+	}
+	fmt.Fprintf(stderr, "reloaded TLS certificate from %s\n", s.certs.certFile)
+}
+
+// serveListen opens the --listen address: host:port, or unix:PATH
+func serveListen(address string) (net.Listener, error) {
+	network := "tcp"
+	if path, ok := strings.CutPrefix(address, "unix:"); ok {
+		network, address = "unix", path
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("cannot listen on '%s': %w", address, err)
+	}
+	return listener, nil
+}
+
+// serveURL describes a listening address for the startup message
+func serveURL(addr net.Addr, tls bool) string {
+	if addr.Network() == "unix" {
+		return "unix:" + addr.String()
+	}
+	if tls {
+		return "https://" + addr.String()
+	}
+	return "http://" + addr.String()
+}
+
+// certReloader serves the certificate last loaded from certFile and keyFile
+type certReloader struct {
+	certFile, keyFile string
+	cert              atomic.Pointer[tls.Certificate]
+}
+
+func (c *certReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("cannot load --tls-cert '%s' and --tls-key '%s': %w", c.certFile, c.keyFile, err)
+	}
+	c.cert.Store(&cert)
+	return nil
+}
+
+// config returns the server TLS settings: TLS 1.2 or later, and for TLS 1.2
+// only ECDHE key exchange with AEAD ciphers (TLS 1.3 suites are all AEAD)
+func (c *certReloader) config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return c.cert.Load(), nil
+		},
+	}
+}
+
+// statusRecorder remembers the status code written through it
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// serveError is the body of every error response
+type serveError struct {
+	Error serveErrorDetail `json:"error"`
+}
+
+// serveErrorDetail has a stable code; invalid_uuid errors also carry the
+// 'uuid validate' reason code
+type serveErrorDetail struct {
+	Code    string `json:"code"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+}
+
+func writeServeError(w http.ResponseWriter, status int, code, message string) {
+	writeServeErrorReason(w, status, code, "", message)
+}
+
+func writeServeErrorReason(w http.ResponseWriter, status int, code, reason, message string) {
+	writeServeJSON(w, status, serveError{Error: serveErrorDetail{Code: code, Reason: reason, Message: message}})
+}
+
+func writeServeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// serveMetrics holds the counters exported on /metrics
+type serveMetrics struct {
+	mu       sync.Mutex
+	families map[string]*metricFamily
+}
+
+// metricFamily is one Prometheus metric: its samples keyed by label set
+type metricFamily struct {
+	help, kind string
+	samples    map[string]float64
+}
+
+func newServeMetrics() *serveMetrics {
+	m := &serveMetrics{families: make(map[string]*metricFamily)}
+	m.register("uuid_requests_total", "counter", "HTTP requests by endpoint and status code.")
+	m.register("uuid_generated_total", "counter", "UUIDs generated by version.")
+	m.register("uuid_streams_active", "gauge", "Open /stream connections.")
+	m.add("uuid_streams_active", 0)
+	m.register("uuid_ratelimit_allowed_total", "counter", "Requests let through by --rate-limit, by endpoint.")
+	m.register("uuid_ratelimit_throttled_total", "counter", "Requests refused by --rate-limit, by endpoint.")
+	return m
+}
+
+func (m *serveMetrics) register(name, kind, help string) {
+	m.families[name] = &metricFamily{help: help, kind: kind, samples: make(map[string]float64)}
+}
+
+// add adds delta to the sample of name with the given label name and value
+// pairs
+func (m *serveMetrics) add(name string, delta float64, labels ...string) {
+	var key strings.Builder
+	for i := 0; i+1 < len(labels); i += 2 {
+		if key.Len() > 0 {
+			key.WriteByte(',')
+		}
+		fmt.Fprintf(&key, "%s=%s", labels[i], strconv.Quote(labels[i+1]))
+	}
+	m.mu.Lock()
+	m.families[name].samples[key.String()] += delta
+	m.mu.Unlock()
+}
+
+// total returns the sum of the samples of name, formatted as in write
+func (m *serveMetrics) total(name string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var sum float64
+	for _, v := range m.families[name].samples {
+		sum += v
+	}
+	return strconv.FormatFloat(sum, 'f', -1, 64)
+}
+
+// write prints every metric in the Prometheus text format, sorted by name
+// and label set so the output is stable
+func (m *serveMetrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.families))
+	for name := range m.families {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		family := m.families[name]
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, family.help, name, family.kind)
+		keys := make([]string, 0, len(family.samples))
+		for key := range family.samples {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if key == "" {
+				fmt.Fprintf(w, "%s %s\n", name, strconv.FormatFloat(family.samples[key], 'f', -1, 64))
+			} else {
+				fmt.Fprintf(w, "%s{%s} %s\n", name, key, strconv.FormatFloat(family.samples[key], 'f', -1, 64))
+			}
+		}
+	}
+}
+
+func init() {
+	serveCmd.Flags().String("listen", "localhost:8080", "Address to listen on: host:port, or unix:PATH for a Unix socket")
+	serveCmd.Flags().Int("max-count", 1000, "Most UUIDs one /uuid request may ask for")
+	serveCmd.Flags().Int("max-batch", 1000, "Most values one POST /inspect array or /v5 batch may hold")
+	serveCmd.Flags().Duration("stream-min-interval", 100*time.Millisecond, "Shortest interval a /stream client may ask for")
+	serveCmd.Flags().Int("stream-max-events", 3600, "Events sent on one /stream connection before it is closed")
+	serveCmd.Flags().Int("max-streams", 100, "Most /stream connections open at once")
+	serveCmd.Flags().Duration("shutdown-timeout", 10*time.Second, "How long to let in-flight requests finish after SIGINT or SIGTERM")
+	serveCmd.Flags().String("tls-cert", "", "Serve HTTPS with this PEM certificate (requires --tls-key; SIGHUP reloads it)")
+	serveCmd.Flags().String("tls-key", "", "PEM private key for --tls-cert")
+	serveCmd.Flags().String("auth-token", "", "Require 'Authorization: Bearer TOKEN' on every endpoint except /healthz")
+	serveCmd.Flags().String("auth-token-file", "", "Read the --auth-token from this file")
+	serveCmd.Flags().String("rate-limit", "", "Requests allowed per client IP, as N/s, N/m, or N/h (default unlimited)")
+	serveCmd.Flags().Int("burst", 0, "Requests a client may make at once under --rate-limit (default the N of --rate-limit)")
+	serveCmd.Flags().Bool("trust-proxy", false, "Limit clients by the last X-Forwarded-For address instead of the peer address")
+	addClockFlags(serveCmd)
+
+	rootCmd.AddCommand(serveCmd)
+}
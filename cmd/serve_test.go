@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe to write from the server while a test
+// reads it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// testServer is a uuid serve running in the background
+type testServer struct {
+	addr    net.Addr
+	signals chan os.Signal
+	done    chan error
+	stderr  *syncBuffer
+}
+
+// startServe runs uuid serve with args on a free local port and stops it
+// when the test ends
+func startServe(t *testing.T, args ...string) *testServer {
+	t.Helper()
+
+	s := &testServer{
+		signals: make(chan os.Signal, 4),
+		done:    make(chan error, 1),
+		stderr:  new(syncBuffer),
+	}
+	started := make(chan []net.Addr, 1)
+	originalSignals, originalStarted := serveSignals, serveStarted
+	serveSignals = func() (<-chan os.Signal, func()) { return s.signals, func() {} }
+	serveStarted = func(addrs []net.Addr) { started <- addrs }
+
+	if !hasFlag(args, "--listen") {
+		args = append(args, "--listen", "127.0.0.1:0")
+	}
+	go func() {
+		s.done <- runCommand("", io.Discard, s.stderr, append([]string{"serve", "--no-clock-check"}, args...)...)
+	}()
+
+	t.Cleanup(func() {
+		select {
+		case s.signals <- syscall.SIGTERM:
+		default:
+		}
+		s.wait(t)
+		serveSignals, serveStarted = originalSignals, originalStarted
+	})
+
+	select {
+	case addrs := <-started:
+		s.addr = addrs[0]
+	case err := <-s.done:
+		s.done <- err
+		t.Fatalf("uuid serve exited: %v\n%s", err, s.stderr)
+	case <-time.After(5 * time.Second):
+		t.Fatal("uuid serve did not start")
+	}
+	return s
+}
+
+// wait returns the error uuid serve exited with
+func (s *testServer) wait(t *testing.T) error {
+	t.Helper()
+	select {
+	case err := <-s.done:
+		s.done <- err
+		return err
+	case <-time.After(10 * time.Second):
+		t.Fatal("uuid serve did not stop")
+		return nil
+	}
+}
+
+// newTestServer builds the server uuid serve would run with args, without
+// listening, so tests can drive its handlers directly
+func newTestServer(t *testing.T, args ...string) *server {
+	t.Helper()
+	t.Cleanup(func() { resetFlags(serveCmd) })
+	if err := serveCmd.ParseFlags(append([]string{"--no-clock-check"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	s, err := newServer(serveCmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// request sends a GET for path from remoteAddr to s and returns the response
+func (s *server) request(path, remoteAddr string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	s.mux.ServeHTTP(w, req)
+	return w
+}
+
+func (s *testServer) url(path string) string {
+	return "http://" + s.addr.String() + path
+}
+
+func hasFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == name || strings.HasPrefix(arg, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// getJSON requests url with an optional bearer token and decodes the JSON
+// body into v
+func getJSON(t *testing.T, client *http.Client, url, token string, v any) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("GET %s: invalid JSON: %v", url, err)
+		}
+	}
+	return resp
+}
+
+func TestServeUUID(t *testing.T) {
+	s := startServe(t)
+
+	for _, version := range []string{"4", "6", "7"} {
+		var body struct{ UUIDs []string }
+		resp := getJSON(t, http.DefaultClient, s.url("/uuid?version="+version+"&count=3"), "", &body)
+		if resp.StatusCode != http.StatusOK || len(body.UUIDs) != 3 {
+			t.Fatalf("version %s: expected 3 UUIDs, got %d %v", version, resp.StatusCode, body.UUIDs)
+		}
+		for _, uuid := range body.UUIDs {
+			if !uuidRegex.MatchString(uuid) || uuid[14:15] != version {
+				t.Errorf("version %s: unexpected UUID %s", version, uuid)
+			}
+		}
+	}
+
+	var health map[string]string
+	if resp := getJSON(t, http.DefaultClient, s.url("/healthz"), "", &health); resp.StatusCode != http.StatusOK || health["status"] != "ok" {
+		t.Errorf("Expected a healthy status, got %d %v", resp.StatusCode, health)
+	}
+
+	resp, err := http.Get(s.url("/metrics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, want := range []string{
+		`uuid_generated_total{version="7"} 3`,
+		`uuid_requests_total{endpoint="/uuid",code="200"} 3`,
+		`uuid_requests_total{endpoint="/healthz",code="200"} 1`,
+		"# TYPE uuid_requests_total counter",
+	} {
+		if !strings.Contains(string(metrics), want) {
+			t.Errorf("Expected /metrics to contain %q, got:\n%s", want, metrics)
+		}
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	s := startServe(t, "--max-count", "10")
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		code   string
+	}{
+		{"bad version", "/uuid?version=5", http.StatusBadRequest, "invalid_version"},
+		{"count too large", "/uuid?count=11", http.StatusBadRequest, "invalid_count"},
+		{"count not a number", "/uuid?count=ten", http.StatusBadRequest, "invalid_count"},
+		{"unknown endpoint", "/nope", http.StatusNotFound, "not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body serveError
+			resp := getJSON(t, http.DefaultClient, s.url(tt.path), "", &body)
+			if resp.StatusCode != tt.status || body.Error.Code != tt.code || body.Error.Message == "" {
+				t.Errorf("Expected %d %s, got %d %+v", tt.status, tt.code, resp.StatusCode, body)
+			}
+			if got := resp.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Expected a JSON content type, got %q", got)
+			}
+		})
+	}
+}
+
+func TestServeUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uuid.sock")
+	startServe(t, "--listen", "unix:"+path)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, "unix", path)
+		},
+	}}
+	var body struct{ UUIDs []string }
+	if resp := getJSON(t, client, "http://uuid/uuid", "", &body); resp.StatusCode != http.StatusOK || len(body.UUIDs) != 1 {
+		t.Errorf("Expected one UUID over the Unix socket, got %d %v", resp.StatusCode, body.UUIDs)
+	}
+}
+
+// writeTestCert writes a self-signed certificate for 127.0.0.1 and its key
+// to dir, returning their paths and a pool trusting the certificate
+func writeTestCert(t *testing.T, dir, name string) (certFile, keyFile string, pool *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
+
+func tlsClient(pool *x509.CertPool) *http.Client {
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+}
+
+func TestServeTLSAndAuth(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, pool := writeTestCert(t, dir, "first")
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := startServe(t, "--tls-cert", certFile, "--tls-key", keyFile, "--auth-token-file", tokenFile)
+	client := tlsClient(pool)
+	base := "https://" + s.addr.String()
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"valid token", "/uuid", "s3cret-token", http.StatusOK},
+		{"missing token", "/uuid", "", http.StatusUnauthorized},
+		{"wrong token", "/uuid", "s3cret-tokem", http.StatusUnauthorized},
+		{"token prefix", "/uuid", "s3cret", http.StatusUnauthorized},
+		{"metrics need a token", "/metrics", "", http.StatusUnauthorized},
+		{"health stays open", "/healthz", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := getJSON(t, client, base+tt.path, tt.token, nil)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.status == http.StatusUnauthorized && !strings.HasPrefix(resp.Header.Get("WWW-Authenticate"), "Bearer") {
+				t.Errorf("Expected a Bearer challenge, got %q", resp.Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	// Plain HTTP is not served alongside HTTPS
+	if resp, err := http.Get("http://" + s.addr.String() + "/healthz"); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Error("Expected plain HTTP to be refused")
+		}
+	}
+
+	// TLS 1.1 is refused
+	old := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MaxVersion: tls.VersionTLS11}}}
+	if _, err := old.Get(base + "/healthz"); err == nil {
+		t.Error("Expected a TLS 1.1 handshake to fail")
+	}
+}
+
+func TestServeReloadsCertificateOnSIGHUP(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, first := writeTestCert(t, dir, "first")
+	s := startServe(t, "--tls-cert", certFile, "--tls-key", keyFile)
+	base := "https://" + s.addr.String() + "/healthz"
+
+	if resp := getJSON(t, tlsClient(first), base, "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 with the first certificate, got %d", resp.StatusCode)
+	}
+
+	_, _, second := writeTestCert(t, dir, "second")
+	s.signals <- syscall.SIGHUP
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(s.stderr.String(), "reloaded TLS certificate") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a reload message, got:\n%s", s.stderr)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if resp := getJSON(t, tlsClient(second), base, "", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with the reloaded certificate, got %d", resp.StatusCode)
+	}
+
+	// A broken pair keeps the previous certificate
+	os.WriteFile(keyFile, []byte("not a key"), 0o600)
+	s.signals <- syscall.SIGHUP
+	deadline = time.Now().Add(5 * time.Second)
+	for !strings.Contains(s.stderr.String(), "keeping the previous certificate") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a reload warning, got:\n%s", s.stderr)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if resp := getJSON(t, tlsClient(second), base, "", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the previous certificate to stay in use, got %d", resp.StatusCode)
+	}
+}
+
+func TestServeFlagErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty")
+	os.WriteFile(empty, []byte(" \n"), 0o600)
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"cert without key", []string{"serve", "--tls-cert", "cert.pem"}, "--tls-cert and --tls-key must be given together"},
+		{"unreadable cert", []string{"serve", "--tls-cert", filepath.Join(dir, "missing.pem"), "--tls-key", filepath.Join(dir, "missing.key")}, "cannot load --tls-cert"},
+		{"both tokens", []string{"serve", "--auth-token", "a", "--auth-token-file", empty}, "--auth-token cannot be combined with --auth-token-file"},
+		{"empty token file", []string{"serve", "--auth-token-file", empty}, "is empty"},
+		{"missing token file", []string{"serve", "--auth-token-file", filepath.Join(dir, "missing")}, "cannot read --auth-token-file"},
+		{"max count", []string{"serve", "--max-count", "0"}, "--max-count must be at least 1"},
+		{"max batch", []string{"serve", "--max-batch", "0"}, "--max-batch must be at least 1"},
+		{"stream interval", []string{"serve", "--stream-min-interval", "0s"}, "--stream-min-interval must be positive"},
+		{"stream events", []string{"serve", "--stream-max-events", "0"}, "--stream-max-events must be at least 1"},
+		{"max streams", []string{"serve", "--max-streams", "0"}, "--max-streams must be at least 1"},
+		{"shutdown timeout", []string{"serve", "--shutdown-timeout", "0s"}, "--shutdown-timeout must be positive"},
+		{"bad listen", []string{"serve", "--no-clock-check", "--listen", "127.0.0.1:notaport"}, "cannot listen on '127.0.0.1:notaport'"},
+		{"arguments", []string{"serve", "extra"}, "unknown command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
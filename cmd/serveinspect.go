@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// serveInspectValueSize bounds the bytes one value of a POST /inspect body
+// may take, including its JSON quoting, so a body larger than --max-batch
+// values of that size is refused before it is decoded
+const serveInspectValueSize = 128
+
+// handleInspectPath decodes the UUID in the path of GET /inspect/{uuid}
+func (s *server) handleInspectPath(w http.ResponseWriter, r *http.Request) {
+	record, ok := decodeServeUUID(w, r.PathValue("uuid"), "")
+	if ok {
+		writeServeJSON(w, http.StatusOK, record)
+	}
+}
+
+// handleInspect decodes the body of POST /inspect: a JSON string gives one
+// record and a JSON array of strings gives an array of records, in order
+func (s *server) handleInspect(w http.ResponseWriter, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, int64(s.maxBatch*serveInspectValueSize))
+	var raw json.RawMessage
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeServeError(w, http.StatusRequestEntityTooLarge, "batch_too_large", fmt.Sprintf("body exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		writeServeError(w, http.StatusBadRequest, "invalid_json", fmt.Sprintf("body must be a JSON string or array of strings: %v", err))
+		return
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		if record, ok := decodeServeUUID(w, value, ""); ok {
+			writeServeJSON(w, http.StatusOK, record)
+		}
+		return
+	}
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		writeServeError(w, http.StatusBadRequest, "invalid_json", "body must be a JSON string or array of strings")
+		return
+	}
+	if len(values) > s.maxBatch {
+		writeServeError(w, http.StatusRequestEntityTooLarge, "batch_too_large", fmt.Sprintf("batch of %d UUIDs exceeds --max-batch %d", len(values), s.maxBatch))
+		return
+	}
+	records := make([]inspectRecord, len(values))
+	for i, value := range values {
+		record, ok := decodeServeUUID(w, value, fmt.Sprintf("element %d: ", i))
+		if !ok {
+			return
+		}
+		records[i] = record
+	}
+	writeServeJSON(w, http.StatusOK, records)
+}
+
+// decodeServeUUID returns the inspect --json record of value, or answers
+// 422 with the reason value is not a UUID, prefixing the message with where
+func decodeServeUUID(w http.ResponseWriter, value, where string) (inspectRecord, bool) {
+	uuid, err := generator.ParseUUID(value)
+	if err != nil {
+		writeServeErrorReason(w, http.StatusUnprocessableEntity, "invalid_uuid", invalidUUIDReason(value),
+			fmt.Sprintf("%sinvalid UUID '%s': %v", where, value, err))
+		return inspectRecord{}, false
+	}
+	return newInspectRecord(generator.Inspect(uuid), nil, nil, nil), true
+}
+
+// invalidUUIDReason gives the 'uuid validate' reason code for a value that
+// does not parse. Braces, a urn:uuid: prefix, and missing hyphens are
+// accepted forms, so they are undone first and the canonical form inside
+// is what gets blamed.
+func invalidUUIDReason(value string) string {
+	if len(value) >= 9 && strings.EqualFold(value[:9], "urn:uuid:") {
+		value = value[9:]
+	}
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		value = value[1 : len(value)-1]
+	}
+	if value = strings.ReplaceAll(value, " ", ""); len(value) == 32 {
+		value = value[:8] + "-" + value[8:12] + "-" + value[12:16] + "-" + value[16:20] + "-" + value[20:]
+	}
+	var validationErr *generator.ValidationError
+	if errors.As(generator.Validate(value), &validationErr) {
+		return validationErr.Reason
+	}
+	return generator.ReasonHex
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// post sends body to path on s and returns the response
+func (s *server) post(path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.RemoteAddr = "192.0.2.1:1"
+	w := httptest.NewRecorder()
+	s.mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestServeInspectRepresentations(t *testing.T) {
+	s := newTestServer(t)
+	const canonical = "0188b733-b800-7079-9ce7-7022b2ba0185"
+	uuid, _ := generator.ParseUUID(canonical)
+	wantMs := generator.Inspect(uuid).Timestamp.UnixMilli()
+
+	representations := map[string]string{
+		"canonical":  canonical,
+		"upper case": strings.ToUpper(canonical),
+		"braced":     "{" + canonical + "}",
+		"urn":        "urn:uuid:" + canonical,
+		"hyphenless": strings.ReplaceAll(canonical, "-", ""),
+		"grouped":    generator.FormatGrouped(uuid, 4),
+	}
+	for name, value := range representations {
+		t.Run(name, func(t *testing.T) {
+			body, _ := json.Marshal(value)
+			for _, w := range []*httptest.ResponseRecorder{
+				s.post("/inspect", string(body)),
+				s.request("/inspect/"+url.PathEscape(value), "192.0.2.1:1", nil),
+			} {
+				var record inspectRecord
+				if err := json.Unmarshal(w.Body.Bytes(), &record); err != nil || w.Code != http.StatusOK {
+					t.Fatalf("Expected a record, got %d %s", w.Code, w.Body)
+				}
+				if record.UUID != canonical || record.Version != 7 || record.TimestampMs == nil || *record.TimestampMs != wantMs {
+					t.Errorf("Unexpected record %+v", record)
+				}
+			}
+		})
+	}
+}
+
+func TestServeInspectMatchesCLI(t *testing.T) {
+	s := newTestServer(t)
+	for _, value := range []string{
+		"0188b733-b800-7079-9ce7-7022b2ba0185",
+		"1ec9414c-232a-6b00-b3c8-9f6bdeced846",
+		"919108f7-52d1-4320-9bac-f847db4148a8",
+		"00000000-0000-0000-0000-000000000000",
+	} {
+		output, err := executeCommand(t, "", "inspect", "--json", value)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var cli, served map[string]any
+		if err := json.Unmarshal([]byte(output), &cli); err != nil {
+			t.Fatal(err)
+		}
+		w := s.request("/inspect/"+value, "192.0.2.1:1", nil)
+		if err := json.Unmarshal(w.Body.Bytes(), &served); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(cli, served) {
+			t.Errorf("%s: expected the CLI record\n%v\ngot\n%v", value, cli, served)
+		}
+	}
+}
+
+func TestServeInspectBatch(t *testing.T) {
+	s := newTestServer(t, "--max-batch", "3")
+	a, b := generator.GenerateUUIDv4(), generator.GenerateUUIDv7()
+
+	w := s.post("/inspect", `["`+a+`", "urn:uuid:`+b+`"]`)
+	var records []inspectRecord
+	if err := json.Unmarshal(w.Body.Bytes(), &records); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Expected records, got %d %s", w.Code, w.Body)
+	}
+	if len(records) != 2 || records[0].UUID != a || records[1].UUID != b || records[1].Version != 7 {
+		t.Errorf("Expected the records of %s and %s in order, got %+v", a, b, records)
+	}
+
+	// A one-element array still gives an array
+	w = s.post("/inspect", `["`+a+`"]`)
+	if !strings.HasPrefix(w.Body.String(), "[") {
+		t.Errorf("Expected an array, got %s", w.Body)
+	}
+}
+
+func TestServeInspectErrors(t *testing.T) {
+	s := newTestServer(t, "--max-batch", "2")
+	valid := generator.GenerateUUIDv4()
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+		reason string
+	}{
+		{"length", `"0188b733-b800-7079"`, http.StatusUnprocessableEntity, "invalid_uuid", "length"},
+		{"hyphens", `"0188b733_b800_7079_9ce7_7022b2ba0185"`, http.StatusUnprocessableEntity, "invalid_uuid", "hyphens"},
+		{"hex", `"0188b733-b800-7079-9ce7-7022b2ba018g"`, http.StatusUnprocessableEntity, "invalid_uuid", "hex"},
+		{"hex without hyphens", `"0188b733b80070799ce77022b2ba018g"`, http.StatusUnprocessableEntity, "invalid_uuid", "hex"},
+		{"braced length", `"{0188b733}"`, http.StatusUnprocessableEntity, "invalid_uuid", "length"},
+		{"batch element", `["` + valid + `", "nope"]`, http.StatusUnprocessableEntity, "invalid_uuid", "length"},
+		{"batch too large", `["` + valid + `", "` + valid + `", "` + valid + `"]`, http.StatusRequestEntityTooLarge, "batch_too_large", ""},
+		{"body too large", `"` + strings.Repeat("0", 1000) + `"`, http.StatusRequestEntityTooLarge, "batch_too_large", ""},
+		{"not JSON", `0188b733`, http.StatusBadRequest, "invalid_json", ""},
+		{"wrong type", `{"uuid": "` + valid + `"}`, http.StatusBadRequest, "invalid_json", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.post("/inspect", tt.body)
+			var body serveError
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if w.Code != tt.status || body.Error.Code != tt.code || body.Error.Reason != tt.reason {
+				t.Errorf("Expected %d %s/%s, got %d %s", tt.status, tt.code, tt.reason, w.Code, w.Body)
+			}
+		})
+	}
+
+	w := s.post("/inspect", `["`+valid+`", "nope"]`)
+	if !strings.Contains(w.Body.String(), "element 1: invalid UUID 'nope'") {
+		t.Errorf("Expected the failing element to be named, got %s", w.Body)
+	}
+	if w := s.request("/inspect/nope", "192.0.2.1:1", nil); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for GET /inspect/nope, got %d", w.Code)
+	}
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// slowHandler is an endpoint that holds its request until released
+type slowHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+// addSlowHandler registers GET /slow on s
+func addSlowHandler(s *server) *slowHandler {
+	h := &slowHandler{started: make(chan struct{}), release: make(chan struct{})}
+	s.mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		close(h.started)
+		<-h.release
+		io.WriteString(w, "finished")
+	})
+	return h
+}
+
+// runServer runs s on listener in the background, returning the channel
+// its signals are read from and the channel run's result arrives on
+func runServer(t *testing.T, s *server, listener net.Listener) (chan os.Signal, chan error) {
+	t.Helper()
+	signals := make(chan os.Signal, 4)
+	original := serveSignals
+	serveSignals = func() (<-chan os.Signal, func()) { return signals, func() {} }
+	t.Cleanup(func() { serveSignals = original })
+
+	done := make(chan error, 1)
+	go func() { done <- s.run([]net.Listener{listener}, io.Discard) }()
+	return signals, done
+}
+
+func waitForRun(t *testing.T, done chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(10 * time.Second):
+		t.Fatal("uuid serve did not stop")
+		return nil
+	}
+}
+
+func TestServeGracefulShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uuid.sock")
+	listener, err := serveListen("unix:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer(t)
+	slow := addSlowHandler(s)
+	signals, done := runServer(t, s, listener)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, "unix", path)
+		},
+	}}
+	type result struct {
+		body string
+		err  error
+	}
+	slowResult := make(chan result, 1)
+	go func() {
+		resp, err := client.Get("http://uuid/slow")
+		if err != nil {
+			slowResult <- result{err: err}
+			return
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		slowResult <- result{string(body), err}
+	}()
+	<-slow.started
+
+	signals <- syscall.SIGTERM
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		// New connections are refused once draining starts
+		conn, err := net.Dial("unix", path)
+		if err != nil {
+			break
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("Expected new connections to be refused while draining")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if w := s.request("/healthz", "192.0.2.1:1", nil); w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "draining") {
+		t.Errorf("Expected /healthz to report draining, got %d %s", w.Code, w.Body)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("Expected run to wait for the slow request, returned %v", err)
+	default:
+	}
+
+	close(slow.release)
+	if r := <-slowResult; r.err != nil || r.body != "finished" {
+		t.Errorf("Expected the slow request to complete, got %q %v", r.body, r.err)
+	}
+	if err := waitForRun(t, done); err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the Unix socket to be removed, got %v", err)
+	}
+}
+
+func TestServeSecondSignalForcesShutdown(t *testing.T) {
+	listener, _ := serveListen("127.0.0.1:0")
+	s := newTestServer(t)
+	slow := addSlowHandler(s)
+	defer close(slow.release)
+	signals, done := runServer(t, s, listener)
+
+	slowErr := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err == nil {
+			_, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+		slowErr <- err
+	}()
+	<-slow.started
+
+	signals <- syscall.SIGTERM
+	signals <- os.Interrupt
+	err := waitForRun(t, done)
+	if err == nil || !strings.Contains(err.Error(), "shutdown forced by a second signal") {
+		t.Errorf("Expected a forced shutdown error, got %v", err)
+	}
+	if err := <-slowErr; err == nil {
+		t.Error("Expected the slow request to be dropped")
+	}
+}
+
+func TestServeShutdownTimeout(t *testing.T) {
+	listener, _ := serveListen("127.0.0.1:0")
+	s := newTestServer(t, "--shutdown-timeout", "50ms")
+	slow := addSlowHandler(s)
+	defer close(slow.release)
+	signals, done := runServer(t, s, listener)
+
+	go http.Get("http://" + listener.Addr().String() + "/slow")
+	<-slow.started
+
+	signals <- syscall.SIGTERM
+	err := waitForRun(t, done)
+	if err == nil || !strings.Contains(err.Error(), "after --shutdown-timeout 50ms were dropped") {
+		t.Errorf("Expected a shutdown timeout error, got %v", err)
+	}
+}
+
+func TestServeShutdownEndsStreams(t *testing.T) {
+	listener, _ := serveListen("127.0.0.1:0")
+	s := newTestServer(t)
+	signals, done := runServer(t, s, listener)
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/stream?interval=1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	readEvents(t, reader, 1)
+
+	signals <- syscall.SIGTERM
+	if events := readEvents(t, reader, 1); events[0].event != "end" || events[0].data != "server shutting down" {
+		t.Errorf("Expected an end event, got %+v", events)
+	}
+	if err := waitForRun(t, done); err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+}
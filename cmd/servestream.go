@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// handleStream sends a UUID as a Server-Sent Event every interval until the
+// client disconnects or --stream-max-events have been sent
+func (s *server) handleStream(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	version, generate, ok := serveGenerator(w, query.Get("version"))
+	if !ok {
+		return
+	}
+	interval := time.Second
+	if value := query.Get("interval"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < s.streamMinInterval {
+			writeServeError(w, http.StatusBadRequest, "invalid_interval", fmt.Sprintf("interval must be a duration of at least %s, got '%s'", s.streamMinInterval, value))
+			return
+		}
+		interval = d
+	}
+
+	if s.streams.Add(1) > int64(s.maxStreams) {
+		s.streams.Add(-1)
+		w.Header().Set("Retry-After", "1")
+		writeServeError(w, http.StatusServiceUnavailable, "too_many_streams", fmt.Sprintf("all %d streams are in use", s.maxStreams))
+		return
+	}
+	s.metrics.add("uuid_streams_active", 1)
+	defer func() {
+		s.metrics.add("uuid_streams_active", -1)
+		s.streams.Add(-1)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	controller := http.NewResponseController(w)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for sent := 1; ; sent++ {
+		uuid, err := generate(r.Context())
+		if err != nil {
+			return
+		}
+		s.metrics.add("uuid_generated_total", 1, "version", version)
+		fmt.Fprintf(w, "id: %d\nevent: uuid\ndata: %s\n\n", sent, uuid)
+		if sent == s.streamMaxEvents {
+			// Tell EventSource clients the stream ended on purpose
+			fmt.Fprint(w, "event: end\ndata: max events reached\n\n")
+		}
+		if err := controller.Flush(); err != nil || sent == s.streamMaxEvents {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.stopping:
+			fmt.Fprint(w, "event: end\ndata: server shutting down\n\n")
+			controller.Flush()
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sseEvent is one Server-Sent Event read from a stream
+type sseEvent struct {
+	id, event, data string
+}
+
+// readEvents reads n events from r
+func readEvents(t *testing.T, r *bufio.Reader, n int) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var current sseEvent
+	for len(events) < n {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Reading event %d: %v", len(events)+1, err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			events = append(events, current)
+			current = sseEvent{}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			current.id = value
+		case "event":
+			current.event = value
+		case "data":
+			current.data = value
+		default:
+			t.Fatalf("Unexpected line %q", line)
+		}
+	}
+	return events
+}
+
+// waitForStreams waits until s has n open streams
+func waitForStreams(t *testing.T, s *server, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.streams.Load() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d open streams, got %d", n, s.streams.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestServeStream(t *testing.T) {
+	s := newTestServer(t, "--stream-min-interval", "1ms")
+	ts := httptest.NewServer(s.mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/stream?version=7&interval=5ms", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" || resp.Header.Get("Cache-Control") != "no-cache" {
+		t.Fatalf("Expected an event stream, got %d %v", resp.StatusCode, resp.Header)
+	}
+
+	events := readEvents(t, bufio.NewReader(resp.Body), 3)
+	seen := make(map[string]bool)
+	for i, e := range events {
+		if e.id != string(rune('1'+i)) || e.event != "uuid" || !uuidRegex.MatchString(e.data) || e.data[14] != '7' || seen[e.data] {
+			t.Errorf("Event %d: unexpected %+v", i+1, e)
+		}
+		seen[e.data] = true
+	}
+	waitForStreams(t, s, 1)
+
+	// Hanging up ends the handler
+	cancel()
+	waitForStreams(t, s, 0)
+
+	metrics := s.request("/metrics", "192.0.2.1:1", nil).Body.String()
+	for _, want := range []string{"uuid_streams_active 0", `uuid_requests_total{endpoint="/stream",code="200"} 1`} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("Expected /metrics to contain %q, got:\n%s", want, metrics)
+		}
+	}
+	if !strings.Contains(metrics, `uuid_generated_total{version="7"}`) {
+		t.Errorf("Expected streamed UUIDs to be counted, got:\n%s", metrics)
+	}
+}
+
+func TestServeStreamMaxEvents(t *testing.T) {
+	s := newTestServer(t, "--stream-min-interval", "1ms", "--stream-max-events", "2")
+	ts := httptest.NewServer(s.mux)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/stream?interval=1ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	events := readEvents(t, reader, 3)
+	if events[0].data[14] != '4' || events[1].event != "uuid" || events[2].event != "end" {
+		t.Errorf("Expected two UUIDv4 events and an end event, got %+v", events)
+	}
+	if rest, _ := reader.ReadString('\n'); rest != "" {
+		t.Errorf("Expected the stream to close, got %q", rest)
+	}
+	waitForStreams(t, s, 0)
+}
+
+func TestServeStreamConnectionLimit(t *testing.T) {
+	s := newTestServer(t, "--max-streams", "1")
+	ts := httptest.NewServer(s.mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/stream", nil)
+	first, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Body.Close()
+	waitForStreams(t, s, 1)
+
+	second, err := http.Get(ts.URL + "/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body serveError
+	json.NewDecoder(second.Body).Decode(&body)
+	second.Body.Close()
+	if second.StatusCode != http.StatusServiceUnavailable || body.Error.Code != "too_many_streams" {
+		t.Errorf("Expected 503 too_many_streams, got %d %+v", second.StatusCode, body)
+	}
+
+	cancel()
+	waitForStreams(t, s, 0)
+}
+
+func TestServeStreamErrors(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name string
+		path string
+		code string
+	}{
+		{"bad version", "/stream?version=1", "invalid_version"},
+		{"interval too short", "/stream?interval=1ms", "invalid_interval"},
+		{"interval not a duration", "/stream?interval=soon", "invalid_interval"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.request(tt.path, "192.0.2.1:1", nil)
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"code":"`+tt.code+`"`) {
+				t.Errorf("Expected 400 %s, got %d %s", tt.code, w.Code, w.Body)
+			}
+		})
+	}
+	if s.streams.Load() != 0 {
+		t.Errorf("Expected rejected requests not to hold a stream, got %d", s.streams.Load())
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// serveV5NameSize bounds the bytes one name of a POST /v5 batch may take,
+// including its JSON quoting, for the --max-batch body limit
+const serveV5NameSize = 1024
+
+// serveV5Request is the body of POST /v5: a namespace and either one name
+// or a batch of names
+type serveV5Request struct {
+	Namespace string    `json:"namespace"`
+	Name      *string   `json:"name"`
+	Names     *[]string `json:"names"`
+}
+
+// handleV5Query answers GET /v5?namespace=NS&name=NAME, where repeating
+// name asks for a batch
+func (s *server) handleV5Query(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	names, ok := query["name"]
+	if !ok {
+		writeServeError(w, http.StatusUnprocessableEntity, "invalid_name", "name is required")
+		return
+	}
+	s.writeV5(w, r, query.Get("namespace"), names, len(names) > 1)
+}
+
+// handleV5 answers POST /v5
+func (s *server) handleV5(w http.ResponseWriter, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, int64(s.maxBatch)*serveV5NameSize)
+	var req serveV5Request
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeServeError(w, http.StatusRequestEntityTooLarge, "batch_too_large", fmt.Sprintf("body exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		writeServeError(w, http.StatusBadRequest, "invalid_json", fmt.Sprintf(`body must be {"namespace": ..., "name": ...} or {"namespace": ..., "names": [...]}: %v`, err))
+		return
+	}
+
+	switch {
+	case req.Name != nil && req.Names != nil:
+		writeServeError(w, http.StatusUnprocessableEntity, "invalid_name", "name cannot be combined with names")
+	case req.Name != nil:
+		s.writeV5(w, r, req.Namespace, []string{*req.Name}, false)
+	case req.Names != nil:
+		s.writeV5(w, r, req.Namespace, *req.Names, true)
+	default:
+		writeServeError(w, http.StatusUnprocessableEntity, "invalid_name", "name or names is required")
+	}
+}
+
+// writeV5 answers with the UUIDv5 of each name in namespace, derived as
+// 'uuid -5' does: {"uuid": ...} for one name, {"uuids": [...]} for a batch
+func (s *server) writeV5(w http.ResponseWriter, r *http.Request, namespaceValue string, names []string, batch bool) {
+	if namespaceValue == "" {
+		writeServeError(w, http.StatusUnprocessableEntity, "invalid_namespace", "namespace is required")
+		return
+	}
+	if len(names) == 0 {
+		writeServeError(w, http.StatusUnprocessableEntity, "invalid_name", "names must not be empty")
+		return
+	}
+	if len(names) > s.maxBatch {
+		writeServeError(w, http.StatusRequestEntityTooLarge, "batch_too_large", fmt.Sprintf("batch of %d names exceeds --max-batch %d", len(names), s.maxBatch))
+		return
+	}
+	registry, err := loadNamespaces()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, "namespace_registry", fmt.Sprintf("cannot read the namespace registry: %v", err))
+		return
+	}
+	namespace, err := lookupNamespace(registry, namespaceValue)
+	if err != nil {
+		writeServeError(w, http.StatusUnprocessableEntity, "invalid_namespace", err.Error())
+		return
+	}
+
+	uuids := make([]string, len(names))
+	for i, name := range names {
+		uuids[i] = generator.GenerateUUIDv5WithNamespace(namespace, name)
+	}
+	s.metrics.add("uuid_generated_total", float64(len(uuids)), "version", "5")
+
+	// A registry name can later be pointed at another namespace, so only
+	// answers for keywords and literal UUIDs are cached indefinitely
+	if _, alias := registry[namespaceValue]; alias {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	}
+	sum := sha256.Sum256([]byte(strings.Join(uuids, ",")))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Method == http.MethodGet && r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if batch {
+		writeServeJSON(w, http.StatusOK, map[string][]string{"uuids": uuids})
+	} else {
+		writeServeJSON(w, http.StatusOK, map[string]string{"uuid": uuids[0]})
+	}
+}
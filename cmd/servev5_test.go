@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestServeV5RFCVector(t *testing.T) {
+	fakeConfigDir(t)
+	s := newTestServer(t)
+	// RFC 9562 Appendix A.4
+	const want = "2ed6657d-e927-568b-95e1-2665a8aea6a2"
+
+	w := s.post("/v5", `{"namespace": "dns", "name": "www.example.com"}`)
+	var body struct{ UUID string }
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || w.Code != http.StatusOK || body.UUID != want {
+		t.Fatalf("Expected %s, got %d %s", want, w.Code, w.Body)
+	}
+	if got := w.Header().Get("Cache-Control"); !strings.Contains(got, "immutable") {
+		t.Errorf("Expected an immutable Cache-Control, got %q", got)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Error("Expected an ETag")
+	}
+
+	// The GET form answers the same and honours the ETag
+	get := s.request("/v5?namespace=6ba7b810-9dad-11d1-80b4-00c04fd430c8&name=www.example.com", "192.0.2.1:1", nil)
+	if !strings.Contains(get.Body.String(), want) || get.Header().Get("ETag") != etag {
+		t.Errorf("Expected GET /v5 to give %s with ETag %s, got %s %q", want, etag, get.Body, get.Header().Get("ETag"))
+	}
+	cached := s.request("/v5?namespace=dns&name=www.example.com", "192.0.2.1:1", http.Header{"If-None-Match": {etag}})
+	if cached.Code != http.StatusNotModified || cached.Body.Len() != 0 {
+		t.Errorf("Expected 304 for a matching If-None-Match, got %d %s", cached.Code, cached.Body)
+	}
+
+	// The CLI derives the same UUID
+	output, err := executeCommand(t, "", "-5", "--namespace", "dns", "--name", "www.example.com")
+	if err != nil || strings.TrimSpace(output) != want {
+		t.Errorf("Expected the CLI to give %s, got %q %v", want, output, err)
+	}
+}
+
+func TestServeV5Batch(t *testing.T) {
+	fakeConfigDir(t)
+	s := newTestServer(t)
+	names := []string{"a.example.com", "", "b.example.com", "a.example.com"}
+
+	request, _ := json.Marshal(map[string]any{"namespace": "url", "names": names})
+	w := s.post("/v5", string(request))
+	var body struct{ UUIDs []string }
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || w.Code != http.StatusOK || len(body.UUIDs) != len(names) {
+		t.Fatalf("Expected %d UUIDs, got %d %s", len(names), w.Code, w.Body)
+	}
+	for i, name := range names {
+		want, _ := generator.GenerateUUIDv5("url", name)
+		if body.UUIDs[i] != want {
+			t.Errorf("Name %q: expected %s, got %s", name, want, body.UUIDs[i])
+		}
+	}
+
+	query := url.Values{"namespace": {"url"}, "name": names}
+	get := s.request("/v5?"+query.Encode(), "192.0.2.1:1", nil)
+	if get.Body.String() != w.Body.String() {
+		t.Errorf("Expected GET with repeated names to match, got %s", get.Body)
+	}
+}
+
+func TestServeV5NamespaceAlias(t *testing.T) {
+	fakeConfigDir(t)
+	s := newTestServer(t)
+	output, err := executeCommand(t, "", "namespace", "create", "myproject")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	namespace := strings.Fields(output)[len(strings.Fields(output))-1]
+	parsed, err := generator.ParseUUID(namespace)
+	if err != nil {
+		t.Fatalf("Expected the namespace UUID at the end of %q: %v", output, err)
+	}
+
+	w := s.post("/v5", `{"namespace": "myproject", "name": "widget-1"}`)
+	want := generator.GenerateUUIDv5WithNamespace(parsed, "widget-1")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), want) {
+		t.Errorf("Expected %s, got %d %s", want, w.Code, w.Body)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=300" {
+		t.Errorf("Expected a short Cache-Control for a registry name, got %q", got)
+	}
+}
+
+func TestServeV5Errors(t *testing.T) {
+	fakeConfigDir(t)
+	s := newTestServer(t, "--max-batch", "2")
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{"unknown namespace", `{"namespace": "nope", "name": "a"}`, http.StatusUnprocessableEntity, "invalid_namespace"},
+		{"missing namespace", `{"name": "a"}`, http.StatusUnprocessableEntity, "invalid_namespace"},
+		{"missing name", `{"namespace": "dns"}`, http.StatusUnprocessableEntity, "invalid_name"},
+		{"name and names", `{"namespace": "dns", "name": "a", "names": ["b"]}`, http.StatusUnprocessableEntity, "invalid_name"},
+		{"empty batch", `{"namespace": "dns", "names": []}`, http.StatusUnprocessableEntity, "invalid_name"},
+		{"batch too large", `{"namespace": "dns", "names": ["a", "b", "c"]}`, http.StatusRequestEntityTooLarge, "batch_too_large"},
+		{"not JSON", `namespace=dns`, http.StatusBadRequest, "invalid_json"},
+		{"unknown field", `{"namespace": "dns", "nmae": "a"}`, http.StatusBadRequest, "invalid_json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.post("/v5", tt.body)
+			var body serveError
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if w.Code != tt.status || body.Error.Code != tt.code {
+				t.Errorf("Expected %d %s, got %d %s", tt.status, tt.code, w.Code, w.Body)
+			}
+		})
+	}
+	if w := s.request("/v5?namespace=dns", "192.0.2.1:1", nil); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for GET without a name, got %d", w.Code)
+	}
+}
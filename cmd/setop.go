@@ -0,0 +1,400 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// setopCmd compares two files of UUIDs as sets
+var setopCmd = &cobra.Command{
+	Use:   "setop (--diff | --intersect | --union) <a.txt> <b.txt>",
+	Short: "Compare two files of UUIDs as sets",
+	Long: `Compare two files containing one UUID per line as sets.
+
+  --diff       UUIDs in the first file that are not in the second
+  --intersect  UUIDs present in both files
+  --union      UUIDs present in either file
+
+UUIDs are normalized before comparison, so upper case, braced, urn:uuid:,
+and hyphenless forms match their canonical equivalents. Output keeps the
+representation used in the first file (for --union, the first file in which
+the UUID appears) and its order, and collapses duplicates. Counts are
+summarized on stderr: distinct UUIDs for a file held in memory, and UUIDs
+read, duplicates included, for a streamed one.
+
+Memory: --diff and --intersect load the smaller file into a hash set and
+stream the larger one without keeping its UUIDs; only the results are held
+to collapse duplicates. --union holds every distinct UUID. Budget roughly
+100 bytes per distinct UUID, so 10 million UUIDs need about 1 GB. For very
+large --diff inputs, --approx replaces the set of the second file with a
+Bloom filter (about 10 bits per UUID); a small fraction (around 1%) of UUIDs
+unique to the first file may then be omitted.
+
+Examples:
+  uuid setop --diff export-a.txt export-b.txt
+  uuid setop --intersect a.txt b.txt --verbose
+  uuid setop --diff --approx huge-a.txt huge-b.txt`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		diff, _ := cmd.Flags().GetBool("diff")
+		intersect, _ := cmd.Flags().GetBool("intersect")
+		union, _ := cmd.Flags().GetBool("union")
+		approx, _ := cmd.Flags().GetBool("approx")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		if !diff && !intersect && !union {
+			return fmt.Errorf("one of --diff, --intersect, or --union is required")
+		}
+		if approx && !diff {
+			return fmt.Errorf("--approx is only supported with --diff")
+		}
+
+		op := &setOperation{
+			a:      args[0],
+			b:      args[1],
+			out:    cmd.OutOrStdout(),
+			stderr: cmd.ErrOrStderr(),
+		}
+
+		var err error
+		switch {
+		case diff && approx:
+			err = op.approxDiff()
+		case diff:
+			err = op.diff()
+		case intersect:
+			err = op.intersect()
+		default:
+			err = op.union()
+		}
+		if err != nil {
+			return err
+		}
+
+		if verbose {
+			fmt.Fprintf(op.stderr, "strategy: %s\n", op.strategy)
+		}
+		fmt.Fprintf(op.stderr, "%s, %s, result: %d\n", op.summary(op.a, op.countA), op.summary(op.b, op.countB), op.emitted)
+		return nil
+	},
+}
+
+// setOperation holds the inputs and running counts for a set comparison
+type setOperation struct {
+	a, b     string
+	out      io.Writer
+	stderr   io.Writer
+	strategy string
+	// streamed is the file read without holding its UUIDs, whose count
+	// includes duplicates
+	streamed string
+	countA   int
+	countB   int
+	emitted  int
+}
+
+// uuidSet maps a normalized UUID to the text it was first seen as
+type uuidSet map[[16]byte]string
+
+// eachUUID calls fn with the normalized UUID and original text of every
+// non-blank line in path, stopping at the first error
+func eachUUID(path string, fn func(key [16]byte, text string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		key, err := generator.ParseUUID(text)
+		if err != nil {
+			return fmt.Errorf("%s:%d: invalid UUID '%s': %w", path, lineNum, text, err)
+		}
+		if err := fn(key, text); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// loadSet reads every UUID in path, returning the set and the keys in first-seen order
+func loadSet(path string) (uuidSet, [][16]byte, error) {
+	set := uuidSet{}
+	var order [][16]byte
+	err := eachUUID(path, func(key [16]byte, text string) error {
+		if _, ok := set[key]; !ok {
+			set[key] = text
+			order = append(order, key)
+		}
+		return nil
+	})
+	return set, order, err
+}
+
+// aIsSmaller reports whether the first file is no larger than the second
+func (op *setOperation) aIsSmaller() (bool, error) {
+	infoA, err := os.Stat(op.a)
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(op.b)
+	if err != nil {
+		return false, err
+	}
+	return infoA.Size() <= infoB.Size(), nil
+}
+
+// summary describes the count of UUIDs in path for the stderr summary
+func (op *setOperation) summary(path string, count int) string {
+	if path == op.streamed {
+		return fmt.Sprintf("%s: %d read", path, count)
+	}
+	return fmt.Sprintf("%s: %d unique", path, count)
+}
+
+func (op *setOperation) emit(text string) {
+	fmt.Fprintln(op.out, text)
+	op.emitted++
+}
+
+func (op *setOperation) diff() error {
+	smaller, err := op.aIsSmaller()
+	if err != nil {
+		return err
+	}
+
+	if smaller {
+		// Hold the first file, strike out everything seen in the second, then
+		// emit what remains in the first file's order
+		op.strategy = fmt.Sprintf("hash set of %s, streaming %s", op.a, op.b)
+		setA, order, err := loadSet(op.a)
+		if err != nil {
+			return err
+		}
+		op.countA, op.streamed = len(order), op.b
+		err = eachUUID(op.b, func(key [16]byte, text string) error {
+			op.countB++
+			delete(setA, key)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range order {
+			if text, ok := setA[key]; ok {
+				op.emit(text)
+			}
+		}
+		return nil
+	}
+
+	// Hold the second file and stream the first, remembering only the UUIDs
+	// already printed so duplicates collapse
+	op.strategy = fmt.Sprintf("hash set of %s, streaming %s", op.b, op.a)
+	setB, orderB, err := loadSet(op.b)
+	if err != nil {
+		return err
+	}
+	op.countB, op.streamed = len(orderB), op.a
+	emitted := map[[16]byte]bool{}
+	return eachUUID(op.a, func(key [16]byte, text string) error {
+		op.countA++
+		if _, ok := setB[key]; ok || emitted[key] {
+			return nil
+		}
+		emitted[key] = true
+		op.emit(text)
+		return nil
+	})
+}
+
+func (op *setOperation) intersect() error {
+	smaller, err := op.aIsSmaller()
+	if err != nil {
+		return err
+	}
+
+	if smaller {
+		// Stream the second file, marking the first file's UUIDs it holds,
+		// then emit them in the first file's order
+		op.strategy = fmt.Sprintf("hash set of %s, streaming %s", op.a, op.b)
+		setA, order, err := loadSet(op.a)
+		if err != nil {
+			return err
+		}
+		op.countA, op.streamed = len(order), op.b
+		matched := map[[16]byte]bool{}
+		err = eachUUID(op.b, func(key [16]byte, text string) error {
+			op.countB++
+			if _, ok := setA[key]; ok {
+				matched[key] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range order {
+			if matched[key] {
+				op.emit(setA[key])
+			}
+		}
+		return nil
+	}
+
+	op.strategy = fmt.Sprintf("hash set of %s, streaming %s", op.b, op.a)
+	setB, orderB, err := loadSet(op.b)
+	if err != nil {
+		return err
+	}
+	op.countB, op.streamed = len(orderB), op.a
+	emitted := map[[16]byte]bool{}
+	return eachUUID(op.a, func(key [16]byte, text string) error {
+		op.countA++
+		if _, ok := setB[key]; !ok || emitted[key] {
+			return nil
+		}
+		emitted[key] = true
+		op.emit(text)
+		return nil
+	})
+}
+
+func (op *setOperation) union() error {
+	op.strategy = "hash set of both files"
+	seen := map[[16]byte]bool{}
+	err := eachUUID(op.a, func(key [16]byte, text string) error {
+		if !seen[key] {
+			seen[key] = true
+			op.countA++
+			op.emit(text)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	seenB := map[[16]byte]bool{}
+	return eachUUID(op.b, func(key [16]byte, text string) error {
+		if seenB[key] {
+			return nil
+		}
+		seenB[key] = true
+		op.countB++
+		if !seen[key] {
+			seen[key] = true
+			op.emit(text)
+		}
+		return nil
+	})
+}
+
+// approxDiff streams the first file against a Bloom filter of the second.
+// Duplicates in the first file are still collapsed exactly.
+func (op *setOperation) approxDiff() error {
+	info, err := os.Stat(op.b)
+	if err != nil {
+		return err
+	}
+	// Every line holds at least 32 characters plus a newline, so this
+	// over-estimates the number of UUIDs and keeps the error rate in bounds
+	filter := newBloomFilter(int(info.Size()/33) + 1)
+
+	op.strategy = fmt.Sprintf("Bloom filter of %s (%d bits), streaming %s", op.b, len(filter.bits)*64, op.a)
+	op.streamed = op.b
+	err = eachUUID(op.b, func(key [16]byte, text string) error {
+		filter.add(key)
+		op.countB++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	seenA := map[[16]byte]bool{}
+	return eachUUID(op.a, func(key [16]byte, text string) error {
+		if seenA[key] {
+			return nil
+		}
+		seenA[key] = true
+		op.countA++
+		if !filter.contains(key) {
+			op.emit(text)
+		}
+		return nil
+	})
+}
+
+// bloomFilter is a fixed-size Bloom filter over 16-byte UUIDs using double hashing
+type bloomFilter struct {
+	bits   []uint64
+	hashes int
+}
+
+// newBloomFilter sizes a filter at 10 bits per expected item with 7 hash
+// functions, giving a false positive rate of about 1%
+func newBloomFilter(expected int) *bloomFilter {
+	words := (expected*10 + 63) / 64
+	return &bloomFilter{bits: make([]uint64, words), hashes: 7}
+}
+
+func (f *bloomFilter) positions(key [16]byte) []uint64 {
+	h := fnv.New128a()
+	h.Write(key[:])
+	sum := h.Sum(nil)
+	h1 := binary.BigEndian.Uint64(sum[:8])
+	h2 := binary.BigEndian.Uint64(sum[8:]) | 1
+
+	m := uint64(len(f.bits) * 64)
+	positions := make([]uint64, f.hashes)
+	for i := range positions {
+		positions[i] = (h1 + uint64(i)*h2) % m
+	}
+	return positions
+}
+
+func (f *bloomFilter) add(key [16]byte) {
+	for _, p := range f.positions(key) {
+		f.bits[p/64] |= 1 << (p % 64)
+	}
+}
+
+func (f *bloomFilter) contains(key [16]byte) bool {
+	for _, p := range f.positions(key) {
+		if f.bits[p/64]&(1<<(p%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	setopCmd.Flags().Bool("diff", false, "Print UUIDs in the first file that are not in the second")
+	setopCmd.Flags().Bool("intersect", false, "Print UUIDs present in both files")
+	setopCmd.Flags().Bool("union", false, "Print UUIDs present in either file")
+	setopCmd.Flags().Bool("approx", false, "Use a Bloom filter for the second file with --diff (may omit ~1% of results)")
+	setopCmd.Flags().Bool("verbose", false, "Report the comparison strategy on stderr")
+	setopCmd.MarkFlagsMutuallyExclusive("diff", "intersect", "union")
+
+	rootCmd.AddCommand(setopCmd)
+}
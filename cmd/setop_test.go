@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	setUUID1 = "0188b733-b800-7079-9ce7-7022b2ba0185"
+	setUUID2 = "919108f7-52d1-4320-9bac-f847db4148a8"
+	setUUID3 = "c232ab00-9414-11ec-b3c8-9f6bdeced846"
+	setUUID4 = "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"
+)
+
+// writeSetFiles creates the two overlapping fixture files used by the setop tests.
+// File A holds 1, 2, 3 (with a duplicate of 1); file B holds 2, 3, 4 in
+// different representations.
+func writeSetFiles(t *testing.T, padB bool) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	a := strings.Join([]string{
+		setUUID1,
+		strings.ToUpper(setUUID2),
+		setUUID3,
+		"{" + setUUID1 + "}",
+	}, "\n") + "\n"
+
+	b := strings.Join([]string{
+		"{" + setUUID2 + "}",
+		"urn:uuid:" + strings.ToUpper(setUUID3),
+		strings.ReplaceAll(setUUID4, "-", ""),
+	}, "\n") + "\n"
+	if padB {
+		// Make B the larger file to exercise the other strategy
+		b += strings.Repeat(setUUID4+"\n", 10)
+	}
+
+	pathA := filepath.Join(dir, "a.txt")
+	pathB := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(pathA, []byte(a), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pathB, []byte(b), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return pathA, pathB
+}
+
+func TestSetopOperations(t *testing.T) {
+	tests := []struct {
+		op       string
+		expected []string
+	}{
+		{"--diff", []string{setUUID1}},
+		{"--intersect", []string{strings.ToUpper(setUUID2), setUUID3}},
+		{"--union", []string{setUUID1, strings.ToUpper(setUUID2), setUUID3, strings.ReplaceAll(setUUID4, "-", "")}},
+	}
+
+	for _, padB := range []bool{false, true} {
+		for _, tt := range tests {
+			t.Run(tt.op, func(t *testing.T) {
+				a, b := writeSetFiles(t, padB)
+
+				var stdout strings.Builder
+				output, err := executeCommand(t, "", "setop", tt.op, a, b)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				for _, line := range strings.Split(output, "\n") {
+					if !strings.Contains(line, "result:") && line != "" {
+						stdout.WriteString(line + "\n")
+					}
+				}
+
+				expected := strings.Join(tt.expected, "\n") + "\n"
+				if stdout.String() != expected {
+					t.Errorf("Expected:\n%s\ngot:\n%s", expected, stdout.String())
+				}
+				// The streamed file, the larger one, is counted with duplicates
+				countA, countB := "a.txt: 3 unique", "b.txt: 3 unique"
+				if tt.op != "--union" {
+					if padB {
+						countB = "b.txt: 13 read"
+					} else {
+						countA = "a.txt: 4 read"
+					}
+				}
+				if !strings.Contains(output, countA) || !strings.Contains(output, countB) {
+					t.Errorf("Expected %q and %q in the counts summary, got: %q", countA, countB, output)
+				}
+			})
+		}
+	}
+}
+
+func TestSetopIntersectKeepsFirstFileOrder(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	// The first file is the smaller, so the second is streamed, in the
+	// opposite order
+	if err := os.WriteFile(a, []byte(setUUID1+"\n"+setUUID2+"\n"+setUUID3+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(strings.Repeat(setUUID4+"\n", 10)+setUUID3+"\n"+setUUID1+"\n"+setUUID3+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := executeCommandSplit(t, "", "setop", "--intersect", a, b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := setUUID1 + "\n" + setUUID3 + "\n"; stdout != want {
+		t.Errorf("Expected the first file's order:\n%s\ngot:\n%s", want, stdout)
+	}
+}
+
+func TestSetopVerboseStrategy(t *testing.T) {
+	a, b := writeSetFiles(t, true)
+
+	output, err := executeCommand(t, "", "setop", "--diff", "--verbose", a, b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "strategy: hash set of "+a+", streaming "+b) {
+		t.Errorf("Expected strategy report for smaller first file, got: %q", output)
+	}
+}
+
+func TestSetopApproxDiff(t *testing.T) {
+	a, b := writeSetFiles(t, false)
+
+	output, err := executeCommand(t, "", "setop", "--diff", "--approx", "--verbose", a, b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(output, setUUID1+"\n") || strings.Count(output, setUUID1) != 1 {
+		t.Errorf("Expected only %s in approximate diff, got: %q", setUUID1, output)
+	}
+	if !strings.Contains(output, "Bloom filter") {
+		t.Errorf("Expected Bloom filter strategy, got: %q", output)
+	}
+}
+
+func TestSetopErrors(t *testing.T) {
+	a, b := writeSetFiles(t, false)
+	bad := filepath.Join(t.TempDir(), "bad.txt")
+	if err := os.WriteFile(bad, []byte(setUUID1+"\nnot-a-uuid\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"no operation", []string{"setop", a, b}, "one of --diff, --intersect, or --union is required"},
+		{"approx without diff", []string{"setop", "--union", "--approx", a, b}, "--approx is only supported with --diff"},
+		{"invalid line", []string{"setop", "--union", bad, b}, bad + ":2: invalid UUID 'not-a-uuid'"},
+		{"missing file", []string{"setop", "--diff", a, filepath.Join(t.TempDir(), "missing.txt")}, "no such file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	filter := newBloomFilter(1000)
+	var keys [][16]byte
+	for i := 0; i < 1000; i++ {
+		var key [16]byte
+		key[0], key[1] = byte(i), byte(i>>8)
+		keys = append(keys, key)
+		filter.add(key)
+	}
+
+	for _, key := range keys {
+		if !filter.contains(key) {
+			t.Fatalf("Bloom filter must not report false negatives")
+		}
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		var key [16]byte
+		key[15], key[14] = byte(i), byte(i>>8)
+		key[13] = 0xff
+		if filter.contains(key) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 300 {
+		t.Errorf("False positive rate too high: %d/10000", falsePositives)
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeKeyFile writes key to a temporary file and returns its path
+func writeKeyFile(t *testing.T, key string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sign.key")
+	if err := os.WriteFile(path, []byte(key), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSignKnownAnswer(t *testing.T) {
+	// A trailing newline is not part of the key
+	key := writeKeyFile(t, "0123456789abcdef0123456789abcdef\n")
+	output, err := executeCommand(t, "", "sign", "--key-file", key, "919108F7-52D1-4320-9BAC-F847DB4148A8")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "919108f7-52d1-4320-9bac-f847db4148a8.iZ5EjEwgEfR1sQ7v\n" {
+		t.Errorf("Unexpected signed value %q", output)
+	}
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	oldKey := writeKeyFile(t, "old-key-old-key-old-key")
+	newKey := writeKeyFile(t, "new-key-new-key-new-key")
+
+	signed, err := executeCommand(t, "", "sign", "--key-file", oldKey, "--generate", "-n", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	values := strings.Fields(signed)
+	if len(values) != 3 {
+		t.Fatalf("Expected 3 signed UUIDs, got %q", signed)
+	}
+
+	// Values signed with the old key verify while it is still listed
+	output, err := executeCommand(t, signed, "verify", "--key-file", newKey, "--key-file", oldKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, line := range strings.Fields(output) {
+		if !uuidRegex.MatchString(line) || line != values[i][:36] {
+			t.Errorf("Expected %s, got %q", values[i][:36], line)
+		}
+	}
+
+	// and fail once it is dropped
+	_, err = executeCommand(t, "", "verify", "--key-file", newKey, values[0])
+	if err == nil || err.Error() != "'"+values[0]+"' failed verification: signature does not match" {
+		t.Errorf("Expected a mismatch, got: %v", err)
+	}
+}
+
+func TestVerifyTampering(t *testing.T) {
+	key := writeKeyFile(t, "0123456789abcdef0123456789abcdef")
+	signed := "919108f7-52d1-4320-9bac-f847db4148a8.iZ5EjEwgEfR1sQ7v"
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"flipped uuid bit", "919108f7-52d1-4320-9bac-f847db4148a9.iZ5EjEwgEfR1sQ7v"},
+		{"flipped tag bit", "919108f7-52d1-4320-9bac-f847db4148a8.iZ5EjEwgEfR1sQ7w"},
+		{"malformed", "919108f7-52d1-4320-9bac-f847db4148a8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(t, signed+"\n"+tt.value+"\n", "verify", "--key-file", key)
+			if err == nil || !strings.Contains(err.Error(), "line 2: '"+tt.value+"' failed verification") {
+				t.Errorf("Expected line 2 to fail verification, got: %v", err)
+			}
+			if !strings.HasPrefix(output, "919108f7-52d1-4320-9bac-f847db4148a8\n") {
+				t.Errorf("Expected the valid first line to be printed, got %q", output)
+			}
+		})
+	}
+}
+
+func TestSignErrors(t *testing.T) {
+	key := writeKeyFile(t, "0123456789abcdef")
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"missing key flag", []string{"sign", "919108f7-52d1-4320-9bac-f847db4148a8"}, `required flag(s) "key-file" not set`},
+		{"missing key file", []string{"sign", "--key-file", "missing.key", "--generate"}, "cannot read --key-file"},
+		{"short key", []string{"sign", "--key-file", writeKeyFile(t, "short"), "--generate"}, "is 5 bytes; at least 16 are required"},
+		{"invalid uuid", []string{"sign", "--key-file", key, "nope"}, "invalid UUID 'nope'"},
+		{"generate with values", []string{"sign", "--key-file", key, "--generate", "919108f7-52d1-4320-9bac-f847db4148a8"}, "--generate cannot be combined with values to sign"},
+		{"count without generate", []string{"sign", "--key-file", key, "-n", "2", "919108f7-52d1-4320-9bac-f847db4148a8"}, "-n/--count requires --generate"},
+		{"zero count", []string{"sign", "--key-file", key, "--generate", "-n", "0"}, "count must be at least 1, got 0"},
+		{"verify without key", []string{"verify", "x"}, `required flag(s) "key-file" not set`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,444 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// sortRecordOverhead approximates the memory a buffered value costs beyond
+// its text: the UUID, its timestamp key, and the string header
+const sortRecordOverhead = 48
+
+// sortMergeFanIn is the most runs merged at once; more runs are first merged
+// into larger ones so the number of open files stays bounded
+var sortMergeFanIn = 128
+
+// sortProgressEvery is how many merged values pass between --progress lines
+var sortProgressEvery int64 = 10_000_000
+
+// sortContext returns the context a sort runs under, cancelled on SIGINT or
+// SIGTERM so temporary files are removed before exiting. The handler is
+// removed once it fires, so a second signal exits at once.
+var sortContext = func(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// sortCmd sorts UUID streams, spilling to disk when they do not fit in memory
+var sortCmd = &cobra.Command{
+	Use:   "sort [uuid...]",
+	Short: "Sort UUIDs, using temporary files for inputs larger than memory",
+	Long: `Sort UUIDs from the arguments, from each --file, or from stdin, one per
+line. Several --file inputs are merged into one sorted output, and --unique
+drops repeated UUIDs, keeping the first occurrence. Lines are printed as
+read; UUIDs are compared after normalization, so upper case, braced, and
+hyphenless forms sort with their canonical equivalents.
+
+--order selects the sort key:
+
+  bytes      byte order, the same as sorting canonical text (default)
+  sqlserver  the order SQL Server gives uniqueidentifier columns
+  time       the embedded UUIDv1, v6, or v7 timestamp, then byte order;
+             UUIDs without a timestamp come first
+
+Values are buffered up to --memory-limit (a size such as 512M or 2G). Larger
+inputs are sorted in runs that are written to a directory under --temp-dir
+and then merged, so a 100 GB file needs about 100 GB of temporary space but
+only --memory-limit of memory. Temporary files are removed when the sort
+finishes, fails, or is interrupted. --progress reports runs and merge
+progress on stderr.
+
+Examples:
+  uuid sort -f ids.txt
+  uuid sort --unique -f export-a.txt -f export-b.txt > merged.txt
+  uuid sort --order time --memory-limit 8G --temp-dir /scratch --progress -f export.txt`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		order, _ := cmd.Flags().GetString("order")
+		unique, _ := cmd.Flags().GetBool("unique")
+		limitValue, _ := cmd.Flags().GetString("memory-limit")
+		tempDir, _ := cmd.Flags().GetString("temp-dir")
+		progress, _ := cmd.Flags().GetBool("progress")
+
+		compare, ok := sortOrders[order]
+		if !ok {
+			return fmt.Errorf("unknown order '%s'. Available orders: bytes, sqlserver, time", order)
+		}
+		limit, err := parseByteSize(limitValue)
+		if err != nil {
+			return fmt.Errorf("invalid --memory-limit: %w", err)
+		}
+		if tempDir == "" {
+			tempDir = os.TempDir()
+		}
+
+		ctx, stop := sortContext(cmd.Context())
+		defer stop()
+		s := &uuidSorter{
+			ctx:     ctx,
+			compare: compare,
+			timeKey: order == "time",
+			unique:  unique,
+			limit:   limit,
+			tempDir: tempDir,
+		}
+		if progress {
+			s.progress = cmd.ErrOrStderr()
+		}
+		defer s.cleanup()
+
+		out := bufio.NewWriter(cmd.OutOrStdout())
+		err = eachInputValue(cmd, args, s.add)
+		if err == nil {
+			err = s.finish(out)
+		}
+		if err == nil {
+			err = out.Flush()
+		}
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("sort interrupted; temporary files removed")
+		}
+		return err
+	},
+}
+
+// sortRecord is one input value with the keys it sorts by
+type sortRecord struct {
+	uuid [16]byte
+	// ts is the embedded timestamp in 100ns ticks since the Unix epoch for
+	// --order time, math.MinInt64 when there is none
+	ts   int64
+	text string
+}
+
+// sortOrders are the --order comparisons
+var sortOrders = map[string]func(a, b *sortRecord) int{
+	"bytes":     func(a, b *sortRecord) int { return bytes.Compare(a.uuid[:], b.uuid[:]) },
+	"sqlserver": func(a, b *sortRecord) int { return generator.CompareSQLServer(a.uuid, b.uuid) },
+	"time": func(a, b *sortRecord) int {
+		if c := cmp.Compare(a.ts, b.ts); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.uuid[:], b.uuid[:])
+	},
+}
+
+// uuidSorter buffers records up to limit bytes, spilling each full buffer to
+// a sorted run file, and merges the runs when the input ends
+type uuidSorter struct {
+	ctx      context.Context
+	compare  func(a, b *sortRecord) int
+	timeKey  bool
+	unique   bool
+	limit    int64
+	tempDir  string
+	progress io.Writer
+
+	records []sortRecord
+	size    int64
+	total   int64
+	dir     string
+	runs    []string
+	nextRun int
+}
+
+// newRecord parses value into a record
+func (s *uuidSorter) newRecord(value string) (sortRecord, error) {
+	uuid, err := generator.ParseUUID(value)
+	if err != nil {
+		return sortRecord{}, fmt.Errorf("invalid UUID '%s': %w", value, err)
+	}
+	r := sortRecord{uuid: uuid, ts: math.MinInt64, text: value}
+	if s.timeKey {
+		if ts := generator.Inspect(uuid).Timestamp; ts != nil {
+			r.ts = ts.Unix()*1e7 + int64(ts.Nanosecond()/100)
+		}
+	}
+	return r, nil
+}
+
+// add buffers one value, spilling a run once the buffer reaches the limit
+func (s *uuidSorter) add(value string) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	r, err := s.newRecord(value)
+	if err != nil {
+		return err
+	}
+	s.records = append(s.records, r)
+	s.size += int64(len(value)) + sortRecordOverhead
+	s.total++
+	if s.size >= s.limit {
+		return s.spill()
+	}
+	return nil
+}
+
+// sortBuffer sorts the buffered records; the stable sort keeps input order
+// among equal UUIDs, so --unique keeps the first occurrence
+func (s *uuidSorter) sortBuffer() {
+	slices.SortStableFunc(s.records, func(a, b sortRecord) int { return s.compare(&a, &b) })
+}
+
+// spill writes the buffered records to a new sorted run
+func (s *uuidSorter) spill() error {
+	s.sortBuffer()
+	path, err := s.newRunPath()
+	if err != nil {
+		return err
+	}
+	if err := writeRunFile(path, func(w *bufio.Writer) error {
+		return s.writeRecords(w)
+	}); err != nil {
+		return err
+	}
+	s.runs = append(s.runs, path)
+	s.reportProgress("run %d: %d values sorted and written to %s\n", len(s.runs), len(s.records), path)
+	s.records = s.records[:0]
+	s.size = 0
+	return nil
+}
+
+// newRunPath returns the path for the next run, creating the spill
+// directory on first use
+func (s *uuidSorter) newRunPath() (string, error) {
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(s.tempDir, "uuid-sort-")
+		if err != nil {
+			return "", fmt.Errorf("cannot create a temporary directory: %w", err)
+		}
+		s.dir = dir
+	}
+	s.nextRun++
+	return filepath.Join(s.dir, fmt.Sprintf("run-%06d", s.nextRun)), nil
+}
+
+// writeRunFile creates path and fills it through a buffered writer
+func writeRunFile(path string, fill func(w *bufio.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	if err := fill(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeRecords writes the sorted buffer, dropping repeats with --unique
+func (s *uuidSorter) writeRecords(w io.Writer) error {
+	for i := range s.records {
+		if i%4096 == 0 {
+			if err := s.ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if s.unique && i > 0 && s.records[i].uuid == s.records[i-1].uuid {
+			continue
+		}
+		if _, err := io.WriteString(w, s.records[i].text+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish writes every value to out in order: straight from memory when no
+// run was spilled, and otherwise by merging the runs
+func (s *uuidSorter) finish(out io.Writer) error {
+	if len(s.runs) == 0 {
+		s.sortBuffer()
+		return s.writeRecords(out)
+	}
+	if len(s.records) > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+	for len(s.runs) > sortMergeFanIn {
+		path, err := s.newRunPath()
+		if err != nil {
+			return err
+		}
+		batch := s.runs[:sortMergeFanIn]
+		if err := writeRunFile(path, func(w *bufio.Writer) error {
+			return s.merge(batch, w, false)
+		}); err != nil {
+			return err
+		}
+		for _, run := range batch {
+			os.Remove(run)
+		}
+		s.reportProgress("merged %d runs into %s\n", len(batch), path)
+		// The merged run replaces the earliest runs, keeping ties in input order
+		s.runs = append([]string{path}, s.runs[sortMergeFanIn:]...)
+	}
+	s.reportProgress("merging %d runs\n", len(s.runs))
+	return s.merge(s.runs, out, true)
+}
+
+// runReader is the next record of one open run
+type runReader struct {
+	scanner *bufio.Scanner
+	record  sortRecord
+	index   int
+}
+
+// runHeap orders open runs by their next record, breaking ties by run index
+// so the merge is stable
+type runHeap struct {
+	readers []*runReader
+	compare func(a, b *sortRecord) int
+}
+
+func (h *runHeap) Len() int { return len(h.readers) }
+func (h *runHeap) Less(i, j int) bool {
+	if c := h.compare(&h.readers[i].record, &h.readers[j].record); c != 0 {
+		return c < 0
+	}
+	return h.readers[i].index < h.readers[j].index
+}
+func (h *runHeap) Swap(i, j int) { h.readers[i], h.readers[j] = h.readers[j], h.readers[i] }
+func (h *runHeap) Push(x any)    { h.readers = append(h.readers, x.(*runReader)) }
+func (h *runHeap) Pop() any {
+	last := h.readers[len(h.readers)-1]
+	h.readers = h.readers[:len(h.readers)-1]
+	return last
+}
+
+// advance reads the run's next record, reporting false at the end
+func (s *uuidSorter) advance(r *runReader) (bool, error) {
+	if !r.scanner.Scan() {
+		return false, r.scanner.Err()
+	}
+	record, err := s.newRecord(r.scanner.Text())
+	r.record = record
+	return err == nil, err
+}
+
+// merge writes the records of runs to w in order. final marks the merge
+// that produces the output, which is the one --progress counts.
+func (s *uuidSorter) merge(runs []string, w io.Writer, final bool) error {
+	h := &runHeap{compare: s.compare}
+	for i, path := range runs {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r := &runReader{scanner: bufio.NewScanner(f), index: i}
+		ok, err := s.advance(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if ok {
+			h.readers = append(h.readers, r)
+		}
+	}
+	heap.Init(h)
+
+	var merged int64
+	var last [16]byte
+	for h.Len() > 0 {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+		r := h.readers[0]
+		if !s.unique || merged == 0 || r.record.uuid != last {
+			if _, err := io.WriteString(w, r.record.text+"\n"); err != nil {
+				return err
+			}
+		}
+		last = r.record.uuid
+		merged++
+		if final && merged%sortProgressEvery == 0 {
+			s.reportProgress("merged %d of %d values\n", merged, s.total)
+		}
+
+		ok, err := s.advance(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", runs[r.index], err)
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	if final {
+		s.reportProgress("sorted %d values\n", s.total)
+	}
+	return nil
+}
+
+func (s *uuidSorter) reportProgress(format string, args ...any) {
+	if s.progress != nil {
+		fmt.Fprintf(s.progress, format, args...)
+	}
+}
+
+// cleanup removes the spill directory and every run in it
+func (s *uuidSorter) cleanup() {
+	if s.dir != "" {
+		os.RemoveAll(s.dir)
+	}
+}
+
+// byteSizeUnits are the suffixes parseByteSize accepts, in powers of 1024
+var byteSizeUnits = map[string]int64{"": 1, "K": 1 << 10, "M": 1 << 20, "G": 1 << 30, "T": 1 << 40}
+
+// parseByteSize parses a positive size such as 512M or 2G; K, M, G, and T
+// are powers of 1024 and may be followed by B or iB
+func parseByteSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	if strings.HasSuffix(s, "IB") {
+		s = strings.TrimSuffix(s, "IB")
+	} else {
+		s = strings.TrimSuffix(s, "B")
+	}
+	digits := strings.TrimRight(s, "KMGT")
+	unit, ok := byteSizeUnits[s[len(digits):]]
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if !ok || err != nil || n < 1 || n > math.MaxInt64/unit {
+		return 0, fmt.Errorf("'%s' is not a size such as 512M or 2G", value)
+	}
+	return n * unit, nil
+}
+
+func init() {
+	addInputFlags(sortCmd)
+	sortCmd.Flags().String("order", "bytes", "Sort key: bytes, sqlserver, or time")
+	sortCmd.Flags().BoolP("unique", "u", false, "Print each UUID once, keeping the first occurrence")
+	sortCmd.Flags().String("memory-limit", "512M", "Memory to buffer values in before sorting through temporary files")
+	sortCmd.Flags().String("temp-dir", "", "Directory for temporary run files (default the system temporary directory)")
+	sortCmd.Flags().Bool("progress", false, "Report runs and merge progress on stderr")
+
+	rootCmd.AddCommand(sortCmd)
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// writeSortInput writes n lines of mixed versions and spellings, with
+// repeats, to a file and returns its path
+func writeSortInput(t *testing.T, n int) string {
+	t.Helper()
+	rng := rand.New(rand.NewPCG(1, 2))
+	var lines []string
+	for i := range n {
+		var uuid string
+		switch {
+		case i > 0 && i%10 == 0:
+			uuid = strings.ToUpper(lines[rng.IntN(len(lines))])
+		case i%3 == 0:
+			uuid = uuidV7At(t, time.UnixMilli(rng.Int64N(2e12)))
+		default:
+			uuid = generator.GenerateUUIDv4()
+		}
+		lines = append(lines, uuid)
+	}
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSortInMemory(t *testing.T) {
+	output, err := executeCommand(t, "", "sort",
+		"{ffffffff-0000-4000-8000-000000000000}",
+		"00000000000040008000000000000001",
+		"7FFFFFFF-0000-4000-8000-000000000000",
+		"00000000-0000-4000-8000-000000000001",
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "00000000000040008000000000000001\n00000000-0000-4000-8000-000000000001\n7FFFFFFF-0000-4000-8000-000000000000\n{ffffffff-0000-4000-8000-000000000000}\n"
+	if output != want {
+		t.Errorf("Expected lines as read in byte order:\n%s\ngot:\n%s", want, output)
+	}
+
+	output, _ = executeCommand(t, "", "sort", "--unique",
+		"00000000-0000-4000-8000-000000000001",
+		"b0000000-0000-4000-8000-000000000000",
+		"00000000000040008000000000000001",
+	)
+	if output != "00000000-0000-4000-8000-000000000001\nb0000000-0000-4000-8000-000000000000\n" {
+		t.Errorf("Expected --unique to keep the first occurrence, got:\n%s", output)
+	}
+}
+
+func TestSortTimeOrder(t *testing.T) {
+	v7Early := uuidV7At(t, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	v7Late := uuidV7At(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	v1 := "d9428888-122b-11e1-b85c-61cd3cbb3210" // 2011-11-18
+	v4 := "ffffffff-ffff-4fff-bfff-ffffffffffff"
+
+	output, err := executeCommand(t, "", "sort", "--order", "time", v7Late, v1, v4, v7Early)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := strings.Join([]string{v4, v7Early, v1, v7Late}, "\n") + "\n"
+	if output != want {
+		t.Errorf("Expected UUIDs without a timestamp first, then by time:\n%s\ngot:\n%s", want, output)
+	}
+}
+
+func TestSortExternalMatchesInMemory(t *testing.T) {
+	original := sortMergeFanIn
+	sortMergeFanIn = 3
+	t.Cleanup(func() { sortMergeFanIn = original })
+	input := writeSortInput(t, 3000)
+
+	for _, order := range []string{"bytes", "sqlserver", "time"} {
+		for _, unique := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s unique=%v", order, unique), func(t *testing.T) {
+				args := []string{"sort", "-f", input, "--order", order}
+				if unique {
+					args = append(args, "--unique")
+				}
+				inMemory, _, err := executeCommandSplit(t, "", args...)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				tempDir := t.TempDir()
+				external, progress, err := executeCommandSplit(t, "", append(args, "--memory-limit", "8K", "--temp-dir", tempDir, "--progress")...)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if external != inMemory {
+					t.Errorf("Expected the external sort to match the in-memory sort")
+				}
+				if !strings.Contains(progress, "run 1: ") || !strings.Contains(progress, "merged 3 runs into ") || !strings.Contains(progress, "sorted 3000 values") {
+					t.Errorf("Expected runs, intermediate merges, and a summary on stderr, got:\n%s", progress)
+				}
+				if entries, _ := os.ReadDir(tempDir); len(entries) != 0 {
+					t.Errorf("Expected temporary files to be removed, found %v", entries)
+				}
+			})
+		}
+	}
+}
+
+func TestSortExternalOutputIsSorted(t *testing.T) {
+	input := writeSortInput(t, 2000)
+	output, err := executeCommand(t, "", "sort", "-f", input, "--memory-limit", "4K", "--temp-dir", t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 2000 {
+		t.Fatalf("Expected 2000 lines, got %d", len(lines))
+	}
+	keys := make([]string, len(lines))
+	for i, line := range lines {
+		uuid, _ := generator.ParseUUID(line)
+		keys[i] = generator.FormatUUID(uuid)
+	}
+	if !slices.IsSorted(keys) {
+		t.Errorf("Expected the output in byte order")
+	}
+}
+
+// cancelOnWrite cancels a context the first time it is written to
+type cancelOnWrite struct {
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnWrite) Write(p []byte) (int, error) {
+	c.cancel()
+	return len(p), nil
+}
+
+func TestSortCancelledMidMerge(t *testing.T) {
+	var cancel context.CancelFunc
+	original := sortContext
+	sortContext = func(parent context.Context) (context.Context, context.CancelFunc) {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(parent)
+		return ctx, cancel
+	}
+	t.Cleanup(func() { sortContext = original })
+
+	input := writeSortInput(t, 2000)
+	tempDir := t.TempDir()
+	var progress strings.Builder
+	out := &cancelOnWrite{cancel: func() { cancel() }}
+	err := runCommand("", out, &progress, "sort", "-f", input, "--memory-limit", "4K", "--temp-dir", tempDir, "--progress")
+	if err == nil || err.Error() != "sort interrupted; temporary files removed" {
+		t.Fatalf("Expected an interruption error, got %v", err)
+	}
+	if !strings.Contains(progress.String(), "merging ") || strings.Contains(progress.String(), "sorted 2000 values") {
+		t.Errorf("Expected the merge to start and not finish, got:\n%s", progress.String())
+	}
+	if entries, _ := os.ReadDir(tempDir); len(entries) != 0 {
+		t.Errorf("Expected temporary files to be removed, found %v", entries)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"4096", 4096},
+		{"512M", 512 << 20},
+		{"2G", 2 << 30},
+		{"64k", 64 << 10},
+		{"16GB", 16 << 30},
+		{"1TiB", 1 << 40},
+		{"100B", 100},
+	}
+	for _, tt := range tests {
+		if got, err := parseByteSize(tt.value); err != nil || got != tt.want {
+			t.Errorf("parseByteSize(%q) = %d, %v; expected %d", tt.value, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"", "0", "-1M", "M", "1.5G", "2X", "1MK", "9999999T"} {
+		if _, err := parseByteSize(value); err == nil {
+			t.Errorf("Expected parseByteSize(%q) to fail", value)
+		}
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"unknown order", []string{"sort", "--order", "random", "x"}, "unknown order 'random'. Available orders: bytes, sqlserver, time"},
+		{"bad memory limit", []string{"sort", "--memory-limit", "lots", "x"}, "invalid --memory-limit: 'lots' is not a size such as 512M or 2G"},
+		{"invalid uuid", []string{"sort", "00000000-0000-4000-8000-000000000001", "nope"}, "invalid UUID 'nope'"},
+		{"missing file", []string{"sort", "-f", "missing.txt"}, "missing.txt: no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
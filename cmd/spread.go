@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// Business hours for --distribution business-hours, in UTC
+const (
+	businessDayStart = 9 * time.Hour
+	businessDayEnd   = 17 * time.Hour
+)
+
+// spreadConflicts are the flags that choose another generator, or generate a
+// UUID per input or reuse one, which a precomputed spread of count timestamps
+// cannot serve
+var spreadConflicts = []string{"timestamp", "from-hex", "nanoid", "ulid", "all", "corrupt", "idempotent", "per-line", "exclude-file", "unique"}
+
+// spreadInterval is a span of whole milliseconds timestamps may be drawn from
+type spreadInterval struct {
+	start int64 // Unix milliseconds
+	size  int64 // milliseconds
+}
+
+// spreadGenerator returns a generator yielding count UUIDv7s whose
+// timestamps are drawn at random from the --spread window, in sorted order
+// unless --shuffle is set. Timestamps always come from a ChaCha8 stream
+// seeded by --seed; with --seed the random bits do too, so the output is
+// reproducible.
+func spreadGenerator(cmd *cobra.Command, count int) (func() string, error) {
+	from, to, err := spreadWindow(cmd)
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range []time.Time{from, to} {
+		if err := generator.CheckUUIDv7Timestamp(endpoint); err != nil {
+			return nil, err
+		}
+	}
+	distribution, _ := cmd.Flags().GetString("distribution")
+	shuffle, _ := cmd.Flags().GetBool("shuffle")
+
+	var intervals []spreadInterval
+	switch distribution {
+	case "uniform":
+		intervals = []spreadInterval{{start: from.UnixMilli(), size: to.UnixMilli() - from.UnixMilli() + 1}}
+	case "business-hours":
+		intervals = businessIntervals(from, to)
+	default:
+		return nil, fmt.Errorf("unknown --distribution '%s'. Available distributions: uniform, business-hours", distribution)
+	}
+	// cumulative[i] is the number of milliseconds in intervals[:i+1]
+	cumulative := make([]int64, len(intervals))
+	var total int64
+	for i, interval := range intervals {
+		total += interval.size
+		cumulative[i] = total
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("the --spread window has no business hours (%s to %s UTC, Monday to Friday)", formatClock(businessDayStart), formatClock(businessDayEnd))
+	}
+
+	seed, err := seedFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	source := rand.NewChaCha8(key)
+	rng := rand.New(source)
+	seeded := cmd.Flags().Changed("seed")
+	debugLogger.Debug("selected generator", "version", 7, "timestamp_source", "spread", "from", from, "to", to,
+		"distribution", distribution, "shuffle", shuffle, "seed", seed, "count", count)
+
+	values := make([]string, count)
+	for i := range values {
+		offset := rng.Int64N(total)
+		j := sort.Search(len(cumulative), func(j int) bool { return cumulative[j] > offset })
+		ms := intervals[j].start + intervals[j].size - (cumulative[j] - offset)
+		timestamp := time.UnixMilli(ms)
+		if seeded {
+			var random [16]byte
+			source.Read(random[:])
+			values[i] = generator.NewV7FromBytes(timestamp, random)
+		} else {
+			if values[i], err = generator.GenerateUUIDv7WithTimestamp(timestamp); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Timestamps are drawn independently, so the draw order is already shuffled
+	if !shuffle {
+		slices.Sort(values)
+	}
+
+	next := 0
+	return func() string {
+		value := values[next%len(values)]
+		next++
+		return value
+	}, nil
+}
+
+// spreadWindow returns the window of --spread, ending now, or of
+// --spread-from and --spread-to, where the end defaults to now
+func spreadWindow(cmd *cobra.Command) (time.Time, time.Time, error) {
+	to := now()
+	if cmd.Flags().Changed("spread") {
+		value, _ := cmd.Flags().GetString("spread")
+		d, err := parseDays(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --spread '%s': %w", value, err)
+		}
+		if d <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("--spread must be positive, got %s", value)
+		}
+		return to.Add(-d), to, nil
+	}
+
+	value, _ := cmd.Flags().GetString("spread-from")
+	from, err := parseTimestamp(value)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --spread-from: %w", err)
+	}
+	if cmd.Flags().Changed("spread-to") {
+		value, _ := cmd.Flags().GetString("spread-to")
+		if to, err = parseTimestamp(value); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --spread-to: %w", err)
+		}
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--spread-to is before --spread-from")
+	}
+	return from, to, nil
+}
+
+// businessIntervals returns the business hours, Monday to Friday in UTC,
+// that fall within from and to inclusive
+func businessIntervals(from, to time.Time) []spreadInterval {
+	first, last := from.UnixMilli(), to.UnixMilli()+1
+	var intervals []spreadInterval
+	day := from.UTC().Truncate(24 * time.Hour)
+	for ; day.UnixMilli() < last; day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		start := max(day.Add(businessDayStart).UnixMilli(), first)
+		end := min(day.Add(businessDayEnd).UnixMilli(), last)
+		if end > start {
+			intervals = append(intervals, spreadInterval{start: start, size: end - start})
+		}
+	}
+	return intervals
+}
+
+// formatClock renders a time of day such as 9h as 09:00
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// parseDays parses a Go duration, also accepting a whole number of days or
+// weeks such as 30d or 2w
+func parseDays(value string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if digits, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(digits)
+			if err != nil {
+				return 0, fmt.Errorf("expected a whole number of days or weeks, such as 30d or 2w")
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	return time.ParseDuration(value)
+}
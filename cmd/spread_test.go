@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// spreadTimestamps parses generated UUIDv7s and returns their timestamps
+func spreadTimestamps(t *testing.T, output string) []time.Time {
+	t.Helper()
+	var timestamps []time.Time
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		uuid, err := generator.ParseUUID(line)
+		if err != nil {
+			t.Fatalf("Invalid UUID %q: %v", line, err)
+		}
+		d := generator.Inspect(uuid)
+		if d.Version != 7 || d.Timestamp == nil {
+			t.Fatalf("Expected a UUIDv7, got %s", line)
+		}
+		timestamps = append(timestamps, *d.Timestamp)
+	}
+	return timestamps
+}
+
+func TestSpreadWindow(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		args     []string
+		from, to time.Time
+	}{
+		{"days", []string{"--spread", "30d"}, pinned.AddDate(0, 0, -30), pinned},
+		{"duration", []string{"--spread", "90m"}, pinned.Add(-90 * time.Minute), pinned},
+		{"explicit", []string{"--spread-from", "2023-06-01", "--spread-to", "2023-06-03"}, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 6, 3, 0, 0, 0, 0, time.UTC)},
+		{"open ended", []string{"--spread-from", "2024-02-29"}, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), pinned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-7", "-n", "500", "--now", "2024-03-01T00:00:00Z"}, tt.args...)
+			output, err := executeCommand(t, "", args...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(output), "\n")
+			if len(lines) != 500 || !slices.IsSorted(lines) {
+				t.Errorf("Expected 500 sorted UUIDs, got %d", len(lines))
+			}
+			for _, ts := range spreadTimestamps(t, output) {
+				if ts.Before(tt.from) || ts.After(tt.to) {
+					t.Errorf("Timestamp %s is outside %s to %s", ts, tt.from, tt.to)
+				}
+			}
+		})
+	}
+}
+
+func TestSpreadSeedAndShuffle(t *testing.T) {
+	args := []string{"-n", "200", "--spread-from", "2023-06-01", "--spread-to", "2023-07-01", "--seed", "42"}
+	sorted, err := executeCommand(t, "", args...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	again, _ := executeCommand(t, "", args...)
+	if sorted != again {
+		t.Error("Expected --seed to reproduce the output")
+	}
+	other, _ := executeCommand(t, "", "-n", "200", "--spread-from", "2023-06-01", "--spread-to", "2023-07-01", "--seed", "43")
+	if other == sorted {
+		t.Error("Expected another seed to give other output")
+	}
+
+	shuffled, err := executeCommand(t, "", append(args, "--shuffle")...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(shuffled), "\n")
+	if slices.IsSorted(lines) {
+		t.Error("Expected --shuffle output out of order")
+	}
+	slices.Sort(lines)
+	if strings.Join(lines, "\n")+"\n" != sorted {
+		t.Error("Expected --shuffle to emit the same UUIDs in another order")
+	}
+}
+
+func TestSpreadBusinessHours(t *testing.T) {
+	// Two weeks starting on a Saturday evening
+	output, err := executeCommand(t, "", "-7", "-n", "2000", "--distribution", "business-hours",
+		"--spread-from", "2024-03-02T20:00:00Z", "--spread-to", "2024-03-16T12:00:00Z")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, ts := range spreadTimestamps(t, output) {
+		if ts.Weekday() == time.Saturday || ts.Weekday() == time.Sunday || ts.Hour() < 9 || ts.Hour() >= 17 {
+			t.Fatalf("Timestamp %s is outside business hours", ts)
+		}
+	}
+
+	// A window that starts and ends inside one working day
+	output, err = executeCommand(t, "", "-n", "100", "--distribution", "business-hours",
+		"--spread-from", "2024-03-04T16:59:00Z", "--spread-to", "2024-03-04T18:00:00Z")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, ts := range spreadTimestamps(t, output) {
+		if ts.Before(time.Date(2024, 3, 4, 16, 59, 0, 0, time.UTC)) || ts.Hour() != 16 {
+			t.Fatalf("Timestamp %s is outside 16:59 to 17:00", ts)
+		}
+	}
+}
+
+func TestSpreadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"weekend only", []string{"--distribution", "business-hours", "--spread-from", "2024-03-02", "--spread-to", "2024-03-03T23:00:00Z"}, "the --spread window has no business hours (09:00 to 17:00 UTC, Monday to Friday)"},
+		{"unknown distribution", []string{"--spread", "1d", "--distribution", "weekly"}, "unknown --distribution 'weekly'"},
+		{"bad duration", []string{"--spread", "thirty"}, "invalid --spread 'thirty'"},
+		{"bad days", []string{"--spread", "1.5d"}, "expected a whole number of days or weeks"},
+		{"negative", []string{"--spread", "-1h"}, "--spread must be positive"},
+		{"reversed", []string{"--spread-from", "2024-03-02", "--spread-to", "2024-03-01"}, "--spread-to is before --spread-from"},
+		{"to without from", []string{"--spread-to", "2024-03-01"}, "--spread-to requires --spread or --spread-from"},
+		{"shuffle alone", []string{"--shuffle"}, "--shuffle requires --spread or --spread-from"},
+		{"both windows", []string{"--spread", "1d", "--spread-from", "2024-03-01"}, "none of the others can be"},
+		{"with v4", []string{"--spread", "1d", "-4"}, "--spread generates UUIDv7 and cannot be combined with -1, -4"},
+		{"with timestamp", []string{"--spread", "1d", "-t", "2024-03-01"}, "--spread cannot be combined with -t"},
+		{"with per-line", []string{"--spread", "1d", "--per-line"}, "--spread cannot be combined with --per-line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
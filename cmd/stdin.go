@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+// stdinConflicts are the flags that name a single UUID, take the count from
+// -n, or produce output other than one record per name, which --stdin
+// cannot honour
+var stdinConflicts = []string{"name", "count", "pg-copy", "pg-copy-binary", "parquet", "qr", "phonetic", "variant", "encrypt-time"}
+
+// writeNames prints the UUIDv5 in namespace of every line of in, in input
+// order. Trailing whitespace is trimmed from each name, and blank lines are
+// skipped, or rejected when strict is set. Lines are read with ReadString
+// rather than a Scanner, so a name is not cut off at the Scanner's token
+// limit, and only the current line is held in memory.
+func (e *emitter) writeNames(in io.Reader, out io.Writer, namespace [16]byte, strict bool) error {
+	w := bufio.NewWriter(out)
+	e.writeHeader(w)
+
+	reader := bufio.NewReader(in)
+	for number := 1; ; number++ {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			w.Flush()
+			return err
+		}
+		if line == "" && err == io.EOF {
+			return w.Flush()
+		}
+
+		name := strings.TrimRight(line, " \t\r\n")
+		if name == "" {
+			if strict {
+				w.Flush()
+				return fmt.Errorf("line %d of stdin is empty (--strict)", number)
+			}
+		} else {
+			record, recordErr := e.record(generator.GenerateUUIDv5WithNamespace(namespace, name))
+			if recordErr != nil {
+				w.Flush()
+				return recordErr
+			}
+			fmt.Fprintln(w, record)
+		}
+
+		if err == io.EOF {
+			return w.Flush()
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scottbrown/uuid/internal/generator"
+)
+
+func TestStdinNames(t *testing.T) {
+	var input strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&input, "host-%05d.example.com\n", i)
+	}
+
+	output, err := executeCommand(t, input.String(), "-5", "--namespace", "dns", "--stdin")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 10000 {
+		t.Fatalf("Expected 10000 UUIDs, got %d", len(lines))
+	}
+	for i, line := range lines {
+		want, _ := generator.GenerateUUIDv5("dns", fmt.Sprintf("host-%05d.example.com", i))
+		if line != want {
+			t.Fatalf("Line %d: expected %s, got %s", i+1, want, line)
+		}
+	}
+
+	again, err := executeCommand(t, input.String(), "-5", "--namespace", "dns", "--stdin")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if again != output {
+		t.Error("Expected the same names to give the same UUIDs")
+	}
+}
+
+func TestStdinNamesTrimsAndSkips(t *testing.T) {
+	output, err := executeCommand(t, "a.example.com \t\r\n\n   \nb.example.com", "-5", "--namespace", "dns", "--stdin")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	a, _ := generator.GenerateUUIDv5("dns", "a.example.com")
+	b, _ := generator.GenerateUUIDv5("dns", "b.example.com")
+	if output != a+"\n"+b+"\n" {
+		t.Errorf("Expected the UUIDs of the two names, got:\n%s", output)
+	}
+}
+
+func TestStdinNamesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		stdin    string
+		args     []string
+		contains string
+	}{
+		{"strict blank line", "a\n\nb\n", []string{"-5", "--namespace", "dns", "--stdin", "--strict"}, "line 2 of stdin is empty (--strict)"},
+		{"without v5", "a\n", []string{"-4", "--stdin"}, "--stdin requires -5"},
+		{"strict alone", "a\n", []string{"-5", "--namespace", "dns", "--name", "a", "--strict"}, "--strict requires --stdin"},
+		{"with name", "a\n", []string{"-5", "--namespace", "dns", "--name", "a", "--stdin"}, "--stdin cannot be combined with --name"},
+		{"with count", "a\n", []string{"-5", "--namespace", "dns", "--stdin", "-n", "3"}, "--stdin cannot be combined with -n"},
+		{"missing namespace", "a\n", []string{"-5", "--stdin"}, "-5 requires --namespace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, tt.stdin, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
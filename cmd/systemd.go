@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFDsStart is the first file descriptor systemd passes to a socket
+// activated service (SD_LISTEN_FDS_START). Tests move it to descriptors
+// they opened.
+var listenFDsStart = 3
+
+// activatedListeners returns the sockets systemd passed when LISTEN_PID
+// names this process, or nil when the process was not socket activated.
+// Like sd_listen_fds, it unsets the variables so child processes do not
+// take the sockets for their own.
+func activatedListeners() ([]net.Listener, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+	if pid == "" || fds == "" || pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS '%s' from socket activation", fds)
+	}
+
+	listeners := make([]net.Listener, 0, n)
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		// FileListener works on a duplicate, so the passed descriptor is
+		// closed either way
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		listener, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("cannot use socket-activated file descriptor %d: %w", fd, err)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// sdNotify sends state, such as READY=1, to the service manager socket in
+// NOTIFY_SOCKET, as sd_notify does; without NOTIFY_SOCKET it does nothing.
+// A name starting with '@' is an abstract socket, which net resolves.
+func sdNotify(state string) error {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("cannot notify NOTIFY_SOCKET '%s': %w", path, err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("cannot notify NOTIFY_SOCKET '%s': %w", path, err)
+	}
+	return nil
+}
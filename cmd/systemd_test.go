@@ -0,0 +1,180 @@
+//go:build unix
+
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// activate hands listener to the next uuid serve as systemd would: as a
+// descriptor owned by the server, announced in LISTEN_PID and LISTEN_FDS
+func activate(t *testing.T, listener net.Listener) {
+	t.Helper()
+	f, err := listener.(interface{ File() (*os.File, error) }).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd, err := syscall.Dup(int(f.Fd()))
+	f.Close()
+	listener.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := listenFDsStart
+	listenFDsStart = fd
+	t.Cleanup(func() { listenFDsStart = original })
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "1")
+}
+
+func TestServeSocketActivationTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	activate(t, listener)
+
+	// --listen would fail, so serving proves the inherited socket is used
+	s := startServe(t, "--listen", "unix:"+filepath.Join(t.TempDir(), "missing", "uuid.sock"))
+	if s.addr.String() != addr {
+		t.Errorf("Expected to serve on the inherited %s, got %s", addr, s.addr)
+	}
+	var body struct{ UUIDs []string }
+	if resp := getJSON(t, http.DefaultClient, "http://"+addr+"/uuid", "", &body); resp.StatusCode != http.StatusOK || len(body.UUIDs) != 1 {
+		t.Errorf("Expected a UUID from the inherited socket, got %d %v", resp.StatusCode, body.UUIDs)
+	}
+	if os.Getenv("LISTEN_FDS") != "" || os.Getenv("LISTEN_PID") != "" {
+		t.Error("Expected the activation variables to be unset")
+	}
+}
+
+func TestServeSocketActivationUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uuid.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// systemd owns the socket file, so closing our copy must not remove it
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	activate(t, listener)
+
+	s := startServe(t)
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, "unix", path)
+		},
+	}}
+	if resp := getJSON(t, client, "http://uuid/healthz", "", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the inherited Unix socket to serve, got %d", resp.StatusCode)
+	}
+
+	s.signals <- syscall.SIGTERM
+	if err := s.wait(t); err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the systemd-owned socket to remain, got %v", err)
+	}
+}
+
+func TestServeSocketActivationOtherProcess(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+
+	s := startServe(t)
+	if !strings.HasPrefix(s.addr.String(), "127.0.0.1:") {
+		t.Errorf("Expected to fall back to --listen, got %s", s.addr)
+	}
+}
+
+func TestServeSocketActivationErrors(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "none")
+	_, err := executeCommand(t, "", "serve", "--no-clock-check")
+	if err == nil || !strings.Contains(err.Error(), "invalid LISTEN_FDS 'none'") {
+		t.Errorf("Expected an invalid LISTEN_FDS error, got: %v", err)
+	}
+
+	// A descriptor that is not a socket
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd, _ := syscall.Dup(int(f.Fd()))
+	f.Close()
+	original := listenFDsStart
+	listenFDsStart = fd
+	defer func() { listenFDsStart = original }()
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "1")
+	_, err = executeCommand(t, "", "serve", "--no-clock-check")
+	if err == nil || !strings.Contains(err.Error(), "cannot use socket-activated file descriptor") {
+		t.Errorf("Expected a file descriptor error, got: %v", err)
+	}
+}
+
+// readNotify reads one sd_notify message from conn
+func readNotify(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 256)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Reading the notify socket: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestServeNotify(t *testing.T) {
+	names := []string{filepath.Join(t.TempDir(), "notify")}
+	if runtime.GOOS == "linux" {
+		names = append(names, "@uuid-serve-test-"+strconv.Itoa(os.Getpid()))
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: name, Net: "unixgram"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			t.Setenv("NOTIFY_SOCKET", name)
+
+			s := startServe(t)
+			if got := readNotify(t, conn); got != "READY=1" {
+				t.Errorf("Expected READY=1, got %q", got)
+			}
+			s.signals <- syscall.SIGTERM
+			if got := readNotify(t, conn); got != "STOPPING=1" {
+				t.Errorf("Expected STOPPING=1, got %q", got)
+			}
+			if err := s.wait(t); err != nil {
+				t.Errorf("Expected a clean shutdown, got %v", err)
+			}
+		})
+	}
+}
+
+func TestServeNotifyFailureWarns(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing"))
+	s := startServe(t)
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(s.stderr.String(), "WARNING: cannot notify NOTIFY_SOCKET") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a notify warning, got:\n%s", s.stderr)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
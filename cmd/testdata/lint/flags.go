@@ -0,0 +1,8 @@
+package flags
+
+// Feature flag IDs
+const (
+	CheckoutFlag = "0188b733-b800-7079-9ce7-7022b2ba0185"
+	SearchFlag   = "0188b733-b800-7079-9ce7-7022b2ba018" // one digit short
+	Placeholder  = "00000000-0000-0000-0000-000000000000"
+)
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// partitionLayouts name each --by granularity's partition label. The labels
+// sort in time order, which --summary relies on.
+var partitionLayouts = map[string]string{
+	"hour":  "2006-01-02T15",
+	"day":   "2006-01-02",
+	"month": "2006-01",
+}
+
+// timePartitionCmd assigns time-based UUIDs to calendar partitions
+var timePartitionCmd = &cobra.Command{
+	Use:   "time-partition [uuid...]",
+	Short: "Assign time-based UUIDs to hour, day, or month partitions",
+	Long: `Assign each time-based UUID (v1, v6, or v7) to the calendar partition
+holding its timestamp, printing "uuid<TAB>partition".
+
+UUIDs are read from the arguments, or one per line from each --file in
+turn ('-' is stdin), or from stdin when no arguments are given.
+
+  --by hour   2024-03-01T10
+  --by day    2024-03-01 (default)
+  --by month  2024-03
+
+Partition boundaries fall at midnight (or the top of the hour) in --tz,
+which takes an IANA zone name such as America/Toronto, or Local; the
+default is UTC.
+
+--summary prints "partition<TAB>count" for each partition in time order
+instead. --split-dir writes the UUIDs of each partition to its own file,
+named after the partition, such as out/2024-03-01.txt.
+
+UUIDs without a timestamp, such as v4s, are skipped with a warning, or
+fail the run under --strict.
+
+Examples:
+  uuid time-partition --by day < ids.txt
+  uuid time-partition --by month --summary -f ids.txt
+  uuid time-partition --tz America/Toronto --split-dir out/ < ids.txt`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		by, _ := cmd.Flags().GetString("by")
+		tz, _ := cmd.Flags().GetString("tz")
+		summary, _ := cmd.Flags().GetBool("summary")
+		splitDir, _ := cmd.Flags().GetString("split-dir")
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		layout, ok := partitionLayouts[by]
+		if !ok {
+			return fmt.Errorf("unknown --by '%s'. Available granularities: hour, day, month", by)
+		}
+		location, err := time.LoadLocation(tz)
+		if err != nil {
+			return fmt.Errorf("unknown --tz '%s': %w", tz, err)
+		}
+		if splitDir != "" {
+			if err := os.MkdirAll(splitDir, 0o755); err != nil {
+				return err
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		counts := map[string]int{}
+		files := map[string]*os.File{}
+		defer func() {
+			for _, f := range files {
+				f.Close()
+			}
+		}()
+		skipped := 0
+
+		handle := func(value string) error {
+			uuid, err := generator.ParseUUID(value)
+			if err != nil {
+				return fmt.Errorf("invalid UUID '%s': %w", value, err)
+			}
+			details := generator.Inspect(uuid)
+			if details.Timestamp == nil {
+				if strict {
+					return fmt.Errorf("UUID '%s' is version %d and has no timestamp", value, details.Version)
+				}
+				skipped++
+				return nil
+			}
+			partition := details.Timestamp.In(location).Format(layout)
+
+			switch {
+			case summary:
+				counts[partition]++
+			case splitDir != "":
+				f, ok := files[partition]
+				if !ok {
+					f, err = os.Create(filepath.Join(splitDir, partition+".txt"))
+					if err != nil {
+						return err
+					}
+					files[partition] = f
+				}
+				if _, err := fmt.Fprintln(f, value); err != nil {
+					return err
+				}
+			default:
+				fmt.Fprintf(out, "%s\t%s\n", value, partition)
+			}
+			return nil
+		}
+
+		if err := eachInputValue(cmd, args, handle); err != nil {
+			return err
+		}
+		if skipped > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: skipped %d UUIDs without a timestamp\n", skipped)
+		}
+		if summary {
+			partitions := make([]string, 0, len(counts))
+			for partition := range counts {
+				partitions = append(partitions, partition)
+			}
+			slices.Sort(partitions)
+			for _, partition := range partitions {
+				fmt.Fprintf(out, "%s\t%d\n", partition, counts[partition])
+			}
+		}
+		for partition, f := range files {
+			delete(files, partition)
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	timePartitionCmd.Flags().String("by", "day", "Partition granularity: hour, day, or month")
+	timePartitionCmd.Flags().String("tz", "UTC", "Time zone for partition boundaries, such as America/Toronto or Local")
+	timePartitionCmd.Flags().Bool("summary", false, "Print the number of UUIDs in each partition instead")
+	timePartitionCmd.Flags().String("split-dir", "", "Write each partition's UUIDs to its own file in this directory")
+	timePartitionCmd.Flags().Bool("strict", false, "Fail on UUIDs without a timestamp instead of skipping them")
+	timePartitionCmd.MarkFlagsMutuallyExclusive("summary", "split-dir")
+	addInputFlags(timePartitionCmd)
+
+	rootCmd.AddCommand(timePartitionCmd)
+}
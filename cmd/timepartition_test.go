@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func partitionTestUUID(t *testing.T, value string) string {
+	t.Helper()
+	ts, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uuidV7At(t, ts)
+}
+
+func TestTimePartitionMidnightBoundaries(t *testing.T) {
+	if _, err := time.LoadLocation("America/Toronto"); err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// Midnight UTC on 2024-03-01, and midnight in Toronto (UTC-5) five hours
+	// later, each with the millisecond before it
+	beforeUTC := partitionTestUUID(t, "2024-02-29T23:59:59.999Z")
+	atUTC := partitionTestUUID(t, "2024-03-01T00:00:00Z")
+	beforeToronto := partitionTestUUID(t, "2024-03-01T04:59:59.999Z")
+	atToronto := partitionTestUUID(t, "2024-03-01T05:00:00Z")
+	input := strings.Join([]string{beforeUTC, atUTC, beforeToronto, atToronto}, "\n") + "\n"
+
+	tests := []struct {
+		tz   string
+		want []string
+	}{
+		{"UTC", []string{"2024-02-29", "2024-03-01", "2024-03-01", "2024-03-01"}},
+		{"America/Toronto", []string{"2024-02-29", "2024-02-29", "2024-02-29", "2024-03-01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tz, func(t *testing.T) {
+			output, err := executeCommand(t, input, "time-partition", "--by", "day", "--tz", tt.tz)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(output), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("Expected %d lines, got %q", len(tt.want), output)
+			}
+			for i, line := range lines {
+				value, partition, _ := strings.Cut(line, "\t")
+				if value != strings.Fields(input)[i] || partition != tt.want[i] {
+					t.Errorf("Line %d: expected partition %s, got %q", i, tt.want[i], line)
+				}
+			}
+		})
+	}
+}
+
+func TestTimePartitionGranularities(t *testing.T) {
+	uuid := partitionTestUUID(t, "2024-03-01T10:04:12.345Z")
+	tests := []struct {
+		by   string
+		want string
+	}{
+		{"hour", "2024-03-01T10"},
+		{"day", "2024-03-01"},
+		{"month", "2024-03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			output, err := executeCommand(t, "", "time-partition", "--by", tt.by, uuid)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != uuid+"\t"+tt.want+"\n" {
+				t.Errorf("Unexpected output %q", output)
+			}
+		})
+	}
+}
+
+func TestTimePartitionSummary(t *testing.T) {
+	input := strings.Join([]string{
+		partitionTestUUID(t, "2024-03-02T01:00:00Z"),
+		partitionTestUUID(t, "2024-02-29T12:00:00Z"),
+		partitionTestUUID(t, "2024-03-02T23:00:00Z"),
+		"1eed7b30-daa5-6a90-8000-000000000000", // v6, 2024-03-01
+	}, "\n")
+	output, err := executeCommand(t, input, "time-partition", "--summary")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "2024-02-29\t1\n2024-03-01\t1\n2024-03-02\t2\n" {
+		t.Errorf("Unexpected summary %q", output)
+	}
+}
+
+func TestTimePartitionSplitDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	first := partitionTestUUID(t, "2024-03-01T10:00:00Z")
+	second := partitionTestUUID(t, "2024-03-01T11:00:00Z")
+	third := partitionTestUUID(t, "2024-04-15T00:00:00Z")
+	input := first + "\n" + third + "\n" + second + "\n"
+
+	output, err := executeCommand(t, input, "time-partition", "--by", "month", "--split-dir", dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("Expected no output, got %q", output)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if strings.Join(names, ",") != "2024-03.txt,2024-04.txt" {
+		t.Errorf("Unexpected partition files %v", names)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "2024-03.txt")); string(data) != first+"\n"+second+"\n" {
+		t.Errorf("Unexpected 2024-03.txt %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "2024-04.txt")); string(data) != third+"\n" {
+		t.Errorf("Unexpected 2024-04.txt %q", data)
+	}
+}
+
+func TestTimePartitionSkipsUntimed(t *testing.T) {
+	v7 := partitionTestUUID(t, "2024-03-01T10:00:00Z")
+	v4 := "919108f7-52d1-4320-9bac-f847db4148a8"
+
+	stdout, stderr, err := executeCommandSplit(t, v4+"\n"+v7+"\n", "time-partition")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stdout != v7+"\t2024-03-01\n" {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+	if !strings.Contains(stderr, "WARNING: skipped 1 UUIDs without a timestamp") {
+		t.Errorf("Expected a skip warning, got %q", stderr)
+	}
+
+	_, err = executeCommand(t, v4+"\n"+v7+"\n", "time-partition", "--strict")
+	if err == nil || !strings.Contains(err.Error(), "UUID '"+v4+"' is version 4 and has no timestamp") {
+		t.Errorf("Expected a strict error, got: %v", err)
+	}
+}
+
+func TestTimePartitionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{"unknown by", []string{"--by", "week"}, "unknown --by 'week'"},
+		{"unknown tz", []string{"--tz", "Mars/Olympus"}, "unknown --tz 'Mars/Olympus'"},
+		{"summary with split", []string{"--summary", "--split-dir", t.TempDir()}, "none of the others can be"},
+		{"invalid uuid", []string{"not-a-uuid"}, "invalid UUID 'not-a-uuid'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", append([]string{"time-partition"}, tt.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// validateCmd strictly checks values against the canonical UUID form
+var validateCmd = &cobra.Command{
+	Use:   "validate [value...]",
+	Short: "Strictly check that values are canonical RFC 9562 UUIDs",
+	Long: `Strictly check that values are canonical RFC 9562 UUIDs.
+
+Values are read from the arguments, or one per line from each --file in
+turn ('-' is stdin), or from stdin when no arguments are given. Each value is printed followed by a tab and "ok", or by
+a tab, a reason code, a tab, and an explanation. Reason codes are stable:
+
+  length    not 36 characters
+  hyphens   hyphens missing or out of place
+  hex       a character that is not a hex digit
+  version   version nibble outside 1-8
+  variant   variant bits other than the RFC 9562 variant
+
+With --porcelain the explanation is left out, so each line is the value
+and "ok" or the reason code.
+
+The Nil and Max UUIDs are valid. The command exits non-zero when any value
+is invalid.
+
+Examples:
+  uuid validate 919108f7-52d1-4320-9bac-f847db4148a8
+  uuid --corrupt random -n 10 | uuid validate
+  uuid validate -f old.txt -f new.txt --keep-going --verbose`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		porcelain, err := porcelainFlag(cmd, nil)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		total, invalid := 0, 0
+		check := func(value string) error {
+			total++
+			var validationErr *generator.ValidationError
+			if err := generator.Validate(value); errors.As(err, &validationErr) {
+				invalid++
+				if porcelain != "" {
+					fmt.Fprintf(out, "%s\t%s\n", value, validationErr.Reason)
+				} else {
+					fmt.Fprintf(out, "%s\t%s\t%s\n", value, validationErr.Reason, validationErr.Message)
+				}
+				return nil
+			}
+			fmt.Fprintf(out, "%s\tok\n", value)
+			return nil
+		}
+
+		inputErr := eachInputValue(cmd, args, check)
+		if invalid > 0 {
+			// Report both problems, so a failed file does not hide invalid values
+			return errors.Join(inputErr, fmt.Errorf("%d of %d values are invalid", invalid, total))
+		}
+		return inputErr
+	},
+}
+
+func init() {
+	addInputFlags(validateCmd)
+	addPorcelainFlag(validateCmd)
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		expected string
+		wantErr  string
+	}{
+		{
+			name:     "valid arguments",
+			args:     []string{"validate", "919108f7-52d1-4320-9bac-f847db4148a8", "00000000-0000-0000-0000-000000000000"},
+			expected: "919108f7-52d1-4320-9bac-f847db4148a8\tok\n00000000-0000-0000-0000-000000000000\tok\n",
+		},
+		{
+			name:     "lenient forms are rejected",
+			args:     []string{"validate", "919108f752d143209bacf847db4148a8"},
+			expected: "919108f752d143209bacf847db4148a8\tlength\t",
+			wantErr:  "1 of 1 values are invalid",
+		},
+		{
+			name:     "stdin",
+			args:     []string{"validate"},
+			stdin:    "919108f7-52d1-4320-9bac-f847db4148a8\n\n919108f7-52d1-0320-9bac-f847db4148a8\n",
+			expected: "919108f7-52d1-4320-9bac-f847db4148a8\tok\n919108f7-52d1-0320-9bac-f847db4148a8\tversion\t",
+			wantErr:  "1 of 2 values are invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(t, tt.stdin, tt.args...)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+			if !strings.HasPrefix(output, tt.expected) {
+				t.Errorf("Expected output starting with %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckDigitRoundTrip(t *testing.T) {
+	output, err := executeCommand(t, "", "--check-digit", "-n", "5")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if len(line) != 38 || line[36] != '~' || !uuidRegex.MatchString(line[:36]) {
+			t.Fatalf("Expected <uuid>~<digit>, got %q", line)
+		}
+		verified, err := executeCommand(t, "", "verify-check", line)
+		if err != nil {
+			t.Fatalf("verify-check %s: %v", line, err)
+		}
+		if verified != line[:36]+"\n" {
+			t.Errorf("Expected %s, got %q", line[:36], verified)
+		}
+	}
+}
+
+func TestVerifyCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		contains string
+	}{
+		{"single character", []string{"verify-check", "d9428888-122b-11e1-b85c-61cd3cbb3211~6"}, "", "check digit mismatch"},
+		{"transposition", []string{"verify-check", "d9428888-122b-11e1-b85c-61cd3cbb2310~6"}, "", "adjacent transpositions are always detected"},
+		{"missing digit", []string{"verify-check", "d9428888-122b-11e1-b85c-61cd3cbb3210"}, "", "missing '~'"},
+		{"stdin line", []string{"verify-check"}, "d9428888-122b-11e1-b85c-61cd3cbb3210~6\nd9428888-122b-11e1-b85c-61cd3cbb3210~0\n", "line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, tt.stdin, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+
+	output, err := executeCommand(t, "", "verify-check", "D9428888-122B-11E1-B85C-61CD3CBB3210~6")
+	if err != nil || output != "d9428888-122b-11e1-b85c-61cd3cbb3210\n" {
+		t.Errorf("Expected upper case input to verify, got %q, %v", output, err)
+	}
+}
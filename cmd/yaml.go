@@ -0,0 +1,385 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/scottbrown/uuid/internal/generator"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// yamlPathPart matches one dot-separated part of a --path: an optional key
+// followed by any number of [N] or [*] subscripts
+var yamlPathPart = sync.OnceValue(func() *regexp.Regexp {
+	return regexp.MustCompile(`^([^\[\]]*)((?:\[(?:\*|\d+)\])*)$`)
+})
+
+// yamlCmd adds generated UUIDs to YAML documents
+var yamlCmd = &cobra.Command{
+	Use:   "yaml",
+	Short: "Set UUIDs at matching locations in YAML documents",
+	Long: `Set a UUIDv4 at every location in a YAML stream matched by --path.
+
+A path is a dot-separated list of keys, each optionally followed by [N] to
+pick one sequence item or [*] to pick every item, such as
+'items[*].metadata.uid'. The last part names the key to set: an existing
+scalar value is replaced (keeping its quote style), and a missing key is
+added as the last entry of its mapping. Locations whose parent keys are
+missing are skipped. With --only-empty, keys that already hold a non-empty
+value are left alone.
+
+The file is edited in place textually, so comments, anchors, key order,
+blank lines, and line endings are preserved and only the changed lines
+differ. Every document of a multi-document stream is processed. Block
+scalars (| and >) and flow collections ({} and []) at a matched location are
+rejected.
+
+The result is written to stdout, or back to --input with -i (and first
+copied to <input>.bak with --backup). Without --input, stdin is read.
+
+Examples:
+  uuid yaml --path 'items[*].metadata.uid' --input list.yaml
+  uuid yaml --path 'all.hosts[*].id' --only-empty -i --backup --input inventory.yaml`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pathFlag, _ := cmd.Flags().GetString("path")
+		input, _ := cmd.Flags().GetString("input")
+		onlyEmpty, _ := cmd.Flags().GetBool("only-empty")
+		inPlace, _ := cmd.Flags().GetBool("in-place")
+		backup, _ := cmd.Flags().GetBool("backup")
+
+		path, err := parseYAMLPath(pathFlag)
+		if err != nil {
+			return err
+		}
+		if inPlace && input == "" {
+			return fmt.Errorf("-i requires --input")
+		}
+		if backup && !inPlace {
+			return fmt.Errorf("--backup requires -i")
+		}
+
+		var src []byte
+		if input == "" {
+			src, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			src, err = os.ReadFile(input)
+		}
+		if err != nil {
+			return err
+		}
+
+		updated, err := setYAMLUUIDs(src, path, onlyEmpty, generator.GenerateUUIDv4)
+		if err != nil {
+			return err
+		}
+
+		if !inPlace {
+			_, err = cmd.OutOrStdout().Write(updated)
+			return err
+		}
+		info, err := os.Stat(input)
+		if err != nil {
+			return err
+		}
+		if backup {
+			if err := os.WriteFile(input+".bak", src, info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+		return os.WriteFile(input, updated, info.Mode().Perm())
+	},
+}
+
+// yamlPathSegment is one step of a --path: a mapping key, one sequence item,
+// or every sequence item
+type yamlPathSegment struct {
+	key   string
+	index int
+	all   bool
+}
+
+// isKey reports whether the segment selects a mapping key
+func (s yamlPathSegment) isKey() bool {
+	return s.key != ""
+}
+
+// parseYAMLPath splits a --path into segments. The last one must be a key.
+func parseYAMLPath(value string) ([]yamlPathSegment, error) {
+	var path []yamlPathSegment
+	for _, part := range strings.Split(value, ".") {
+		m := yamlPathPart().FindStringSubmatch(part)
+		if m == nil || (m[1] == "" && m[2] == "") {
+			return nil, fmt.Errorf("invalid --path '%s': expected keys and [N] or [*] subscripts separated by '.'", value)
+		}
+		if m[1] != "" {
+			if !plainYAMLKey().MatchString(m[1]) {
+				return nil, fmt.Errorf("invalid --path '%s': key '%s' must use letters, digits, '_', and '-'", value, m[1])
+			}
+			path = append(path, yamlPathSegment{key: m[1]})
+		}
+		for _, sub := range strings.Split(strings.Trim(m[2], "[]"), "][") {
+			switch sub {
+			case "":
+			case "*":
+				path = append(path, yamlPathSegment{all: true})
+			default:
+				index, _ := strconv.Atoi(sub)
+				path = append(path, yamlPathSegment{index: index})
+			}
+		}
+	}
+	if !path[len(path)-1].isKey() {
+		return nil, fmt.Errorf("invalid --path '%s': the last part must name a key to set", value)
+	}
+	return path, nil
+}
+
+// yamlEdit replaces remove bytes at offset in the source with insert
+type yamlEdit struct {
+	offset int
+	remove int
+	insert string
+}
+
+// yamlSource locates yaml.v3 line and column positions in the raw bytes
+type yamlSource struct {
+	src        []byte
+	lineStarts []int
+	eol        string
+}
+
+func newYAMLSource(src []byte) *yamlSource {
+	first, _ := splitLine(src)
+	s := &yamlSource{src: src, lineStarts: []int{0}, eol: lineEnding(first)}
+	if s.eol == "" {
+		s.eol = "\n"
+	}
+	for i, c := range src {
+		if c == '\n' {
+			s.lineStarts = append(s.lineStarts, i+1)
+		}
+	}
+	return s
+}
+
+// line returns line n (1-based) without its line ending
+func (s *yamlSource) line(n int) string {
+	start, end := s.lineStarts[n-1], len(s.src)
+	if n < len(s.lineStarts) {
+		end = s.lineStarts[n]
+	}
+	return strings.TrimRight(string(s.src[start:end]), "\r\n")
+}
+
+// offset converts a 1-based line and column, counted in characters, to a
+// byte offset
+func (s *yamlSource) offset(line, column int) int {
+	text := s.line(line)
+	i := 0
+	for range column - 1 {
+		if i >= len(text) {
+			i++
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return s.lineStarts[line-1] + i
+}
+
+// setYAMLUUIDs sets a UUID from next at every location path matches in
+// every document of src
+func setYAMLUUIDs(src []byte, path []yamlPathSegment, onlyEmpty bool, next func() string) ([]byte, error) {
+	s := newYAMLSource(src)
+	var edits []yamlEdit
+	seen := map[int]bool{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(src))
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		err = walkYAMLPath(&doc, path, func(mapping *yaml.Node, key string) error {
+			edit, ok, err := s.editFor(mapping, key, onlyEmpty, next)
+			if err != nil || !ok || seen[edit.offset] {
+				return err
+			}
+			// An aliased mapping is reached once per alias; edit it once
+			seen[edit.offset] = true
+			edits = append(edits, edit)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply from the end so earlier offsets stay valid
+	slices.SortStableFunc(edits, func(a, b yamlEdit) int { return b.offset - a.offset })
+	out := slices.Clone(src)
+	for _, e := range edits {
+		out = slices.Concat(out[:e.offset], []byte(e.insert), out[e.offset+e.remove:])
+	}
+	return out, nil
+}
+
+// walkYAMLPath calls fn with every mapping whose key is the last path segment
+func walkYAMLPath(node *yaml.Node, path []yamlPathSegment, fn func(mapping *yaml.Node, key string) error) error {
+	for node.Kind == yaml.DocumentNode || node.Kind == yaml.AliasNode {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		} else if len(node.Content) == 0 {
+			return nil
+		} else {
+			node = node.Content[0]
+		}
+	}
+
+	segment := path[0]
+	switch {
+	case segment.isKey():
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		if len(path) == 1 {
+			return fn(node, segment.key)
+		}
+		if value := yamlMappingValue(node, segment.key); value != nil {
+			return walkYAMLPath(value, path[1:], fn)
+		}
+	case node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			if segment.all || i == segment.index {
+				if err := walkYAMLPath(item, path[1:], fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// yamlMappingValue returns the value of key in a mapping, or nil
+func yamlMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// editFor builds the edit that sets key in mapping to a new UUID. ok is false
+// when --only-empty leaves an existing value alone.
+func (s *yamlSource) editFor(mapping *yaml.Node, key string, onlyEmpty bool, next func() string) (yamlEdit, bool, error) {
+	if mapping.Style&yaml.FlowStyle != 0 {
+		return yamlEdit{}, false, fmt.Errorf("line %d: cannot add '%s' to a flow mapping", mapping.Line, key)
+	}
+
+	value := yamlMappingValue(mapping, key)
+	if value == nil {
+		return s.insertKey(mapping, key, next()), true, nil
+	}
+	if value.Kind != yaml.ScalarNode {
+		return yamlEdit{}, false, fmt.Errorf("line %d: '%s' is not a scalar", value.Line, key)
+	}
+	empty := value.Tag == "!!null" || value.Value == ""
+	if onlyEmpty && !empty {
+		return yamlEdit{}, false, nil
+	}
+
+	offset := s.offset(value.Line, value.Column)
+	switch {
+	case value.Tag == "!!null" && value.Value == "":
+		// "key:" with nothing after the colon
+		return yamlEdit{offset: offset, insert: " " + next()}, true, nil
+	case value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		return yamlEdit{}, false, fmt.Errorf("line %d: cannot replace the block scalar in '%s'", value.Line, key)
+	case value.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0:
+		quote := s.src[offset]
+		end := quotedScalarEnd(s.src, offset)
+		if end < 0 {
+			return yamlEdit{}, false, fmt.Errorf("line %d: cannot find the end of the quoted value of '%s'", value.Line, key)
+		}
+		return yamlEdit{offset: offset, remove: end - offset, insert: string(quote) + next() + string(quote)}, true, nil
+	}
+	if !bytes.HasPrefix(s.src[offset:], []byte(value.Value)) {
+		return yamlEdit{}, false, fmt.Errorf("line %d: cannot locate the value of '%s'", value.Line, key)
+	}
+	return yamlEdit{offset: offset, remove: len(value.Value), insert: next()}, true, nil
+}
+
+// insertKey adds "key: id" as the last entry of a block mapping, after any
+// nested content but before trailing blank lines
+func (s *yamlSource) insertKey(mapping *yaml.Node, key, id string) yamlEdit {
+	column := mapping.Content[0].Column
+	last := mapping.Content[len(mapping.Content)-2].Line
+	for n := last + 1; n <= len(s.lineStarts); n++ {
+		text := s.line(n)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		// Deeper lines belong to the last value, as does a sequence written at
+		// the same indentation as its key
+		trimmed := strings.TrimLeft(text, " ")
+		indent := len(text) - len(trimmed)
+		if indent < column-1 || (indent == column-1 && !strings.HasPrefix(trimmed, "-")) {
+			break
+		}
+		last = n
+	}
+
+	entry := strings.Repeat(" ", column-1) + key + ": " + id + s.eol
+	if last == len(s.lineStarts) {
+		// The mapping runs to the end of the input
+		if len(s.src) > 0 && s.src[len(s.src)-1] != '\n' {
+			entry = s.eol + entry
+		}
+		return yamlEdit{offset: len(s.src), insert: entry}
+	}
+	return yamlEdit{offset: s.lineStarts[last], insert: entry}
+}
+
+// quotedScalarEnd returns the offset just past the closing quote of the
+// quoted scalar starting at start, or -1
+func quotedScalarEnd(src []byte, start int) int {
+	quote := src[start]
+	for i := start + 1; i < len(src); i++ {
+		switch {
+		case quote == '"' && src[i] == '\\':
+			i++
+		case src[i] == quote && quote == '\'' && i+1 < len(src) && src[i+1] == '\'':
+			i++
+		case src[i] == quote:
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func init() {
+	yamlCmd.Flags().String("path", "", "Locations to set, such as 'items[*].metadata.uid'")
+	yamlCmd.Flags().String("input", "", "YAML file to read (default stdin)")
+	yamlCmd.Flags().Bool("only-empty", false, "Only set keys that are missing, null, or empty")
+	yamlCmd.Flags().BoolP("in-place", "i", false, "Write the result back to --input")
+	yamlCmd.Flags().Bool("backup", false, "With -i, copy the original to <input>.bak first")
+	yamlCmd.MarkFlagRequired("path")
+	rootCmd.AddCommand(yamlCmd)
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// yamlFixture is a commented multi-document stream. Lines marked "# set"
+// are the only ones the path should change; "# add" marks where new keys go.
+const yamlFixture = `# Deployment list
+apiVersion: v1
+kind: List
+items:
+  # first item keeps its comments
+  - kind: ConfigMap
+    metadata:
+      name: first   # trailing comment
+      uid: "old-uid" # set
+
+  - kind: Secret
+    metadata: &shared
+      name: second
+      labels:
+        app: demo
+      # add
+
+  - kind: Alias
+    metadata: *shared
+---
+items:
+  - metadata:
+      uid: ~ # set
+      tags:
+      - a
+      - b
+  - metadata:
+      uid: # set
+      name: 'third'
+  - metadata:
+      uid: kept-value # set
+  - other: no metadata here
+`
+
+// changedLines compares before and after line by line, returning the lines
+// that were rewritten ("old => new") and the lines that were inserted
+func changedLines(t *testing.T, before, after string) (changed []string, added []string) {
+	t.Helper()
+	b, a := strings.Split(before, "\n"), strings.Split(after, "\n")
+	j := 0
+	for i := 0; i < len(b); i++ {
+		for j < len(a) && a[j] != b[i] && (i+1 >= len(b) || a[j] != b[i+1]) && !strings.Contains(b[i], "# set") {
+			added = append(added, a[j])
+			j++
+		}
+		if j >= len(a) {
+			t.Fatalf("Line %d %q missing from output:\n%s", i+1, b[i], after)
+		}
+		if a[j] != b[i] {
+			changed = append(changed, b[i]+" => "+a[j])
+		}
+		j++
+	}
+	added = append(added, a[j:]...)
+	return changed, added
+}
+
+var uuidV4 = `[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}`
+
+func TestYAMLSetsOnlyIntendedLines(t *testing.T) {
+	output, err := executeCommand(t, yamlFixture, "yaml", "--path", "items[*].metadata.uid")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	changed, added := changedLines(t, yamlFixture, output)
+	expectedChanged := []*regexp.Regexp{
+		regexp.MustCompile(`^      uid: "old-uid" # set =>       uid: "` + uuidV4 + `" # set$`),
+		regexp.MustCompile(`^      uid: ~ # set =>       uid: ` + uuidV4 + ` # set$`),
+		regexp.MustCompile(`^      uid: # set =>       uid: ` + uuidV4 + ` # set$`),
+		regexp.MustCompile(`^      uid: kept-value # set =>       uid: ` + uuidV4 + ` # set$`),
+	}
+	if len(changed) != len(expectedChanged) {
+		t.Fatalf("Expected %d changed lines, got %q\n%s", len(expectedChanged), changed, output)
+	}
+	for i, re := range expectedChanged {
+		if !re.MatchString(changed[i]) {
+			t.Errorf("Unexpected change %q", changed[i])
+		}
+	}
+	// The anchored mapping gets one key, even though an alias reaches it too
+	if len(added) != 1 || !regexp.MustCompile(`^      uid: `+uuidV4+`$`).MatchString(added[0]) {
+		t.Fatalf("Expected one added uid line, got %q", added)
+	}
+	if !strings.Contains(output, "        app: demo\n      uid: ") {
+		t.Errorf("Expected the new key after the nested labels, got:\n%s", output)
+	}
+}
+
+func TestYAMLOnlyEmpty(t *testing.T) {
+	output, err := executeCommand(t, yamlFixture, "yaml", "--path", "items[*].metadata.uid", "--only-empty")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, kept := range []string{`uid: "old-uid" # set`, "uid: kept-value # set"} {
+		if !strings.Contains(output, kept) {
+			t.Errorf("Expected %q to be kept", kept)
+		}
+	}
+	if strings.Contains(output, "uid: ~") || strings.Contains(output, "uid: # set") {
+		t.Errorf("Expected empty values to be filled:\n%s", output)
+	}
+
+	// A second pass changes nothing
+	again, err := executeCommand(t, output, "yaml", "--path", "items[*].metadata.uid", "--only-empty")
+	if err != nil || again != output {
+		t.Errorf("Expected --only-empty to be idempotent, got %v:\n%s", err, again)
+	}
+}
+
+func TestYAMLIndexAndLineEndings(t *testing.T) {
+	input := "servers:\r\n  - name: a\r\n  - name: b\r\n"
+	output, err := executeCommand(t, input, "yaml", "--path", "servers[1].id")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(`^servers:\r\n  - name: a\r\n  - name: b\r\n    id: ` + uuidV4 + `\r\n$`).MatchString(output) {
+		t.Errorf("Unexpected output %q", output)
+	}
+
+	// No trailing newline at the end of the input
+	output, err = executeCommand(t, "id: x\nname: y", "yaml", "--path", "uid")
+	if err != nil || !regexp.MustCompile(`^id: x\nname: y\nuid: `+uuidV4+`\n$`).MatchString(output) {
+		t.Errorf("Unexpected output %q, %v", output, err)
+	}
+}
+
+func TestYAMLInPlace(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "inventory.yaml")
+	original := "all:\n  hosts:\n    - name: web1\n"
+	os.WriteFile(path, []byte(original), 0o640)
+
+	output, err := executeCommand(t, "", "yaml", "--path", "all.hosts[*].id", "--input", path, "-i", "--backup")
+	if err != nil || output != "" {
+		t.Fatalf("Expected no output, got %q, %v", output, err)
+	}
+	updated, _ := os.ReadFile(path)
+	if !regexp.MustCompile(`^all:\n  hosts:\n    - name: web1\n      id: ` + uuidV4 + `\n$`).Match(updated) {
+		t.Errorf("Unexpected file %q", updated)
+	}
+	backup, _ := os.ReadFile(path + ".bak")
+	if string(backup) != original {
+		t.Errorf("Expected the original in the backup, got %q", backup)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+		t.Errorf("Expected the file mode to be kept, got %v", info.Mode())
+	}
+}
+
+func TestYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		contains string
+	}{
+		{"missing path", []string{"yaml"}, "", `required flag(s) "path" not set`},
+		{"path ends in index", []string{"yaml", "--path", "items[*]"}, "", "the last part must name a key"},
+		{"bad path", []string{"yaml", "--path", "items[x].id"}, "", "invalid --path 'items[x].id'"},
+		{"empty part", []string{"yaml", "--path", "a..id"}, "", "invalid --path 'a..id'"},
+		{"in place without input", []string{"yaml", "--path", "id", "-i"}, "", "-i requires --input"},
+		{"backup without in place", []string{"yaml", "--path", "id", "--input", "x.yaml", "--backup"}, "", "--backup requires -i"},
+		{"invalid yaml", []string{"yaml", "--path", "id"}, "a: [1, 2\n", "invalid YAML"},
+		{"flow mapping", []string{"yaml", "--path", "meta.id"}, "meta: {name: a}\n", "line 1: cannot add 'id' to a flow mapping"},
+		{"block scalar", []string{"yaml", "--path", "id"}, "id: |\n  text\n", "cannot replace the block scalar in 'id'"},
+		{"not a scalar", []string{"yaml", "--path", "id"}, "id:\n  nested: true\n", "line 2: 'id' is not a scalar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, tt.stdin, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
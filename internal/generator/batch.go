@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// ContextGenerator produces one UUID, returning ctx.Err() once ctx is done.
+// GenerateUUIDv4Context, GenerateUUIDv7Context, and the GenerateContext
+// methods of the monotonic generators all fit.
+type ContextGenerator func(ctx context.Context) (string, error)
+
+// GenerateBatch returns n UUIDs from generate. If ctx is done or generate
+// fails part way, it returns the UUIDs produced so far along with the error.
+func GenerateBatch(ctx context.Context, n int, generate ContextGenerator) ([]string, error) {
+	uuids := make([]string, 0, n)
+	for range n {
+		if err := ctx.Err(); err != nil {
+			return uuids, err
+		}
+		uuid, err := generate(ctx)
+		if err != nil {
+			return uuids, err
+		}
+		uuids = append(uuids, uuid)
+	}
+	return uuids, nil
+}
+
+// WriteUUIDs writes n UUIDs from generate to w, one per line, stopping with
+// the error when ctx is done, generate fails, or w fails. Lines already
+// written stay written.
+func WriteUUIDs(ctx context.Context, w io.Writer, n int, generate ContextGenerator) error {
+	for range n {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		uuid, err := generate(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, uuid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
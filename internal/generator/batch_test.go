@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateBatch(t *testing.T) {
+	uuids, err := GenerateBatch(context.Background(), 5, GenerateUUIDv7Context)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(uuids) != 5 {
+		t.Fatalf("Expected 5 UUIDs, got %d", len(uuids))
+	}
+	for _, uuid := range uuids {
+		if Validate(uuid) != nil || uuid[14] != '7' {
+			t.Errorf("Expected a UUIDv7, got %s", uuid)
+		}
+	}
+}
+
+func TestGenerateBatchCancelledMidway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	generate := func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return GenerateUUIDv4Context(ctx)
+	}
+
+	uuids, err := GenerateBatch(ctx, 10, generate)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if len(uuids) != 2 || calls != 3 {
+		t.Errorf("Expected the 2 UUIDs made before cancelling, got %d after %d calls", len(uuids), calls)
+	}
+}
+
+func TestWriteUUIDs(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteUUIDs(context.Background(), &out, 3, new(MonotonicV7).GenerateContext); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 || !(lines[0] < lines[1] && lines[1] < lines[2]) {
+		t.Errorf("Expected 3 increasing UUIDs, got %q", out.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out.Reset()
+	if err := WriteUUIDs(ctx, &out, 3, GenerateUUIDv4Context); !errors.Is(err, context.Canceled) || out.Len() != 0 {
+		t.Errorf("Expected nothing written for a cancelled context, got %q, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,21 @@
+package generator
+
+import "time"
+
+// UUIDv7Min returns the smallest UUIDv7 with the timestamp's millisecond:
+// every bit after the timestamp is zero except the version and variant.
+// Together with UUIDv7Max it bounds a range query on a UUIDv7 key, since
+// UUIDv7s sort by timestamp first.
+func UUIDv7Min(timestamp time.Time) string {
+	return NewV7FromBytes(timestamp, [16]byte{})
+}
+
+// UUIDv7Max returns the largest UUIDv7 with the timestamp's millisecond:
+// every bit after the timestamp is one except the version and variant
+func UUIDv7Max(timestamp time.Time) string {
+	var ones [16]byte
+	for i := range ones {
+		ones[i] = 0xff
+	}
+	return NewV7FromBytes(timestamp, ones)
+}
@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUUIDv7Bounds(t *testing.T) {
+	ts := time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC)
+	if got, want := UUIDv7Min(ts), "0188b733-b800-7000-8000-000000000000"; got != want {
+		t.Errorf("UUIDv7Min = %s, want %s", got, want)
+	}
+	if got, want := UUIDv7Max(ts), "0188b733-b800-7fff-bfff-ffffffffffff"; got != want {
+		t.Errorf("UUIDv7Max = %s, want %s", got, want)
+	}
+
+	// Sub-millisecond parts of the timestamp do not widen the bounds
+	if got := UUIDv7Max(ts.Add(999 * time.Microsecond)); got != UUIDv7Max(ts) {
+		t.Errorf("Expected the bounds of the same millisecond, got %s", got)
+	}
+
+	for _, bound := range []string{UUIDv7Min(ts), UUIDv7Max(ts)} {
+		details := Inspect(mustParse(t, bound))
+		if details.Version != 7 || details.Variant != VariantRFC || !details.Timestamp.Equal(ts) {
+			t.Errorf("Expected %s to be a UUIDv7 at %s, got %+v", bound, ts, details)
+		}
+	}
+}
+
+func TestUUIDv7BoundsContainGenerated(t *testing.T) {
+	ts := time.Date(2023, 6, 14, 10, 30, 45, 123_000_000, time.UTC)
+	low, high := UUIDv7Min(ts), UUIDv7Max(ts)
+	for i := 0; i < 1000; i++ {
+		uuid := mustV7At(t, ts)
+		if uuid < low || uuid > high {
+			t.Fatalf("Expected %s to sort between %s and %s", uuid, low, high)
+		}
+	}
+
+	// The neighbouring milliseconds fall outside
+	if before := UUIDv7Max(ts.Add(-time.Millisecond)); before >= low {
+		t.Errorf("Expected %s to sort before %s", before, low)
+	}
+	if after := UUIDv7Min(ts.Add(time.Millisecond)); after <= high {
+		t.Errorf("Expected %s to sort after %s", after, high)
+	}
+}
@@ -0,0 +1,33 @@
+package generator
+
+import "hash/fnv"
+
+// The bucket algorithms below are frozen: changing them would move keys
+// between shards for every user, so the golden tests must never be updated.
+
+// keyHash returns the 64-bit FNV-1a hash of the 16 raw UUID bytes
+func keyHash(uuid [16]byte) uint64 {
+	h := fnv.New64a()
+	h.Write(uuid[:])
+	return h.Sum64()
+}
+
+// Bucket assigns a UUID to one of n shards as FNV-1a-64(raw bytes) mod n.
+// Changing n reassigns most keys; use JumpBucket when n may change.
+func Bucket(uuid [16]byte, n int) int {
+	return int(keyHash(uuid) % uint64(n))
+}
+
+// JumpBucket assigns a UUID to one of n shards using Lamping and Veach's jump
+// consistent hash over the FNV-1a-64 hash of the raw bytes. Growing from n to
+// n+1 shards moves only about 1/(n+1) of the keys.
+func JumpBucket(uuid [16]byte, n int) int {
+	key := keyHash(uuid)
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
@@ -0,0 +1,81 @@
+package generator
+
+import "testing"
+
+// bucketGolden pins UUID to bucket assignments. These values must never
+// change: doing so would silently move keys between shards for every user.
+var bucketGolden = []struct {
+	uuid       string
+	n          int
+	bucket     int
+	consistent int
+}{
+	{"0188b733-b800-7079-9ce7-7022b2ba0185", 16, 12, 6},
+	{"919108f7-52d1-4320-9bac-f847db4148a8", 16, 6, 13},
+	{"c232ab00-9414-11ec-b3c8-9f6bdeced846", 16, 4, 15},
+	{"017f22e2-79b0-7cc3-98c4-dc0c0c07398f", 16, 4, 0},
+	{"00000000-0000-0000-0000-000000000000", 16, 5, 15},
+	{"0188b733-b800-7079-9ce7-7022b2ba0185", 1000, -1, 447},
+	{"919108f7-52d1-4320-9bac-f847db4148a8", 1000, -1, 829},
+	{"017f22e2-79b0-7cc3-98c4-dc0c0c07398f", 1000, -1, 919},
+}
+
+func TestBucketGolden(t *testing.T) {
+	for _, tt := range bucketGolden {
+		uuid, err := ParseUUID(tt.uuid)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", tt.uuid, err)
+		}
+		if tt.bucket >= 0 {
+			if got := Bucket(uuid, tt.n); got != tt.bucket {
+				t.Errorf("Bucket(%s, %d) = %d, expected %d", tt.uuid, tt.n, got, tt.bucket)
+			}
+		}
+		if got := JumpBucket(uuid, tt.n); got != tt.consistent {
+			t.Errorf("JumpBucket(%s, %d) = %d, expected %d", tt.uuid, tt.n, got, tt.consistent)
+		}
+	}
+}
+
+func TestJumpBucketMinimalMovement(t *testing.T) {
+	const keys = 10000
+	moved := 0
+	for i := 0; i < keys; i++ {
+		uuid, err := ParseUUID(GenerateUUIDv4())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		before := JumpBucket(uuid, 10)
+		after := JumpBucket(uuid, 11)
+		if before != after {
+			moved++
+			// Keys may only move into the new bucket
+			if after != 10 {
+				t.Fatalf("Key moved from bucket %d to existing bucket %d", before, after)
+			}
+		}
+	}
+
+	// Expect about 1/11 of keys to move
+	if moved < keys/11/2 || moved > keys/11*2 {
+		t.Errorf("Expected about %d keys to move, got %d", keys/11, moved)
+	}
+}
+
+func TestBucketRange(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		uuid, err := ParseUUID(GenerateUUIDv7())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range []int{1, 2, 7, 16} {
+			if b := Bucket(uuid, n); b < 0 || b >= n {
+				t.Fatalf("Bucket %d out of range for n=%d", b, n)
+			}
+			if b := JumpBucket(uuid, n); b < 0 || b >= n {
+				t.Fatalf("JumpBucket %d out of range for n=%d", b, n)
+			}
+		}
+	}
+}
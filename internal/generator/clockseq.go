@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"sync"
+	"time"
+)
+
+// ClockSequence is the state RFC 9562 section 6.1 asks UUIDv1 and UUIDv6
+// generators to keep: the last timestamp used and the 14-bit clock sequence.
+// The sequence is incremented whenever the clock has not advanced past the
+// previous UUID, whether it went backwards (an NTP step, a resumed VM) or
+// two UUIDs fell in the same 100ns tick, so UUIDs from one node never
+// repeat. Once all 16384 sequences have been used within one tick, the
+// timestamp moves on to the next tick rather than the sequence repeating.
+// The zero value seeds the sequence at random on first use. A
+// ClockSequence is safe for concurrent use.
+type ClockSequence struct {
+	mu     sync.Mutex
+	seeded bool
+	// lastTicks is the timestamp of the last UUID, and lastClock the clock
+	// reading it was generated at; lastTicks is ahead of lastClock once a
+	// tick has run out of sequences
+	lastTicks uint64
+	lastClock uint64
+	seq       uint16
+	// tickSeq is the sequence lastTicks started at, so a wrap back to it
+	// means the tick is used up
+	tickSeq uint16
+}
+
+// RestoreClockSequence returns a ClockSequence continuing from state saved
+// with State, so the sequence survives restarts: a clock that went
+// backwards while the process was not running is still detected
+func RestoreClockSequence(seq uint16, last time.Time) *ClockSequence {
+	ticks := gregorianTicks60(last)
+	return &ClockSequence{seeded: true, lastTicks: ticks, lastClock: ticks, seq: seq & 0x3fff, tickSeq: seq & 0x3fff}
+}
+
+// State returns the clock sequence and the timestamp of the last UUID, for
+// RestoreClockSequence. ok is false until the first UUID is generated.
+func (c *ClockSequence) State() (seq uint16, last time.Time, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.seeded {
+		return 0, time.Time{}, false
+	}
+	return c.seq, gregorianTime(c.lastTicks), true
+}
+
+// next records the clock reading ticks and returns the timestamp and clock
+// sequence to use with it, seeding the sequence from random on first use.
+// The timestamp is ticks unless the clock has not advanced past the last
+// UUID, when it stays at the last UUID's timestamp, or the tick after it
+// once every sequence has been used there.
+func (c *ClockSequence) next(ticks uint64, random uint16) (uint64, uint16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clock := ticks
+	switch {
+	case !c.seeded:
+		c.seeded, c.seq = true, random&0x3fff
+		c.tickSeq = c.seq
+	case ticks > c.lastTicks:
+		c.tickSeq = c.seq
+	case ticks < c.lastClock:
+		// The clock went backwards
+		c.seq = (c.seq + 1) & 0x3fff
+		c.tickSeq = c.seq
+	default:
+		// The same tick again, or one a used-up tick was moved past
+		c.seq = (c.seq + 1) & 0x3fff
+		ticks = c.lastTicks
+		if c.seq == c.tickSeq {
+			ticks++
+		}
+	}
+	c.lastTicks, c.lastClock = ticks, clock
+	return ticks, c.seq
+}
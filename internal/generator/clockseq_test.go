@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClockSequenceWraps(t *testing.T) {
+	c := &ClockSequence{seeded: true, lastTicks: 100, lastClock: 100, seq: 0x3fff}
+	if _, got := c.next(50, 0); got != 0 {
+		t.Errorf("Expected the 14-bit clock sequence to wrap to 0, got %d", got)
+	}
+}
+
+func TestClockSequenceTickUsedUp(t *testing.T) {
+	c := new(ClockSequence)
+	ticks, first := c.next(100, 5)
+	for range 0x3fff {
+		if got, _ := c.next(100, 0); got != ticks {
+			t.Fatalf("Expected tick %d while sequences remain, got %d", ticks, got)
+		}
+	}
+	got, seq := c.next(100, 0)
+	if got != ticks+1 || seq != first {
+		t.Errorf("Expected tick %d with sequence %d once the tick was used up, got %d, %d", ticks+1, first, got, seq)
+	}
+	// The clock catching up to the moved-on tick continues from it
+	if got, seq := c.next(101, 0); got != ticks+1 || seq != first+1 {
+		t.Errorf("Expected tick %d with sequence %d, got %d, %d", ticks+1, first+1, got, seq)
+	}
+}
+
+func TestClockSequenceNoDuplicates(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+	t.Cleanup(func() { SetClock(nil) })
+	node := [6]byte{0x02, 0x00, 0x5e, 0x10, 0x00, 0x01}
+
+	// More UUIDs than there are clock sequences, all at one clock reading
+	const count = 20000
+	g := &Generator{Node: &node, ClockSequence: new(ClockSequence)}
+	generators := map[string]func() string{
+		"v1": func() string { return GenerateUUIDv1WithNode(node) },
+		"v6": func() string {
+			uuid, err := g.NewV6(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return uuid
+		},
+	}
+	for name, generate := range generators {
+		t.Run(name, func(t *testing.T) {
+			seen := make(map[string]bool, count)
+			for range count {
+				uuid := generate()
+				if seen[uuid] {
+					t.Fatalf("Duplicate UUID %s", uuid)
+				}
+				seen[uuid] = true
+			}
+		})
+	}
+}
+
+func TestGeneratorClockSequence(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	node := [6]byte{0x02, 0x00, 0x5e, 0x10, 0x00, 0x01}
+	g := &Generator{Clock: func() time.Time { return now }, Node: &node, ClockSequence: new(ClockSequence)}
+	clockSeq := func() int {
+		t.Helper()
+		uuid, err := g.NewV6(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *Inspect(mustParse(t, uuid)).ClockSeq
+	}
+
+	first := clockSeq()
+	now = now.Add(time.Second)
+	if got := clockSeq(); got != first {
+		t.Errorf("Expected the clock sequence to hold while the clock advances, got %d then %d", first, got)
+	}
+
+	// An NTP step backwards, then the same tick again
+	now = now.Add(-time.Minute)
+	if got := clockSeq(); got != (first+1)&0x3fff {
+		t.Errorf("Expected the clock sequence to increment when the clock goes backwards, got %d then %d", first, got)
+	}
+	if got := clockSeq(); got != (first+2)&0x3fff {
+		t.Errorf("Expected the clock sequence to increment within one tick, got %d", got)
+	}
+}
+
+func TestRestoreClockSequence(t *testing.T) {
+	c := new(ClockSequence)
+	if _, _, ok := c.State(); ok {
+		t.Error("Expected no state before the first UUID")
+	}
+
+	last := time.Date(2024, 3, 1, 10, 0, 0, 123_456_700, time.UTC)
+	g := &Generator{Clock: func() time.Time { return last }, ClockSequence: c}
+	if _, err := g.NewV6(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	seq, saved, ok := c.State()
+	if !ok || !saved.Equal(last) {
+		t.Fatalf("Expected the state to record %s, got %s, %v", last, saved, ok)
+	}
+
+	// A new process whose clock is behind the saved timestamp increments
+	// the restored sequence; one whose clock is ahead keeps it
+	tests := []struct {
+		name string
+		now  time.Time
+		want uint16
+	}{
+		{"clock went backwards", last.Add(-time.Hour), (seq + 1) & 0x3fff},
+		{"same tick", last, (seq + 1) & 0x3fff},
+		{"clock advanced", last.Add(time.Hour), seq},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{Clock: func() time.Time { return tt.now }, ClockSequence: RestoreClockSequence(seq, saved)}
+			uuid, err := g.NewV6(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := *Inspect(mustParse(t, uuid)).ClockSeq; got != int(tt.want) {
+				t.Errorf("Expected clock sequence %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
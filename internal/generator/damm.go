@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dammHex is a weak totally anti-symmetric quasigroup of order 16 for the
+// Damm algorithm over hex digits, built as x∘y = 2x ⊕ y in GF(16) with the
+// reduction polynomial x⁴+x+1. Every row and column is a permutation, so any
+// single wrong digit changes the result, and (c∘x)∘y = (c∘y)∘x only when
+// x = y, so swapping two adjacent different digits does too.
+var dammHex = [][]byte{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{2, 3, 0, 1, 6, 7, 4, 5, 10, 11, 8, 9, 14, 15, 12, 13},
+	{4, 5, 6, 7, 0, 1, 2, 3, 12, 13, 14, 15, 8, 9, 10, 11},
+	{6, 7, 4, 5, 2, 3, 0, 1, 14, 15, 12, 13, 10, 11, 8, 9},
+	{8, 9, 10, 11, 12, 13, 14, 15, 0, 1, 2, 3, 4, 5, 6, 7},
+	{10, 11, 8, 9, 14, 15, 12, 13, 2, 3, 0, 1, 6, 7, 4, 5},
+	{12, 13, 14, 15, 8, 9, 10, 11, 4, 5, 6, 7, 0, 1, 2, 3},
+	{14, 15, 12, 13, 10, 11, 8, 9, 6, 7, 4, 5, 2, 3, 0, 1},
+	{3, 2, 1, 0, 7, 6, 5, 4, 11, 10, 9, 8, 15, 14, 13, 12},
+	{1, 0, 3, 2, 5, 4, 7, 6, 9, 8, 11, 10, 13, 12, 15, 14},
+	{7, 6, 5, 4, 3, 2, 1, 0, 15, 14, 13, 12, 11, 10, 9, 8},
+	{5, 4, 7, 6, 1, 0, 3, 2, 13, 12, 15, 14, 9, 8, 11, 10},
+	{11, 10, 9, 8, 15, 14, 13, 12, 3, 2, 1, 0, 7, 6, 5, 4},
+	{9, 8, 11, 10, 13, 12, 15, 14, 1, 0, 3, 2, 5, 4, 7, 6},
+	{15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+	{13, 12, 15, 14, 9, 8, 11, 10, 5, 4, 7, 6, 1, 0, 3, 2},
+}
+
+// dammSeparator sits between a UUID and its check digit
+const dammSeparator = "~"
+
+// damm runs the Damm algorithm over digits, returning the interim digit. A
+// sequence that ends with its check digit yields zero.
+func damm(table [][]byte, digits []byte) byte {
+	var interim byte
+	for _, d := range digits {
+		interim = table[interim][d]
+	}
+	return interim
+}
+
+// dammCheckDigit returns the check digit for a UUID's 32 hex digits: the digit
+// that brings the interim back to zero
+func dammCheckDigit(uuid [16]byte) byte {
+	interim := damm(dammHex, uuidNibbles(uuid))
+	for d := byte(0); d < 16; d++ {
+		if dammHex[interim][d] == 0 {
+			return d
+		}
+	}
+	panic("dammHex is not a quasigroup")
+}
+
+func uuidNibbles(uuid [16]byte) []byte {
+	nibbles := make([]byte, 0, 32)
+	for _, b := range uuid {
+		nibbles = append(nibbles, b>>4, b&0x0f)
+	}
+	return nibbles
+}
+
+func encodeDamm(uuid [16]byte) string {
+	return FormatUUID(uuid) + dammSeparator + fmt.Sprintf("%x", dammCheckDigit(uuid))
+}
+
+// decodeDamm parses "<uuid>~<digit>" and verifies the check digit. The UUID
+// part may be in any form ParseUUID accepts.
+func decodeDamm(value string) ([16]byte, error) {
+	var uuid [16]byte
+	i := strings.LastIndex(value, dammSeparator)
+	if i < 0 {
+		return uuid, classify(ErrInvalidFormat, "missing '%s' before the check digit", dammSeparator)
+	}
+	digit := strings.ToLower(value[i+1:])
+	if len(digit) != 1 || !isHexDigit(digit[0]) {
+		return uuid, classify(ErrInvalidFormat, "invalid check digit '%s', expected one hex digit", value[i+1:])
+	}
+	uuid, err := ParseUUID(value[:i])
+	if err != nil {
+		return uuid, err
+	}
+	if want := fmt.Sprintf("%x", dammCheckDigit(uuid)); want != digit {
+		return uuid, classify(ErrInvalidFormat, "check digit mismatch: expected '%s', got '%s'", want, digit)
+	}
+	return uuid, nil
+}
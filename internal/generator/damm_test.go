@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// dammDecimal is the order-10 table published with the Damm algorithm, used to
+// check the algorithm itself against the published example
+var dammDecimal = [][]byte{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+func TestDammPublishedExample(t *testing.T) {
+	// The worked example: the check digit of 572 is 4, and 5724 validates
+	if got := damm(dammDecimal, []byte{5, 7, 2}); got != 4 {
+		t.Errorf("Expected interim digit 4 for 572, got %d", got)
+	}
+	if got := damm(dammDecimal, []byte{5, 7, 2, 4}); got != 0 {
+		t.Errorf("Expected 5724 to validate, got %d", got)
+	}
+	if got := damm(dammDecimal, []byte{5, 2, 7, 4}); got == 0 {
+		t.Error("Expected the transposition 5274 to be detected")
+	}
+}
+
+func TestDammTableProperties(t *testing.T) {
+	for name, table := range map[string][][]byte{"decimal": dammDecimal, "hex": dammHex} {
+		n := byte(len(table))
+		for i := byte(0); i < n; i++ {
+			var row, col [16]bool
+			for j := byte(0); j < n; j++ {
+				if row[table[i][j]] || col[table[j][i]] {
+					t.Fatalf("%s: row or column %d is not a permutation", name, i)
+				}
+				row[table[i][j]], col[table[j][i]] = true, true
+			}
+		}
+		for c := byte(0); c < n; c++ {
+			for x := byte(0); x < n; x++ {
+				for y := byte(0); y < n; y++ {
+					if x != y && table[table[c][x]][y] == table[table[c][y]][x] {
+						t.Fatalf("%s: not weak totally anti-symmetric at c=%d x=%d y=%d", name, c, x, y)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDammDetectsSingleErrorsAndTranspositions(t *testing.T) {
+	const hexDigits = "0123456789abcdef"
+	for i := 0; i < 200; i++ {
+		uuid, _ := ParseUUID(GenerateUUIDv4())
+		valid := strings.ReplaceAll(encodeDamm(uuid), "-", "")
+		if _, err := decodeDamm(valid); err != nil {
+			t.Fatalf("%s should validate: %v", valid, err)
+		}
+
+		// Positions 0-31 are the UUID and 33 is the check digit; 32 is '~'
+		for pos := 0; pos < len(valid); pos++ {
+			if pos == 32 {
+				continue
+			}
+			for _, c := range hexDigits {
+				if byte(c) == valid[pos] {
+					continue
+				}
+				mutated := valid[:pos] + string(c) + valid[pos+1:]
+				if _, err := decodeDamm(mutated); err == nil {
+					t.Fatalf("Single-character error %s -> %s was not detected", valid, mutated)
+				}
+			}
+		}
+
+		digits := valid[:32] + valid[33:]
+		for pos := 0; pos < len(digits)-1; pos++ {
+			if digits[pos] == digits[pos+1] {
+				continue
+			}
+			swapped := digits[:pos] + string(digits[pos+1]) + string(digits[pos]) + digits[pos+2:]
+			if _, err := decodeDamm(swapped[:32] + "~" + swapped[32:]); err == nil {
+				t.Fatalf("Transposition at %d of %s was not detected", pos, valid)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Generator holds the settings behind UUID generation, so code that needs
+// different behavior can pass one explicitly instead of changing the package
+// globals. The zero value behaves exactly like the package-level functions:
+// it reads the entropy source from SetEntropySource and the clock from
+// SetClock. A Generator is safe for concurrent use, but its fields must not
+// change once it is in use.
+type Generator struct {
+	// Entropy supplies random bytes in place of the package entropy source.
+	// Failed reads are retried as configured with SetEntropyRetry.
+	Entropy io.Reader
+
+	// Clock supplies the current time in place of the package clock
+	Clock func() time.Time
+
+	// Node is the node NewV6 writes into every UUID, such as a MAC address
+	// or ProcessNode, so UUIDs identify their source. When nil, each UUIDv6
+	// gets a fresh random node with the multicast bit set.
+	Node *[6]byte
+
+	// ClockSequence, when set, gives NewV6 a clock sequence kept as RFC 9562
+	// section 6.1 describes instead of a random one per UUID, so UUIDs stay
+	// unique when the clock goes backwards even with a fixed Node
+	ClockSequence *ClockSequence
+
+	// Monotonic makes NewV7 draw from a MonotonicV7, so the UUIDv7s of this
+	// Generator strictly increase across every caller
+	Monotonic bool
+
+	monotonic MonotonicV7
+}
+
+// zeroGenerator is the default until SetDefault is called
+var zeroGenerator = new(Generator)
+
+// defaultGenerator is the Generator set with SetDefault, or nil
+var defaultGenerator atomic.Pointer[Generator]
+
+// Default returns the Generator used by GenerateUUIDv4, GenerateUUIDv6,
+// GenerateUUIDv7, and their Context variants
+func Default() *Generator {
+	if g := defaultGenerator.Load(); g != nil {
+		return g
+	}
+	return zeroGenerator
+}
+
+// SetDefault makes g the Generator behind the package-level functions for
+// the whole process; passing nil restores the zero Generator. The swap is
+// atomic, so it is safe while other goroutines generate: each call uses
+// either the old Generator or the new one, never a mix.
+//
+// This is meant for settings that genuinely apply everywhere, such as a
+// certified entropy source in a FIPS build, set once during start-up. It
+// affects every package in the program that generates through this one,
+// including code that never asked for it, so libraries should accept a
+// *Generator from their caller rather than call SetDefault, and tests that
+// need a fixed clock or entropy should pass their own Generator.
+func SetDefault(g *Generator) {
+	defaultGenerator.Store(g)
+}
+
+// NewV4 returns a random UUID (version 4). It returns ctx.Err() once ctx
+// is done, and entropy failures as errors.
+func (g *Generator) NewV4(ctx context.Context) (string, error) {
+	start := time.Now()
+	random, err := g.randomBytes(ctx)
+	if err != nil {
+		return "", err
+	}
+	uuid := NewV4FromBytes(random)
+	debug("generated UUID", "version", 4, "uuid", uuid, "duration", time.Since(start))
+	return uuid, nil
+}
+
+// NewV6 returns a time-ordered UUID (version 6) with a random clock
+// sequence unless ClockSequence is set, and a random node unless Node is
+// set. It returns ctx.Err() once ctx is done, and entropy failures as errors.
+func (g *Generator) NewV6(ctx context.Context) (string, error) {
+	// Without a ClockSequence the clock sequence is fully random, which
+	// keeps high-frequency generation unique even with a fixed node
+	start := time.Now()
+	random, err := g.randomBytes(ctx)
+	if err != nil {
+		return "", err
+	}
+	var node [6]byte
+	if g.Node != nil {
+		node = *g.Node
+	} else {
+		copy(node[:], random[10:16])
+		node[0] |= 0x01
+	}
+	ticks := gregorianTicks60(g.now())
+	clockSeq := uint16(random[8])<<8 | uint16(random[9])
+	if g.ClockSequence != nil {
+		ticks, clockSeq = g.ClockSequence.next(ticks, clockSeq)
+	}
+	uuid := FormatUUID(buildUUIDv6Ticks(ticks, clockSeq, node))
+	debug("generated UUID", "version", 6, "uuid", uuid, "duration", time.Since(start))
+	return uuid, nil
+}
+
+// NewV7 returns a time-ordered UUID (version 7). It returns ctx.Err() once
+// ctx is done, and entropy failures as errors; with Monotonic set it also
+// fails with ErrCounterExhausted as MonotonicV7 does.
+func (g *Generator) NewV7(ctx context.Context) (string, error) {
+	start := time.Now()
+	random, err := g.randomBytes(ctx)
+	if err != nil {
+		return "", err
+	}
+	var uuid string
+	if g.Monotonic {
+		b, err := g.monotonic.advance(g.now(), random)
+		if err != nil {
+			return "", err
+		}
+		uuid = FormatUUID(b)
+	} else {
+		uuid = NewV7FromBytes(g.now(), random)
+	}
+	debug("generated UUID", "version", 7, "uuid", uuid, "monotonic", g.Monotonic, "duration", time.Since(start))
+	return uuid, nil
+}
+
+// randomBytes returns 16 bytes from the Generator's entropy source
+func (g *Generator) randomBytes(ctx context.Context) ([16]byte, error) {
+	if g.Entropy == nil {
+		return randomBytesContext(ctx)
+	}
+	var b [16]byte
+	err := readEntropyFrom(ctx, g.Entropy, "generator", b[:])
+	return b, err
+}
+
+// now returns the current time from the Generator's clock
+func (g *Generator) now() time.Time {
+	if g.Clock == nil {
+		return currentTime()
+	}
+	t := g.Clock()
+	debug("read clock", "timestamp_source", "generator", "timestamp", t)
+	return t
+}
@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestZeroGeneratorMatchesPackageFunctions(t *testing.T) {
+	fakeEntropy(t, &scriptedReader{fill: 0x5a}, 1, 0)
+	pinned := time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC)
+	SetClock(func() time.Time { return pinned })
+	t.Cleanup(func() { SetClock(nil) })
+
+	g := new(Generator)
+	for _, tt := range []struct {
+		version int
+		method  func(context.Context) (string, error)
+		pkg     func() string
+	}{
+		{4, g.NewV4, GenerateUUIDv4},
+		{6, g.NewV6, GenerateUUIDv6},
+		{7, g.NewV7, GenerateUUIDv7},
+	} {
+		got, err := tt.method(context.Background())
+		if err != nil {
+			t.Fatalf("v%d: unexpected error: %v", tt.version, err)
+		}
+		if want := tt.pkg(); got != want {
+			t.Errorf("v%d: expected %s, got %s", tt.version, want, got)
+		}
+	}
+}
+
+func TestGeneratorSources(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC)
+	g := &Generator{
+		Entropy: bytes.NewReader(bytes.Repeat([]byte{0xff}, 48)),
+		Clock:   func() time.Time { return pinned },
+	}
+
+	v4, err := g.NewV4(context.Background())
+	if err != nil || v4 != "ffffffff-ffff-4fff-bfff-ffffffffffff" {
+		t.Errorf("Expected a v4 from the custom entropy, got %s, %v", v4, err)
+	}
+	v7, err := g.NewV7(context.Background())
+	if err != nil || v7 != "018df978-aeb9-7fff-bfff-ffffffffffff" {
+		t.Errorf("Expected a v7 at the custom clock, got %s, %v", v7, err)
+	}
+	uuid, _ := ParseUUID(mustGenerate(g.NewV6(context.Background())))
+	if ts := Inspect(uuid).Timestamp; ts == nil || !ts.Equal(pinned) {
+		t.Errorf("Expected a v6 at the custom clock, got %v", ts)
+	}
+
+	// The source is now exhausted
+	if _, err := g.NewV4(context.Background()); !errors.Is(err, ErrEntropyUnavailable) {
+		t.Errorf("Expected ErrEntropyUnavailable, got %v", err)
+	}
+}
+
+func TestGeneratorNode(t *testing.T) {
+	// A set node is shared by every UUIDv6 and round-trips through Inspect
+	node := [6]byte{0x02, 0x00, 0x5e, 0x10, 0x00, 0x01}
+	g := &Generator{Node: &node}
+	for range 3 {
+		d := Inspect(mustParse(t, mustGenerate(g.NewV6(context.Background()))))
+		if d.Node == nil || *d.Node != node {
+			t.Fatalf("Expected node %x, got %v", node, d.Node)
+		}
+	}
+
+	// Without one, each random node has the multicast bit set
+	g = &Generator{Entropy: bytes.NewReader(make([]byte, 16))}
+	uuid := mustGenerate(g.NewV6(context.Background()))
+	if !strings.HasSuffix(uuid, "-010000000000") {
+		t.Errorf("Expected a random node with the multicast bit set, got %s", uuid)
+	}
+}
+
+func TestGeneratorMonotonic(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC)
+	g := &Generator{Clock: func() time.Time { return pinned }, Monotonic: true}
+
+	var last string
+	for i := range 1000 {
+		uuid, err := g.NewV7(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if uuid <= last {
+			t.Fatalf("UUID %d (%s) does not sort after %s", i, uuid, last)
+		}
+		last = uuid
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+	if Default() != zeroGenerator {
+		t.Fatal("Expected the zero Generator by default")
+	}
+
+	SetDefault(&Generator{Entropy: bytes.NewReader(make([]byte, 16))})
+	if uuid := GenerateUUIDv4(); uuid != "00000000-0000-4000-8000-000000000000" {
+		t.Errorf("Expected GenerateUUIDv4 to use the default Generator, got %s", uuid)
+	}
+
+	SetDefault(nil)
+	if Default() != zeroGenerator {
+		t.Error("Expected SetDefault(nil) to restore the zero Generator")
+	}
+}
+
+// TestSetDefaultConcurrent swaps the default while other goroutines
+// generate; run with -race
+func TestSetDefaultConcurrent(t *testing.T) {
+	t.Cleanup(func() { SetDefault(nil) })
+	monotonic := &Generator{Monotonic: true}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				for _, generate := range []func() string{GenerateUUIDv4, GenerateUUIDv6, GenerateUUIDv7} {
+					if _, err := ParseUUID(generate()); err != nil {
+						t.Errorf("Generated an invalid UUID: %v", err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	for i := range 200 {
+		if i%2 == 0 {
+			SetDefault(monotonic)
+		} else {
+			SetDefault(nil)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"encoding/binary"
+	"io"
+	"math/rand/v2"
+)
+
+// NewDeterministicSource returns an entropy source that yields the same
+// ChaCha8 stream for the same seed on every run and platform. The seed is
+// the first 8 bytes of the ChaCha8 key, little-endian, and the rest of the
+// key is zero.
+//
+// INSECURE: anyone who knows or guesses the seed can predict every byte.
+// It exists for test fixtures and snapshots and must never be used for
+// identifiers that need to be unguessable.
+func NewDeterministicSource(seed uint64) io.Reader {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	return rand.NewChaCha8(key)
+}
+
+// NewDeterministic returns a Generator whose random bits come from
+// NewDeterministicSource(seed), so the same seed and sequence of calls give
+// the same UUIDs. NewV6 and NewV7 also embed the clock; set Clock on the
+// result to make them fully reproducible. It is INSECURE in the same way as
+// NewDeterministicSource and is never used unless asked for.
+func NewDeterministic(seed int64) *Generator {
+	return &Generator{Entropy: NewDeterministicSource(uint64(seed))}
+}
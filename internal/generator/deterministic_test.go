@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewDeterministic(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC)
+	sequence := func(seed int64) []string {
+		g := NewDeterministic(seed)
+		g.Clock = func() time.Time { return pinned }
+		var uuids []string
+		for _, generate := range []func(context.Context) (string, error){g.NewV4, g.NewV6, g.NewV7, g.NewV4} {
+			uuids = append(uuids, mustGenerate(generate(context.Background())))
+		}
+		return uuids
+	}
+
+	// Frozen so that a change to the stream, which would break users'
+	// snapshots, fails here first
+	want := []string{
+		"22301fb8-d829-48da-b007-b05614969f34",
+		"1eed7b30-daa5-6a90-9579-8d340c0a17e8",
+		"018df978-aeb9-7fc7-8d92-b4a1351f2a09",
+		"302503de-9d56-4261-8f6d-62c634169cd2",
+	}
+	got := sequence(42)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Call %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+	if again := sequence(42); again[3] != got[3] {
+		t.Error("Expected the same seed to repeat the sequence")
+	}
+	if other := sequence(43); other[0] == got[0] {
+		t.Error("Expected another seed to give another sequence")
+	}
+}
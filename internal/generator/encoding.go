@@ -0,0 +1,482 @@
+package generator
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Alphabets for the positional encodings. Base58 uses the Bitcoin alphabet and
+// base57 uses the shortuuid alphabet, so values interoperate with those tools.
+const (
+	base58Alphabet    = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	base57Alphabet    = "23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// base32Encoding is RFC 4648 base32 rendered in lowercase without padding
+var base32Encoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// maxUUIDValue is the largest 128-bit value (2^128 - 1)
+var maxUUIDValue = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// oidPrefix is the ITU-T X.667 arc under which a UUID is a single decimal arc
+const oidPrefix = "2.25."
+
+// Encoding describes an alternate textual representation of a UUID
+type Encoding struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Encode      func(uuid [16]byte) string
+	Decode      func(value string) ([16]byte, error)
+}
+
+// encodings is the registry of alternate representations, in display order
+var encodings = []Encoding{
+	{
+		Name:        "canonical",
+		Description: "Canonical hyphenated form (8-4-4-4-12 hex digits)",
+		Encode:      FormatUUID,
+		Decode:      decodeCanonical,
+	},
+	{
+		Name:        "hex32",
+		Aliases:     []string{"simple"},
+		Description: "32 hex digits without hyphens",
+		Encode:      encodeHex32,
+		Decode:      decodeHex32,
+	},
+	{
+		Name:        "urn",
+		Description: "RFC 9562 URN (urn:uuid: prefix)",
+		Encode:      encodeURN,
+		Decode:      decodeURN,
+	},
+	{
+		Name:        "braces",
+		Description: "Canonical form in curly braces, as used by Microsoft tools",
+		Encode:      encodeBraces,
+		Decode:      decodeBraces,
+	},
+	{
+		Name:        "base64",
+		Description: "URL-safe base64 without padding (22 characters)",
+		Encode:      encodeBase64,
+		Decode:      decodeBase64,
+	},
+	{
+		Name:        "base32",
+		Description: "Lowercase RFC 4648 base32 without padding (26 characters)",
+		Encode:      encodeBase32,
+		Decode:      decodeBase32,
+	},
+	{
+		Name:        "base58",
+		Description: "Bitcoin-alphabet base58, left-padded to 22 characters",
+		Encode:      encodeBase58,
+		Decode:      decodeBase58,
+	},
+	{
+		Name:        "base57",
+		Description: "shortuuid-alphabet base57, left-padded to 22 characters",
+		Encode:      encodeBase57,
+		Decode:      decodeBase57,
+	},
+	{
+		Name:        "ulid",
+		Description: "Crockford base32 in ULID layout (26 characters)",
+		Encode:      encodeULID,
+		Decode:      decodeULID,
+	},
+	{
+		Name:        "decimal",
+		Description: "Unsigned 128-bit integer in decimal",
+		Encode:      encodeDecimal,
+		Decode:      decodeDecimal,
+	},
+	{
+		Name:        "oid",
+		Description: "ITU-T X.667 OID under the 2.25 arc",
+		Encode:      encodeOID,
+		Decode:      decodeOID,
+	},
+	{
+		Name:        "immutableid",
+		Description: "Azure AD ImmutableID (padded base64 of the little-endian GUID bytes)",
+		Encode:      encodeImmutableID,
+		Decode:      decodeImmutableID,
+	},
+	{
+		Name:        "damm",
+		Description: "Canonical form followed by '~' and a Damm check digit over the 32 hex digits",
+		Encode:      encodeDamm,
+		Decode:      decodeDamm,
+	},
+}
+
+// Encodings returns the registered alternate representations in display order
+func Encodings() []Encoding {
+	result := make([]Encoding, len(encodings))
+	copy(result, encodings)
+	return result
+}
+
+// EncodingNames returns the names of all registered encodings
+func EncodingNames() []string {
+	names := make([]string, len(encodings))
+	for i, e := range encodings {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// LookupEncoding returns the registered encoding with the given name or alias
+func LookupEncoding(name string) (Encoding, error) {
+	for _, e := range encodings {
+		if e.Name == name {
+			return e, nil
+		}
+		for _, alias := range e.Aliases {
+			if alias == name {
+				return e, nil
+			}
+		}
+	}
+	return Encoding{}, fmt.Errorf("unknown encoding '%s'. Available encodings: %s", name, strings.Join(EncodingNames(), ", "))
+}
+
+// DetectEncoding returns the names of every registered encoding that can decode
+// value, together with the decoded UUID when exactly one encoding matches.
+// Callers should treat more than one match as ambiguous.
+func DetectEncoding(value string) ([16]byte, []string) {
+	var result [16]byte
+	var matches []string
+	for _, e := range encodings {
+		if decoded, err := e.Decode(value); err == nil {
+			result = decoded
+			matches = append(matches, e.Name)
+		}
+	}
+	if len(matches) != 1 {
+		return [16]byte{}, matches
+	}
+	return result, matches
+}
+
+// FormatUUID renders 16 bytes in the canonical hyphenated form
+func FormatUUID(uuid [16]byte) string {
+	return string(AppendCanonicalTo(make([]byte, 0, 36), uuid))
+}
+
+// FormatGrouped renders the 32 hex digits of uuid in space-separated groups
+// of size digits, for reading aloud or comparing by eye. The last group is
+// shorter when size does not divide 32.
+func FormatGrouped(uuid [16]byte, size int) string {
+	digits := hex.EncodeToString(uuid[:])
+	if size < 1 {
+		return digits
+	}
+	var b strings.Builder
+	for i := 0; i < len(digits); i += size {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(digits[i:min(i+size, len(digits))])
+	}
+	return b.String()
+}
+
+// ParseUUID parses a UUID in canonical form, tolerating upper case, surrounding
+// braces, a "urn:uuid:" prefix, or missing hyphens. Hex digits separated by
+// spaces, as printed by FormatGrouped, are also accepted, as are X.667 OIDs
+// under the 2.25 arc.
+func ParseUUID(value string) ([16]byte, error) {
+	if strings.HasPrefix(value, oidPrefix) {
+		return decodeOID(value)
+	}
+	s := value
+	if strings.Contains(s, " ") {
+		return decodeHex32(strings.ReplaceAll(s, " ", ""))
+	}
+	if len(s) >= 9 && strings.EqualFold(s[:9], "urn:uuid:") {
+		s = s[9:]
+	}
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = s[1 : len(s)-1]
+	}
+	if len(s) == 32 {
+		return decodeHex32(s)
+	}
+	return decodeCanonical(s)
+}
+
+func decodeCanonical(value string) ([16]byte, error) {
+	var uuid [16]byte
+	if len(value) != 36 {
+		return uuid, classify(ErrInvalidFormat, "invalid length %d for canonical UUID, expected 36 characters", len(value))
+	}
+	for _, i := range []int{8, 13, 18, 23} {
+		if value[i] != '-' {
+			return uuid, classify(ErrInvalidFormat, "invalid character '%c' at position %d, expected '-'", value[i], i+1)
+		}
+	}
+	return decodeHexDigits(strings.ReplaceAll(value, "-", ""), value)
+}
+
+func encodeURN(uuid [16]byte) string {
+	return "urn:uuid:" + FormatUUID(uuid)
+}
+
+func decodeURN(value string) ([16]byte, error) {
+	if len(value) < 9 || !strings.EqualFold(value[:9], "urn:uuid:") {
+		return [16]byte{}, classify(ErrInvalidFormat, "missing 'urn:uuid:' prefix")
+	}
+	return decodeCanonical(value[9:])
+}
+
+func encodeBraces(uuid [16]byte) string {
+	return "{" + FormatUUID(uuid) + "}"
+}
+
+func decodeBraces(value string) ([16]byte, error) {
+	if !strings.HasPrefix(value, "{") || !strings.HasSuffix(value, "}") {
+		return [16]byte{}, classify(ErrInvalidFormat, "missing surrounding '{' and '}'")
+	}
+	return decodeCanonical(value[1 : len(value)-1])
+}
+
+func encodeHex32(uuid [16]byte) string {
+	return hex.EncodeToString(uuid[:])
+}
+
+func decodeHex32(value string) ([16]byte, error) {
+	if len(value) != 32 {
+		return [16]byte{}, classify(ErrInvalidFormat, "invalid length %d for hex32, expected 32 characters", len(value))
+	}
+	return decodeHexDigits(value, value)
+}
+
+// decodeHexDigits decodes 32 hex digits, reporting bad characters by their
+// position within original so hyphenated input is described accurately
+func decodeHexDigits(digits, original string) ([16]byte, error) {
+	var uuid [16]byte
+	if _, err := hex.Decode(uuid[:], []byte(digits)); err != nil {
+		for i := 0; i < len(original); i++ {
+			c := original[i]
+			if c != '-' && !isHexDigit(c) {
+				return [16]byte{}, classify(ErrInvalidFormat, "invalid character '%c' at position %d, expected a hex digit", c, i+1)
+			}
+		}
+		return [16]byte{}, err
+	}
+	return uuid, nil
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func encodeBase64(uuid [16]byte) string {
+	return base64.RawURLEncoding.EncodeToString(uuid[:])
+}
+
+func decodeBase64(value string) ([16]byte, error) {
+	var uuid [16]byte
+	if len(value) != 22 {
+		return uuid, classify(ErrInvalidFormat, "invalid length %d for base64, expected 22 characters", len(value))
+	}
+	if err := checkAlphabet(value, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_", "base64"); err != nil {
+		return uuid, err
+	}
+	decoded, err := base64.RawURLEncoding.Strict().DecodeString(value)
+	if err != nil {
+		return uuid, classify(ErrInvalidFormat, "invalid base64 value: non-zero trailing bits in final character '%c'", value[21])
+	}
+	copy(uuid[:], decoded)
+	return uuid, nil
+}
+
+func encodeBase32(uuid [16]byte) string {
+	return base32Encoding.EncodeToString(uuid[:])
+}
+
+func decodeBase32(value string) ([16]byte, error) {
+	var uuid [16]byte
+	if len(value) != 26 {
+		return uuid, classify(ErrInvalidFormat, "invalid length %d for base32, expected 26 characters", len(value))
+	}
+	if err := checkAlphabet(value, "abcdefghijklmnopqrstuvwxyz234567", "base32"); err != nil {
+		return uuid, err
+	}
+	decoded, err := base32Encoding.DecodeString(value)
+	if err != nil || base32Encoding.EncodeToString(decoded) != value {
+		return uuid, classify(ErrInvalidFormat, "invalid base32 value: non-zero trailing bits in final character '%c'", value[25])
+	}
+	copy(uuid[:], decoded)
+	return uuid, nil
+}
+
+func encodeBase58(uuid [16]byte) string {
+	return encodeBaseN(uuid, base58Alphabet, 22)
+}
+
+func decodeBase58(value string) ([16]byte, error) {
+	return decodeBaseN(value, base58Alphabet, 22, "base58")
+}
+
+func encodeBase57(uuid [16]byte) string {
+	return encodeBaseN(uuid, base57Alphabet, 22)
+}
+
+func decodeBase57(value string) ([16]byte, error) {
+	return decodeBaseN(value, base57Alphabet, 22, "base57")
+}
+
+func encodeULID(uuid [16]byte) string {
+	return encodeBaseN(uuid, crockfordAlphabet, 26)
+}
+
+// decodeULID accepts lower case input, as Crockford base32 is case-insensitive
+func decodeULID(value string) ([16]byte, error) {
+	if len(value) == 26 && value[0] > '7' {
+		return [16]byte{}, classify(ErrInvalidFormat, "invalid character '%c' at position 1 for ulid, first character must be 0-7", value[0])
+	}
+	return decodeBaseN(strings.ToUpper(value), crockfordAlphabet, 26, "ulid")
+}
+
+func encodeDecimal(uuid [16]byte) string {
+	return new(big.Int).SetBytes(uuid[:]).String()
+}
+
+func decodeDecimal(value string) ([16]byte, error) {
+	var uuid [16]byte
+	if value == "" {
+		return uuid, classify(ErrInvalidFormat, "invalid length 0 for decimal, expected 1 to 39 digits")
+	}
+	if err := checkAlphabet(value, "0123456789", "decimal"); err != nil {
+		return uuid, err
+	}
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok || n.Cmp(maxUUIDValue) > 0 {
+		return uuid, classify(ErrInvalidFormat, "decimal value %s exceeds the 128-bit maximum %s", value, maxUUIDValue)
+	}
+	n.FillBytes(uuid[:])
+	return uuid, nil
+}
+
+func encodeOID(uuid [16]byte) string {
+	return oidPrefix + encodeDecimal(uuid)
+}
+
+// decodeOID accepts "2.25.<n>" where n is a single arc holding the UUID as a
+// 128-bit integer. Other arcs are rejected explicitly rather than treated as
+// malformed input.
+func decodeOID(value string) ([16]byte, error) {
+	var uuid [16]byte
+	if !strings.HasPrefix(value, oidPrefix) {
+		return uuid, classify(ErrInvalidFormat, "OID '%s' is not under the 2.25 arc used for UUIDs", value)
+	}
+	arc := value[len(oidPrefix):]
+	if arc == "" {
+		return uuid, classify(ErrInvalidFormat, "missing integer arc after '2.25.'")
+	}
+	for i := 0; i < len(arc); i++ {
+		switch c := arc[i]; {
+		case c == '.':
+			return uuid, classify(ErrInvalidFormat, "unexpected sub-arc at position %d, a UUID OID has exactly one arc under 2.25", len(oidPrefix)+i+1)
+		case c < '0' || c > '9':
+			return uuid, classify(ErrInvalidFormat, "invalid character '%c' at position %d, expected a digit", c, len(oidPrefix)+i+1)
+		}
+	}
+	if len(arc) > 1 && arc[0] == '0' {
+		return uuid, classify(ErrInvalidFormat, "leading zero at position %d is not allowed in an OID arc", len(oidPrefix)+1)
+	}
+	n, ok := new(big.Int).SetString(arc, 10)
+	if !ok || n.Cmp(maxUUIDValue) > 0 {
+		return uuid, classify(ErrInvalidFormat, "OID arc %s exceeds the 128-bit maximum %s", arc, maxUUIDValue)
+	}
+	n.FillBytes(uuid[:])
+	return uuid, nil
+}
+
+// MicrosoftByteOrder converts between RFC 9562 byte order and the mixed-endian
+// layout Windows uses for a GUID in memory (and Active Directory uses for
+// objectGUID), where the first three groups are little-endian. The swap is its
+// own inverse.
+func MicrosoftByteOrder(uuid [16]byte) [16]byte {
+	return [16]byte{
+		uuid[3], uuid[2], uuid[1], uuid[0],
+		uuid[5], uuid[4],
+		uuid[7], uuid[6],
+		uuid[8], uuid[9], uuid[10], uuid[11], uuid[12], uuid[13], uuid[14], uuid[15],
+	}
+}
+
+func encodeImmutableID(uuid [16]byte) string {
+	b := MicrosoftByteOrder(uuid)
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+func decodeImmutableID(value string) ([16]byte, error) {
+	var uuid [16]byte
+	decoded, err := base64.StdEncoding.Strict().DecodeString(value)
+	if err != nil {
+		return uuid, classify(ErrInvalidFormat, "invalid base64 value: %w", err)
+	}
+	if len(decoded) != 16 {
+		return uuid, classify(ErrInvalidFormat, "ImmutableID decodes to %d bytes, expected 16", len(decoded))
+	}
+	copy(uuid[:], decoded)
+	return MicrosoftByteOrder(uuid), nil
+}
+
+// encodeBaseN renders the UUID as a big-endian integer in the given alphabet,
+// left-padded with the alphabet's zero digit to width characters
+func encodeBaseN(uuid [16]byte, alphabet string, width int) string {
+	n := new(big.Int).SetBytes(uuid[:])
+	base := big.NewInt(int64(len(alphabet)))
+	digit := new(big.Int)
+
+	out := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		n.DivMod(n, base, digit)
+		out[i] = alphabet[digit.Int64()]
+	}
+	return string(out)
+}
+
+func decodeBaseN(value, alphabet string, width int, name string) ([16]byte, error) {
+	var uuid [16]byte
+	if len(value) != width {
+		return uuid, classify(ErrInvalidFormat, "invalid length %d for %s, expected %d characters", len(value), name, width)
+	}
+	if err := checkAlphabet(value, alphabet, name); err != nil {
+		return uuid, err
+	}
+
+	n := new(big.Int)
+	base := big.NewInt(int64(len(alphabet)))
+	for i := 0; i < len(value); i++ {
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(strings.IndexByte(alphabet, value[i]))))
+	}
+	if n.Cmp(maxUUIDValue) > 0 {
+		return uuid, classify(ErrInvalidFormat, "%s value %s exceeds the 128-bit maximum", name, value)
+	}
+	n.FillBytes(uuid[:])
+	return uuid, nil
+}
+
+// checkAlphabet reports the first character of value not present in alphabet
+func checkAlphabet(value, alphabet, name string) error {
+	for i := 0; i < len(value); i++ {
+		if strings.IndexByte(alphabet, value[i]) < 0 {
+			return classify(ErrInvalidFormat, "invalid character '%c' at position %d for %s", value[i], i+1, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodingsKnownValues(t *testing.T) {
+	uuid, err := ParseUUID("d9428888-122b-11e1-b85c-61cd3cbb3210")
+	if err != nil {
+		t.Fatalf("Failed to parse test UUID: %v", err)
+	}
+
+	expected := map[string]string{
+		"canonical":   "d9428888-122b-11e1-b85c-61cd3cbb3210",
+		"hex32":       "d9428888122b11e1b85c61cd3cbb3210",
+		"urn":         "urn:uuid:d9428888-122b-11e1-b85c-61cd3cbb3210",
+		"braces":      "{d9428888-122b-11e1-b85c-61cd3cbb3210}",
+		"base64":      "2UKIiBIrEeG4XGHNPLsyEA",
+		"base32":      "3fbircasfmi6doc4mhgtzozsca",
+		"base58":      "Tq2zVsESD1552ggtR5CuCB",
+		"base57":      "gfMWVuhTWjYTSbx44Pdeqx",
+		"ulid":        "6S8A48G4HB27GVGQ31SMYBPCGG",
+		"decimal":     "288787935866349040041796580581842825744",
+		"oid":         "2.25.288787935866349040041796580581842825744",
+		"immutableid": "iIhC2SsS4RG4XGHNPLsyEA==",
+		"damm":        "d9428888-122b-11e1-b85c-61cd3cbb3210~6",
+	}
+
+	for _, e := range Encodings() {
+		t.Run(e.Name, func(t *testing.T) {
+			want, ok := expected[e.Name]
+			if !ok {
+				t.Fatalf("No known value for encoding %s", e.Name)
+			}
+			if got := e.Encode(uuid); got != want {
+				t.Errorf("Expected %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestEncodingsRoundTrip(t *testing.T) {
+	var zero, max [16]byte
+	for i := range max {
+		max[i] = 0xff
+	}
+
+	inputs := [][16]byte{zero, max}
+	for i := 0; i < 50; i++ {
+		uuid, err := ParseUUID(GenerateUUIDv4())
+		if err != nil {
+			t.Fatalf("Failed to parse generated UUID: %v", err)
+		}
+		inputs = append(inputs, uuid)
+	}
+
+	for _, e := range Encodings() {
+		t.Run(e.Name, func(t *testing.T) {
+			for _, uuid := range inputs {
+				encoded := e.Encode(uuid)
+				decoded, err := e.Decode(encoded)
+				if err != nil {
+					t.Fatalf("Failed to decode %s value %s: %v", e.Name, encoded, err)
+				}
+				if decoded != uuid {
+					t.Errorf("Round trip mismatch for %s: %s -> %s", e.Name, FormatUUID(uuid), FormatUUID(decoded))
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		encoding string
+		input    string
+		contains string
+	}{
+		{"canonical", "d9428888-122b-11e1-b85c-61cd3cbb321", "invalid length 35"},
+		{"canonical", "d9428888-122b-11e1-b85c-61cd3cbb32g0", "invalid character 'g' at position 35"},
+		{"canonical", "d9428888_122b-11e1-b85c-61cd3cbb3210", "invalid character '_' at position 9"},
+		{"hex32", "d9428888122b11e1b85c61cd3cbb321x", "invalid character 'x' at position 32"},
+		{"urn", "d9428888-122b-11e1-b85c-61cd3cbb3210", "missing 'urn:uuid:' prefix"},
+		{"braces", "{d9428888-122b-11e1-b85c-61cd3cbb3210", "missing surrounding '{' and '}'"},
+		{"base64", "2UKIiBIrEeG4XGHNPLsyE", "invalid length 21"},
+		{"base64", "2UKIiBIrEeG4XGHNP+syEA", "invalid character '+' at position 18"},
+		{"base64", "2UKIiBIrEeG4XGHNPLsyEB", "non-zero trailing bits"},
+		{"base32", "3fbircasfmi6doc4mhgtzozsc1", "invalid character '1' at position 26"},
+		{"base58", "Tq2zVsESD1552ggtR5CuC0", "invalid character '0' at position 22"},
+		{"base57", "gfMWVuhTWjYTSbx44Pdeq1", "invalid character '1' at position 22"},
+		{"base57", "zzzzzzzzzzzzzzzzzzzzzz", "exceeds the 128-bit maximum"},
+		{"ulid", "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "first character must be 0-7"},
+		{"ulid", "6S8A48G4HB27GVGQ31SMYBPCGU", "invalid character 'U' at position 26"},
+		{"decimal", "340282366920938463463374607431768211456", "exceeds the 128-bit maximum"},
+		{"decimal", "12a4", "invalid character 'a' at position 3"},
+		{"decimal", "", "invalid length 0"},
+		{"oid", "1.3.6.1.4.1.343", "OID '1.3.6.1.4.1.343' is not under the 2.25 arc"},
+		{"oid", "2.25.", "missing integer arc"},
+		{"oid", "2.25.12.5", "unexpected sub-arc at position 8"},
+		{"oid", "2.25.12a", "invalid character 'a' at position 8"},
+		{"oid", "2.25.0123", "leading zero at position 6"},
+		{"oid", "2.25.340282366920938463463374607431768211456", "exceeds the 128-bit maximum"},
+		{"immutableid", "iIhC2SsS4RG4XGHNPLsyEA", "invalid base64 value"},
+		{"immutableid", "iIhC2SsS4RG4XGHN!LsyEA==", "invalid base64 value"},
+		{"immutableid", "iIhC2SsS4RG4XGHNPLsy", "decodes to 15 bytes, expected 16"},
+		{"damm", "d9428888-122b-11e1-b85c-61cd3cbb3210", "missing '~' before the check digit"},
+		{"damm", "d9428888-122b-11e1-b85c-61cd3cbb3210~g", "invalid check digit 'g'"},
+		{"damm", "d9428888-122b-11e1-b85c-61cd3cbb3210~7", "check digit mismatch: expected '6', got '7'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding+"/"+tt.input, func(t *testing.T) {
+			e, err := LookupEncoding(tt.encoding)
+			if err != nil {
+				t.Fatalf("Failed to look up encoding: %v", err)
+			}
+			_, err = e.Decode(tt.input)
+			if err == nil {
+				t.Fatalf("Expected error decoding %q as %s", tt.input, tt.encoding)
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"canonical", "d9428888-122b-11e1-b85c-61cd3cbb3210", []string{"canonical"}},
+		{"base64 only", "2UKIiBIrEeG4XGHNPLsyEA", []string{"base64"}},
+		{"base32 only", "3fbircasfmi6doc4mhgtzozsca", []string{"base32"}},
+		{"decimal only", "288787935866349040041796580581842825744", []string{"decimal"}},
+		{"ambiguous base64 and base57", "J2jvCWcuVkoXcNvTKsJ7AA", []string{"base64", "base58", "base57"}},
+		{"no match", "not a uuid", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uuid, matches := DetectEncoding(tt.input)
+			if strings.Join(matches, ",") != strings.Join(tt.expected, ",") {
+				t.Fatalf("Expected matches %v, got %v", tt.expected, matches)
+			}
+			if len(matches) == 1 && FormatUUID(uuid) != "d9428888-122b-11e1-b85c-61cd3cbb3210" {
+				t.Errorf("Unexpected decoded UUID %s", FormatUUID(uuid))
+			}
+		})
+	}
+}
+
+func TestParseUUIDForms(t *testing.T) {
+	expected := "d9428888-122b-11e1-b85c-61cd3cbb3210"
+	inputs := []string{
+		"d9428888-122b-11e1-b85c-61cd3cbb3210",
+		"D9428888-122B-11E1-B85C-61CD3CBB3210",
+		"{d9428888-122b-11e1-b85c-61cd3cbb3210}",
+		"urn:uuid:d9428888-122b-11e1-b85c-61cd3cbb3210",
+		"d9428888122b11e1b85c61cd3cbb3210",
+		"d942 8888 122b 11e1 b85c 61cd 3cbb 3210",
+		"D9428 888122 B11E1B 85C61C D3CBB3 210",
+	}
+
+	for _, input := range inputs {
+		uuid, err := ParseUUID(input)
+		if err != nil {
+			t.Errorf("Unexpected error parsing %s: %v", input, err)
+			continue
+		}
+		if got := FormatUUID(uuid); got != expected {
+			t.Errorf("Parsing %s: expected %s, got %s", input, expected, got)
+		}
+	}
+}
+
+func TestFormatGrouped(t *testing.T) {
+	uuid, _ := ParseUUID("d9428888-122b-11e1-b85c-61cd3cbb3210")
+	tests := []struct {
+		size     int
+		expected string
+	}{
+		{4, "d942 8888 122b 11e1 b85c 61cd 3cbb 3210"},
+		{5, "d9428 88812 2b11e 1b85c 61cd3 cbb32 10"},
+		{7, "d942888 8122b11 e1b85c6 1cd3cbb 3210"},
+		{1, "d 9 4 2 8 8 8 8 1 2 2 b 1 1 e 1 b 8 5 c 6 1 c d 3 c b b 3 2 1 0"},
+		{32, "d9428888122b11e1b85c61cd3cbb3210"},
+	}
+
+	for _, tt := range tests {
+		got := FormatGrouped(uuid, tt.size)
+		if got != tt.expected {
+			t.Errorf("Group size %d: expected %q, got %q", tt.size, tt.expected, got)
+		}
+		if parsed, err := ParseUUID(got); err != nil || parsed != uuid {
+			t.Errorf("Group size %d should parse back, got %s (%v)", tt.size, FormatUUID(parsed), err)
+		}
+	}
+}
+
+func TestParseUUIDRejectsShortGroups(t *testing.T) {
+	if _, err := ParseUUID("d942 8888 122b"); err == nil {
+		t.Error("Expected an error for too few grouped digits")
+	}
+}
+
+func TestULIDSpecExample(t *testing.T) {
+	// Example from the ULID specification's canonical string representation
+	uuid, err := decodeULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatalf("Failed to decode ULID: %v", err)
+	}
+	if got := FormatUUID(uuid); got != "01563e3a-b5d3-d676-4c61-efb99302bd5b" {
+		t.Errorf("Unexpected UUID for ULID spec example: %s", got)
+	}
+
+	lower, err := decodeULID("01arz3ndektsv4rrffq69g5fav")
+	if err != nil || lower != uuid {
+		t.Errorf("Lower case ULID should decode identically, got %s (%v)", FormatUUID(lower), err)
+	}
+}
+
+func TestOIDX667Example(t *testing.T) {
+	// The example from ITU-T X.667 clause 6.3
+	uuid, err := ParseUUID("f81d4fae-7dec-11d0-a765-00a0c91e6bf6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const oid = "2.25.329800735698586629295641978511506172918"
+	if got := encodeOID(uuid); got != oid {
+		t.Errorf("Expected %s, got %s", oid, got)
+	}
+	parsed, err := ParseUUID(oid)
+	if err != nil || parsed != uuid {
+		t.Errorf("ParseUUID should accept the OID form, got %v, %v", FormatUUID(parsed), err)
+	}
+
+	zero, err := decodeOID("2.25.0")
+	if err != nil || zero != [16]byte{} {
+		t.Errorf("2.25.0 should decode to the nil UUID, got %v, %v", zero, err)
+	}
+}
+
+func TestImmutableIDByteOrder(t *testing.T) {
+	// The first three groups are stored little-endian, the last eight bytes as-is
+	uuid, _ := ParseUUID("00112233-4455-6677-8899-aabbccddeeff")
+	want := [16]byte{0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	if got := MicrosoftByteOrder(uuid); got != want {
+		t.Errorf("Expected % x, got % x", want, got)
+	}
+	if got := encodeImmutableID(uuid); got != "MyIRAFVEd2aImaq7zN3u/w==" {
+		t.Errorf("Unexpected ImmutableID %s", got)
+	}
+	if MicrosoftByteOrder(MicrosoftByteOrder(uuid)) != uuid {
+		t.Error("The byte-order swap should be its own inverse")
+	}
+}
+
+func TestLookupEncodingAlias(t *testing.T) {
+	e, err := LookupEncoding("simple")
+	if err != nil || e.Name != "hex32" {
+		t.Errorf("Expected simple to resolve to hex32, got %q, %v", e.Name, err)
+	}
+}
+
+func TestLookupEncodingUnknown(t *testing.T) {
+	_, err := LookupEncoding("base99")
+	if err == nil {
+		t.Fatal("Expected error for unknown encoding")
+	}
+	if !strings.Contains(err.Error(), "base64") {
+		t.Errorf("Error should list available encodings, got: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"time"
+)
+
+// Defaults for retrying failed entropy reads
+const (
+	DefaultEntropyAttempts = 3
+	DefaultEntropyBackoff  = time.Millisecond
+)
+
+var (
+	// entropySource supplies every random byte; nil means crypto/rand
+	entropySource io.Reader
+
+	// entropyAttempts and entropyBackoff bound the retries of a failed read
+	entropyAttempts = DefaultEntropyAttempts
+	entropyBackoff  = DefaultEntropyBackoff
+
+	// sleep waits between attempts; tests replace it to observe the backoff
+	sleep = time.Sleep
+)
+
+// SetEntropySource replaces crypto/rand as the source of random bytes for
+// every generator. Passing nil restores crypto/rand. Reads from a custom
+// source can fail, which crypto/rand never does as of Go 1.24.
+func SetEntropySource(r io.Reader) {
+	entropySource = r
+}
+
+// SetEntropyRetry configures how failed entropy reads are retried: up to
+// attempts reads in total, waiting backoff before the second and doubling
+// the wait before each one after that. Attempts below 1 are treated as 1.
+func SetEntropyRetry(attempts int, backoff time.Duration) {
+	entropyAttempts = max(attempts, 1)
+	entropyBackoff = backoff
+}
+
+// ReadEntropy fills b from the entropy source, retrying failed or short reads
+// with exponential backoff. Each attempt reads into a fresh buffer, so b is
+// either filled completely by a single read or left untouched.
+func ReadEntropy(b []byte) error {
+	return ReadEntropyContext(context.Background(), b)
+}
+
+// ReadEntropyContext is ReadEntropy bounded by ctx: it returns ctx.Err() as
+// soon as ctx is done, whether it is waiting to retry or blocked in a read.
+// A read that is abandoned keeps running in the background until the source
+// returns, but its bytes are discarded and b is left untouched.
+func ReadEntropyContext(ctx context.Context, b []byte) error {
+	source, name := entropySource, "custom"
+	if source == nil {
+		source, name = rand.Reader, "crypto/rand"
+	}
+	return readEntropyFrom(ctx, source, name, b)
+}
+
+// readEntropyFrom is ReadEntropyContext reading from source, which name
+// identifies in debug records
+func readEntropyFrom(ctx context.Context, source io.Reader, name string, b []byte) error {
+	start := time.Now()
+	wait := entropyBackoff
+	var err error
+	for attempt := 1; attempt <= entropyAttempts; attempt++ {
+		if attempt > 1 {
+			debug("retrying entropy read", "entropy_source", name, "attempt", attempt, "backoff", wait, "error", err)
+			if err := sleepContext(ctx, wait); err != nil {
+				return err
+			}
+			wait *= 2
+		}
+		buf := make([]byte, len(b))
+		if err = readFullContext(ctx, source, buf); err == nil {
+			copy(b, buf)
+			debug("read entropy", "entropy_source", name, "bytes", len(b), "attempts", attempt, "duration", time.Since(start))
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			debug("entropy read cancelled", "entropy_source", name, "attempts", attempt, "error", ctxErr, "duration", time.Since(start))
+			return ctxErr
+		}
+	}
+	debug("entropy read failed", "entropy_source", name, "attempts", entropyAttempts, "error", err, "duration", time.Since(start))
+	return classify(ErrEntropyUnavailable, "entropy source failed after %d attempts: %w", entropyAttempts, err)
+}
+
+// readFullContext fills buf from source, giving up when ctx is done. Contexts
+// that can never be cancelled read directly, without a goroutine.
+func readFullContext(ctx context.Context, source io.Reader, buf []byte) error {
+	if ctx.Done() == nil {
+		_, err := io.ReadFull(source, buf)
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.ReadFull(source, buf)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sleepContext waits for d or until ctx is done. Contexts that can never be
+// cancelled use sleep, so tests can still observe the backoff.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if ctx.Done() == nil {
+		sleep(d)
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// randomBytesContext returns 16 bytes from the entropy source, or ctx.Err()
+// once ctx is done
+func randomBytesContext(ctx context.Context) ([16]byte, error) {
+	var b [16]byte
+	err := ReadEntropyContext(ctx, b[:])
+	return b, err
+}
+
+// readEntropy fills b for generators that have no error return. Exhausting
+// the retries panics, just as crypto/rand itself crashes the program rather
+// than return weak randomness; only a custom source can get there.
+func readEntropy(b []byte) {
+	if err := ReadEntropy(b); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedReader fails its first failures reads after writing garbage into
+// part of the buffer, then returns fill bytes
+type scriptedReader struct {
+	failures int
+	reads    int
+	fill     byte
+}
+
+func (r *scriptedReader) Read(p []byte) (int, error) {
+	r.reads++
+	if r.failures < 0 || r.reads <= r.failures {
+		p[0] = 0xee
+		return 1, errors.New("resource temporarily unavailable")
+	}
+	for i := range p {
+		p[i] = r.fill
+	}
+	return len(p), nil
+}
+
+// fakeEntropy installs a source and a sleeper that records its waits
+func fakeEntropy(t *testing.T, r *scriptedReader, attempts int, backoff time.Duration) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	SetEntropySource(r)
+	SetEntropyRetry(attempts, backoff)
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() {
+		SetEntropySource(nil)
+		SetEntropyRetry(DefaultEntropyAttempts, DefaultEntropyBackoff)
+		sleep = time.Sleep
+	})
+	return &waits
+}
+
+func TestReadEntropyRecovers(t *testing.T) {
+	r := &scriptedReader{failures: 2, fill: 0x5a}
+	waits := fakeEntropy(t, r, 3, 2*time.Millisecond)
+
+	b := make([]byte, 16)
+	if err := ReadEntropy(b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(b, bytes.Repeat([]byte{0x5a}, 16)) {
+		t.Errorf("Expected the bytes of the successful read only, got %x", b)
+	}
+	if r.reads != 3 {
+		t.Errorf("Expected 3 reads, got %d", r.reads)
+	}
+	if !slices.Equal(*waits, []time.Duration{2 * time.Millisecond, 4 * time.Millisecond}) {
+		t.Errorf("Expected exponential backoff of 2ms then 4ms, got %v", *waits)
+	}
+}
+
+func TestReadEntropyGivesUp(t *testing.T) {
+	r := &scriptedReader{failures: -1}
+	waits := fakeEntropy(t, r, 4, time.Millisecond)
+
+	b := make([]byte, 16)
+	err := ReadEntropy(b)
+	if err == nil || !strings.Contains(err.Error(), "entropy source failed after 4 attempts: resource temporarily unavailable") {
+		t.Errorf("Expected the final error, got: %v", err)
+	}
+	if !bytes.Equal(b, make([]byte, 16)) {
+		t.Errorf("A failed read must leave the buffer untouched, got %x", b)
+	}
+	if r.reads != 4 || !slices.Equal(*waits, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond}) {
+		t.Errorf("Expected 4 reads and 3 waits, got %d and %v", r.reads, *waits)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected generators without an error return to panic")
+		}
+	}()
+	GenerateUUIDv4()
+}
+
+func TestSetEntropyRetryMinimum(t *testing.T) {
+	r := &scriptedReader{failures: -1}
+	waits := fakeEntropy(t, r, 0, time.Millisecond)
+
+	if err := ReadEntropy(make([]byte, 16)); err == nil || !strings.Contains(err.Error(), "after 1 attempts") {
+		t.Errorf("Expected a single attempt, got: %v", err)
+	}
+	if len(*waits) != 0 {
+		t.Errorf("Expected no waits, got %v", *waits)
+	}
+}
+
+func TestEntropySourceFeedsGenerators(t *testing.T) {
+	fakeEntropy(t, &scriptedReader{fill: 0xff}, 1, 0)
+	if got := GenerateUUIDv4(); got != "ffffffff-ffff-4fff-bfff-ffffffffffff" {
+		t.Errorf("Expected the UUID built from the custom source, got %s", got)
+	}
+}
+
+// blockingReader blocks every read until release is closed
+type blockingReader struct {
+	release chan struct{}
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	<-r.release
+	return len(p), nil
+}
+
+// fakeBlockingEntropy installs a source whose reads block until the test ends
+func fakeBlockingEntropy(t *testing.T) {
+	t.Helper()
+	r := &blockingReader{release: make(chan struct{})}
+	SetEntropySource(r)
+	t.Cleanup(func() {
+		close(r.release)
+		SetEntropySource(nil)
+	})
+}
+
+func TestReadEntropyContextUnblocks(t *testing.T) {
+	fakeBlockingEntropy(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	b := make([]byte, 16)
+	err := ReadEntropyContext(ctx, b)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the read to give up at the deadline, took %v", elapsed)
+	}
+	if !bytes.Equal(b, make([]byte, 16)) {
+		t.Errorf("An abandoned read must leave the buffer untouched, got %x", b)
+	}
+}
+
+func TestReadEntropyContextCancelledBackoff(t *testing.T) {
+	SetEntropySource(&scriptedReader{failures: -1})
+	SetEntropyRetry(3, time.Hour)
+	t.Cleanup(func() {
+		SetEntropySource(nil)
+		SetEntropyRetry(DefaultEntropyAttempts, DefaultEntropyBackoff)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := ReadEntropyContext(ctx, make([]byte, 16)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the hour-long backoff to end at the deadline, got %v", err)
+	}
+}
+
+func TestGenerateContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	generators := map[string]ContextGenerator{
+		"v4":                GenerateUUIDv4Context,
+		"v6":                GenerateUUIDv6Context,
+		"v7":                GenerateUUIDv7Context,
+		"monotonic":         new(MonotonicV7).GenerateContext,
+		"sharded monotonic": new(ShardedMonotonicV7).GenerateContext,
+	}
+	for name, generate := range generators {
+		if uuid, err := generate(ctx); !errors.Is(err, context.Canceled) || uuid != "" {
+			t.Errorf("%s: expected context.Canceled, got %q, %v", name, uuid, err)
+		}
+	}
+}
+
+func TestGenerateContextBlockingEntropy(t *testing.T) {
+	fakeBlockingEntropy(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := GenerateUUIDv7Context(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline error, got %v", err)
+	}
+}
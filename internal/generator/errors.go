@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors classifying the failures of the package. Every error
+// returned by parsing, timestamp handling, and generation matches one of
+// them with errors.Is, through any fmt.Errorf("%w") wrapping added by
+// callers; the error text itself stays specific to the failure.
+var (
+	// ErrInvalidFormat: a value is not a UUID, or not in the expected form
+	ErrInvalidFormat = errors.New("invalid format")
+	// ErrWrongVersion: a UUID's version is not one the operation supports.
+	// Use errors.As with *VersionError for the versions involved.
+	ErrWrongVersion = errors.New("wrong UUID version")
+	// ErrTimestampOutOfRange: a timestamp does not fit the target field
+	ErrTimestampOutOfRange = errors.New("timestamp out of range")
+	// ErrEntropyUnavailable: the entropy source failed every attempt
+	ErrEntropyUnavailable = errors.New("entropy unavailable")
+	// ErrCounterExhausted: a monotonic counter ran out within a millisecond
+	// and the generator was not allowed to borrow the next one
+	ErrCounterExhausted = errors.New("counter exhausted")
+)
+
+// VersionError reports a UUID whose version an operation does not support.
+// It matches ErrWrongVersion.
+type VersionError struct {
+	// Expected lists the versions the operation supports
+	Expected []int
+	// Actual is the version found
+	Actual  int
+	message string
+}
+
+func (e *VersionError) Error() string {
+	return e.message
+}
+
+// Is reports whether target is ErrWrongVersion
+func (e *VersionError) Is(target error) bool {
+	return target == ErrWrongVersion
+}
+
+// wrongVersion returns a *VersionError with a formatted message
+func wrongVersion(expected []int, actual int, format string, args ...any) error {
+	return &VersionError{Expected: expected, Actual: actual, message: fmt.Sprintf(format, args...)}
+}
+
+// classifiedError attaches a sentinel to an error without changing its text
+type classifiedError struct {
+	sentinel error
+	err      error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.sentinel, e.err}
+}
+
+// classify formats an error, as fmt.Errorf does, that also matches sentinel
+func classify(sentinel error, format string, args ...any) error {
+	return &classifiedError{sentinel: sentinel, err: fmt.Errorf(format, args...)}
+}
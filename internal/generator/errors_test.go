@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrInvalidFormat(t *testing.T) {
+	base64, _ := LookupEncoding("base64")
+	damm, _ := LookupEncoding("damm")
+	failures := map[string]func() error{
+		"ParseUUID":           func() error { _, err := ParseUUID("not-a-uuid"); return err },
+		"ParseUUID hex":       func() error { _, err := ParseUUID("919108f752d143209bacf847db4148ag"); return err },
+		"ParseUUID oid":       func() error { _, err := ParseUUID("2.25.x"); return err },
+		"ParseBytes":          func() error { _, err := ParseBytes([]byte("919108f7-52d1-4320-9bac-f847db4148a")); return err },
+		"base64 decode":       func() error { _, err := base64.Decode("short"); return err },
+		"check digit":         func() error { _, err := damm.Decode("919108f7-52d1-4320-9bac-f847db4148a8~0"); return err },
+		"Validate":            func() error { return Validate("919108f7-52d1-4320-9bac-f847db4148a8x") },
+		"ParseTimestamp":      func() error { _, err := ParseTimestamp("yesterday"); return err },
+		"ParseTimestampSince": func() error { _, err := ParseTimestampSince("1.5", time.Time{}); return err },
+		"ParseTimestampLayout": func() error {
+			_, err := ParseTimestampLayout("14-06-2023", "02/01/2006")
+			return err
+		},
+		"ParseSnowflake":     func() error { _, err := ParseSnowflake("-1", time.Time{}); return err },
+		"UUIDv7FromObjectID": func() error { _, err := UUIDv7FromObjectID("xyz", false); return err },
+		"ParseV8Layout":      func() error { _, err := ParseV8Layout("ms:48,bogus:74"); return err },
+		"Verify":             func() error { _, err := Verify([][]byte{signKey}, "919108f7-52d1-4320-9bac-f847db4148a8"); return err },
+	}
+	for name, fail := range failures {
+		err := fail()
+		if !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("%s: expected ErrInvalidFormat, got %v", name, err)
+		}
+		if wrapped := fmt.Errorf("reading input: %w", err); !errors.Is(wrapped, ErrInvalidFormat) {
+			t.Errorf("%s: expected ErrInvalidFormat through a wrapping error", name)
+		}
+		if errors.Is(err, ErrWrongVersion) || errors.Is(err, ErrEntropyUnavailable) {
+			t.Errorf("%s: expected a single classification, got %v", name, err)
+		}
+	}
+
+	// The classification does not change the message, or hide the details
+	err := Validate("919108f7-52d1-4320-9bac-f847db4148a8x")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Reason != ReasonLength {
+		t.Errorf("Expected a *ValidationError, got %v", err)
+	}
+	if _, err := ParseUUID("not-a-uuid"); err.Error() != "invalid length 10 for canonical UUID, expected 36 characters" {
+		t.Errorf("Unexpected message %q", err)
+	}
+}
+
+func TestErrWrongVersion(t *testing.T) {
+	v4 := mustParse(t, "919108f7-52d1-4320-9bac-f847db4148a8")
+	tests := []struct {
+		name     string
+		fail     func() error
+		expected []int
+		actual   int
+	}{
+		{"ObjectIDFromUUID", func() error { _, err := ObjectIDFromUUID(v4, false); return err }, []int{1, 6, 7}, 4},
+		{"SnowflakeFromUUID", func() error { _, err := SnowflakeFromUUID(v4, time.Time{}); return err }, []int{7}, 4},
+		{"NewNameBasedFromReader", func() error {
+			_, err := NewNameBasedFromReader(3, NamespaceDNS, strings.NewReader("x"))
+			return err
+		}, []int{5, 8}, 3},
+		{"V8 layout decode", func() error {
+			layout, _ := ParseV8Layout("ms:48,rand:74")
+			_, err := layout.Decode(v4)
+			return err
+		}, []int{8}, 4},
+		{"Seq", func() error {
+			for _, err := range Seq(context.Background(), WithVersion(2)) {
+				return err
+			}
+			return nil
+		}, []int{4, 6, 7}, 2},
+	}
+	for _, tt := range tests {
+		err := tt.fail()
+		if !errors.Is(fmt.Errorf("wrapped: %w", err), ErrWrongVersion) {
+			t.Errorf("%s: expected ErrWrongVersion, got %v", tt.name, err)
+		}
+		var versionErr *VersionError
+		if !errors.As(err, &versionErr) || !slices.Equal(versionErr.Expected, tt.expected) || versionErr.Actual != tt.actual {
+			t.Errorf("%s: expected a *VersionError for %v, got %+v", tt.name, tt.expected, versionErr)
+		}
+	}
+}
+
+func TestErrTimestampOutOfRange(t *testing.T) {
+	farFuture := mustParse(t, NewV7FromBytes(time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC), [16]byte{}))
+	beforeEpoch := mustParse(t, NewV7FromBytes(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), [16]byte{}))
+	twitter := time.UnixMilli(1288834974657)
+
+	if _, err := ObjectIDFromUUID(farFuture, false); !errors.Is(err, ErrTimestampOutOfRange) {
+		t.Errorf("ObjectIDFromUUID: expected ErrTimestampOutOfRange, got %v", err)
+	}
+	if _, err := SnowflakeFromUUID(beforeEpoch, twitter); !errors.Is(err, ErrTimestampOutOfRange) {
+		t.Errorf("SnowflakeFromUUID: expected ErrTimestampOutOfRange, got %v", err)
+	}
+}
+
+func TestErrEntropyUnavailable(t *testing.T) {
+	fakeEntropy(t, &scriptedReader{failures: -1}, 2, time.Millisecond)
+
+	if _, err := GenerateUUIDv4Context(context.Background()); !errors.Is(err, ErrEntropyUnavailable) {
+		t.Errorf("GenerateUUIDv4Context: expected ErrEntropyUnavailable, got %v", err)
+	}
+	if _, err := new(MonotonicV7).GenerateContext(context.Background()); !errors.Is(err, ErrEntropyUnavailable) {
+		t.Errorf("MonotonicV7: expected ErrEntropyUnavailable, got %v", err)
+	}
+	for _, err := range V7Seq(context.Background()) {
+		if !errors.Is(err, ErrEntropyUnavailable) {
+			t.Errorf("V7Seq: expected ErrEntropyUnavailable, got %v", err)
+		}
+	}
+	// The underlying read error is still there
+	if _, err := GenerateUUIDv6Context(context.Background()); err == nil || !strings.Contains(err.Error(), "resource temporarily unavailable") {
+		t.Errorf("Expected the read error in the message, got %v", err)
+	}
+}
+
+func TestErrCounterExhausted(t *testing.T) {
+	frozen := time.UnixMilli(1718361000123)
+	SetClock(func() time.Time { return frozen })
+	t.Cleanup(func() { SetClock(nil) })
+
+	g := &MonotonicV7{NoBorrow: true}
+	generated := 0
+	var err error
+	for generated <= 1<<monotonicCounterBits {
+		if _, err = g.GenerateContext(context.Background()); err != nil {
+			break
+		}
+		generated++
+	}
+	if !errors.Is(err, ErrCounterExhausted) {
+		t.Fatalf("Expected ErrCounterExhausted, got %v", err)
+	}
+	// The counter starts below 2^15, leaving at least 2^15 values
+	if generated < 1<<15 {
+		t.Errorf("Expected at least %d UUIDs before exhaustion, got %d", 1<<15, generated)
+	}
+
+	// Once the clock moves the generator continues
+	frozen = frozen.Add(time.Millisecond)
+	if _, err := g.GenerateContext(context.Background()); err != nil {
+		t.Errorf("Expected generation to resume, got %v", err)
+	}
+
+	// Without NoBorrow the next millisecond is borrowed instead
+	borrowing := new(MonotonicV7)
+	for range 1 << monotonicCounterBits {
+		if _, err := borrowing.GenerateContext(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UUID is a 16-byte UUID that formats itself with the fmt verbs below. It
+// converts to and from the [16]byte values the rest of the package uses.
+type UUID [16]byte
+
+// String returns the canonical lowercase form
+func (u UUID) String() string {
+	return FormatUUID(u)
+}
+
+// Format implements fmt.Formatter:
+//
+//	%s, %v  canonical lowercase: d9428888-122b-11e1-b85c-61cd3cbb3210
+//	%x      lowercase hex without hyphens
+//	%X      uppercase hex without hyphens
+//	%q      canonical form in double quotes; %#q uses backquotes
+//	%+v     canonical form followed by its version, variant, and any
+//	        embedded timestamp, as reported by inspect
+//	%#v     Go syntax: generator.UUID{0xd9, 0x42, ...}
+//
+// Width pads with spaces, on the left unless the '-' flag is given, and
+// precision keeps only the first characters, so %.8x prints a short ID.
+// Other verbs print %!verb(generator.UUID=<canonical>), as fmt does for
+// values a verb does not fit.
+func (u UUID) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's':
+		pad(f, u.String())
+	case 'v':
+		switch {
+		case f.Flag('+'):
+			pad(f, u.verbose())
+		case f.Flag('#'):
+			pad(f, u.goSyntax())
+		default:
+			pad(f, u.String())
+		}
+	case 'x':
+		pad(f, encodeHex32([16]byte(u)))
+	case 'X':
+		pad(f, strings.ToUpper(encodeHex32([16]byte(u))))
+	case 'q':
+		if f.Flag('#') {
+			pad(f, "`"+u.String()+"`")
+		} else {
+			pad(f, strconv.Quote(u.String()))
+		}
+	default:
+		fmt.Fprintf(f, "%%!%c(generator.UUID=%s)", verb, u.String())
+	}
+}
+
+// pad writes text with the width, precision, and '-' flag of f applied
+func pad(f fmt.State, text string) {
+	directive := "%"
+	if f.Flag('-') {
+		directive += "-"
+	}
+	if width, ok := f.Width(); ok {
+		directive += strconv.Itoa(width)
+	}
+	if precision, ok := f.Precision(); ok {
+		directive += "." + strconv.Itoa(precision)
+	}
+	fmt.Fprintf(f, directive+"s", text)
+}
+
+// verbose renders the %+v form
+func (u UUID) verbose() string {
+	switch u {
+	case UUID{}:
+		return u.String() + " (nil)"
+	case UUID(maxUUID):
+		return u.String() + " (max)"
+	}
+	d := Inspect(u)
+	if d.Variant != VariantRFC {
+		return fmt.Sprintf("%s (variant %s)", u, d.Variant)
+	}
+	s := fmt.Sprintf("%s (version %d, variant %s", u, d.Version, d.Variant)
+	if d.Timestamp != nil {
+		s += ", time " + d.Timestamp.Format(time.RFC3339Nano)
+	}
+	return s + ")"
+}
+
+// goSyntax renders the %#v form, matching what fmt prints for the array
+func (u UUID) goSyntax() string {
+	var b strings.Builder
+	b.WriteString("generator.UUID{")
+	for i, c := range u {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%#x", c)
+	}
+	b.WriteString("}")
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUUIDFormat(t *testing.T) {
+	v1, _ := ParseUUID("d9428888-122b-11e1-b85c-61cd3cbb3210")
+	v4, _ := ParseUUID("919108f7-52d1-4320-9bac-f847db4148a8")
+	ms, _ := ParseUUID("919108f7-52d1-4320-db4c-f847db4148a8")
+	u := UUID(v1)
+
+	tests := []struct {
+		format string
+		value  any
+		want   string
+	}{
+		{"%s", u, "d9428888-122b-11e1-b85c-61cd3cbb3210"},
+		{"%v", u, "d9428888-122b-11e1-b85c-61cd3cbb3210"},
+		{"%x", u, "d9428888122b11e1b85c61cd3cbb3210"},
+		{"%X", u, "D9428888122B11E1B85C61CD3CBB3210"},
+		{"%q", u, `"d9428888-122b-11e1-b85c-61cd3cbb3210"`},
+		{"%#q", u, "`d9428888-122b-11e1-b85c-61cd3cbb3210`"},
+		{"%+v", u, "d9428888-122b-11e1-b85c-61cd3cbb3210 (version 1, variant rfc, time 2011-11-18T21:25:33.5735432Z)"},
+		{"%+v", UUID(v4), "919108f7-52d1-4320-9bac-f847db4148a8 (version 4, variant rfc)"},
+		{"%+v", UUID(ms), "919108f7-52d1-4320-db4c-f847db4148a8 (variant microsoft)"},
+		{"%#v", u, "generator.UUID{0xd9, 0x42, 0x88, 0x88, 0x12, 0x2b, 0x11, 0xe1, 0xb8, 0x5c, 0x61, 0xcd, 0x3c, 0xbb, 0x32, 0x10}"},
+
+		// Width, precision, and flags
+		{"%40s|", u, "    d9428888-122b-11e1-b85c-61cd3cbb3210|"},
+		{"%-40v|", u, "d9428888-122b-11e1-b85c-61cd3cbb3210    |"},
+		{"%.8x", u, "d9428888"},
+		{"%-10.8X|", u, "D9428888  |"},
+		{"%40q", u, `  "d9428888-122b-11e1-b85c-61cd3cbb3210"`},
+		{"%05s", u, "d9428888-122b-11e1-b85c-61cd3cbb3210"},
+		{"%+s", u, "d9428888-122b-11e1-b85c-61cd3cbb3210"},
+		{"%4s", u, "d9428888-122b-11e1-b85c-61cd3cbb3210"},
+
+		// Nil and max UUIDs
+		{"%s", UUID{}, "00000000-0000-0000-0000-000000000000"},
+		{"%X", UUID{}, "00000000000000000000000000000000"},
+		{"%+v", UUID{}, "00000000-0000-0000-0000-000000000000 (nil)"},
+		{"%+v", UUID(maxUUID), "ffffffff-ffff-ffff-ffff-ffffffffffff (max)"},
+		{"%v", (*UUID)(nil), "<nil>"},
+		{"%v", &u, "d9428888-122b-11e1-b85c-61cd3cbb3210"},
+
+		// Unsupported verbs
+		{"%d", u, "%!d(generator.UUID=d9428888-122b-11e1-b85c-61cd3cbb3210)"},
+		{"%t", UUID{}, "%!t(generator.UUID=00000000-0000-0000-0000-000000000000)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.value); got != tt.want {
+				t.Errorf("Sprintf(%q) = %q, expected %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUUIDString(t *testing.T) {
+	uuid, _ := ParseUUID("D9428888-122B-11E1-B85C-61CD3CBB3210")
+	if got := UUID(uuid).String(); got != FormatUUID(uuid) {
+		t.Errorf("Expected String to match FormatUUID, got %s", got)
+	}
+	if got := fmt.Sprint(UUID(uuid)); got != "d9428888-122b-11e1-b85c-61cd3cbb3210" {
+		t.Errorf("Expected Sprint to use the canonical form, got %s", got)
+	}
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"fmt"
+	"time"
+)
+
+// gregorianOffset is the number of 100ns intervals between the UUID epoch
+// (1582-10-15) and the Unix epoch, as used by UUIDv1 and UUIDv6 timestamps
+const gregorianOffset = 122192928000000000
+
+// Variant names reported for the variant field of a UUID
+const (
+	VariantNCS       = "ncs"
+	VariantRFC       = "rfc"
+	VariantMicrosoft = "microsoft"
+	VariantFuture    = "future"
+)
+
+// Details describes the fields decoded from a UUID. Fields that are not
+// meaningful for the UUID's version are left nil.
+type Details struct {
+	UUID      [16]byte
+	Version   int
+	Variant   string
+	Timestamp *time.Time
+	Node      *[6]byte
+	ClockSeq  *int
+	Counter   *int
+}
+
+// Inspect decodes the version, variant, and any embedded timestamp, node, and
+// clock sequence from a UUID
+func Inspect(uuid [16]byte) Details {
+	d := Details{
+		UUID:    uuid,
+		Version: int(uuid[6] >> 4),
+		Variant: variantOf(uuid),
+	}
+
+	if d.Variant != VariantRFC {
+		return d
+	}
+
+	switch d.Version {
+	case 1, 6:
+		var ticks uint64
+		if d.Version == 1 {
+			// time_low (32) | time_mid (16) | version + time_hi (4+12)
+			ticks = uint64(uuid[6]&0x0f)<<56 | uint64(uuid[7])<<48 |
+				uint64(uuid[4])<<40 | uint64(uuid[5])<<32 |
+				uint64(uuid[0])<<24 | uint64(uuid[1])<<16 | uint64(uuid[2])<<8 | uint64(uuid[3])
+		} else {
+			// time_high (32) | time_mid (16) | version + time_low (4+12)
+			ticks = uint64(uuid[0])<<52 | uint64(uuid[1])<<44 | uint64(uuid[2])<<36 | uint64(uuid[3])<<28 |
+				uint64(uuid[4])<<20 | uint64(uuid[5])<<12 |
+				uint64(uuid[6]&0x0f)<<8 | uint64(uuid[7])
+		}
+		ts := gregorianTime(ticks)
+		d.Timestamp = &ts
+
+		clockSeq := int(uuid[8]&0x3f)<<8 | int(uuid[9])
+		d.ClockSeq = &clockSeq
+
+		var node [6]byte
+		copy(node[:], uuid[10:16])
+		d.Node = &node
+	case 7:
+		ms := int64(uuid[0])<<40 | int64(uuid[1])<<32 | int64(uuid[2])<<24 |
+			int64(uuid[3])<<16 | int64(uuid[4])<<8 | int64(uuid[5])
+		ts := time.UnixMilli(ms).UTC()
+		d.Timestamp = &ts
+	}
+
+	return d
+}
+
+// variantOf classifies the variant bits in octet 8
+func variantOf(uuid [16]byte) string {
+	switch {
+	case uuid[8]&0x80 == 0x00:
+		return VariantNCS
+	case uuid[8]&0xc0 == 0x80:
+		return VariantRFC
+	case uuid[8]&0xe0 == 0xc0:
+		return VariantMicrosoft
+	default:
+		return VariantFuture
+	}
+}
+
+// SetVariant overwrites the variant bits in octet 8, leaving the remaining
+// bits of the octet as they were. Every variant except rfc produces a value
+// that is not an RFC 9562 UUID.
+func SetVariant(uuid [16]byte, variant string) ([16]byte, error) {
+	switch variant {
+	case VariantRFC:
+		uuid[8] = uuid[8]&0x3f | 0x80
+	case VariantNCS:
+		uuid[8] &= 0x7f
+	case VariantMicrosoft:
+		uuid[8] = uuid[8]&0x1f | 0xc0
+	case VariantFuture:
+		uuid[8] = uuid[8]&0x1f | 0xe0
+	default:
+		return uuid, fmt.Errorf("unknown variant '%s'. Available variants: rfc, ncs, microsoft, future", variant)
+	}
+	return uuid, nil
+}
+
+// gregorianTime converts 100ns intervals since 1582-10-15 to a UTC time
+func gregorianTime(ticks uint64) time.Time {
+	unixTicks := int64(ticks) - gregorianOffset
+	return time.Unix(unixTicks/10000000, (unixTicks%10000000)*100).UTC()
+}
+
+// VersionName returns a short description of a UUID version
+func VersionName(version int) string {
+	switch version {
+	case 1:
+		return "Gregorian time-based"
+	case 2:
+		return "DCE Security"
+	case 3:
+		return "MD5 name-based"
+	case 4:
+		return "random"
+	case 5:
+		return "SHA-1 name-based"
+	case 6:
+		return "reordered Gregorian time-based"
+	case 7:
+		return "Unix Epoch time-based"
+	case 8:
+		return "custom"
+	default:
+		return fmt.Sprintf("unknown version %d", version)
+	}
+}
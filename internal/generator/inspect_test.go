@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInspectTimeBasedVersions(t *testing.T) {
+	// RFC 9562 appendix examples all encode 2022-02-22T19:22:22Z
+	expected := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		uuid     string
+		version  int
+		hasNode  bool
+		clockSeq int
+	}{
+		{"UUIDv1", "c232ab00-9414-11ec-b3c8-9f6bdeced846", 1, true, 0x33c8},
+		{"UUIDv6", "1ec9414c-232a-6b00-b3c8-9f6bdeced846", 6, true, 0x33c8},
+		{"UUIDv7", "017f22e2-79b0-7cc3-98c4-dc0c0c07398f", 7, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uuid, err := ParseUUID(tt.uuid)
+			if err != nil {
+				t.Fatalf("Failed to parse UUID: %v", err)
+			}
+
+			d := Inspect(uuid)
+			if d.Version != tt.version {
+				t.Errorf("Expected version %d, got %d", tt.version, d.Version)
+			}
+			if d.Variant != VariantRFC {
+				t.Errorf("Expected rfc variant, got %s", d.Variant)
+			}
+			if d.Timestamp == nil || !d.Timestamp.Equal(expected) {
+				t.Errorf("Expected timestamp %v, got %v", expected, d.Timestamp)
+			}
+			if tt.hasNode {
+				if d.Node == nil || *d.Node != [6]byte{0x9f, 0x6b, 0xde, 0xce, 0xd8, 0x46} {
+					t.Errorf("Unexpected node %v", d.Node)
+				}
+				if d.ClockSeq == nil || *d.ClockSeq != tt.clockSeq {
+					t.Errorf("Expected clock sequence %d, got %v", tt.clockSeq, d.ClockSeq)
+				}
+			} else if d.Node != nil || d.ClockSeq != nil {
+				t.Errorf("UUIDv7 should not report node or clock sequence")
+			}
+		})
+	}
+}
+
+func TestInspectGeneratedUUIDs(t *testing.T) {
+	testTime := time.Date(2023, 6, 14, 10, 30, 45, 123000000, time.UTC)
+	uuid, err := ParseUUID(mustV7At(t, testTime))
+	if err != nil {
+		t.Fatalf("Failed to parse UUID: %v", err)
+	}
+
+	d := Inspect(uuid)
+	if d.Timestamp == nil || !d.Timestamp.Equal(testTime) {
+		t.Errorf("Expected timestamp %v, got %v", testTime, d.Timestamp)
+	}
+
+	uuid, err = ParseUUID(GenerateUUIDv4())
+	if err != nil {
+		t.Fatalf("Failed to parse UUID: %v", err)
+	}
+	if d := Inspect(uuid); d.Version != 4 || d.Timestamp != nil {
+		t.Errorf("UUIDv4 should have version 4 and no timestamp, got %+v", d)
+	}
+}
+
+func TestInspectVariants(t *testing.T) {
+	tests := []struct {
+		octet   byte
+		variant string
+	}{
+		{0x00, VariantNCS},
+		{0x7f, VariantNCS},
+		{0x80, VariantRFC},
+		{0xbf, VariantRFC},
+		{0xc0, VariantMicrosoft},
+		{0xdf, VariantMicrosoft},
+		{0xe0, VariantFuture},
+		{0xff, VariantFuture},
+	}
+
+	for _, tt := range tests {
+		var uuid [16]byte
+		uuid[6] = 0x70
+		uuid[8] = tt.octet
+		d := Inspect(uuid)
+		if d.Variant != tt.variant {
+			t.Errorf("Octet 8 = %#02x: expected %s, got %s", tt.octet, tt.variant, d.Variant)
+		}
+		if tt.variant != VariantRFC && d.Timestamp != nil {
+			t.Errorf("Non-RFC variant should not decode a timestamp")
+		}
+	}
+}
+
+func TestSetVariant(t *testing.T) {
+	tests := []struct {
+		variant  string
+		fromZero byte
+		fromOnes byte
+	}{
+		{VariantRFC, 0x80, 0xbf},
+		{VariantNCS, 0x00, 0x7f},
+		{VariantMicrosoft, 0xc0, 0xdf},
+		{VariantFuture, 0xe0, 0xff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.variant, func(t *testing.T) {
+			var zeros, ones [16]byte
+			for i := range ones {
+				ones[i] = 0xff
+			}
+			for _, c := range []struct {
+				in   [16]byte
+				want byte
+			}{{zeros, tt.fromZero}, {ones, tt.fromOnes}} {
+				got, err := SetVariant(c.in, tt.variant)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if got[8] != c.want {
+					t.Errorf("Expected octet 8 = %08b, got %08b", c.want, got[8])
+				}
+				if variantOf(got) != tt.variant {
+					t.Errorf("Expected Inspect to report %s, got %s", tt.variant, variantOf(got))
+				}
+				got[8] = c.in[8]
+				if got != c.in {
+					t.Error("Only octet 8 should change")
+				}
+			}
+		})
+	}
+
+	if _, err := SetVariant([16]byte{}, "apollo"); err == nil {
+		t.Error("Expected an error for an unknown variant")
+	}
+}
@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateUUIDv8HMAC(t *testing.T) {
+	// Vectors from the Python recipe in the GenerateUUIDv8HMAC comment
+	tests := []struct {
+		key, name, expected string
+	}{
+		{"0123456789abcdef", "customer-42", "08784800-cc11-81f6-b731-49fe5cce052a"},
+		{"0123456789abcdef", "", "496dc93f-a2d2-8eae-900e-c0bc37a12270"},
+		{"fedcba9876543210", "customer-42", "c0828610-af7d-88f9-b680-ec87c48261c0"},
+		{"0123456789abcdef", "héllo", "40d3d8b7-fdb8-8552-a58a-0b25c7dccac6"},
+	}
+	for _, tt := range tests {
+		got, err := GenerateUUIDv8HMAC([]byte(tt.key), tt.name)
+		if err != nil || got != tt.expected {
+			t.Errorf("GenerateUUIDv8HMAC(%q, %q): expected %s, got %s, %v", tt.key, tt.name, tt.expected, got, err)
+		}
+		if d := Inspect(mustParse(t, got)); d.Version != 8 || d.Variant != VariantRFC {
+			t.Errorf("Expected an RFC UUIDv8, got %s", got)
+		}
+	}
+
+	if _, err := GenerateUUIDv8HMAC([]byte("short"), "x"); err == nil || !strings.Contains(err.Error(), "HMAC key is 5 bytes; at least 16 are required") {
+		t.Errorf("Expected a short key error, got: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package generator
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Monotonic UUIDv7 layout (RFC 9562 section 6.2, method 1): after the 48-bit
+// millisecond timestamp, a 16-bit counter fills rand_a (its top 12 bits) and
+// the first 4 bits of rand_b, followed by a 6-bit shard ID and 52 random bits.
+// Each millisecond the counter starts at a random value below 2^15, leaving at
+// least 32768 increments; when it overflows the generator moves on to the next
+// millisecond rather than wrapping.
+const (
+	monotonicCounterBits = 16
+	monotonicCounterMask = 1<<monotonicCounterBits - 1
+	monotonicShardBits   = 6
+
+	// MonotonicShards is the number of counters a ShardedMonotonicV7 spreads
+	// generation across
+	MonotonicShards = 1 << monotonicShardBits
+)
+
+// MonotonicV7 generates UUIDv7s that strictly increase across every caller.
+// A single mutex guards the timestamp and counter, so it is the simplest
+// choice but serializes heavily concurrent generation. The zero value is
+// ready to use.
+type MonotonicV7 struct {
+	// NoBorrow makes GenerateContext fail with ErrCounterExhausted, instead
+	// of moving on to the next millisecond, when a millisecond's counter
+	// overflows, so timestamps never run ahead of the clock
+	NoBorrow bool
+
+	mu      sync.Mutex
+	lastMs  uint64
+	counter uint64
+}
+
+// Generate returns the next UUIDv7. Its shard ID is always 0.
+func (g *MonotonicV7) Generate() string {
+	return mustGenerate(g.GenerateContext(context.Background()))
+}
+
+// GenerateContext is Generate bounded by ctx: it returns ctx.Err() once ctx
+// is done, without advancing the counter
+func (g *MonotonicV7) GenerateContext(ctx context.Context) (string, error) {
+	uuid, err := g.next(ctx)
+	if err != nil {
+		return "", err
+	}
+	return FormatUUID(uuid), nil
+}
+
+// next returns the next UUIDv7 as bytes
+func (g *MonotonicV7) next(ctx context.Context) ([16]byte, error) {
+	random, err := randomBytesContext(ctx)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	return g.advance(currentTime(), random)
+}
+
+// advance returns the UUIDv7 following the last one, built from the current
+// time and 16 random bytes
+func (g *MonotonicV7) advance(now time.Time, random [16]byte) ([16]byte, error) {
+	ms := unixMillis48(now)
+
+	g.mu.Lock()
+	next, counter := nextMonotonic(g.lastMs, g.counter, ms, random)
+	if g.NoBorrow && next > max(ms, g.lastMs) {
+		g.mu.Unlock()
+		return [16]byte{}, classify(ErrCounterExhausted, "monotonic counter exhausted at %d ms; the clock must advance before the next UUIDv7", next-1)
+	}
+	ms, g.lastMs, g.counter = next, next, counter
+	g.mu.Unlock()
+
+	return buildMonotonicV7(ms, counter, 0, random), nil
+}
+
+// State returns the timestamp and counter of the last UUIDv7, so another
+// process can continue the sequence with Restore. Both are zero before the
+// first UUIDv7.
+func (g *MonotonicV7) State() (last time.Time, counter uint16) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return time.UnixMilli(int64(g.lastMs)).UTC(), uint16(g.counter)
+}
+
+// Restore continues the sequence after a UUIDv7 with timestamp last and
+// counter, as returned by State, so UUIDs from separate processes sharing
+// saved state still strictly increase
+func (g *MonotonicV7) Restore(last time.Time, counter uint16) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lastMs, g.counter = unixMillis48(last), uint64(counter)
+}
+
+// ShardedMonotonicV7 generates unique UUIDv7s with low contention by keeping
+// MonotonicShards independent counters, each updated with a lock-free
+// compare-and-swap and padded to its own cache line. Every call picks a shard
+// at random using the runtime's per-thread generator, so concurrent callers
+// rarely touch the same counter.
+//
+// UUIDs are globally unique because the shard ID is part of the UUID, and
+// UUIDs from one shard strictly increase. Across shards ordering is k-sorted:
+// UUIDs generated at least a millisecond apart sort in generation order, but
+// those from the same millisecond may not, even from one goroutine. A shard
+// whose counter overflows borrows the next millisecond, which widens that
+// bound for its UUIDs. Use MonotonicV7 when strict ordering matters more than
+// throughput. The zero value is ready to use.
+type ShardedMonotonicV7 struct {
+	shards [MonotonicShards]monotonicShard
+}
+
+// monotonicShard packs the last timestamp (48 bits) and counter (16 bits)
+// into one word so both update atomically
+type monotonicShard struct {
+	state atomic.Uint64
+	_     [56]byte
+}
+
+// Generate returns the next UUIDv7 from a randomly chosen shard
+func (g *ShardedMonotonicV7) Generate() string {
+	return mustGenerate(g.GenerateContext(context.Background()))
+}
+
+// GenerateContext is Generate bounded by ctx: it returns ctx.Err() once ctx
+// is done, without advancing any counter
+func (g *ShardedMonotonicV7) GenerateContext(ctx context.Context) (string, error) {
+	random, err := randomBytesContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	now := unixMillis48(currentTime())
+	id := rand.Uint32() % MonotonicShards
+	shard := &g.shards[id]
+
+	for {
+		old := shard.state.Load()
+		ms, counter := nextMonotonic(old>>monotonicCounterBits, old&monotonicCounterMask, now, random)
+		if shard.state.CompareAndSwap(old, ms<<monotonicCounterBits|counter) {
+			return FormatUUID(buildMonotonicV7(ms, counter, byte(id), random)), nil
+		}
+	}
+}
+
+// nextMonotonic advances a counter given the last timestamp and counter used
+// and the current time. A clock that has gone backwards is treated as not
+// having moved, so output never decreases.
+func nextMonotonic(lastMs, counter, now uint64, random [16]byte) (uint64, uint64) {
+	if now > lastMs {
+		return now, randomCounterStart(random)
+	}
+	if counter < monotonicCounterMask {
+		return lastMs, counter + 1
+	}
+	return lastMs + 1, randomCounterStart(random)
+}
+
+// randomCounterStart draws a counter start below 2^15 from the random bytes
+// that are otherwise overwritten by the counter
+func randomCounterStart(random [16]byte) uint64 {
+	return (uint64(random[6])<<8 | uint64(random[7])) & (monotonicCounterMask >> 1)
+}
+
+// buildMonotonicV7 lays out a monotonic UUIDv7; see the layout above
+func buildMonotonicV7(ms, counter uint64, shard byte, random [16]byte) [16]byte {
+	uuid := random
+	uuid[0] = byte(ms >> 40)
+	uuid[1] = byte(ms >> 32)
+	uuid[2] = byte(ms >> 24)
+	uuid[3] = byte(ms >> 16)
+	uuid[4] = byte(ms >> 8)
+	uuid[5] = byte(ms)
+	uuid[6] = 0x70 | byte(counter>>12)
+	uuid[7] = byte(counter >> 4)
+	// Variant, the low 4 counter bits, then the top 2 shard bits
+	uuid[8] = 0x80 | byte(counter&0x0f)<<2 | shard>>4
+	uuid[9] = shard<<4 | uuid[9]&0x0f
+	return uuid
+}
@@ -0,0 +1,234 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stressCount is the number of UUIDs the uniqueness tests generate. Set
+// UUID_STRESS_COUNT to run them over tens of millions.
+func stressCount(t *testing.T) int {
+	if v := os.Getenv("UUID_STRESS_COUNT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			t.Fatalf("Invalid UUID_STRESS_COUNT: %v", err)
+		}
+		return n
+	}
+	if testing.Short() {
+		return 1 << 16
+	}
+	return 1 << 20
+}
+
+// generateConcurrently runs generate total times across goroutines and
+// returns each goroutine's output in order
+func generateConcurrently(goroutines, total int, generate func() string) [][][16]byte {
+	results := make([][][16]byte, goroutines)
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out := make([][16]byte, 0, total/goroutines)
+			for range total / goroutines {
+				uuid, _ := ParseUUID(generate())
+				out = append(out, uuid)
+			}
+			results[g] = out
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// assertUnique fails if any UUID appears twice
+func assertUnique(t *testing.T, results [][][16]byte) {
+	t.Helper()
+	all := slices.Concat(results...)
+	slices.SortFunc(all, func(a, b [16]byte) int { return bytes.Compare(a[:], b[:]) })
+	for i := 1; i < len(all); i++ {
+		if all[i] == all[i-1] {
+			t.Fatalf("Duplicate UUID %s among %d", FormatUUID(all[i]), len(all))
+		}
+	}
+}
+
+func TestBuildMonotonicV7Layout(t *testing.T) {
+	random := [16]byte{15: 0xff, 14: 0xff, 13: 0xff, 12: 0xff, 11: 0xff, 10: 0xff, 9: 0xff}
+	uuid := buildMonotonicV7(0x0188b733b800, 0xabcd, 0x2b, random)
+	if got := FormatUUID(uuid); got != "0188b733-b800-7abc-b6bf-ffffffffffff" {
+		t.Errorf("Unexpected layout %s", got)
+	}
+	details := Inspect(uuid)
+	if details.Version != 7 || details.Variant != VariantRFC || details.Timestamp.UnixMilli() != 0x0188b733b800 {
+		t.Errorf("Expected an RFC UUIDv7 at the given time, got %+v", details)
+	}
+}
+
+func TestNextMonotonic(t *testing.T) {
+	random := [16]byte{6: 0xff, 7: 0xff}
+	tests := []struct {
+		name                string
+		lastMs, counter, at uint64
+		ms, next            uint64
+	}{
+		{"new millisecond", 10, 500, 11, 11, 0x7fff},
+		{"same millisecond", 10, 500, 10, 10, 501},
+		{"clock went backwards", 10, 500, 9, 10, 501},
+		{"counter overflow", 10, monotonicCounterMask, 10, 11, 0x7fff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms, next := nextMonotonic(tt.lastMs, tt.counter, tt.at, random)
+			if ms != tt.ms || next != tt.next {
+				t.Errorf("Expected (%d, %d), got (%d, %d)", tt.ms, tt.next, ms, next)
+			}
+		})
+	}
+}
+
+func TestMonotonicV7Ordering(t *testing.T) {
+	// A frozen clock forces every UUID into the counter, including overflow
+	SetClock(func() time.Time { return time.UnixMilli(1700000000000) })
+	defer SetClock(nil)
+
+	var g MonotonicV7
+	previous := g.Generate()
+	for range 3 * monotonicCounterMask {
+		next := g.Generate()
+		if next <= previous {
+			t.Fatalf("Expected %s > %s", next, previous)
+		}
+		previous = next
+	}
+}
+
+func TestMonotonicV7Restore(t *testing.T) {
+	SetClock(func() time.Time { return time.UnixMilli(1700000000000) })
+	defer SetClock(nil)
+
+	var first MonotonicV7
+	if last, counter := first.State(); last.UnixMilli() != 0 || counter != 0 {
+		t.Errorf("Expected a zero state before the first UUIDv7, got %s, %d", last, counter)
+	}
+	previous := first.Generate()
+	last, counter := first.State()
+	if last.UnixMilli() != 1700000000000 {
+		t.Errorf("Expected the state to record the clock, got %s", last)
+	}
+
+	// A second generator continuing from the saved state, in the same
+	// millisecond, still sorts after the first
+	var second MonotonicV7
+	second.Restore(last, counter)
+	if next := second.Generate(); next <= previous {
+		t.Errorf("Expected %s > %s", next, previous)
+	}
+	if _, got := second.State(); got != counter+1 {
+		t.Errorf("Expected the counter to continue from %d, got %d", counter, got)
+	}
+
+	// A saved state ahead of the clock is kept, not reset
+	var third MonotonicV7
+	third.Restore(last.Add(time.Second), 5)
+	if next := third.Generate(); Inspect(mustParse(t, next)).Timestamp.UnixMilli() != 1700000001000 {
+		t.Errorf("Expected the UUIDv7 to continue at the saved timestamp, got %s", next)
+	}
+}
+
+func TestMonotonicV7Concurrent(t *testing.T) {
+	var g MonotonicV7
+	results := generateConcurrently(8, stressCount(t), g.Generate)
+	for _, out := range results {
+		for i := 1; i < len(out); i++ {
+			if bytes.Compare(out[i][:], out[i-1][:]) <= 0 {
+				t.Fatalf("Expected each goroutine's UUIDs to increase")
+			}
+		}
+	}
+	assertUnique(t, results)
+}
+
+func TestShardedMonotonicV7Concurrent(t *testing.T) {
+	var g ShardedMonotonicV7
+	results := generateConcurrently(64, stressCount(t), g.Generate)
+	assertUnique(t, results)
+
+	// Within a shard UUIDs strictly increase; across shards they are k-sorted
+	// within a millisecond
+	perShard := map[byte][][16]byte{}
+	for _, out := range results {
+		for _, uuid := range out {
+			shard := (uuid[8]&0x03)<<4 | uuid[9]>>4
+			perShard[shard] = append(perShard[shard], uuid)
+		}
+	}
+	if len(perShard) != MonotonicShards {
+		t.Errorf("Expected all %d shards to be used, got %d", MonotonicShards, len(perShard))
+	}
+	for _, out := range results {
+		for i := 1; i < len(out); i++ {
+			prev, cur := Inspect(out[i-1]).Timestamp, Inspect(out[i]).Timestamp
+			if cur.Before(prev.Add(-time.Millisecond)) {
+				t.Fatalf("UUIDs from one goroutine went back more than 1ms: %v then %v", prev, cur)
+			}
+		}
+	}
+}
+
+func TestShardedMonotonicV7ShardOrdering(t *testing.T) {
+	SetClock(func() time.Time { return time.UnixMilli(1700000000000) })
+	defer SetClock(nil)
+
+	var g ShardedMonotonicV7
+	last := map[byte]string{}
+	for range 4 * monotonicCounterMask {
+		uuid := g.Generate()
+		b, _ := ParseUUID(uuid)
+		shard := (b[8]&0x03)<<4 | b[9]>>4
+		if prev, ok := last[shard]; ok && uuid <= prev {
+			t.Fatalf("Shard %d went from %s to %s", shard, prev, uuid)
+		}
+		last[shard] = uuid
+	}
+}
+
+// benchmarkConcurrent splits b.N generations across a fixed number of goroutines
+func benchmarkConcurrent(b *testing.B, goroutines int, generate func() string) {
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		n := b.N / goroutines
+		if g < b.N%goroutines {
+			n++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range n {
+				generate()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkMonotonicV7(b *testing.B) {
+	for _, goroutines := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("mutex/goroutines=%d", goroutines), func(b *testing.B) {
+			var g MonotonicV7
+			benchmarkConcurrent(b, goroutines, g.Generate)
+		})
+		b.Run(fmt.Sprintf("sharded/goroutines=%d", goroutines), func(b *testing.B) {
+			var g ShardedMonotonicV7
+			benchmarkConcurrent(b, goroutines, g.Generate)
+		})
+	}
+}
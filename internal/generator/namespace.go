@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"strings"
+)
+
+// The well-known namespaces from RFC 9562 section 6.6
+var (
+	NamespaceDNS  = [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceURL  = [16]byte{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceOID  = [16]byte{0x6b, 0xa7, 0xb8, 0x12, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceX500 = [16]byte{0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+)
+
+// namespaceKeywords names the well-known namespaces, in display order
+var namespaceKeywords = []struct {
+	Keyword   string
+	Namespace [16]byte
+}{
+	{"dns", NamespaceDNS},
+	{"url", NamespaceURL},
+	{"oid", NamespaceOID},
+	{"x500", NamespaceX500},
+}
+
+// NamespaceKeywords returns the keywords of the well-known namespaces
+func NamespaceKeywords() []string {
+	keywords := make([]string, len(namespaceKeywords))
+	for i, k := range namespaceKeywords {
+		keywords[i] = k.Keyword
+	}
+	return keywords
+}
+
+// LookupNamespace returns the well-known namespace with the given keyword
+func LookupNamespace(keyword string) ([16]byte, bool) {
+	for _, k := range namespaceKeywords {
+		if k.Keyword == keyword {
+			return k.Namespace, true
+		}
+	}
+	return [16]byte{}, false
+}
+
+// NewV5 derives the name-based UUIDv5 of name within namespace: the first 16
+// bytes of SHA-1(namespace || name) with the version and variant bits set
+func NewV5(namespace [16]byte, name string) [16]byte {
+	uuid, _ := NewNameBasedFromReader(5, namespace, strings.NewReader(name))
+	return uuid
+}
+
+// ParseNamespace parses a namespace given as a keyword (dns, url, oid, or
+// x500) or a UUID, such as the value of a --namespace flag
+func ParseNamespace(value string) ([16]byte, error) {
+	if namespace, ok := LookupNamespace(value); ok {
+		return namespace, nil
+	}
+	namespace, err := ParseUUID(value)
+	if err != nil {
+		return [16]byte{}, classify(ErrInvalidFormat, "invalid namespace '%s': expected a keyword (%s) or a UUID in the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", value, strings.Join(NamespaceKeywords(), ", "))
+	}
+	return namespace, nil
+}
+
+// GenerateUUIDv5 returns the name-based UUIDv5 of name within namespace,
+// given as a keyword (dns, url, oid, or x500) or a UUID. The result matches
+// RFC 4122 section 4.3, and so Python's uuid.uuid5, for the same inputs.
+func GenerateUUIDv5(namespace, name string) (string, error) {
+	ns, err := ParseNamespace(namespace)
+	if err != nil {
+		return "", err
+	}
+	return GenerateUUIDv5WithNamespace(ns, name), nil
+}
+
+// GenerateUUIDv5WithNamespace is GenerateUUIDv5 for a namespace that has
+// already been parsed, such as a private namespace held as a constant
+func GenerateUUIDv5WithNamespace(namespace [16]byte, name string) string {
+	return FormatUUID(NewV5(namespace, name))
+}
+
+// NewV8SHA256 derives a name-based UUIDv8 the same way as NewV5 but with
+// SHA-256, following the example in RFC 9562 appendix B.2
+func NewV8SHA256(namespace [16]byte, name string) [16]byte {
+	uuid, _ := NewNameBasedFromReader(8, namespace, strings.NewReader(name))
+	return uuid
+}
+
+// NewNameBasedFromReader derives a name-based UUID whose name is read from r,
+// so large inputs such as files need not be held in memory. Version 5 hashes
+// with SHA-1 and version 8 with SHA-256.
+func NewNameBasedFromReader(version int, namespace [16]byte, r io.Reader) ([16]byte, error) {
+	var h hash.Hash
+	switch version {
+	case 5:
+		h = sha1.New()
+	case 8:
+		h = sha256.New()
+	default:
+		return [16]byte{}, wrongVersion([]int{5, 8}, version, "name-based UUIDs are version 5 or 8, got %d", version)
+	}
+	h.Write(namespace[:])
+	if _, err := io.Copy(h, r); err != nil {
+		return [16]byte{}, err
+	}
+	var uuid [16]byte
+	copy(uuid[:], h.Sum(nil))
+	uuid[6] = (uuid[6] & 0x0f) | byte(version)<<4
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return uuid, nil
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewV5(t *testing.T) {
+	// The first vector is the uuid5 example in Python's documentation
+	tests := []struct {
+		namespace [16]byte
+		name      string
+		expected  string
+	}{
+		{NamespaceDNS, "python.org", "886313e1-3b8a-5372-9b90-0c9aee199e5d"},
+		{NamespaceURL, "https://example.com/", "dd2c1780-811a-5296-81c5-178a0ef488bc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatUUID(NewV5(tt.namespace, tt.name)); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateUUIDv5(t *testing.T) {
+	// Expected values from Python's uuid.uuid5
+	tests := []struct {
+		namespace string
+		name      string
+		expected  string
+	}{
+		{"dns", "example.com", "cfbff0d1-9375-5685-968c-48ce8b15ae17"},
+		{"url", "https://example.com/a", "6639460f-3425-5329-8097-a58f06127860"},
+		{"dns", "", "4ebd0208-8328-5d69-8c44-ec50939c0967"},
+		{"919108f7-52d1-4320-9bac-f847db4148a8", "h\u00e9llo", "117b709e-9f17-51d9-9689-1021e492bab9"},
+		{"6BA7B810-9DAD-11D1-80B4-00C04FD430C8", "example.com", "cfbff0d1-9375-5685-968c-48ce8b15ae17"},
+	}
+	for _, tt := range tests {
+		got, err := GenerateUUIDv5(tt.namespace, tt.name)
+		if err != nil || got != tt.expected {
+			t.Errorf("GenerateUUIDv5(%q, %q): expected %s, got %s, %v", tt.namespace, tt.name, tt.expected, got, err)
+		}
+	}
+
+	if _, err := GenerateUUIDv5("example", "x"); !errors.Is(err, ErrInvalidFormat) || !strings.Contains(err.Error(), "invalid namespace 'example'") {
+		t.Errorf("Expected an invalid namespace error, got: %v", err)
+	}
+}
+
+func TestParseNamespace(t *testing.T) {
+	private := mustParse(t, "919108f7-52d1-4320-9bac-f847db4148a8")
+	for value, expected := range map[string][16]byte{
+		"x500":                                 NamespaceX500,
+		"919108f7-52d1-4320-9bac-f847db4148a8": private,
+	} {
+		if got, err := ParseNamespace(value); err != nil || got != expected {
+			t.Errorf("ParseNamespace(%q): expected %x, got %x, %v", value, expected, got, err)
+		}
+	}
+
+	_, err := ParseNamespace("6ba7b810-9dad")
+	if !errors.Is(err, ErrInvalidFormat) || !strings.Contains(err.Error(), "expected a keyword (dns, url, oid, x500) or a UUID in the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx") {
+		t.Errorf("Expected an error listing the accepted forms, got: %v", err)
+	}
+
+	// The parsed form gives the same UUID as the string form
+	if got := GenerateUUIDv5WithNamespace(private, "h\u00e9llo"); got != "117b709e-9f17-51d9-9689-1021e492bab9" {
+		t.Errorf("Expected the Python uuid5 value, got %s", got)
+	}
+}
+
+func TestNewV8SHA256(t *testing.T) {
+	// RFC 9562 appendix B.2
+	if got := FormatUUID(NewV8SHA256(NamespaceDNS, "www.example.com")); got != "5c146b14-3c52-8afd-938a-375d0df1fbf6" {
+		t.Errorf("Expected the RFC 9562 example, got %s", got)
+	}
+}
+
+func TestNewNameBasedFromReader(t *testing.T) {
+	uuid, err := NewNameBasedFromReader(5, NamespaceDNS, strings.NewReader("python.org"))
+	if err != nil || uuid != NewV5(NamespaceDNS, "python.org") {
+		t.Errorf("Expected the reader to match NewV5, got %s, %v", FormatUUID(uuid), err)
+	}
+	if _, err := NewNameBasedFromReader(3, NamespaceDNS, strings.NewReader("")); err == nil || !strings.Contains(err.Error(), "version 5 or 8, got 3") {
+		t.Errorf("Expected an unsupported version error, got: %v", err)
+	}
+}
+
+func TestLookupNamespace(t *testing.T) {
+	if !slices.Equal(NamespaceKeywords(), []string{"dns", "url", "oid", "x500"}) {
+		t.Errorf("Unexpected keywords %v", NamespaceKeywords())
+	}
+	for _, keyword := range NamespaceKeywords() {
+		ns, ok := LookupNamespace(keyword)
+		if !ok || FormatUUID(ns)[8:] != "-9dad-11d1-80b4-00c04fd430c8" {
+			t.Errorf("Unexpected namespace for %s: %s", keyword, FormatUUID(ns))
+		}
+	}
+	if _, ok := LookupNamespace("DNS"); ok {
+		t.Error("Keywords should be case-sensitive")
+	}
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// NanoIDAlphabet is the default URL-safe NanoID alphabet of 64 characters
+const NanoIDAlphabet = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+
+// DefaultNanoIDLength gives roughly the collision resistance of UUIDv4
+const DefaultNanoIDLength = 21
+
+// GenerateNanoID returns a random NanoID of length characters drawn from
+// alphabet. Alphabets whose size is not a power of two use rejection sampling:
+// random bytes are masked to the next power of two and values past the end of
+// the alphabet are discarded, so every character is equally likely.
+func GenerateNanoID(length int, alphabet string) (string, error) {
+	if err := validateNanoID(length, alphabet); err != nil {
+		return "", err
+	}
+
+	size := len(alphabet)
+	mask := byte(1<<bits.Len(uint(size-1)) - 1)
+	// Read enough bytes per batch that one batch usually suffices, as the
+	// reference implementation does
+	step := (8*int(mask)*length + 5*size - 1) / (5 * size)
+
+	var id strings.Builder
+	id.Grow(length)
+	batch := make([]byte, step)
+	for {
+		readEntropy(batch)
+		for _, b := range batch {
+			if i := int(b & mask); i < size {
+				id.WriteByte(alphabet[i])
+				if id.Len() == length {
+					return id.String(), nil
+				}
+			}
+		}
+	}
+}
+
+func validateNanoID(length int, alphabet string) error {
+	if length < 1 {
+		return fmt.Errorf("nanoid length must be at least 1, got %d", length)
+	}
+	if len(alphabet) < 2 {
+		return fmt.Errorf("nanoid alphabet must have at least 2 characters, got %d", len(alphabet))
+	}
+	var seen [128]bool
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c < 0x21 || c > 0x7e {
+			return fmt.Errorf("invalid character at position %d in nanoid alphabet, expected printable ASCII", i+1)
+		}
+		if seen[c] {
+			return fmt.Errorf("duplicate character '%c' at position %d in nanoid alphabet", c, i+1)
+		}
+		seen[c] = true
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateNanoIDDefaults(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id, err := GenerateNanoID(DefaultNanoIDLength, NanoIDAlphabet)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(id) != 21 {
+			t.Fatalf("Expected 21 characters, got %q", id)
+		}
+		for _, c := range id {
+			if !strings.ContainsRune(NanoIDAlphabet, c) {
+				t.Fatalf("Character %q of %s is not in the alphabet", c, id)
+			}
+		}
+		if seen[id] {
+			t.Fatalf("Duplicate NanoID %s", id)
+		}
+		seen[id] = true
+	}
+
+	if len(NanoIDAlphabet) != 64 {
+		t.Errorf("The default alphabet should have 64 characters, got %d", len(NanoIDAlphabet))
+	}
+	if err := validateNanoID(21, NanoIDAlphabet); err != nil {
+		t.Errorf("The default alphabet should be valid: %v", err)
+	}
+}
+
+// TestGenerateNanoIDNoModuloBias uses a 3-character alphabet, where masking a
+// byte to 2 bits and reducing modulo 3 would pick the first character half the
+// time. A chi-squared test with 2 degrees of freedom fails above 13.82, which a
+// uniform generator exceeds with probability 0.001.
+func TestGenerateNanoIDNoModuloBias(t *testing.T) {
+	const alphabet = "abc"
+	const samples = 30000
+
+	id, err := GenerateNanoID(samples, alphabet)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := float64(samples) / float64(len(alphabet))
+	var chiSquared float64
+	for _, c := range alphabet {
+		observed := float64(strings.Count(id, string(c)))
+		chiSquared += (observed - expected) * (observed - expected) / expected
+	}
+	if chiSquared > 13.82 {
+		t.Errorf("Character frequencies are not uniform (chi-squared %.2f)", chiSquared)
+	}
+}
+
+func TestGenerateNanoIDErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		length   int
+		alphabet string
+		contains string
+	}{
+		{"zero length", 0, NanoIDAlphabet, "length must be at least 1, got 0"},
+		{"one character", 21, "a", "at least 2 characters, got 1"},
+		{"duplicate", 21, "abca", "duplicate character 'a' at position 4"},
+		{"space", 21, "ab c", "invalid character at position 3"},
+		{"non-ASCII", 21, "abé", "invalid character at position 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateNanoID(tt.length, tt.alphabet)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"time"
+)
+
+// ObjectIDFromUUID converts a time-based UUID into a MongoDB ObjectID whose
+// 4-byte timestamp holds the UUID's embedded time truncated to whole seconds.
+// The remaining 8 bytes are random, or derived from a SHA-256 of the UUID when
+// deterministic is set so the same UUID always yields the same ObjectID.
+func ObjectIDFromUUID(uuid [16]byte, deterministic bool) (string, error) {
+	details := Inspect(uuid)
+	if details.Timestamp == nil {
+		return "", wrongVersion([]int{1, 6, 7}, details.Version, "UUID version %d has no embedded timestamp", details.Version)
+	}
+	seconds := details.Timestamp.Unix()
+	if seconds < 0 || seconds > math.MaxUint32 {
+		return "", classify(ErrTimestampOutOfRange, "timestamp %s is outside the ObjectID range (1970 to 2106)", details.Timestamp.UTC().Format(time.RFC3339))
+	}
+
+	var tail [8]byte
+	if deterministic {
+		sum := sha256.Sum256(uuid[:])
+		copy(tail[:], sum[:])
+	} else {
+		random := randomBytes()
+		copy(tail[:], random[:])
+	}
+
+	var id [12]byte
+	id[0] = byte(seconds >> 24)
+	id[1] = byte(seconds >> 16)
+	id[2] = byte(seconds >> 8)
+	id[3] = byte(seconds)
+	copy(id[4:], tail[:])
+	return hex.EncodeToString(id[:]), nil
+}
+
+// UUIDv7FromObjectID converts a MongoDB ObjectID (24 hex digits) into a
+// UUIDv7 carrying its timestamp. ObjectIDs only have second precision, so the
+// millisecond part of the UUID's timestamp is always zero. The random bits are
+// random, or derived from a SHA-256 of the ObjectID when deterministic is set.
+func UUIDv7FromObjectID(value string, deterministic bool) ([16]byte, error) {
+	var uuid [16]byte
+	if len(value) != 24 {
+		return uuid, classify(ErrInvalidFormat, "invalid length %d for objectid, expected 24 hex digits", len(value))
+	}
+	for i := 0; i < len(value); i++ {
+		if !isHexDigit(value[i]) {
+			return uuid, classify(ErrInvalidFormat, "invalid character '%c' at position %d, expected a hex digit", value[i], i+1)
+		}
+	}
+	id, _ := hex.DecodeString(value)
+
+	seconds := int64(id[0])<<24 | int64(id[1])<<16 | int64(id[2])<<8 | int64(id[3])
+	var random [16]byte
+	if deterministic {
+		sum := sha256.Sum256(id)
+		copy(random[:], sum[:])
+	} else {
+		random = randomBytes()
+	}
+	return ParseUUID(NewV7FromBytes(time.Unix(seconds, 0), random))
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var objectIDPattern = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+func TestObjectIDFromUUID(t *testing.T) {
+	// RFC 9562 UUIDv7 example: 2022-02-22T19:22:22Z is 0x621537ee seconds
+	uuid, _ := ParseUUID("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+
+	for _, deterministic := range []bool{false, true} {
+		id, err := ObjectIDFromUUID(uuid, deterministic)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !objectIDPattern.MatchString(id) {
+			t.Fatalf("Expected 24 lowercase hex digits, got %s", id)
+		}
+		if id[:8] != "621537ee" {
+			t.Errorf("Expected the timestamp prefix 621537ee, got %s", id[:8])
+		}
+	}
+
+	first, _ := ObjectIDFromUUID(uuid, true)
+	second, _ := ObjectIDFromUUID(uuid, true)
+	if first != "621537ee4e2e22477383ff21" || second != first {
+		t.Errorf("Deterministic conversion should be stable, got %s and %s", first, second)
+	}
+}
+
+func TestObjectIDFromUUIDErrors(t *testing.T) {
+	v4, _ := ParseUUID("919108f7-52d1-4320-9bac-f847db4148a8")
+	if _, err := ObjectIDFromUUID(v4, false); err == nil || err.Error() != "UUID version 4 has no embedded timestamp" {
+		t.Errorf("Expected a missing timestamp error, got: %v", err)
+	}
+
+	// A UUIDv6 from 1969 has a timestamp the 32-bit seconds field cannot hold
+	var node [6]byte
+	v6 := buildUUIDv6(time.Date(1969, 7, 20, 0, 0, 0, 0, time.UTC), 0, node)
+	if _, err := ObjectIDFromUUID(v6, false); err == nil {
+		t.Error("Expected an out of range error for a pre-1970 timestamp")
+	}
+}
+
+func TestUUIDv7FromObjectID(t *testing.T) {
+	// 0x507f1f77 is 2012-10-17T21:13:27Z
+	want := time.Date(2012, 10, 17, 21, 13, 27, 0, time.UTC)
+
+	for _, deterministic := range []bool{false, true} {
+		uuid, err := UUIDv7FromObjectID("507f1f77bcf86cd799439011", deterministic)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		details := Inspect(uuid)
+		if details.Version != 7 || details.Variant != VariantRFC {
+			t.Errorf("Expected an RFC UUIDv7, got %+v", details)
+		}
+		if !details.Timestamp.Equal(want) {
+			t.Errorf("Expected %v, got %v", want, details.Timestamp)
+		}
+	}
+
+	first, _ := UUIDv7FromObjectID("507f1f77bcf86cd799439011", true)
+	second, _ := UUIDv7FromObjectID("507f1f77bcf86cd799439011", true)
+	if first != second {
+		t.Errorf("Deterministic conversion should be stable, got %s and %s", FormatUUID(first), FormatUUID(second))
+	}
+}
+
+func TestObjectIDRoundTripSecondPrecision(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		uuid, _ := ParseUUID(GenerateUUIDv7())
+		id, err := ObjectIDFromUUID(uuid, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := UUIDv7FromObjectID(id, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := Inspect(uuid).Timestamp.Truncate(time.Second)
+		if !Inspect(back).Timestamp.Equal(original) {
+			t.Errorf("Round trip of %s via %s lost more than sub-second precision", FormatUUID(uuid), id)
+		}
+	}
+}
+
+func TestUUIDv7FromObjectIDErrors(t *testing.T) {
+	tests := []struct {
+		value    string
+		contains string
+	}{
+		{"507f1f77bcf86cd79943901", "invalid length 23 for objectid"},
+		{"507f1f77bcf86cd79943901g", "invalid character 'g' at position 24"},
+	}
+
+	for _, tt := range tests {
+		if _, err := UUIDv7FromObjectID(tt.value, false); err == nil || !strings.Contains(err.Error(), tt.contains) {
+			t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+		}
+	}
+}
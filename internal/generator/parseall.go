@@ -0,0 +1,81 @@
+package generator
+
+// ParseOption configures ParseAll and Partition
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	strict bool
+}
+
+// Strict accepts only canonical RFC 9562 UUIDs, as Validate does, instead of
+// every form ParseUUID accepts. Errors are then *ValidationError values.
+func Strict() ParseOption {
+	return func(o *parseOptions) { o.strict = true }
+}
+
+// parserFor resolves options once into the function applied to each input
+func parserFor(opts []ParseOption) func(string) ([16]byte, error) {
+	var o parseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.strict {
+		return ParseUUID
+	}
+	return func(value string) ([16]byte, error) {
+		if err := Validate(value); err != nil {
+			return [16]byte{}, err
+		}
+		return decodeCanonical(value)
+	}
+}
+
+// ParseAll parses every input without stopping at the first failure. The
+// results are aligned with inputs: errs[i] is nil when inputs[i] parsed into
+// uuids[i], and uuids[i] is the zero UUID otherwise. Both slices are nil for
+// empty input.
+func ParseAll(inputs []string, opts ...ParseOption) (uuids [][16]byte, errs []error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+	parse := parserFor(opts)
+	uuids = make([][16]byte, len(inputs))
+	errs = make([]error, len(inputs))
+	for i, value := range inputs {
+		uuids[i], errs[i] = parse(value)
+	}
+	return uuids, errs
+}
+
+// CountValid returns how many of the errors returned by ParseAll are nil
+func CountValid(errs []error) int {
+	valid := 0
+	for _, err := range errs {
+		if err == nil {
+			valid++
+		}
+	}
+	return valid
+}
+
+// ParseFailure is an input that Partition could not parse
+type ParseFailure struct {
+	Index int
+	Input string
+	Err   error
+}
+
+// Partition parses every input and splits the results into the UUIDs that
+// parsed, in input order, and the failures, each with its index in inputs
+func Partition(inputs []string, opts ...ParseOption) (valid [][16]byte, invalid []ParseFailure) {
+	parse := parserFor(opts)
+	for i, value := range inputs {
+		uuid, err := parse(value)
+		if err != nil {
+			invalid = append(invalid, ParseFailure{Index: i, Input: value, Err: err})
+			continue
+		}
+		valid = append(valid, uuid)
+	}
+	return valid, invalid
+}
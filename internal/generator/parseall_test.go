@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var parseAllInputs = []string{
+	"919108f7-52d1-4320-9bac-f847db4148a8",
+	"not-a-uuid",
+	"{919108F7-52D1-4320-9BAC-F847DB4148A8}",
+	"",
+	"919108f752d143209bacf847db4148a8",
+	"919108f7-52d1-0320-9bac-f847db4148a8",
+	"919108F7-52D1-4320-9BAC-F847DB4148A8",
+}
+
+func TestParseAllLenient(t *testing.T) {
+	uuids, errs := ParseAll(parseAllInputs)
+	if len(uuids) != len(parseAllInputs) || len(errs) != len(parseAllInputs) {
+		t.Fatalf("Expected results aligned with %d inputs, got %d and %d", len(parseAllInputs), len(uuids), len(errs))
+	}
+
+	expectedValid := []bool{true, false, true, false, true, true, true}
+	for i, valid := range expectedValid {
+		if (errs[i] == nil) != valid {
+			t.Errorf("Input %d %q: expected valid=%v, got error %v", i, parseAllInputs[i], valid, errs[i])
+		}
+		if valid {
+			single, _ := ParseUUID(parseAllInputs[i])
+			if uuids[i] != single {
+				t.Errorf("Input %d: expected the same result as ParseUUID", i)
+			}
+		} else if uuids[i] != [16]byte{} {
+			t.Errorf("Input %d: expected the zero UUID for a failure", i)
+		}
+	}
+	if got := CountValid(errs); got != 5 {
+		t.Errorf("Expected 5 valid inputs, got %d", got)
+	}
+}
+
+func TestParseAllStrict(t *testing.T) {
+	_, errs := ParseAll(parseAllInputs, Strict())
+
+	reasons := make([]string, len(errs))
+	for i, err := range errs {
+		var validationErr *ValidationError
+		switch {
+		case err == nil:
+			reasons[i] = "ok"
+		case errors.As(err, &validationErr):
+			reasons[i] = validationErr.Reason
+		default:
+			t.Fatalf("Input %d: expected a *ValidationError, got %T", i, err)
+		}
+	}
+	if got := fmt.Sprint(reasons); got != "[ok length length length length version ok]" {
+		t.Errorf("Unexpected strict results %s", got)
+	}
+}
+
+func TestParseAllEmpty(t *testing.T) {
+	uuids, errs := ParseAll(nil)
+	if uuids != nil || errs != nil {
+		t.Errorf("Expected nil results for empty input, got %v, %v", uuids, errs)
+	}
+	if CountValid(errs) != 0 {
+		t.Error("Expected no valid inputs")
+	}
+}
+
+func TestPartition(t *testing.T) {
+	valid, invalid := Partition(parseAllInputs, Strict())
+	if len(valid) != 2 {
+		t.Fatalf("Expected 2 valid UUIDs, got %d", len(valid))
+	}
+	if FormatUUID(valid[0]) != parseAllInputs[0] || FormatUUID(valid[1]) != parseAllInputs[0] {
+		t.Errorf("Expected valid UUIDs in input order, got %s and %s", FormatUUID(valid[0]), FormatUUID(valid[1]))
+	}
+
+	var indexes []int
+	for _, f := range invalid {
+		if parseAllInputs[f.Index] != f.Input || f.Err == nil {
+			t.Errorf("Failure %+v does not line up with its input", f)
+		}
+		indexes = append(indexes, f.Index)
+	}
+	if fmt.Sprint(indexes) != "[1 2 3 4 5]" {
+		t.Errorf("Unexpected failure indexes %v", indexes)
+	}
+}
+
+// parseAllBenchmarkInputs mixes valid and invalid values
+func parseAllBenchmarkInputs() []string {
+	inputs := make([]string, 10000)
+	for i := range inputs {
+		if i%10 == 0 {
+			inputs[i] = "invalid"
+		} else {
+			inputs[i] = GenerateUUIDv4()
+		}
+	}
+	return inputs
+}
+
+func BenchmarkParseAll(b *testing.B) {
+	inputs := parseAllBenchmarkInputs()
+	b.Run("ParseAll", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ParseAll(inputs, Strict())
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var uuids [][16]byte
+			var errs []error
+			for _, value := range inputs {
+				uuid, err := parserFor([]ParseOption{Strict()})(value)
+				uuids = append(uuids, uuid)
+				errs = append(errs, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package generator
+
+// hexDigits are the lowercase digits used by the canonical form
+const hexDigits = "0123456789abcdef"
+
+// canonicalHexOffsets are the positions of each byte's two hex digits in the
+// canonical 8-4-4-4-12 form
+var canonicalHexOffsets = [16]int{0, 2, 4, 6, 9, 11, 14, 16, 19, 21, 24, 26, 28, 30, 32, 34}
+
+// ParseBytes parses a UUID from a byte slice, accepting everything ParseUUID
+// does. The canonical form is decoded in place without allocating; other
+// representations fall back to ParseUUID, as do invalid values so errors
+// match.
+func ParseBytes(b []byte) ([16]byte, error) {
+	if uuid, ok := parseCanonicalBytes(b); ok {
+		return uuid, nil
+	}
+	return ParseUUID(string(b))
+}
+
+// parseCanonicalBytes decodes a 36-byte canonical UUID in either case,
+// reporting false for anything else
+func parseCanonicalBytes(b []byte) (uuid [16]byte, ok bool) {
+	if len(b) != 36 || b[8] != '-' || b[13] != '-' || b[18] != '-' || b[23] != '-' {
+		return uuid, false
+	}
+	for i, offset := range canonicalHexOffsets {
+		hi, okHi := hexValue(b[offset])
+		lo, okLo := hexValue(b[offset+1])
+		if !okHi || !okLo {
+			return [16]byte{}, false
+		}
+		uuid[i] = hi<<4 | lo
+	}
+	return uuid, true
+}
+
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// AppendCanonicalTo appends the canonical lowercase form of uuid to dst and
+// returns the extended slice. It does not allocate when dst has room for 36
+// more bytes.
+func AppendCanonicalTo(dst []byte, uuid [16]byte) []byte {
+	for i, c := range uuid {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			dst = append(dst, '-')
+		}
+		dst = append(dst, hexDigits[c>>4], hexDigits[c&0x0f])
+	}
+	return dst
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBytesMatchesParseUUID(t *testing.T) {
+	inputs := []string{
+		"d9428888-122b-11e1-b85c-61cd3cbb3210",
+		"D9428888-122B-11E1-B85C-61CD3CBB3210",
+		"d9428888-122B-11e1-B85c-61cd3cbb3210",
+		"urn:uuid:d9428888-122b-11e1-b85c-61cd3cbb3210",
+		"{d9428888-122b-11e1-b85c-61cd3cbb3210}",
+		"d9428888122b11e1b85c61cd3cbb3210",
+		"d942 8888 122b 11e1 b85c 61cd 3cbb 3210",
+		"2.25.288880212319153474311395093604839518736",
+		"",
+		"d9428888-122b-11e1-b85c-61cd3cbb321",
+		"d9428888-122b-11e1-b85c-61cd3cbb321g",
+		"d9428888x122b-11e1-b85c-61cd3cbb3210",
+		"d9428888-122b-11e1-b85c-61cd3cbb3210 ",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			want, wantErr := ParseUUID(input)
+			got, err := ParseBytes([]byte(input))
+			if got != want || errString(err) != errString(wantErr) {
+				t.Errorf("ParseBytes gave %x, %v; ParseUUID gave %x, %v", got, err, want, wantErr)
+			}
+		})
+	}
+}
+
+func TestParseBytesZeroAllocs(t *testing.T) {
+	b := []byte("D9428888-122b-11e1-b85c-61cd3cbb3210")
+	buf := make([]byte, 0, 36)
+	allocs := testing.AllocsPerRun(100, func() {
+		uuid, err := ParseBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf = AppendCanonicalTo(buf[:0], uuid)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations for a parse and reformat, got %v", allocs)
+	}
+	if string(buf) != strings.ToLower(string(b)) {
+		t.Errorf("Expected %s, got %s", strings.ToLower(string(b)), buf)
+	}
+}
+
+func TestAppendCanonicalTo(t *testing.T) {
+	uuid := [16]byte{0xd9, 0x42, 0x88, 0x88, 0x12, 0x2b, 0x11, 0xe1, 0xb8, 0x5c, 0x61, 0xcd, 0x3c, 0xbb, 0x32, 0x10}
+	got := AppendCanonicalTo([]byte("id="), uuid)
+	if string(got) != "id=d9428888-122b-11e1-b85c-61cd3cbb3210" {
+		t.Errorf("Expected the canonical form appended, got %s", got)
+	}
+	if FormatUUID(uuid) != "d9428888-122b-11e1-b85c-61cd3cbb3210" {
+		t.Errorf("Expected FormatUUID to agree, got %s", FormatUUID(uuid))
+	}
+}
+
+func FuzzParseBytes(f *testing.F) {
+	for _, seed := range []string{
+		"d9428888-122b-11e1-b85c-61cd3cbb3210",
+		"D9428888-122B-11E1-B85C-61CD3CBB3210",
+		"{d9428888-122b-11e1-b85c-61cd3cbb3210}",
+		"urn:uuid:d9428888-122b-11e1-b85c-61cd3cbb3210",
+		"d9428888122b11e1b85c61cd3cbb3210",
+		"2.25.1",
+		"d9428888-122b-11e1-b85c-61cd3cbb321\xff",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		want, wantErr := ParseUUID(string(b))
+		got, err := ParseBytes(b)
+		if got != want || errString(err) != errString(wantErr) {
+			t.Fatalf("ParseBytes(%q) gave %x, %v; ParseUUID gave %x, %v", b, got, err, want, wantErr)
+		}
+		if err == nil {
+			again, err := ParseBytes(AppendCanonicalTo(nil, got))
+			if err != nil || again != got {
+				t.Fatalf("Reformatted %x did not parse back: %x, %v", got, again, err)
+			}
+		}
+	})
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	input := []byte(GenerateUUIDv4())
+	buf := make([]byte, 0, 36)
+	b.Run("ParseBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ParseBytes(input)
+		}
+	})
+	b.Run("ParseUUID", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ParseUUID(string(input))
+		}
+	})
+	b.Run("round trip", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			uuid, _ := ParseBytes(input)
+			buf = AppendCanonicalTo(buf[:0], uuid)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package generator
+
+import "time"
+
+// RetimeUUIDv7 returns uuid with its timestamp replaced by timestamp,
+// truncated to the millisecond. Only the first 48 bits change: rand_a,
+// rand_b, and the version and variant bits are kept, so the result shares
+// every random bit with the original.
+func RetimeUUIDv7(uuid [16]byte, timestamp time.Time) ([16]byte, error) {
+	details := Inspect(uuid)
+	if details.Version != 7 || details.Variant != VariantRFC {
+		return [16]byte{}, wrongVersion([]int{7}, details.Version, "only UUIDv7s can be retimed, got version %d", details.Version)
+	}
+	if err := CheckUUIDv7Timestamp(timestamp); err != nil {
+		return [16]byte{}, err
+	}
+	ms := uint64(timestamp.UnixMilli())
+	for i := 0; i < 6; i++ {
+		uuid[i] = byte(ms >> (40 - 8*i))
+	}
+	return uuid, nil
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetimeUUIDv7(t *testing.T) {
+	original := mustParse(t, mustV7At(t, time.Date(2023, 6, 14, 10, 30, 0, 0, time.UTC)))
+	corrected := time.Date(2023, 6, 13, 22, 15, 30, 250_000_000, time.UTC)
+
+	retimed, err := RetimeUUIDv7(original, corrected)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(retimed[6:], original[6:]) {
+		t.Errorf("Expected bytes 6-15 to be kept, got %x from %x", retimed, original)
+	}
+	if bytes.Equal(retimed[:6], original[:6]) {
+		t.Errorf("Expected the timestamp bytes to change, got %x", retimed)
+	}
+	details := Inspect(retimed)
+	if details.Version != 7 || details.Variant != VariantRFC || !details.Timestamp.Equal(corrected) {
+		t.Errorf("Expected a UUIDv7 at %s, got %+v", corrected, details)
+	}
+
+	// Sub-millisecond parts of the timestamp are dropped
+	again, _ := RetimeUUIDv7(original, corrected.Add(999*time.Microsecond))
+	if again != retimed {
+		t.Errorf("Expected the same UUID within the millisecond, got %x", again)
+	}
+}
+
+func TestRetimeUUIDv7Errors(t *testing.T) {
+	v7 := mustParse(t, mustV7At(t, time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC)))
+	v4 := mustParse(t, GenerateUUIDv4())
+
+	if _, err := RetimeUUIDv7(v4, time.Now()); !errors.Is(err, ErrWrongVersion) || err.Error() != "only UUIDv7s can be retimed, got version 4" {
+		t.Errorf("Expected a wrong version error, got %v", err)
+	}
+	if _, err := RetimeUUIDv7(v7, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrTimestampOutOfRange) {
+		t.Errorf("Expected a range error before the epoch, got %v", err)
+	}
+	if _, err := RetimeUUIDv7(v7, time.UnixMilli(1<<48)); !errors.Is(err, ErrTimestampOutOfRange) {
+		t.Errorf("Expected a range error past the last timestamp, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"context"
+	"iter"
+)
+
+// Option configures the iterators returned by Seq and V7Seq
+type Option func(*seqOptions)
+
+type seqOptions struct {
+	version int
+	limit   int
+}
+
+// WithVersion selects the UUID version Seq generates: 4 (the default), 6,
+// or 7
+func WithVersion(version int) Option {
+	return func(o *seqOptions) { o.version = version }
+}
+
+// WithLimit stops the iterator after n UUIDs; without it the iterator runs
+// until the loop breaks or ctx is done
+func WithLimit(n int) Option {
+	return func(o *seqOptions) { o.limit = n }
+}
+
+// V7Seq returns an iterator over new UUIDv7s. It is Seq with WithVersion(7).
+//
+//	for u, err := range generator.V7Seq(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		ids = append(ids, u)
+//		if len(ids) == n {
+//			break
+//		}
+//	}
+func V7Seq(ctx context.Context, opts ...Option) iter.Seq2[UUID, error] {
+	return Seq(ctx, append([]Option{WithVersion(7)}, opts...)...)
+}
+
+// Seq returns an iterator over new UUIDs of the version chosen with
+// WithVersion. Nothing is generated until the loop asks for a value, and
+// generation stops as soon as the loop breaks.
+//
+// Each range over the iterator draws UUIDv7s from its own MonotonicV7, so
+// they strictly increase in the order they are yielded. When ctx is done,
+// or entropy cannot be read, the iterator yields the zero UUID with the
+// error once and stops; an unsupported version is reported the same way.
+func Seq(ctx context.Context, opts ...Option) iter.Seq2[UUID, error] {
+	o := seqOptions{version: 4}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(yield func(UUID, error) bool) {
+		var next func(ctx context.Context) ([16]byte, error)
+		switch o.version {
+		case 4:
+			next = nextV4
+		case 6:
+			next = nextV6
+		case 7:
+			next = new(MonotonicV7).next
+		default:
+			yield(UUID{}, wrongVersion([]int{4, 6, 7}, o.version, "unsupported version %d; Seq generates versions 4, 6, and 7", o.version))
+			return
+		}
+		for i := 0; o.limit <= 0 || i < o.limit; i++ {
+			if err := ctx.Err(); err != nil {
+				yield(UUID{}, err)
+				return
+			}
+			uuid, err := next(ctx)
+			if err != nil {
+				yield(UUID{}, err)
+				return
+			}
+			if !yield(UUID(uuid), nil) {
+				return
+			}
+		}
+	}
+}
+
+// nextV4 returns a UUIDv4 as bytes
+func nextV4(ctx context.Context) ([16]byte, error) {
+	random, err := randomBytesContext(ctx)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	random[6] = (random[6] & 0x0f) | 0x40
+	random[8] = (random[8] & 0x3f) | 0x80
+	return random, nil
+}
+
+// nextV6 returns a UUIDv6 with a random clock sequence and node as bytes
+func nextV6(ctx context.Context) ([16]byte, error) {
+	random, err := randomBytesContext(ctx)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	var node [6]byte
+	copy(node[:], random[10:16])
+	return buildUUIDv6(currentTime(), uint16(random[8])<<8|uint16(random[9]), node), nil
+}
@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func ExampleV7Seq() {
+	ctx := context.Background()
+	var ids []UUID
+	for u, err := range V7Seq(ctx) {
+		if err != nil {
+			panic(err)
+		}
+		ids = append(ids, u)
+		if len(ids) == 3 {
+			break
+		}
+	}
+	fmt.Println(len(ids))
+	// Output: 3
+}
+
+func TestV7SeqOrderedUnique(t *testing.T) {
+	var prev UUID
+	n := 0
+	for u, err := range V7Seq(context.Background()) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if u[6]>>4 != 7 || u[8]>>6 != 0b10 {
+			t.Fatalf("Expected a UUIDv7, got %s", u)
+		}
+		if n > 0 && bytes.Compare(prev[:], u[:]) >= 0 {
+			t.Fatalf("Expected strictly increasing UUIDs, got %s then %s", prev, u)
+		}
+		prev = u
+		n++
+		if n == 10000 {
+			break
+		}
+	}
+	if n != 10000 {
+		t.Errorf("Expected to consume 10000 UUIDs, got %d", n)
+	}
+}
+
+func TestSeqVersionsAndLimit(t *testing.T) {
+	for _, version := range []int{4, 6, 7} {
+		seen := map[UUID]bool{}
+		for u, err := range Seq(context.Background(), WithVersion(version), WithLimit(50)) {
+			if err != nil {
+				t.Fatalf("v%d: unexpected error: %v", version, err)
+			}
+			if got := int(u[6] >> 4); got != version {
+				t.Errorf("Expected version %d, got %s", version, u)
+			}
+			seen[u] = true
+		}
+		if len(seen) != 50 {
+			t.Errorf("v%d: expected 50 distinct UUIDs from WithLimit(50), got %d", version, len(seen))
+		}
+	}
+}
+
+func TestSeqCancelMidIteration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var errs []error
+	n := 0
+	for _, err := range V7Seq(ctx) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		n++
+		if n == 5 {
+			cancel()
+		}
+	}
+	if n != 5 {
+		t.Errorf("Expected 5 UUIDs before the cancel, got %d", n)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("Expected a single context.Canceled, got %v", errs)
+	}
+}
+
+func TestSeqBlockingEntropy(t *testing.T) {
+	fakeBlockingEntropy(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	for u, err := range Seq(ctx) {
+		if !errors.Is(err, context.DeadlineExceeded) || u != (UUID{}) {
+			t.Errorf("Expected the deadline error with the zero UUID, got %s, %v", u, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the iterator to stop at the deadline, took %v", elapsed)
+	}
+}
+
+func TestSeqUnsupportedVersion(t *testing.T) {
+	n := 0
+	for _, err := range Seq(context.Background(), WithVersion(3)) {
+		n++
+		if err == nil || err.Error() != "unsupported version 3; Seq generates versions 4, 6, and 7" {
+			t.Errorf("Expected an unsupported version error, got %v", err)
+		}
+	}
+	if n != 1 {
+		t.Errorf("Expected one yield, got %d", n)
+	}
+}
+
+func BenchmarkV7Seq(b *testing.B) {
+	b.ReportAllocs()
+	n := 0
+	for _, err := range V7Seq(context.Background()) {
+		if err != nil {
+			b.Fatal(err)
+		}
+		n++
+		if n == b.N {
+			break
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SignatureTagSize is the length in bytes of the tag Sign appends: the first
+// 12 bytes of the HMAC-SHA256. 96 bits keeps a signed UUID short enough for a
+// URL (16 base64url characters) while leaving forgery at one chance in 2^96
+// per guess, far beyond what an online attacker can try.
+const SignatureTagSize = 12
+
+// MinKeySize is the shortest signing key Sign and Verify accept
+const MinKeySize = 16
+
+// signatureSeparator sits between a UUID and its tag
+const signatureSeparator = "."
+
+// errSignatureMismatch is returned by Verify when no key produces the tag
+var errSignatureMismatch = errors.New("signature does not match")
+
+// signatureTag returns the truncated HMAC-SHA256 of the UUID's 16 bytes
+func signatureTag(key []byte, uuid [16]byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(uuid[:])
+	return mac.Sum(nil)[:SignatureTagSize]
+}
+
+// Sign returns the canonical form of uuid followed by a '.' and the
+// base64url (unpadded) tag of its bytes under key
+func Sign(key []byte, uuid [16]byte) (string, error) {
+	if len(key) < MinKeySize {
+		return "", fmt.Errorf("signing key is %d bytes; at least %d are required", len(key), MinKeySize)
+	}
+	return FormatUUID(uuid) + signatureSeparator + base64.RawURLEncoding.EncodeToString(signatureTag(key, uuid)), nil
+}
+
+// Verify checks a value produced by Sign against each key in turn, so
+// values signed before a key rotation still verify, and returns the UUID.
+// Tags are compared in constant time.
+func Verify(keys [][]byte, value string) ([16]byte, error) {
+	text, encodedTag, ok := strings.Cut(value, signatureSeparator)
+	if !ok {
+		return [16]byte{}, classify(ErrInvalidFormat, "missing '%s' between the UUID and its tag", signatureSeparator)
+	}
+	uuid, err := ParseUUID(text)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	tag, err := base64.RawURLEncoding.DecodeString(encodedTag)
+	if err != nil || len(tag) != SignatureTagSize {
+		return [16]byte{}, classify(ErrInvalidFormat, "tag '%s' is not %d base64url-encoded bytes", encodedTag, SignatureTagSize)
+	}
+	for _, key := range keys {
+		if len(key) < MinKeySize {
+			return [16]byte{}, fmt.Errorf("signing key is %d bytes; at least %d are required", len(key), MinKeySize)
+		}
+		if hmac.Equal(tag, signatureTag(key, uuid)) {
+			return uuid, nil
+		}
+	}
+	return [16]byte{}, errSignatureMismatch
+}
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+var (
+	signKey      = []byte("0123456789abcdef0123456789abcdef")
+	otherSignKey = []byte("fedcba9876543210fedcba9876543210")
+	signUUID     = [16]byte{0x91, 0x91, 0x08, 0xf7, 0x52, 0xd1, 0x43, 0x20, 0x9b, 0xac, 0xf8, 0x47, 0xdb, 0x41, 0x48, 0xa8}
+)
+
+func TestSignKnownAnswer(t *testing.T) {
+	signed, err := Sign(signKey, signUUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The tag is the first 12 bytes of HMAC-SHA256(key, uuid bytes), as
+	// computed independently with Python's hmac module
+	if want := "919108f7-52d1-4320-9bac-f847db4148a8.iZ5EjEwgEfR1sQ7v"; signed != want {
+		t.Errorf("Expected %s, got %s", want, signed)
+	}
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	signed, err := Sign(signKey, signUUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uuid, err := Verify([][]byte{signKey}, signed)
+	if err != nil || uuid != signUUID {
+		t.Errorf("Expected %x to verify, got %x, %v", signUUID, uuid, err)
+	}
+	// A rotated-out key is still accepted when listed
+	if _, err := Verify([][]byte{otherSignKey, signKey}, signed); err != nil {
+		t.Errorf("Expected the second key to verify, got %v", err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	signed, _ := Sign(signKey, signUUID)
+	text, tag, _ := strings.Cut(signed, ".")
+
+	flippedUUID := signUUID
+	flippedUUID[15] ^= 0x01
+	rawTag, _ := base64.RawURLEncoding.DecodeString(tag)
+	rawTag[0] ^= 0x80
+
+	tests := []struct {
+		name     string
+		keys     [][]byte
+		value    string
+		contains string
+	}{
+		{"flipped uuid bit", [][]byte{signKey}, FormatUUID(flippedUUID) + "." + tag, "signature does not match"},
+		{"flipped tag bit", [][]byte{signKey}, text + "." + base64.RawURLEncoding.EncodeToString(rawTag), "signature does not match"},
+		{"wrong key", [][]byte{otherSignKey}, signed, "signature does not match"},
+		{"no tag", [][]byte{signKey}, text, "missing '.'"},
+		{"short tag", [][]byte{signKey}, text + "." + tag[:8], "is not 12 base64url-encoded bytes"},
+		{"bad base64", [][]byte{signKey}, text + ".!!!!!!!!!!!!!!!!", "is not 12 base64url-encoded bytes"},
+		{"bad uuid", [][]byte{signKey}, "not-a-uuid." + tag, "invalid length 10"},
+		{"short key", [][]byte{[]byte("short")}, signed, "at least 16 are required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(tt.keys, tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+	if _, err := Sign([]byte("short"), signUUID); err == nil {
+		t.Error("Expected Sign to reject a short key")
+	}
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"strconv"
+	"time"
+)
+
+// TwitterEpoch is the custom epoch of Twitter's Snowflake IDs
+var TwitterEpoch = time.UnixMilli(1288834974657).UTC()
+
+// Snowflake is a decoded 64-bit Snowflake ID: a 41-bit millisecond timestamp
+// relative to a deployment-specific epoch, a 10-bit worker ID, and a 12-bit
+// sequence number. The top bit is always zero.
+type Snowflake struct {
+	ID        uint64
+	Timestamp time.Time
+	Worker    uint16
+	Sequence  uint16
+}
+
+const maxSnowflakeMillis = 1<<41 - 1
+
+// ParseSnowflake decodes a decimal Snowflake ID relative to epoch
+func ParseSnowflake(value string, epoch time.Time) (Snowflake, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return Snowflake{}, classify(ErrInvalidFormat, "invalid snowflake '%s': expected an unsigned 64-bit decimal integer", value)
+	}
+	if id>>63 != 0 {
+		return Snowflake{}, classify(ErrInvalidFormat, "invalid snowflake '%s': the sign bit is set", value)
+	}
+	return Snowflake{
+		ID:        id,
+		Timestamp: epoch.Add(time.Duration(id>>22) * time.Millisecond).UTC(),
+		Worker:    uint16(id >> 12 & 0x3ff),
+		Sequence:  uint16(id & 0xfff),
+	}, nil
+}
+
+// UUIDv7FromSnowflake embeds a Snowflake in a UUIDv7. The UUID's timestamp is
+// the Snowflake's absolute time in milliseconds, rand_a (12 bits) holds the
+// sequence, and the first 10 bits of rand_b (after the variant) hold the
+// worker ID. The remaining 52 bits are random.
+func UUIDv7FromSnowflake(s Snowflake) [16]byte {
+	uuid, _ := ParseUUID(NewV7FromBytes(s.Timestamp, randomBytes()))
+	uuid[6] = 0x70 | byte(s.Sequence>>8&0x0f)
+	uuid[7] = byte(s.Sequence)
+	uuid[8] = 0x80 | byte(s.Worker>>4&0x3f)
+	uuid[9] = byte(s.Worker&0x0f)<<4 | uuid[9]&0x0f
+	return uuid
+}
+
+// SnowflakeFromUUID recovers the Snowflake embedded by UUIDv7FromSnowflake,
+// using epoch to turn the UUID's absolute timestamp back into the ID
+func SnowflakeFromUUID(uuid [16]byte, epoch time.Time) (Snowflake, error) {
+	details := Inspect(uuid)
+	if details.Version != 7 || details.Variant != VariantRFC {
+		return Snowflake{}, wrongVersion([]int{7}, details.Version, "only UUIDv7 can carry a snowflake, got version %d", details.Version)
+	}
+	millis := details.Timestamp.Sub(epoch).Milliseconds()
+	if millis < 0 || millis > maxSnowflakeMillis {
+		return Snowflake{}, classify(ErrTimestampOutOfRange, "timestamp %s is outside the 41-bit range of epoch %s", details.Timestamp.Format(time.RFC3339Nano), epoch.UTC().Format(time.RFC3339Nano))
+	}
+
+	s := Snowflake{
+		Timestamp: *details.Timestamp,
+		Worker:    uint16(uuid[8]&0x3f)<<4 | uint16(uuid[9]>>4),
+		Sequence:  uint16(uuid[6]&0x0f)<<8 | uint16(uuid[7]),
+	}
+	s.ID = uint64(millis)<<22 | uint64(s.Worker)<<12 | uint64(s.Sequence)
+	return s, nil
+}
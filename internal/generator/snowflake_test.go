@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSnowflakeTwitterExamples(t *testing.T) {
+	// The first ID is the Tweet object example in Twitter's API documentation,
+	// created_at "Wed Oct 10 20:19:24 +0000 2018"; the second is one
+	// millisecond after the epoch
+	tests := []struct {
+		id        string
+		timestamp string
+		worker    uint16
+		sequence  uint16
+	}{
+		{"1050118621198921728", "2018-10-10T20:19:24.211Z", 347, 0},
+		{"4194304", "2010-11-04T01:42:54.658Z", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			s, err := ParseSnowflake(tt.id, TwitterEpoch)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := s.Timestamp.Format("2006-01-02T15:04:05.000Z07:00"); got != tt.timestamp {
+				t.Errorf("Expected timestamp %s, got %s", tt.timestamp, got)
+			}
+			if s.Worker != tt.worker || s.Sequence != tt.sequence {
+				t.Errorf("Expected worker %d and sequence %d, got %d and %d", tt.worker, tt.sequence, s.Worker, s.Sequence)
+			}
+		})
+	}
+}
+
+func TestParseSnowflakeCustomEpoch(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := uint64(1500)<<22 | 5<<12 | 7
+	s, err := ParseSnowflake("6291476487", epoch)
+	if err != nil || s.ID != id {
+		t.Fatalf("Expected ID %d, got %+v, %v", id, s, err)
+	}
+	if !s.Timestamp.Equal(epoch.Add(1500*time.Millisecond)) || s.Worker != 5 || s.Sequence != 7 {
+		t.Errorf("Unexpected fields %+v", s)
+	}
+}
+
+func TestSnowflakeUUIDRoundTrip(t *testing.T) {
+	for _, value := range []string{"1050118621198921728", "9223372036854775807", "0", "6291476487"} {
+		s, err := ParseSnowflake(value, TwitterEpoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uuid := UUIDv7FromSnowflake(s)
+		details := Inspect(uuid)
+		if details.Version != 7 || details.Variant != VariantRFC {
+			t.Fatalf("Expected an RFC UUIDv7, got %+v", details)
+		}
+		if !details.Timestamp.Equal(s.Timestamp) {
+			t.Errorf("Expected timestamp %v, got %v", s.Timestamp, details.Timestamp)
+		}
+
+		recovered, err := SnowflakeFromUUID(uuid, TwitterEpoch)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if recovered != s {
+			t.Errorf("Expected %+v, got %+v", s, recovered)
+		}
+	}
+}
+
+func TestSnowflakeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		contains string
+	}{
+		{"not a number", errOf(ParseSnowflake("12ab", TwitterEpoch)), "expected an unsigned 64-bit decimal integer"},
+		{"sign bit", errOf(ParseSnowflake("9223372036854775808", TwitterEpoch)), "the sign bit is set"},
+		{"not v7", errOf(SnowflakeFromUUID(mustParse(t, "919108f7-52d1-4320-9bac-f847db4148a8"), TwitterEpoch)), "only UUIDv7 can carry a snowflake, got version 4"},
+		{"before epoch", errOf(SnowflakeFromUUID(mustParse(t, "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"), time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))), "outside the 41-bit range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil || !strings.Contains(tt.err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, tt.err)
+			}
+		})
+	}
+}
+
+func errOf[T any](_ T, err error) error {
+	return err
+}
+
+func mustParse(t *testing.T, value string) [16]byte {
+	t.Helper()
+	uuid, err := ParseUUID(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uuid
+}
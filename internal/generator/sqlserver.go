@@ -0,0 +1,63 @@
+package generator
+
+import "time"
+
+// sqlServerOrder lists canonical byte positions in the order SQL Server
+// compares uniqueidentifier values: the last group (bytes 10-15) is the most
+// significant, followed by the fourth group (8-9), third (6-7), second (4-5),
+// and finally the first group (0-3). Within each group bytes compare left to
+// right as they appear in the canonical string.
+var sqlServerOrder = [16]int{10, 11, 12, 13, 14, 15, 8, 9, 6, 7, 4, 5, 0, 1, 2, 3}
+
+// CompareSQLServer compares two UUIDs the way SQL Server orders
+// uniqueidentifier columns, returning -1, 0, or +1
+func CompareSQLServer(a, b [16]byte) int {
+	for _, i := range sqlServerOrder {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// GenerateSQLServerSequential generates a UUIDv8 that sorts chronologically
+// under SQL Server's uniqueidentifier ordering
+func GenerateSQLServerSequential() string {
+	start := time.Now()
+	uuid := NewSQLServerSequentialFromBytes(currentTime(), randomBytes())
+	debug("generated UUID", "version", 8, "layout", "sqlserver-sequential", "uuid", uuid, "duration", time.Since(start))
+	return uuid
+}
+
+// GenerateSQLServerSequentialWithTimestamp generates a SQL Server sequential
+// UUIDv8 for a specific timestamp
+func GenerateSQLServerSequentialWithTimestamp(timestamp time.Time) string {
+	debug("read clock", "timestamp_source", "explicit", "timestamp", timestamp)
+	return NewSQLServerSequentialFromBytes(timestamp, randomBytes())
+}
+
+// NewSQLServerSequentialFromBytes lays out a UUIDv8 whose 48-bit Unix
+// millisecond timestamp occupies the last group (bytes 10-15, big-endian),
+// which SQL Server compares first. Every other bit is taken from random except
+// the version (8) and variant (10) bits.
+func NewSQLServerSequentialFromBytes(timestamp time.Time, random [16]byte) string {
+	timestampMs := unixMillis48(timestamp)
+
+	random[10] = byte(timestampMs >> 40)
+	random[11] = byte(timestampMs >> 32)
+	random[12] = byte(timestampMs >> 24)
+	random[13] = byte(timestampMs >> 16)
+	random[14] = byte(timestampMs >> 8)
+	random[15] = byte(timestampMs)
+
+	// Set version (4 bits): version 8
+	random[6] = (random[6] & 0x0f) | 0x80
+
+	// Set variant (2 bits): 10
+	random[8] = (random[8] & 0x3f) | 0x80
+
+	return FormatUUID(random)
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCompareSQLServerByteOrder(t *testing.T) {
+	// Each pair differs in a single byte; the earlier entries outrank the later
+	// ones because SQL Server compares the last group first
+	ordered := []string{
+		"00000000-0000-0000-0000-010000000000", // byte 10
+		"00000000-0000-0000-0000-000000000001", // byte 15
+		"00000000-0000-0000-0100-000000000000", // byte 8
+		"00000000-0000-0000-0001-000000000000", // byte 9
+		"00000000-0000-0100-0000-000000000000", // byte 6
+		"00000000-0000-0001-0000-000000000000", // byte 7
+		"00000000-0100-0000-0000-000000000000", // byte 4
+		"00000000-0001-0000-0000-000000000000", // byte 5
+		"01000000-0000-0000-0000-000000000000", // byte 0
+		"00000001-0000-0000-0000-000000000000", // byte 3
+	}
+
+	var zero [16]byte
+	for i := 0; i < len(ordered)-1; i++ {
+		higher, _ := ParseUUID(ordered[i])
+		lower, _ := ParseUUID(ordered[i+1])
+		if CompareSQLServer(higher, lower) != 1 || CompareSQLServer(lower, higher) != -1 {
+			t.Errorf("%s should sort after %s", ordered[i], ordered[i+1])
+		}
+		if CompareSQLServer(higher, higher) != 0 || CompareSQLServer(zero, higher) != -1 {
+			t.Errorf("Unexpected comparison for %s", ordered[i])
+		}
+	}
+}
+
+func TestSQLServerSequentialSortsChronologically(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var chronological [][16]byte
+	for i := 0; i < 500; i++ {
+		// Gaps growing from 1ms to several minutes exercise every timestamp byte
+		ts := start.Add(time.Duration(i*i*i) * time.Millisecond)
+		uuid, err := ParseUUID(GenerateSQLServerSequentialWithTimestamp(ts))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := Inspect(uuid); d.Version != 8 || d.Variant != VariantRFC {
+			t.Fatalf("Expected an RFC variant UUIDv8, got %+v", d)
+		}
+		chronological = append(chronological, uuid)
+	}
+
+	sorted := slices.Clone(chronological)
+	slices.Reverse(sorted)
+	slices.SortFunc(sorted, CompareSQLServer)
+	if !slices.Equal(sorted, chronological) {
+		t.Error("SQL Server ordering should match chronological order")
+	}
+
+	// Plain byte order does not, which is the point of the layout
+	slices.SortFunc(sorted, func(a, b [16]byte) int { return slices.Compare(a[:], b[:]) })
+	if slices.Equal(sorted, chronological) {
+		t.Error("Expected byte order to differ from chronological order")
+	}
+}
+
+func TestSQLServerSequentialLayout(t *testing.T) {
+	var random [16]byte
+	for i := range random {
+		random[i] = 0xff
+	}
+	ts := time.UnixMilli(0x0123456789ab)
+	if got := NewSQLServerSequentialFromBytes(ts, random); got != "ffffffff-ffff-8fff-bfff-0123456789ab" {
+		t.Errorf("Unexpected layout %s", got)
+	}
+}
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+var timeCipherKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestTimeCipherKnownAnswers(t *testing.T) {
+	// Computed with an independent Python implementation of the documented
+	// Feistel network
+	vectors := []struct{ plain, encrypted string }{
+		{"018f4e3c-1a2b-7c3d-8e4f-5a6b7c8d9e0f", "b773082b-a103-7c3d-8e4f-5a6b7c8d9e0f"},
+		{"00000000-0000-7000-8000-000000000000", "aaaaee42-3f3d-7000-8000-000000000000"},
+		{"ffffffff-ffff-7fff-bfff-ffffffffffff", "3cea11b8-2129-7fff-bfff-ffffffffffff"},
+	}
+	c, err := NewTimeCipher(timeCipherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vectors {
+		plain, _ := ParseUUID(v.plain)
+		if got := FormatUUID(c.Encrypt(plain)); got != v.encrypted {
+			t.Errorf("Encrypt(%s) = %s, expected %s", v.plain, got, v.encrypted)
+		}
+		encrypted, _ := ParseUUID(v.encrypted)
+		if got := FormatUUID(c.Decrypt(encrypted)); got != v.plain {
+			t.Errorf("Decrypt(%s) = %s, expected %s", v.encrypted, got, v.plain)
+		}
+	}
+}
+
+func TestTimeCipherRoundTrip(t *testing.T) {
+	c, _ := NewTimeCipher(timeCipherKey)
+	other, _ := NewTimeCipher([]byte("another key, another key"))
+	for range 1000 {
+		uuid, _ := ParseUUID(GenerateUUIDv7())
+		encrypted := c.Encrypt(uuid)
+		if c.Decrypt(encrypted) != uuid {
+			t.Fatalf("Round trip of %s failed", FormatUUID(uuid))
+		}
+		if encrypted[6]>>4 != 7 || encrypted[8]>>6 != 0b10 || [10]byte(encrypted[6:]) != [10]byte(uuid[6:]) {
+			t.Fatalf("Expected only the timestamp to change, got %s from %s", FormatUUID(encrypted), FormatUUID(uuid))
+		}
+		if other.Encrypt(uuid) == encrypted {
+			t.Fatalf("Expected different keys to give different timestamps for %s", FormatUUID(uuid))
+		}
+	}
+}
+
+func TestTimeCipherSameMillisecond(t *testing.T) {
+	c, _ := NewTimeCipher(timeCipherKey)
+	at := time.UnixMilli(1718361000123)
+	a, _ := ParseUUID(mustV7At(t, at))
+	b, _ := ParseUUID(mustV7At(t, at))
+	if a == b {
+		t.Fatal("Expected two different UUIDs")
+	}
+	ea, eb := c.Encrypt(a), c.Encrypt(b)
+	if [6]byte(ea[:6]) != [6]byte(eb[:6]) {
+		t.Errorf("Expected the same encrypted timestamp, got %s and %s", FormatUUID(ea), FormatUUID(eb))
+	}
+	if [6]byte(ea[:6]) == [6]byte(a[:6]) {
+		t.Errorf("Expected the timestamp to be encrypted, got %s", FormatUUID(ea))
+	}
+	for _, encrypted := range [][16]byte{ea, eb} {
+		if got := c.Time(encrypted); !got.Equal(at) {
+			t.Errorf("Expected %s, got %s", at.UTC(), got)
+		}
+	}
+}
+
+func TestNewTimeCipherShortKey(t *testing.T) {
+	if _, err := NewTimeCipher([]byte("short")); err == nil || err.Error() != "timestamp key is 5 bytes; at least 16 are required" {
+		t.Errorf("Expected a key length error, got %v", err)
+	}
+}
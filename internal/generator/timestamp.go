@@ -0,0 +1,304 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timestampFormat is one of the input forms ParseTimestamp recognises
+type timestampFormat struct {
+	name  string
+	parse func(string) (time.Time, error)
+}
+
+// timestampFormats are tried in order of likelihood; the first that parses
+// the whole input wins
+var timestampFormats = []timestampFormat{
+	{"unix-seconds", fixedDigits(10, func(n int64) time.Time { return time.Unix(n, 0) })},
+	{"unix-milliseconds", fixedDigits(13, time.UnixMilli)},
+	{"rfc3339", layoutParser(time.RFC3339)},
+	{"rfc3339-no-offset", layoutParser("2006-01-02T15:04:05")},
+	{"iso-date", layoutParser("2006-01-02")},
+	{"date-time", layoutParser("2006-01-02 15:04:05")},
+	{"iso-week-date", isoWeekParser(isoWeekDatePattern, "2006-W01-1")},
+	{"iso-week", isoWeekParser(isoWeekPattern, "2006-W01")},
+	{"unix-integer", parseUnixInteger},
+}
+
+// ISO 8601 week dates: 2023-W24-3 is the Wednesday of week 24, and 2023-W24
+// is the Monday of that week
+var (
+	isoWeekDatePattern = sync.OnceValue(func() *regexp.Regexp {
+		return regexp.MustCompile(`^(\d{4})-W(\d{2})-(\d)$`)
+	})
+	isoWeekPattern = sync.OnceValue(func() *regexp.Regexp {
+		return regexp.MustCompile(`^(\d{4})-W(\d{2})()$`)
+	})
+)
+
+// registeredTimestampFormats are tried after the built-in formats, in
+// registration order
+var (
+	registeredTimestampMu      sync.RWMutex
+	registeredTimestampFormats []timestampFormat
+)
+
+// RegisterTimestampLayout teaches ParseTimestamp a Go reference layout, such
+// as "02/01/2006 15:04", under the given name. Registered formats are tried
+// after the built-in ones, so they never change how a value that already
+// parses is read. Registering a name again replaces its earlier layout.
+func RegisterTimestampLayout(name, layout string) error {
+	if err := ValidateTimestampLayout(layout); err != nil {
+		return fmt.Errorf("timestamp layout '%s': %w", name, err)
+	}
+	return registerTimestampFormat(name, layoutParser(layout))
+}
+
+// RegisterTimestampParser is like RegisterTimestampLayout for formats that a
+// layout cannot describe. fn reports whether it recognised the value.
+func RegisterTimestampParser(name string, fn func(string) (time.Time, bool)) error {
+	if fn == nil {
+		return fmt.Errorf("timestamp parser '%s' is nil", name)
+	}
+	return registerTimestampFormat(name, func(value string) (time.Time, error) {
+		if t, ok := fn(value); ok {
+			return t, nil
+		}
+		return time.Time{}, errors.New("not recognised by the registered parser")
+	})
+}
+
+func registerTimestampFormat(name string, parse func(string) (time.Time, error)) error {
+	if name == "" {
+		return errors.New("timestamp format name is empty")
+	}
+	for _, f := range timestampFormats {
+		if f.name == name {
+			return fmt.Errorf("'%s' is a built-in timestamp format and cannot be replaced", name)
+		}
+	}
+
+	registeredTimestampMu.Lock()
+	defer registeredTimestampMu.Unlock()
+	for i, f := range registeredTimestampFormats {
+		if f.name == name {
+			registeredTimestampFormats[i].parse = parse
+			return nil
+		}
+	}
+	registeredTimestampFormats = append(registeredTimestampFormats, timestampFormat{name, parse})
+	return nil
+}
+
+// layoutProbe is rendered and parsed back to check a layout. Every field
+// differs from Go's reference time (Mon Jan 2 15:04:05 MST 2006), so a layout
+// without any reference elements renders unchanged.
+var layoutProbe = time.Date(2019, 11, 28, 21, 37, 48, 0, time.UTC)
+
+// ValidateTimestampLayout checks that layout is a usable Go reference layout
+// by rendering a probe time with it and parsing the result back. Go would
+// otherwise accept almost any string as a layout and only fail, with a
+// confusing message, when parsing a value. The layout must include the year,
+// month, and day.
+func ValidateTimestampLayout(layout string) error {
+	rendered := layoutProbe.Format(layout)
+	if rendered == layout {
+		return fmt.Errorf("'%s' has no elements of the reference time Mon Jan 2 15:04:05 MST 2006", layout)
+	}
+	parsed, err := time.Parse(layout, rendered)
+	if err != nil {
+		return fmt.Errorf("'%s' cannot parse the time it renders (%s): %w", layout, rendered, err)
+	}
+	if y, m, d := parsed.Date(); y != 2019 || m != time.November || d != 28 {
+		return fmt.Errorf("'%s' must include the year (2006), month (01 or Jan), and day (02)", layout)
+	}
+	return nil
+}
+
+// ParseTimestampLayout parses value with a single Go reference layout
+// instead of trying every format. The result is in UTC; layouts without an
+// offset are read as UTC.
+func ParseTimestampLayout(value, layout string) (time.Time, error) {
+	if err := ValidateTimestampLayout(layout); err != nil {
+		return time.Time{}, classify(ErrInvalidFormat, "invalid layout: %w", err)
+	}
+	t, err := layoutParser(layout)(value)
+	if err != nil {
+		return time.Time{}, classify(ErrInvalidFormat, "timestamp '%s' does not match layout '%s': %w", value, layout, err)
+	}
+	return t.UTC(), nil
+}
+
+// ParseTimestampSince reads a numeric value as a count of seconds or
+// milliseconds since epoch rather than since the Unix epoch. The unit is
+// chosen as ParseTimestamp chooses it for Unix timestamps: 13 digits or
+// anything above 1e12 is milliseconds, otherwise seconds. The result is in
+// UTC.
+func ParseTimestampSince(value string, epoch time.Time) (time.Time, error) {
+	var t time.Time
+	var err error
+	if len(value) == 13 {
+		t, err = fixedDigits(13, time.UnixMilli)(value)
+	} else {
+		t, err = parseUnixInteger(value)
+	}
+	if err != nil {
+		return time.Time{}, classify(ErrInvalidFormat, "timestamp '%s' is not a whole number of seconds or milliseconds", value)
+	}
+	// Offsetting the Unix parts avoids overflowing time.Duration for spans
+	// of more than 292 years
+	return time.Unix(epoch.Unix()+t.Unix(), int64(epoch.Nanosecond()+t.Nanosecond())).UTC(), nil
+}
+
+// TimestampFormats returns the names of the formats ParseTimestamp tries, in
+// order: the built-in formats followed by the registered ones
+func TimestampFormats() []string {
+	var names []string
+	for _, f := range allTimestampFormats() {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// allTimestampFormats returns the built-in formats followed by a snapshot of
+// the registered ones
+func allTimestampFormats() []timestampFormat {
+	registeredTimestampMu.RLock()
+	defer registeredTimestampMu.RUnlock()
+	return append(slices.Clip(timestampFormats), registeredTimestampFormats...)
+}
+
+// TimestampAttempt is a format that was tried and why it did not match
+type TimestampAttempt struct {
+	Format string
+	Err    error
+}
+
+// TimestampExplanation describes how ParseTimestamp reads a value: the
+// format that matched and every format tried before it. When nothing
+// matches, Format is empty and Attempts holds every format.
+type TimestampExplanation struct {
+	Input    string
+	Time     time.Time
+	Format   string
+	Attempts []TimestampAttempt
+}
+
+// ParseTimestamp parses various timestamp formats and returns a time.Time
+func ParseTimestamp(timestampStr string) (time.Time, error) {
+	explanation, err := ExplainTimestamp(timestampStr)
+	return explanation.Time, err
+}
+
+// ExplainTimestamp parses a timestamp exactly as ParseTimestamp does and
+// reports which format matched. Registered formats are tried last.
+func ExplainTimestamp(value string) (TimestampExplanation, error) {
+	explanation := TimestampExplanation{Input: value}
+	formats := allTimestampFormats()
+	for _, f := range formats {
+		t, err := f.parse(value)
+		if err == nil {
+			explanation.Time = t.UTC()
+			explanation.Format = f.name
+			return explanation, nil
+		}
+		explanation.Attempts = append(explanation.Attempts, TimestampAttempt{Format: f.name, Err: err})
+	}
+	supported := "Unix timestamp (seconds/milliseconds), RFC3339 (2006-01-02T15:04:05Z), ISO date (2006-01-02), date-time (2006-01-02 15:04:05), or ISO week date (2006-W01-1 or 2006-W01)"
+	if registered := formats[len(timestampFormats):]; len(registered) > 0 {
+		names := make([]string, len(registered))
+		for i, f := range registered {
+			names[i] = f.name
+		}
+		supported += ", or the registered formats " + strings.Join(names, ", ")
+	}
+	return explanation, classify(ErrInvalidFormat, "unable to parse timestamp '%s'. Supported formats: %s", value, supported)
+}
+
+// fixedDigits parses Unix timestamps written with exactly n characters
+func fixedDigits(n int, toTime func(int64) time.Time) func(string) (time.Time, error) {
+	return func(value string) (time.Time, error) {
+		if len(value) != n {
+			return time.Time{}, fmt.Errorf("not %d characters long", n)
+		}
+		ts, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("not an integer")
+		}
+		return toTime(ts), nil
+	}
+}
+
+// layoutParser parses values in a Go reference layout, as UTC unless the
+// layout includes an offset. Errors leave out the input and layout, which
+// the caller already knows.
+func layoutParser(layout string) func(string) (time.Time, error) {
+	return func(value string) (time.Time, error) {
+		t, err := time.Parse(layout, value)
+		var parseErr *time.ParseError
+		if errors.As(err, &parseErr) {
+			if parseErr.Message != "" {
+				return t, errors.New(strings.TrimPrefix(parseErr.Message, ": "))
+			}
+			return t, fmt.Errorf("cannot parse %q as %q", parseErr.ValueElem, parseErr.LayoutElem)
+		}
+		return t, err
+	}
+}
+
+// isoWeekParser parses ISO 8601 week dates matched by pattern, whose groups
+// are the week-numbering year, the week, and an optional weekday (1 is
+// Monday). The result is midnight UTC; form names the expected shape in
+// errors.
+func isoWeekParser(pattern func() *regexp.Regexp, form string) func(string) (time.Time, error) {
+	return func(value string) (time.Time, error) {
+		m := pattern().FindStringSubmatch(value)
+		if m == nil {
+			return time.Time{}, fmt.Errorf("not in the form %s", form)
+		}
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		weekday := 1
+		if m[3] != "" {
+			weekday, _ = strconv.Atoi(m[3])
+		}
+		return ISOWeekDate(year, week, weekday)
+	}
+}
+
+// ISOWeekDate returns midnight UTC on the given weekday (1 for Monday to 7
+// for Sunday) of an ISO 8601 week. Week 1 is the week containing 4 January,
+// so it can start in the previous calendar year, and years have 52 or 53
+// weeks.
+func ISOWeekDate(year, week, weekday int) (time.Time, error) {
+	if weekday < 1 || weekday > 7 {
+		return time.Time{}, fmt.Errorf("weekday %d is out of range (1-7)", weekday)
+	}
+	// 28 December is always in the last week of its week-numbering year
+	_, weeks := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	if week < 1 || week > weeks {
+		return time.Time{}, fmt.Errorf("week %d is out of range for %d (1-%d)", week, year, weeks)
+	}
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	mondayOfWeek1 := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	return mondayOfWeek1.AddDate(0, 0, (week-1)*7+weekday-1), nil
+}
+
+// parseUnixInteger reads any other integer as Unix time: milliseconds when
+// it is above 1e12 and seconds otherwise
+func parseUnixInteger(value string) (time.Time, error) {
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("not an integer")
+	}
+	if ts > 1e12 {
+		return time.UnixMilli(ts), nil
+	}
+	return time.Unix(ts, 0), nil
+}
@@ -0,0 +1,321 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The format names are reported by 'uuid parse-timestamp'; pinning them here
+// keeps refactors of the parser observable
+func TestExplainTimestampFormats(t *testing.T) {
+	tests := []struct {
+		input    string
+		format   string
+		tried    int
+		expected time.Time
+	}{
+		{"1686742245", "unix-seconds", 0, time.Unix(1686742245, 0)},
+		{"1686742245123", "unix-milliseconds", 1, time.UnixMilli(1686742245123)},
+		{"2023-06-14T10:30:45-05:00", "rfc3339", 2, time.Date(2023, 6, 14, 15, 30, 45, 0, time.UTC)},
+		{"2023-06-14T10:30:45", "rfc3339-no-offset", 3, time.Date(2023, 6, 14, 10, 30, 45, 0, time.UTC)},
+		{"2023-06-14", "iso-date", 4, time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC)},
+		{"2023-06-14 10:30:45", "date-time", 5, time.Date(2023, 6, 14, 10, 30, 45, 0, time.UTC)},
+		{"2023-W24-3", "iso-week-date", 6, time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC)},
+		{"2023-W24", "iso-week", 7, time.Date(2023, 6, 12, 0, 0, 0, 0, time.UTC)},
+		{"86400", "unix-integer", 8, time.Unix(86400, 0)},
+		{"99999999999999", "unix-integer", 8, time.UnixMilli(99999999999999)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			explanation, err := ExplainTimestamp(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if explanation.Format != tt.format {
+				t.Errorf("Expected format %s, got %s", tt.format, explanation.Format)
+			}
+			if len(explanation.Attempts) != tt.tried {
+				t.Errorf("Expected %d formats tried first, got %v", tt.tried, explanation.Attempts)
+			}
+			if !explanation.Time.Equal(tt.expected) || explanation.Time.Location() != time.UTC {
+				t.Errorf("Expected %v in UTC, got %v", tt.expected, explanation.Time)
+			}
+		})
+	}
+}
+
+func TestExplainTimestampFailure(t *testing.T) {
+	explanation, err := ExplainTimestamp("2023-13-01")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if explanation.Format != "" || len(explanation.Attempts) != len(timestampFormats) {
+		t.Fatalf("Expected every format to be reported, got %+v", explanation)
+	}
+
+	reasons := map[string]string{}
+	for _, a := range explanation.Attempts {
+		reasons[a.Format] = a.Err.Error()
+	}
+	if reasons["unix-seconds"] != "not an integer" || reasons["unix-milliseconds"] != "not 13 characters long" {
+		t.Errorf("Unexpected reasons for the Unix formats: %v", reasons)
+	}
+	if !strings.Contains(reasons["iso-date"], "month out of range") {
+		t.Errorf("Expected the ISO date reason to name the bad month, got %q", reasons["iso-date"])
+	}
+}
+
+func TestParseTimestampISOWeek(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		// 2020 has 53 weeks; its last week ends in January 2021
+		{"2020-W53-5", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2020-W53-7", time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"2021-W01-1", time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"2015-W53", time.Date(2015, 12, 28, 0, 0, 0, 0, time.UTC)},
+		// Week 1 of 2020 starts in December 2019
+		{"2020-W01-1", time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{"2023-W52-7", time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestamp(tt.input)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", tt.input, err)
+		}
+		if !got.Equal(tt.expected) || got.Location() != time.UTC {
+			t.Errorf("%s: expected %v, got %v", tt.input, tt.expected, got)
+		}
+	}
+
+	// Every day round-trips through Go's own ISO week numbering
+	for d := time.Date(2014, 12, 1, 0, 0, 0, 0, time.UTC); d.Year() < 2027; d = d.AddDate(0, 0, 1) {
+		year, week := d.ISOWeek()
+		weekday := (int(d.Weekday())+6)%7 + 1
+		got, err := ISOWeekDate(year, week, weekday)
+		if err != nil || !got.Equal(d) {
+			t.Fatalf("%d-W%02d-%d: expected %v, got %v, %v", year, week, weekday, d, got, err)
+		}
+	}
+}
+
+func TestParseTimestampISOWeekErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		reason string
+	}{
+		{"2023-W54", "iso-week: week 54 is out of range for 2023 (1-52)"},
+		{"2023-W53-1", "iso-week-date: week 53 is out of range for 2023 (1-52)"},
+		{"2023-W00-1", "iso-week-date: week 0 is out of range for 2023 (1-52)"},
+		{"2023-W24-8", "iso-week-date: weekday 8 is out of range (1-7)"},
+		{"2023-W24-0", "iso-week-date: weekday 0 is out of range (1-7)"},
+	}
+	for _, tt := range tests {
+		explanation, err := ExplainTimestamp(tt.input)
+		if err == nil {
+			t.Fatalf("Expected an error for %s, got %v", tt.input, explanation.Time)
+		}
+		found := false
+		for _, a := range explanation.Attempts {
+			found = found || fmt.Sprintf("%s: %v", a.Format, a.Err) == tt.reason
+		}
+		if !found {
+			t.Errorf("%s: expected reason %q, got %v", tt.input, tt.reason, explanation.Attempts)
+		}
+	}
+}
+
+func TestParseTimestampSince(t *testing.T) {
+	gps := time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input    string
+		epoch    time.Time
+		expected time.Time
+	}{
+		// GPS week 2200 began on Sunday 2022-03-06
+		{"1330560000", gps, time.Date(2022, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"1330560000123", gps, time.Date(2022, 3, 6, 0, 0, 0, 123_000_000, time.UTC)},
+		{"0", gps, gps},
+		{"-86400", gps, time.Date(1980, 1, 5, 0, 0, 0, 0, time.UTC)},
+		// Beyond the 292 years a time.Duration can hold
+		{"99999999999999", gps, time.Date(5148, 11, 20, 9, 46, 39, 999_000_000, time.UTC)},
+		{"1686742245", time.Unix(0, 0), time.Unix(1686742245, 0).UTC()},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestampSince(tt.input, tt.epoch)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", tt.input, err)
+		}
+		if !got.Equal(tt.expected) || got.Location() != time.UTC {
+			t.Errorf("%s: expected %v, got %v", tt.input, tt.expected, got)
+		}
+	}
+
+	if _, err := ParseTimestampSince("2023-06-14", gps); err == nil || err.Error() != "timestamp '2023-06-14' is not a whole number of seconds or milliseconds" {
+		t.Errorf("Expected a non-numeric value to be rejected, got %v", err)
+	}
+}
+
+// restoreTimestampFormats drops formats registered during a test
+func restoreTimestampFormats(t *testing.T) {
+	t.Helper()
+	registeredTimestampMu.Lock()
+	saved := registeredTimestampFormats
+	registeredTimestampMu.Unlock()
+	t.Cleanup(func() {
+		registeredTimestampMu.Lock()
+		registeredTimestampFormats = saved
+		registeredTimestampMu.Unlock()
+	})
+}
+
+func TestRegisterTimestampFormats(t *testing.T) {
+	restoreTimestampFormats(t)
+
+	if err := RegisterTimestampLayout("dmy", "02/01/2006 15.04"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Tenths of seconds since 2000, an example of a format no layout describes
+	err := RegisterTimestampParser("deciseconds", func(value string) (time.Time, bool) {
+		n, ok := strings.CutPrefix(value, "ds:")
+		if !ok {
+			return time.Time{}, false
+		}
+		ticks, err := time.ParseDuration(n + "00ms")
+		return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Add(ticks), err == nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		format   string
+		expected time.Time
+	}{
+		{"14/06/2023 10.30", "dmy", time.Date(2023, 6, 14, 10, 30, 0, 0, time.UTC)},
+		{"ds:15", "deciseconds", time.Date(2000, 1, 1, 0, 0, 1, 500_000_000, time.UTC)},
+		// Built-in formats always win
+		{"2023-06-14", "iso-date", time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		explanation, err := ExplainTimestamp(tt.input)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", tt.input, err)
+		}
+		if explanation.Format != tt.format || !explanation.Time.Equal(tt.expected) {
+			t.Errorf("%s: expected %s at %v, got %s at %v", tt.input, tt.format, tt.expected, explanation.Format, explanation.Time)
+		}
+	}
+
+	names := TimestampFormats()
+	if got := strings.Join(names[len(names)-2:], ","); got != "dmy,deciseconds" {
+		t.Errorf("Expected registered formats after the built-ins, got %v", names)
+	}
+
+	// Registering again replaces the layout in place
+	if err := RegisterTimestampLayout("dmy", "02.01.2006"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if explanation, err := ExplainTimestamp("14.06.2023"); err != nil || explanation.Format != "dmy" {
+		t.Errorf("Expected the replaced layout to parse, got %+v, %v", explanation, err)
+	}
+	if len(TimestampFormats()) != len(names) {
+		t.Errorf("Expected replacing a format not to add one")
+	}
+
+	_, err = ParseTimestamp("nonsense")
+	if err == nil || !strings.HasSuffix(err.Error(), ", or the registered formats dmy, deciseconds") {
+		t.Errorf("Expected the error to list the registered formats, got %v", err)
+	}
+}
+
+func TestRegisterTimestampFormatErrors(t *testing.T) {
+	restoreTimestampFormats(t)
+
+	if err := RegisterTimestampLayout("rfc3339", "2006/01/02"); err == nil || !strings.Contains(err.Error(), "built-in") {
+		t.Errorf("Expected built-in names to be refused, got %v", err)
+	}
+	if err := RegisterTimestampLayout("", "2006/01/02"); err == nil {
+		t.Error("Expected an empty name to be refused")
+	}
+	if err := RegisterTimestampLayout("empty", ""); err == nil {
+		t.Error("Expected an empty layout to be refused")
+	}
+	if err := RegisterTimestampParser("nil", nil); err == nil {
+		t.Error("Expected a nil parser to be refused")
+	}
+}
+
+func TestRegisterTimestampConcurrently(t *testing.T) {
+	restoreTimestampFormats(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterTimestampLayout(fmt.Sprintf("layout-%d", i%4), "2006/01/02")
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := ParseTimestamp("2023/06/14"); err != nil && !strings.Contains(err.Error(), "unable to parse") {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(TimestampFormats()) - len(timestampFormats); got != 4 {
+		t.Errorf("Expected 4 registered formats, got %d", got)
+	}
+}
+
+func TestValidateTimestampLayout(t *testing.T) {
+	tests := []struct {
+		layout   string
+		contains string
+	}{
+		{"02/01/2006 15:04", ""},
+		{"Jan _2 2006", ""},
+		{time.RFC1123Z, ""},
+		{"", "has no elements"},
+		{"yyyy-mm-dd", "has no elements"},
+		{"15:04:05", "must include the year"},
+		{"2006-01", "must include the year"},
+		{"01/02 15:04", "must include the year"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateTimestampLayout(tt.layout)
+		if tt.contains == "" {
+			if err != nil {
+				t.Errorf("Layout %q: unexpected error %v", tt.layout, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.contains) {
+			t.Errorf("Layout %q: expected error containing %q, got %v", tt.layout, tt.contains, err)
+		}
+	}
+}
+
+func TestParseTimestampLayout(t *testing.T) {
+	got, err := ParseTimestampLayout("14/06/2023 10:30 +0200", "02/01/2006 15:04 -0700")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := time.Date(2023, 6, 14, 8, 30, 0, 0, time.UTC); !got.Equal(expected) || got.Location() != time.UTC {
+		t.Errorf("Expected %v in UTC, got %v", expected, got)
+	}
+
+	// The layout is used alone, so built-in formats do not apply
+	if _, err := ParseTimestampLayout("2023-06-14", "02/01/2006"); err == nil {
+		t.Error("Expected a value in another format to be rejected")
+	}
+}
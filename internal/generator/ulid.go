@@ -0,0 +1,30 @@
+package generator
+
+import "time"
+
+// GenerateULID returns a ULID for the current time: a 48-bit Unix
+// millisecond timestamp and 80 random bits in Crockford base32 (26
+// characters). ULIDs are not UUIDs and carry no version or variant bits.
+func GenerateULID() string {
+	return GenerateULIDWithTimestamp(currentTime())
+}
+
+// GenerateULIDWithTimestamp returns a ULID for a specific time. Like
+// UUIDv7, the timestamp can be read back by anyone holding the ULID.
+func GenerateULIDWithTimestamp(timestamp time.Time) string {
+	start := time.Now()
+	ulid := NewULID(timestamp, randomBytes())
+	debug("generated ULID", "ulid", ulid, "timestamp", timestamp, "duration", time.Since(start))
+	return ulid
+}
+
+// NewULID builds a ULID from a timestamp and caller-supplied random bytes.
+// This is the UUIDv7 layout without the version and variant: the first six
+// bytes of random are replaced by the timestamp and the other ten are kept.
+func NewULID(timestamp time.Time, random [16]byte) string {
+	ms := unixMillis48(timestamp)
+	for i := range 6 {
+		random[i] = byte(ms >> (40 - 8*i))
+	}
+	return encodeULID(random)
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewULID(t *testing.T) {
+	ones := [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		random    [16]byte
+		expected  string
+	}{
+		{"epoch", time.UnixMilli(0), [16]byte{}, "00000000000000000000000000"},
+		{"largest", time.UnixMilli(1<<48 - 1), ones, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{"timestamp only", time.UnixMilli(1469918176385), [16]byte{}, "01ARYZ6S410000000000000000"},
+		{"random only", time.UnixMilli(0), ones, "0000000000ZZZZZZZZZZZZZZZZ"},
+		{"date", time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC), [16]byte{}, "01H2VK7E000000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewULID(tt.timestamp, tt.random); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateULIDWithTimestamp(t *testing.T) {
+	ts := time.Date(2023, 6, 14, 10, 30, 45, 123_000_000, time.UTC)
+	ulid := GenerateULIDWithTimestamp(ts)
+	if len(ulid) != 26 {
+		t.Fatalf("Expected 26 characters, got %q", ulid)
+	}
+
+	// The ulid encoding decodes it to bytes laid out like a UUIDv7
+	encoding, _ := LookupEncoding("ulid")
+	b, err := encoding.Decode(ulid)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := time.UnixMilli(int64(b[0])<<40 | int64(b[1])<<32 | int64(b[2])<<24 | int64(b[3])<<16 | int64(b[4])<<8 | int64(b[5])); !got.Equal(ts) {
+		t.Errorf("Expected timestamp %s, got %s", ts, got)
+	}
+	if other := GenerateULIDWithTimestamp(ts); other == ulid {
+		t.Errorf("Expected random bits to differ, got %s twice", ulid)
+	}
+}
+
+func TestGenerateULID(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC)
+	SetClock(func() time.Time { return pinned })
+	t.Cleanup(func() { SetClock(nil) })
+
+	if got, want := GenerateULID()[:10], NewULID(pinned, [16]byte{})[:10]; got != want {
+		t.Errorf("Expected the clock's timestamp %s, got %s", want, got)
+	}
+}
@@ -1,254 +1,326 @@
 package generator
 
 import (
-	"crypto/rand"
-	"fmt"
-	"math"
-	"strconv"
+	"context"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"sync"
 	"time"
-
-	"github.com/google/uuid"
 )
 
+// clockOverride replaces the system clock for time-based generation when set
+var clockOverride func() time.Time
+
+// SetClock overrides the current time used by time-based generators, so
+// tests and reproducible pipelines can pin it. Passing nil restores the
+// system clock. This is a testing facility, not a way to backdate UUIDs;
+// use GenerateUUIDv7WithTimestamp for that.
+func SetClock(now func() time.Time) {
+	clockOverride = now
+}
+
+// logger receives debug records about generation internals when set
+var logger *slog.Logger
+
+// SetLogger routes debug records describing generation internals (timestamp
+// and entropy sources, bytes read, and phase durations) to l. Passing nil,
+// the default, disables them. The package never prints on its own.
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
+
+// debug emits a debug record when a logger is installed
+func debug(msg string, args ...any) {
+	if logger != nil {
+		logger.Debug(msg, args...)
+	}
+}
+
+// currentTime returns the overridden clock if one is set, else the system time
+func currentTime() time.Time {
+	if clockOverride != nil {
+		t := clockOverride()
+		debug("read clock", "timestamp_source", "override", "timestamp", t)
+		return t
+	}
+	t := time.Now()
+	debug("read clock", "timestamp_source", "system", "timestamp", t)
+	return t
+}
+
 // GenerateUUIDv4 generates a random UUID (version 4)
 func GenerateUUIDv4() string {
-	return uuid.New().String()
+	return mustGenerate(GenerateUUIDv4Context(context.Background()))
+}
+
+// GenerateUUIDv4Context is GenerateUUIDv4 bounded by ctx: it returns
+// ctx.Err() once ctx is done, and entropy failures as errors. It generates
+// with the Default Generator.
+func GenerateUUIDv4Context(ctx context.Context) (string, error) {
+	return Default().NewV4(ctx)
 }
 
 // GenerateUUIDv6 generates a time-ordered UUID (version 6)
 func GenerateUUIDv6() string {
-	// Use our manual implementation for better randomness and uniqueness
-	// The google/uuid library's NewV6 may not provide sufficient randomness
-	// in the node portion for high-frequency generation
-	return generateUUIDv6Manual()
+	return mustGenerate(GenerateUUIDv6Context(context.Background()))
 }
 
-// GenerateUUIDv7 generates a time-ordered UUID (version 7)
-func GenerateUUIDv7() string {
-	// UUIDv7 implementation using time-based ordering
-	// The google/uuid library supports UUIDv7 in newer versions
-	uuidv7, err := uuid.NewV7()
-	if err != nil {
-		// Fallback to manual implementation if NewV7 is not available
-		return generateUUIDv7Manual()
-	}
-	return uuidv7.String()
+// GenerateUUIDv6Context is GenerateUUIDv6 bounded by ctx: it returns
+// ctx.Err() once ctx is done, and entropy failures as errors. It generates
+// with the Default Generator.
+func GenerateUUIDv6Context(ctx context.Context) (string, error) {
+	return Default().NewV6(ctx)
 }
 
-// GenerateUUIDv7WithTimestamp generates a UUIDv7 with a specific timestamp
-// SECURITY NOTE: UUIDv7 embeds the timestamp directly in the UUID, revealing timing information.
-// This is by design per RFC 9562 but may not be suitable for privacy-sensitive applications.
-func GenerateUUIDv7WithTimestamp(timestamp time.Time) string {
-	// UUIDv7 format: timestamp (48 bits) + random (74 bits) + version (4 bits) + variant (2 bits)
-	// WARNING: The timestamp is embedded in the first 48 bits and can be extracted by anyone
-	timestampMs := timestamp.UnixMilli()
+// GenerateUUIDv6WithNode generates a UUIDv6 with a caller-chosen node, so
+// UUIDs from one source can be correlated. The clock sequence stays random.
+func GenerateUUIDv6WithNode(node [6]byte) string {
+	start := time.Now()
+	random := randomBytes()
+	clockSeq := uint16(random[8])<<8 | uint16(random[9])
+	uuid := FormatUUID(buildUUIDv6(currentTime(), clockSeq, node))
+	debug("generated UUID", "version", 6, "uuid", uuid, "node_source", "fixed", "duration", time.Since(start))
+	return uuid
+}
 
-	// Create 16 bytes for UUID
-	var uuid [16]byte
+// GenerateUUIDv6WithTimestamp generates a UUIDv6 for a specific time, kept to
+// 100ns precision, with a random clock sequence and node, so UUIDs for
+// historical events sort by when they happened. Timestamps the 60-bit field
+// cannot hold fail with ErrTimestampOutOfRange; see CheckUUIDv6Timestamp.
+func GenerateUUIDv6WithTimestamp(timestamp time.Time) (string, error) {
+	if err := CheckUUIDv6Timestamp(timestamp); err != nil {
+		return "", err
+	}
+	start := time.Now()
+	debug("read clock", "timestamp_source", "explicit", "timestamp", timestamp)
+	random := randomBytes()
+	clockSeq := uint16(random[8])<<8 | uint16(random[9])
+	uuid := FormatUUID(buildUUIDv6(timestamp, clockSeq, RandomNode()))
+	debug("generated UUID", "version", 6, "uuid", uuid, "duration", time.Since(start))
+	return uuid, nil
+}
 
-	// First 6 bytes: 48-bit timestamp in milliseconds
-	uuid[0] = byte(timestampMs >> 40)
-	uuid[1] = byte(timestampMs >> 32)
-	uuid[2] = byte(timestampMs >> 24)
-	uuid[3] = byte(timestampMs >> 16)
-	uuid[4] = byte(timestampMs >> 8)
-	uuid[5] = byte(timestampMs)
-
-	// Fill remaining bytes with random data
-	if _, err := rand.Read(uuid[6:]); err != nil {
-		// If we can't get random data, use a simple fallback
-		for i := 6; i < 16; i++ {
-			uuid[i] = byte(time.Now().UnixNano() % 256)
-		}
+// CheckUUIDv6Timestamp reports whether a UUIDv6 can hold timestamp: the
+// field is a 60-bit count of 100ns intervals since the Gregorian reform, so
+// it runs from 1582-10-15 to 5236-03-31T21:21:00.6846975Z. The error matches
+// ErrTimestampOutOfRange.
+func CheckUUIDv6Timestamp(timestamp time.Time) error {
+	const max60 = 1<<60 - 1
+	const ticksPerSecond = 10_000_000
+	first := time.Unix(-gregorianOffset/ticksPerSecond, 0)
+	last := time.Unix((max60-gregorianOffset)/ticksPerSecond, (max60-gregorianOffset)%ticksPerSecond*100)
+	if timestamp.Before(first) {
+		return classify(ErrTimestampOutOfRange, "timestamp %s is before %s, the first UUIDv6 timestamp", timestamp.UTC().Format(time.RFC3339Nano), first.UTC().Format(time.RFC3339Nano))
+	}
+	if !timestamp.Before(last.Add(100 * time.Nanosecond)) {
+		return classify(ErrTimestampOutOfRange, "timestamp %s is after %s, the last UUIDv6 timestamp", timestamp.UTC().Format(time.RFC3339Nano), last.UTC().Format(time.RFC3339Nano))
 	}
+	return nil
+}
 
-	// Set version (4 bits): version 7
-	uuid[6] = (uuid[6] & 0x0f) | 0x70
+// RandomNode returns a random 48-bit node with the multicast bit set, which
+// RFC 9562 section 6.10 requires so it can never collide with a real MAC
+// address
+func RandomNode() [6]byte {
+	var node [6]byte
+	readEntropy(node[:])
+	node[0] |= 0x01
+	return node
+}
 
-	// Set variant (2 bits): 10
-	uuid[8] = (uuid[8] & 0x3f) | 0x80
+// processNode is the node ProcessNode returns, drawn on first use
+var processNode = sync.OnceValue(RandomNode)
 
-	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x",
-		uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+// ProcessNode returns a random node with the multicast bit set that is
+// drawn once and then shared by every caller for the life of the process
+func ProcessNode() [6]byte {
+	return processNode()
 }
 
-// generateUUIDv6Manual is a manual implementation of UUIDv6
-func generateUUIDv6Manual() string {
-	// UUIDv6 is a field-compatible version of UUIDv1, reordered for improved DB locality
-	// Format: time_high (32 bits) + time_mid (16 bits) + time_low_and_version (16 bits) +
-	//         clock_seq_and_variant (16 bits) + node (48 bits)
-
-	// Get current time in 100-nanosecond intervals since UUID epoch (1582-10-15)
-	const uuidEpoch = uint64(122192928000000000) // UUID epoch in 100ns intervals
-	now := time.Now().UnixNano() / 100           // Convert to 100ns intervals
-
-	// Bounds checking for timestamp conversion to prevent integer overflow
-	var timestamp uint64
-	if now < 0 {
-		// Handle negative timestamps by using epoch time
-		timestamp = uuidEpoch
-	} else {
-		nowUint64 := uint64(now)
-		// Check if adding epoch would cause overflow
-		if nowUint64 > math.MaxUint64-uuidEpoch {
-			// Use maximum safe value to prevent overflow
-			timestamp = math.MaxUint64
-		} else {
-			timestamp = nowUint64 + uuidEpoch
+// ParseNode parses a 48-bit node written as 12 hex digits, optionally in
+// pairs separated by colons or hyphens as MAC addresses usually are
+func ParseNode(value string) ([6]byte, error) {
+	var node [6]byte
+	digits := strings.NewReplacer(":", "", "-", "").Replace(value)
+	if len(digits) == 12 {
+		if _, err := hex.Decode(node[:], []byte(digits)); err == nil {
+			return node, nil
 		}
 	}
+	return [6]byte{}, classify(ErrInvalidFormat, "invalid node '%s': expected 12 hex digits, such as 02005e100001 or 02:00:5e:10:00:01", value)
+}
 
-	var uuid [16]byte
+// GenerateUUIDv7 generates a time-ordered UUID (version 7)
+func GenerateUUIDv7() string {
+	return mustGenerate(GenerateUUIDv7Context(context.Background()))
+}
 
-	// Reorder timestamp for UUIDv6 (high, mid, low) with bounds checking
-	// Check bounds before narrowing conversions to prevent overflow
-	var timeHigh uint32
-	timeHighVal := timestamp >> 28
-	if timeHighVal > math.MaxUint32 {
-		timeHigh = math.MaxUint32
-	} else {
-		timeHigh = uint32(timeHighVal)
-	}
+// GenerateUUIDv7Context is GenerateUUIDv7 bounded by ctx: it returns
+// ctx.Err() once ctx is done, and entropy failures as errors. It generates
+// with the Default Generator.
+func GenerateUUIDv7Context(ctx context.Context) (string, error) {
+	return Default().NewV7(ctx)
+}
 
-	var timeMid uint16
-	timeMidVal := (timestamp >> 12) & 0xFFFF
-	if timeMidVal > math.MaxUint16 {
-		timeMid = math.MaxUint16
-	} else {
-		timeMid = uint16(timeMidVal)
+// GenerateUUIDv7WithTimestamp generates a UUIDv7 with a specific timestamp.
+// Timestamps outside the 48-bit Unix millisecond field, before 1970 or
+// after the year 10889, fail with ErrTimestampOutOfRange rather than
+// producing a UUID that sorts at the wrong time; see CheckUUIDv7Timestamp.
+// SECURITY NOTE: UUIDv7 embeds the timestamp directly in the UUID, revealing timing information.
+// This is by design per RFC 9562 but may not be suitable for privacy-sensitive applications.
+func GenerateUUIDv7WithTimestamp(timestamp time.Time) (string, error) {
+	if err := CheckUUIDv7Timestamp(timestamp); err != nil {
+		return "", err
 	}
+	start := time.Now()
+	debug("read clock", "timestamp_source", "explicit", "timestamp", timestamp)
+	uuid := NewV7FromBytes(timestamp, randomBytes())
+	debug("generated UUID", "version", 7, "uuid", uuid, "duration", time.Since(start))
+	return uuid, nil
+}
 
-	// timeLow is already masked to 12 bits, so no overflow possible
-	timeLowVal := timestamp & 0x0fff
-	var timeLow uint16
-	if timeLowVal > math.MaxUint16 {
-		timeLow = math.MaxUint16
-	} else {
-		timeLow = uint16(timeLowVal) // Safe conversion as timeLowVal is masked to 12 bits (< 4096)
+// CheckUUIDv7Timestamp reports whether a UUIDv7 can hold timestamp: the
+// field is an unsigned count of milliseconds since the Unix epoch, so it
+// runs from 1970-01-01 to 10889-08-02T05:31:50.655Z. The error matches
+// ErrTimestampOutOfRange.
+func CheckUUIDv7Timestamp(timestamp time.Time) error {
+	const max48 = 1<<48 - 1
+	if timestamp.Before(time.Unix(0, 0)) {
+		return classify(ErrTimestampOutOfRange, "timestamp %s is before the Unix epoch; UUIDv7 timestamps start at 1970-01-01T00:00:00Z", timestamp.UTC().Format(time.RFC3339Nano))
 	}
-
-	// Time high (32 bits)
-	uuid[0] = byte(timeHigh >> 24)
-	uuid[1] = byte(timeHigh >> 16)
-	uuid[2] = byte(timeHigh >> 8)
-	uuid[3] = byte(timeHigh)
-
-	// Time mid (16 bits)
-	uuid[4] = byte(timeMid >> 8)
-	uuid[5] = byte(timeMid)
-
-	// Time low and version (16 bits)
-	uuid[6] = byte(timeLow>>8) | 0x60 // Version 6
-	uuid[7] = byte(timeLow)
-
-	// Clock sequence and variant (16 bits) - use random + nano time for better entropy
-	clockSeq := make([]byte, 2)
-	if _, err := rand.Read(clockSeq); err != nil {
-		// Fallback with better entropy
-		nanoTime := time.Now().UnixNano()
-		clockSeq[0] = byte(nanoTime)
-		clockSeq[1] = byte(nanoTime >> 8)
+	if timestamp.UnixMilli() > max48 {
+		return classify(ErrTimestampOutOfRange, "timestamp %s is after %s, the last UUIDv7 timestamp", timestamp.UTC().Format(time.RFC3339Nano), time.UnixMilli(max48).UTC().Format(time.RFC3339Nano))
 	}
-	uuid[8] = (clockSeq[0] & 0x3f) | 0x80 // Set variant bits
-	uuid[9] = clockSeq[1]
-
-	// Node (48 bits) - fully random for better uniqueness
-	nodeBytes := make([]byte, 6)
-	if _, err := rand.Read(nodeBytes); err != nil {
-		// Fallback with high entropy from time and memory address
-		nanoTime := time.Now().UnixNano()
-		for i := 0; i < 6; i++ {
-			// Use different time shifts for each byte to maximize entropy
-			nodeBytes[i] = byte((nanoTime >> (i * 7)) ^ (nanoTime >> (i * 13)))
-		}
+	return nil
+}
+
+// GenerateUUIDv7Truncated generates a UUIDv7 whose timestamp is rounded down
+// to a multiple of granularity since the Unix epoch, so it reveals only the
+// bucket it was created in. UUIDs from different buckets still sort by
+// time; within a bucket they sort randomly. Granularities below a
+// millisecond leave the timestamp as it is.
+func GenerateUUIDv7Truncated(granularity time.Duration) string {
+	start := time.Now()
+	timestamp := currentTime()
+	if step := granularity.Milliseconds(); step > 1 {
+		ms := timestamp.UnixMilli()
+		timestamp = time.UnixMilli(ms - ((ms%step)+step)%step)
 	}
-	copy(uuid[10:], nodeBytes)
+	debug("read clock", "timestamp_source", "truncated", "granularity", granularity, "timestamp", timestamp)
+	uuid := NewV7FromBytes(timestamp, randomBytes())
+	debug("generated UUID", "version", 7, "uuid", uuid, "duration", time.Since(start))
+	return uuid
+}
 
-	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x",
-		uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+// NewV4FromBytes builds a UUIDv4 from caller-supplied random bytes,
+// overwriting only the version and variant bits. The caller is responsible
+// for the quality of the randomness.
+func NewV4FromBytes(random [16]byte) string {
+	random[6] = (random[6] & 0x0f) | 0x40
+	random[8] = (random[8] & 0x3f) | 0x80
+	return FormatUUID(random)
 }
 
-// generateUUIDv7Manual is a manual implementation of UUIDv7
-func generateUUIDv7Manual() string {
+// NewV7FromBytes builds a UUIDv7 from a timestamp and caller-supplied
+// random bytes. The first six bytes of random are replaced by the timestamp.
+func NewV7FromBytes(timestamp time.Time, random [16]byte) string {
 	// UUIDv7 format: timestamp (48 bits) + random (74 bits) + version (4 bits) + variant (2 bits)
-	now := time.Now().UnixMilli()
-
-	// Create 16 bytes for UUID
-	var uuid [16]byte
+	// WARNING: The timestamp is embedded in the first 48 bits and can be extracted by anyone
+	timestampMs := unixMillis48(timestamp)
 
 	// First 6 bytes: 48-bit timestamp in milliseconds
-	uuid[0] = byte(now >> 40)
-	uuid[1] = byte(now >> 32)
-	uuid[2] = byte(now >> 24)
-	uuid[3] = byte(now >> 16)
-	uuid[4] = byte(now >> 8)
-	uuid[5] = byte(now)
-
-	// Fill remaining bytes with random data
-	if _, err := rand.Read(uuid[6:]); err != nil {
-		// If we can't get random data, use a simple fallback
-		for i := 6; i < 16; i++ {
-			uuid[i] = byte(time.Now().UnixNano() % 256)
-		}
-	}
+	random[0] = byte(timestampMs >> 40)
+	random[1] = byte(timestampMs >> 32)
+	random[2] = byte(timestampMs >> 24)
+	random[3] = byte(timestampMs >> 16)
+	random[4] = byte(timestampMs >> 8)
+	random[5] = byte(timestampMs)
 
 	// Set version (4 bits): version 7
-	uuid[6] = (uuid[6] & 0x0f) | 0x70
+	random[6] = (random[6] & 0x0f) | 0x70
 
 	// Set variant (2 bits): 10
-	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	random[8] = (random[8] & 0x3f) | 0x80
 
-	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x",
-		uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+	return FormatUUID(random)
 }
 
-// ParseTimestamp parses various timestamp formats and returns a time.Time
-func ParseTimestamp(timestampStr string) (time.Time, error) {
-	// Try different formats in order of likelihood
-
-	// Unix timestamp (seconds) - 10 digits
-	if len(timestampStr) == 10 {
-		if ts, err := strconv.ParseInt(timestampStr, 10, 64); err == nil {
-			return time.Unix(ts, 0).UTC(), nil
-		}
-	}
+// buildUUIDv6 lays out a UUIDv6 from its fields
+func buildUUIDv6(timestamp time.Time, clockSeq uint16, node [6]byte) [16]byte {
+	// UUIDv6 is a field-compatible version of UUIDv1, reordered for improved DB locality
+	// Format: time_high (32 bits) + time_mid (16 bits) + time_low_and_version (16 bits) +
+	//         clock_seq_and_variant (16 bits) + node (48 bits)
+	return buildUUIDv6Ticks(gregorianTicks60(timestamp), clockSeq, node)
+}
 
-	// Unix timestamp (milliseconds) - 13 digits
-	if len(timestampStr) == 13 {
-		if ts, err := strconv.ParseInt(timestampStr, 10, 64); err == nil {
-			return time.UnixMilli(ts).UTC(), nil
-		}
-	}
+// buildUUIDv6Ticks lays out a UUIDv6 from a 60-bit count of 100ns intervals
+// since the UUID epoch
+func buildUUIDv6Ticks(ticks uint64, clockSeq uint16, node [6]byte) [16]byte {
+	var uuid [16]byte
+	uuid[0] = byte(ticks >> 52)
+	uuid[1] = byte(ticks >> 44)
+	uuid[2] = byte(ticks >> 36)
+	uuid[3] = byte(ticks >> 28)
+	uuid[4] = byte(ticks >> 20)
+	uuid[5] = byte(ticks >> 12)
+	uuid[6] = byte(ticks>>8)&0x0f | 0x60 // Version 6
+	uuid[7] = byte(ticks)
+	uuid[8] = byte(clockSeq>>8)&0x3f | 0x80 // Variant 10
+	uuid[9] = byte(clockSeq)
+	copy(uuid[10:], node[:])
+	return uuid
+}
 
-	// RFC3339 format: 2006-01-02T15:04:05Z07:00
-	if t, err := time.Parse(time.RFC3339, timestampStr); err == nil {
-		return t.UTC(), nil
+// mustGenerate unwraps the result of a context generator called with a
+// context that is never cancelled, where only an exhausted entropy source
+// can fail; see readEntropy
+func mustGenerate(uuid string, err error) string {
+	if err != nil {
+		panic(err)
 	}
+	return uuid
+}
 
-	// RFC3339 without timezone (assume UTC)
-	if t, err := time.Parse("2006-01-02T15:04:05", timestampStr); err == nil {
-		return t.UTC(), nil
-	}
+// randomBytes returns 16 bytes from the entropy source
+func randomBytes() [16]byte {
+	var b [16]byte
+	readEntropy(b[:])
+	return b
+}
 
-	// ISO date format: 2006-01-02
-	if t, err := time.Parse("2006-01-02", timestampStr); err == nil {
-		return t.UTC(), nil
+// unixMillis48 returns the Unix time in milliseconds clamped to the unsigned
+// 48-bit range of the UUIDv7 timestamp field, so out-of-range times saturate
+// instead of wrapping around
+func unixMillis48(t time.Time) uint64 {
+	const max48 = 1<<48 - 1
+	ms := t.UnixMilli()
+	switch {
+	case ms < 0:
+		return 0
+	case ms > max48:
+		return max48
 	}
+	return uint64(ms)
+}
 
-	// Date with time: 2006-01-02 15:04:05
-	if t, err := time.Parse("2006-01-02 15:04:05", timestampStr); err == nil {
-		return t.UTC(), nil
+// gregorianTicks60 returns the number of 100ns intervals since the UUID epoch
+// (1582-10-15) clamped to the 60-bit range of the UUIDv1/v6 timestamp field.
+// Seconds and nanoseconds are handled separately so times outside the range
+// of UnixNano do not overflow.
+func gregorianTicks60(t time.Time) uint64 {
+	const max60 = 1<<60 - 1
+	const ticksPerSecond = 10_000_000
+	seconds := t.Unix() + gregorianOffset/ticksPerSecond
+	switch {
+	case seconds < 0:
+		return 0
+	case uint64(seconds) > max60/ticksPerSecond:
+		return max60
 	}
-
-	// Try numeric timestamp with different lengths
-	if ts, err := strconv.ParseInt(timestampStr, 10, 64); err == nil {
-		// Heuristic: if > 1e12, likely milliseconds; otherwise seconds
-		if ts > 1e12 {
-			return time.UnixMilli(ts).UTC(), nil
-		} else {
-			return time.Unix(ts, 0).UTC(), nil
-		}
+	ticks := uint64(seconds)*ticksPerSecond + uint64(t.Nanosecond()/100)
+	if ticks > max60 {
+		return max60
 	}
-
-	return time.Time{}, fmt.Errorf("unable to parse timestamp '%s'. Supported formats: Unix timestamp (seconds/milliseconds), RFC3339 (2006-01-02T15:04:05Z), ISO date (2006-01-02), or date-time (2006-01-02 15:04:05)", timestampStr)
+	return ticks
 }
@@ -1,6 +1,10 @@
 package generator
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"regexp"
 	"strconv"
 	"strings"
@@ -73,6 +77,53 @@ func TestGenerateUUIDv6(t *testing.T) {
 	}
 }
 
+func TestGenerateUUIDv6WithNode(t *testing.T) {
+	node := [6]byte{0x03, 0x11, 0x22, 0x33, 0x44, 0x55}
+	seen := map[string]bool{}
+	for i := 0; i < 10; i++ {
+		uuid := GenerateUUIDv6WithNode(node)
+		if !strings.HasPrefix(uuid[14:], "6") || !strings.HasSuffix(uuid, "-031122334455") {
+			t.Errorf("Expected a UUIDv6 with node 031122334455, got %s", uuid)
+		}
+		if seen[uuid] {
+			t.Errorf("Duplicate UUIDv6 generated: %s", uuid)
+		}
+		seen[uuid] = true
+	}
+}
+
+func TestRandomNode(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if node := RandomNode(); node[0]&0x01 == 0 {
+			t.Fatalf("Expected the multicast bit to be set, got %x", node)
+		}
+	}
+}
+
+func TestProcessNode(t *testing.T) {
+	node := ProcessNode()
+	if node[0]&0x01 == 0 {
+		t.Errorf("Expected the multicast bit to be set, got %x", node)
+	}
+	if again := ProcessNode(); again != node {
+		t.Errorf("Expected the same node for the whole process, got %x then %x", node, again)
+	}
+}
+
+func TestParseNode(t *testing.T) {
+	want := [6]byte{0x02, 0x00, 0x5e, 0x10, 0x00, 0x01}
+	for _, value := range []string{"02005e100001", "02:00:5e:10:00:01", "02-00-5E-10-00-01"} {
+		if got, err := ParseNode(value); err != nil || got != want {
+			t.Errorf("ParseNode(%q): expected %x, got %x, %v", value, want, got, err)
+		}
+	}
+	for _, value := range []string{"", "02005e10000", "02005e1000011", "02005e10000g", "02005e100001ff"} {
+		if _, err := ParseNode(value); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("ParseNode(%q): expected ErrInvalidFormat, got %v", value, err)
+		}
+	}
+}
+
 func TestGenerateUUIDv7(t *testing.T) {
 	uuid := GenerateUUIDv7()
 
@@ -138,42 +189,220 @@ func TestUUIDUniqueness(t *testing.T) {
 	}
 }
 
-func TestGenerateUUIDv6Manual(t *testing.T) {
-	uuid := generateUUIDv6Manual()
+// The generators are native implementations; these tests pin their field
+// layout to the RFC 9562 appendix test vectors that the google/uuid package
+// they replaced also produced
+func TestFieldLayoutMatchesRFCVectors(t *testing.T) {
+	// RFC 9562 Appendix A.4: UUIDv4
+	random := [16]byte{0x91, 0x91, 0x08, 0xf7, 0x52, 0xd1, 0x43, 0x20, 0x9b, 0xac, 0xf8, 0x47, 0xdb, 0x41, 0x48, 0xa8}
+	if got := NewV4FromBytes(random); got != "919108f7-52d1-4320-9bac-f847db4148a8" {
+		t.Errorf("UUIDv4 layout mismatch: %s", got)
+	}
 
-	// Test format
-	if !uuidRegex.MatchString(uuid) {
-		t.Errorf("Manual UUIDv6 format is invalid: %s", uuid)
+	// RFC 9562 Appendix A.5: UUIDv6 for 2022-02-22 19:22:22 UTC
+	timestamp := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	node := [6]byte{0x9f, 0x6b, 0xde, 0xce, 0xd8, 0x46}
+	if got := FormatUUID(buildUUIDv6(timestamp, 0x33c8, node)); got != "1ec9414c-232a-6b00-b3c8-9f6bdeced846" {
+		t.Errorf("UUIDv6 layout mismatch: %s", got)
 	}
 
-	// Check version bit (should be 6)
-	parts := strings.Split(uuid, "-")
-	versionChar := parts[2][0]
-	if versionChar != '6' {
-		t.Errorf("Manual UUIDv6 version bit should be 6, got %c", versionChar)
+	// RFC 9562 Appendix A.6: UUIDv7 for the same instant
+	random = [16]byte{0, 0, 0, 0, 0, 0, 0x0c, 0xc3, 0x18, 0xc4, 0xdc, 0x0c, 0x0c, 0x07, 0x39, 0x8f}
+	if got := NewV7FromBytes(timestamp, random); got != "017f22e2-79b0-7cc3-98c4-dc0c0c07398f" {
+		t.Errorf("UUIDv7 layout mismatch: %s", got)
 	}
 }
 
-func TestGenerateUUIDv7Manual(t *testing.T) {
-	uuid := generateUUIDv7Manual()
+func TestGeneratedVersionAndVariantConformance(t *testing.T) {
+	generators := map[int]func() string{
+		4: GenerateUUIDv4,
+		6: GenerateUUIDv6,
+		7: GenerateUUIDv7,
+	}
 
-	// Test format
-	if !uuidRegex.MatchString(uuid) {
-		t.Errorf("Manual UUIDv7 format is invalid: %s", uuid)
+	for version, generate := range generators {
+		for i := 0; i < 1000; i++ {
+			value := generate()
+			if !uuidRegex.MatchString(value) {
+				t.Fatalf("UUIDv%d format is invalid: %s", version, value)
+			}
+			uuid, _ := ParseUUID(value)
+			details := Inspect(uuid)
+			if details.Version != version || details.Variant != VariantRFC {
+				t.Fatalf("Expected version %d with the RFC variant, got version %d variant %s: %s",
+					version, details.Version, details.Variant, value)
+			}
+		}
 	}
+}
 
-	// Check version bit (should be 7)
-	parts := strings.Split(uuid, "-")
-	versionChar := parts[2][0]
-	if versionChar != '7' {
-		t.Errorf("Manual UUIDv7 version bit should be 7, got %c", versionChar)
+func TestTimestampFieldsSaturate(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		v7   string
+		v6   string
+	}{
+		{"before epochs", time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC), "00000000-0000", "00000000-0000-6000"},
+		{"far future", time.Date(12000, 1, 1, 0, 0, 0, 0, time.UTC), "ffffffff-ffff", "ffffffff-ffff-6fff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewV7FromBytes(tt.time, [16]byte{}); !strings.HasPrefix(got, tt.v7) {
+				t.Errorf("UUIDv7 timestamp should not wrap, got %s", got)
+			}
+			if got := FormatUUID(buildUUIDv6(tt.time, 0, [6]byte{})); !strings.HasPrefix(got, tt.v6) {
+				t.Errorf("UUIDv6 timestamp should saturate, got %s", got)
+			}
+		})
+	}
+}
+
+// mustV6At generates a UUIDv6 for a timestamp the field can hold
+func mustV6At(t *testing.T, timestamp time.Time) string {
+	t.Helper()
+	uuid, err := GenerateUUIDv6WithTimestamp(timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uuid
+}
+
+func TestGenerateUUIDv6WithTimestampRange(t *testing.T) {
+	gregorian := time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC)
+	last := time.Date(5236, 3, 31, 21, 21, 0, 684_697_500, time.UTC)
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		prefix    string
+		err       string
+	}{
+		{"before the Gregorian reform", gregorian.Add(-time.Second), "", "timestamp 1582-10-14T23:59:59Z is before 1582-10-15T00:00:00Z, the first UUIDv6 timestamp"},
+		{"Gregorian reform", gregorian, "00000000-0000-6000-", ""},
+		{"before the Unix epoch", time.Date(1969, 7, 20, 0, 0, 0, 0, time.UTC), "1b19c29b-d588-6000-", ""},
+		{"last tick", last, "ffffffff-ffff-6fff-", ""},
+		{"after the last tick", last.Add(100 * time.Nanosecond), "", "timestamp 5236-03-31T21:21:00.6846976Z is after 5236-03-31T21:21:00.6846975Z, the last UUIDv6 timestamp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uuid, err := GenerateUUIDv6WithTimestamp(tt.timestamp)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err || !errors.Is(err, ErrTimestampOutOfRange) {
+					t.Errorf("Expected the out of range error %q, got %q, %v", tt.err, uuid, err)
+				}
+				return
+			}
+			if err != nil || !strings.HasPrefix(uuid, tt.prefix) {
+				t.Errorf("Expected a UUIDv6 starting %s, got %q, %v", tt.prefix, uuid, err)
+			}
+		})
+	}
+}
+
+func TestGenerateUUIDv6WithTimestamp(t *testing.T) {
+	// Sub-microsecond digits survive to 100ns; the last 23ns are below precision
+	testTime := time.Date(1999, 12, 31, 23, 59, 59, 123_456_723, time.UTC)
+	uuid := mustV6At(t, testTime)
+
+	d := Inspect(mustParse(t, uuid))
+	if d.Version != 6 || d.Variant != VariantRFC {
+		t.Fatalf("Expected an RFC UUIDv6, got %s", uuid)
+	}
+	if want := testTime.Truncate(100 * time.Nanosecond); d.Timestamp == nil || !d.Timestamp.Equal(want) {
+		t.Errorf("Expected timestamp %s, got %v", want, d.Timestamp)
+	}
+	if d.Node == nil || d.Node[0]&0x01 == 0 {
+		t.Errorf("Expected a random node with the multicast bit set, got %v", d.Node)
+	}
+
+	// UUIDs for later times sort later, whatever their random fields
+	later := mustV6At(t, testTime.Add(100*time.Nanosecond))
+	if later <= uuid {
+		t.Errorf("Expected %s to sort after %s", later, uuid)
+	}
+	if other := mustV6At(t, testTime); other == uuid {
+		t.Errorf("Expected random clock sequence and node to differ, got %s twice", uuid)
+	}
+}
+
+func TestGenerateUUIDv7Truncated(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC)
+	SetClock(func() time.Time { return now })
+	t.Cleanup(func() { SetClock(nil) })
+
+	for _, granularity := range []time.Duration{time.Second, time.Hour, 24 * time.Hour, 7 * 24 * time.Hour, 1500 * time.Millisecond} {
+		var last string
+		for i := range 48 {
+			now = time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC).Add(time.Duration(i) * granularity)
+			uuid := GenerateUUIDv7Truncated(granularity)
+			ts := Inspect(mustParse(t, uuid)).Timestamp
+			if ts == nil || ts.UnixMilli()%granularity.Milliseconds() != 0 {
+				t.Fatalf("%s: expected a timestamp on a bucket boundary, got %v", granularity, ts)
+			}
+			if ts.After(now) || now.Sub(*ts) >= granularity {
+				t.Fatalf("%s: expected the bucket containing %s, got %s", granularity, now, ts)
+			}
+			// Each UUID is in the next bucket, so it sorts after the last
+			if uuid <= last {
+				t.Fatalf("%s: expected %s to sort after %s", granularity, uuid, last)
+			}
+			last = uuid
+		}
+	}
+
+	// Below a millisecond the timestamp is kept
+	now = time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC)
+	if ts := Inspect(mustParse(t, GenerateUUIDv7Truncated(time.Microsecond))).Timestamp; ts == nil || !ts.Equal(now) {
+		t.Errorf("Expected the timestamp unchanged, got %v", ts)
+	}
+}
+
+// mustV7At generates a UUIDv7 for a timestamp the field can hold
+func mustV7At(t *testing.T, timestamp time.Time) string {
+	t.Helper()
+	uuid, err := GenerateUUIDv7WithTimestamp(timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uuid
+}
+
+func TestGenerateUUIDv7WithTimestampRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		prefix    string
+		err       string
+	}{
+		{"day before the epoch", time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), "", "timestamp 1969-12-31T00:00:00Z is before the Unix epoch; UUIDv7 timestamps start at 1970-01-01T00:00:00Z"},
+		{"nanosecond before the epoch", time.Unix(0, -1), "", "timestamp 1969-12-31T23:59:59.999999999Z is before the Unix epoch"},
+		{"1960", time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), "", "is before the Unix epoch"},
+		{"epoch", time.Unix(0, 0), "00000000-0000-7", ""},
+		{"modern", time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC), "0188b733-b800-7", ""},
+		{"last millisecond", time.UnixMilli(1<<48 - 1), "ffffffff-ffff-7", ""},
+		{"after the last millisecond", time.UnixMilli(1 << 48), "", "timestamp 10889-08-02T05:31:50.656Z is after 10889-08-02T05:31:50.655Z, the last UUIDv7 timestamp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uuid, err := GenerateUUIDv7WithTimestamp(tt.timestamp)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) || !errors.Is(err, ErrTimestampOutOfRange) {
+					t.Errorf("Expected an out of range error containing %q, got %q, %v", tt.err, uuid, err)
+				}
+				return
+			}
+			if err != nil || !strings.HasPrefix(uuid, tt.prefix) {
+				t.Errorf("Expected a UUIDv7 starting %s, got %q, %v", tt.prefix, uuid, err)
+			}
+		})
 	}
 }
 
 func TestGenerateUUIDv7WithTimestamp(t *testing.T) {
 	// Test with a known timestamp
 	testTime := time.Date(2023, 6, 14, 10, 30, 45, 0, time.UTC)
-	uuid := GenerateUUIDv7WithTimestamp(testTime)
+	uuid := mustV7At(t, testTime)
 
 	// Test format
 	if !uuidRegex.MatchString(uuid) {
@@ -209,13 +438,51 @@ func TestGenerateUUIDv7WithTimestamp(t *testing.T) {
 	}
 
 	// Test that multiple UUIDs with same timestamp are different (due to random bits)
-	uuid1 := GenerateUUIDv7WithTimestamp(testTime)
-	uuid2 := GenerateUUIDv7WithTimestamp(testTime)
+	uuid1 := mustV7At(t, testTime)
+	uuid2 := mustV7At(t, testTime)
 	if uuid1 == uuid2 {
 		t.Errorf("UUIDs with same timestamp should be different due to random bits")
 	}
 }
 
+func TestGenerateFromBytes(t *testing.T) {
+	var random [16]byte
+	for i := range random {
+		random[i] = 0xff
+	}
+
+	if got := NewV4FromBytes(random); got != "ffffffff-ffff-4fff-bfff-ffffffffffff" {
+		t.Errorf("NewV4FromBytes should only set version and variant bits, got %s", got)
+	}
+
+	timestamp := time.UnixMilli(0x0188b733b800)
+	if got := NewV7FromBytes(timestamp, random); got != "0188b733-b800-7fff-bfff-ffffffffffff" {
+		t.Errorf("NewV7FromBytes should embed the timestamp, got %s", got)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	pinned := time.Date(2023, 6, 14, 12, 30, 45, 123456700, time.UTC)
+	SetClock(func() time.Time { return pinned })
+	defer SetClock(nil)
+
+	uuid, err := ParseUUID(GenerateUUIDv6())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts := Inspect(uuid).Timestamp; ts == nil || !ts.Equal(pinned) {
+		t.Errorf("UUIDv6 should embed the pinned clock %v, got %v", pinned, ts)
+	}
+
+	uuid, err = ParseUUID(GenerateUUIDv7())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts := Inspect(uuid).Timestamp; ts == nil || !ts.Equal(pinned.Truncate(time.Millisecond)) {
+		t.Errorf("UUIDv7 should embed the pinned clock %v, got %v", pinned, ts)
+	}
+}
+
 func TestParseTimestamp(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -406,7 +673,7 @@ func TestUUIDv7GenerationFromVariousTimestampFormats(t *testing.T) {
 			}
 
 			// Generate UUIDv7 from the parsed timestamp
-			uuid := GenerateUUIDv7WithTimestamp(parsedTime)
+			uuid := mustV7At(t, parsedTime)
 
 			// Validate UUID format
 			if !uuidRegex.MatchString(uuid) {
@@ -440,3 +707,37 @@ func TestUUIDv7GenerationFromVariousTimestampFormats(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	uuid := GenerateUUIDv7()
+
+	records := make(map[string]map[string]any)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid JSON log line %q: %v", line, err)
+		}
+		records[record["msg"].(string)] = record
+	}
+
+	if r := records["read clock"]; r == nil || r["timestamp_source"] != "system" || r["timestamp"] == nil {
+		t.Errorf("Expected a clock record with its source, got %v", r)
+	}
+	if r := records["read entropy"]; r == nil || r["entropy_source"] != "crypto/rand" || r["bytes"] != float64(16) || r["duration"] == nil {
+		t.Errorf("Expected an entropy record with source, bytes, and duration, got %v", r)
+	}
+	if r := records["generated UUID"]; r == nil || r["version"] != float64(7) || r["uuid"] != uuid {
+		t.Errorf("Expected a generation record with the version and UUID, got %v", r)
+	}
+
+	SetLogger(nil)
+	buf.Reset()
+	GenerateUUIDv7()
+	if buf.Len() != 0 {
+		t.Errorf("Nothing should be logged without a logger, got %s", buf.String())
+	}
+}
@@ -0,0 +1,62 @@
+package generator
+
+import "time"
+
+// v1Clock is the clock sequence shared by every UUIDv1 in the process
+var v1Clock ClockSequence
+
+// GenerateUUIDv1 generates a Gregorian time-based UUID (version 1) with a
+// fresh random node whose multicast bit is set, so it never claims to be a
+// real MAC address
+func GenerateUUIDv1() string {
+	return GenerateUUIDv1WithNode(RandomNode())
+}
+
+// GenerateUUIDv1WithNode generates a UUIDv1 with a caller-chosen node, such
+// as the machine's MAC address. The clock sequence is kept for the process
+// as ClockSequence describes, so UUIDs from one node never repeat.
+func GenerateUUIDv1WithNode(node [6]byte) string {
+	start := time.Now()
+	random := randomBytes()
+	ticks, clockSeq := v1Clock.next(gregorianTicks60(currentTime()), uint16(random[8])<<8|uint16(random[9]))
+	uuid := FormatUUID(buildUUIDv1(ticks, clockSeq, node))
+	debug("generated UUID", "version", 1, "uuid", uuid, "clock_seq", clockSeq, "duration", time.Since(start))
+	return uuid
+}
+
+// buildUUIDv1 lays out a UUIDv1 from its fields
+func buildUUIDv1(ticks uint64, clockSeq uint16, node [6]byte) [16]byte {
+	// time_low (32 bits) + time_mid (16 bits) + version + time_high (4+12 bits) +
+	// variant + clock_seq (2+14 bits) + node (48 bits)
+	var uuid [16]byte
+	uuid[0] = byte(ticks >> 24)
+	uuid[1] = byte(ticks >> 16)
+	uuid[2] = byte(ticks >> 8)
+	uuid[3] = byte(ticks)
+	uuid[4] = byte(ticks >> 40)
+	uuid[5] = byte(ticks >> 32)
+	uuid[6] = byte(ticks>>56)&0x0f | 0x10 // Version 1
+	uuid[7] = byte(ticks >> 48)
+	uuid[8] = byte(clockSeq>>8)&0x3f | 0x80 // Variant 10
+	uuid[9] = byte(clockSeq)
+	copy(uuid[10:], node[:])
+	return uuid
+}
+
+// UUIDv6FromV1 rewrites a UUIDv1 as the UUIDv6 with the same timestamp,
+// clock sequence, and node: the 60-bit timestamp is reordered from most to
+// least significant so the result sorts by time, and the version becomes 6.
+// The conversion is lossless.
+func UUIDv6FromV1(uuid [16]byte) ([16]byte, error) {
+	details := Inspect(uuid)
+	if details.Version != 1 || details.Variant != VariantRFC {
+		return [16]byte{}, wrongVersion([]int{1}, details.Version, "only UUIDv1 converts to UUIDv6, got version %d", details.Version)
+	}
+	ticks := uint64(uuid[6]&0x0f)<<56 | uint64(uuid[7])<<48 |
+		uint64(uuid[4])<<40 | uint64(uuid[5])<<32 |
+		uint64(uuid[0])<<24 | uint64(uuid[1])<<16 | uint64(uuid[2])<<8 | uint64(uuid[3])
+	clockSeq := uint16(uuid[8])<<8 | uint16(uuid[9])
+	var node [6]byte
+	copy(node[:], uuid[10:])
+	return buildUUIDv6Ticks(ticks, clockSeq, node), nil
+}
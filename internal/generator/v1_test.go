@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildUUIDv1(t *testing.T) {
+	// RFC 9562 appendix A.1
+	node := [6]byte{0x9f, 0x6b, 0xde, 0xce, 0xd8, 0x46}
+	uuid := buildUUIDv1(0x1ec9414c232ab00, 0x33c8, node)
+	if got := FormatUUID(uuid); got != "c232ab00-9414-11ec-b3c8-9f6bdeced846" {
+		t.Errorf("Expected the RFC 9562 example, got %s", got)
+	}
+}
+
+func TestGenerateUUIDv1RoundTrip(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 10, 4, 12, 345_678_900, time.UTC)
+	SetClock(func() time.Time { return pinned })
+	t.Cleanup(func() { SetClock(nil) })
+
+	node := [6]byte{0x02, 0x00, 0x5e, 0x10, 0x00, 0x01}
+	d := Inspect(mustParse(t, GenerateUUIDv1WithNode(node)))
+	if d.Version != 1 || d.Variant != VariantRFC {
+		t.Fatalf("Expected an RFC UUIDv1, got version %d variant %s", d.Version, d.Variant)
+	}
+	if d.Timestamp == nil || !d.Timestamp.Equal(pinned) {
+		t.Errorf("Expected timestamp %s, got %v", pinned, d.Timestamp)
+	}
+	if d.Node == nil || *d.Node != node {
+		t.Errorf("Expected node %x, got %v", node, d.Node)
+	}
+
+	d = Inspect(mustParse(t, GenerateUUIDv1()))
+	if d.Node == nil || d.Node[0]&0x01 == 0 {
+		t.Errorf("Expected a random node with the multicast bit set, got %v", d.Node)
+	}
+}
+
+func TestGenerateUUIDv1ClockSequence(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+	t.Cleanup(func() { SetClock(nil) })
+
+	clockSeq := func() int {
+		t.Helper()
+		return *Inspect(mustParse(t, GenerateUUIDv1())).ClockSeq
+	}
+
+	// Start from a tick no earlier UUIDv1 in the test binary has used
+	now = now.Add(time.Hour)
+	first := clockSeq()
+
+	now = now.Add(time.Second)
+	if got := clockSeq(); got != first {
+		t.Errorf("Expected the clock sequence to hold while the clock advances, got %d then %d", first, got)
+	}
+
+	// Backwards, then the same tick again
+	now = now.Add(-time.Minute)
+	if got := clockSeq(); got != (first+1)&0x3fff {
+		t.Errorf("Expected the clock sequence to increment when the clock goes backwards, got %d then %d", first, got)
+	}
+	if got := clockSeq(); got != (first+2)&0x3fff {
+		t.Errorf("Expected the clock sequence to increment within one tick, got %d", got)
+	}
+}
+
+func TestUUIDv6FromV1(t *testing.T) {
+	// RFC 9562 appendices A.1 and A.5 describe the same instant, clock
+	// sequence, and node
+	v6, err := UUIDv6FromV1(mustParse(t, "c232ab00-9414-11ec-b3c8-9f6bdeced846"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := FormatUUID(v6); got != "1ec9414c-232a-6b00-b3c8-9f6bdeced846" {
+		t.Errorf("Expected the RFC 9562 UUIDv6 example, got %s", got)
+	}
+}
+
+func TestUUIDv6FromV1RoundTrip(t *testing.T) {
+	for _, ticks := range []uint64{0, 1, 0x1ec9414c232ab00, 1<<60 - 1} {
+		node := [6]byte{0x02, 0x00, 0x5e, 0x10, 0x00, 0x01}
+		v1 := buildUUIDv1(ticks, 0x2a5c, node)
+		v6, err := UUIDv6FromV1(v1)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		before, after := Inspect(v1), Inspect(v6)
+		if after.Version != 6 || after.Variant != VariantRFC {
+			t.Errorf("Expected an RFC UUIDv6, got version %d variant %s", after.Version, after.Variant)
+		}
+		if !after.Timestamp.Equal(*before.Timestamp) {
+			t.Errorf("Ticks %x: expected timestamp %s, got %s", ticks, before.Timestamp, after.Timestamp)
+		}
+		if *after.ClockSeq != *before.ClockSeq || *after.Node != *before.Node {
+			t.Errorf("Ticks %x: expected clock sequence %d and node %x kept, got %d and %x",
+				ticks, *before.ClockSeq, *before.Node, *after.ClockSeq, *after.Node)
+		}
+		if [8]byte(v6[8:]) != [8]byte(v1[8:]) {
+			t.Errorf("Ticks %x: expected the clock sequence and node bytes intact, got %x", ticks, v6[8:])
+		}
+	}
+
+	if _, err := UUIDv6FromV1(mustParse(t, "1ec9414c-232a-6b00-b3c8-9f6bdeced846")); err == nil || err.Error() != "only UUIDv1 converts to UUIDv6, got version 6" {
+		t.Errorf("Expected a version error, got: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package generator
+
+import "fmt"
+
+// V7Layout says how the 74 bits after a UUIDv7's timestamp are used. Nothing
+// in a UUIDv7 records its layout, so it has to come from knowing which
+// generator produced the UUID.
+type V7Layout int
+
+const (
+	// V7LayoutNone treats rand_a and rand_b as random bits with no counter
+	V7LayoutNone V7Layout = iota
+
+	// V7LayoutCounter12 is a 12-bit counter filling rand_a (RFC 9562
+	// section 6.2, method 1 with a 12-bit counter)
+	V7LayoutCounter12
+
+	// V7LayoutCounter is the 16-bit counter written by MonotonicV7 and
+	// ShardedMonotonicV7: the 12 bits of rand_a and the first 4 bits of
+	// rand_b
+	V7LayoutCounter
+)
+
+// v7LayoutNames are the names ParseV7Layout accepts, in V7Layout order
+var v7LayoutNames = []string{"none", "counter12", "counter"}
+
+// String returns the layout's name as accepted by ParseV7Layout
+func (l V7Layout) String() string {
+	if l < 0 || int(l) >= len(v7LayoutNames) {
+		return fmt.Sprintf("V7Layout(%d)", int(l))
+	}
+	return v7LayoutNames[l]
+}
+
+// ParseV7Layout returns the layout named name: none, counter12, or counter
+func ParseV7Layout(name string) (V7Layout, error) {
+	for i, n := range v7LayoutNames {
+		if n == name {
+			return V7Layout(i), nil
+		}
+	}
+	return 0, classify(ErrInvalidFormat, "unknown v7 layout '%s'. Available layouts: counter12, counter, none", name)
+}
+
+// RandA returns the 12 bits of rand_a of a UUIDv7, whatever they hold
+func (u UUID) RandA() (int, error) {
+	if err := u.requireV7(); err != nil {
+		return 0, err
+	}
+	return int(u[6]&0x0f)<<8 | int(u[7]), nil
+}
+
+// V7Counter decodes the counter of a UUIDv7 generated with layout. Any
+// UUIDv7 decodes, since every bit pattern is a valid counter; the result is
+// only meaningful when layout matches the generator that produced it.
+// V7LayoutNone has no counter and always fails.
+func (u UUID) V7Counter(layout V7Layout) (int, error) {
+	if err := u.requireV7(); err != nil {
+		return 0, err
+	}
+	randA := int(u[6]&0x0f)<<8 | int(u[7])
+	switch layout {
+	case V7LayoutCounter12:
+		return randA, nil
+	case V7LayoutCounter:
+		return randA<<4 | int(u[8]>>2)&0x0f, nil
+	case V7LayoutNone:
+		return 0, fmt.Errorf("v7 layout none has no counter")
+	default:
+		return 0, fmt.Errorf("unknown v7 layout %d", int(layout))
+	}
+}
+
+// requireV7 fails unless u is an RFC 9562 UUIDv7
+func (u UUID) requireV7() error {
+	if version := int(u[6] >> 4); version != 7 {
+		return wrongVersion([]int{7}, version, "%s is version %d, not 7", u, version)
+	}
+	if variant := variantOf(u); variant != VariantRFC {
+		return classify(ErrInvalidFormat, "%s has the %s variant, not rfc", u, variant)
+	}
+	return nil
+}
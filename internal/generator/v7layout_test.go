@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestV7CounterMonotonicBurst(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 10, 4, 12, 345_000_000, time.UTC)
+	SetClock(func() time.Time { return pinned })
+	t.Cleanup(func() { SetClock(nil) })
+
+	var g MonotonicV7
+	var last int
+	for i := range 100 {
+		uuid := UUID(mustParse(t, g.Generate()))
+		counter, err := uuid.V7Counter(V7LayoutCounter)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if i > 0 && counter != last+1 {
+			t.Fatalf("UUID %d: expected counter %d, got %d", i, last+1, counter)
+		}
+		last = counter
+	}
+}
+
+func TestV7CounterBitPlacement(t *testing.T) {
+	uuid := UUID(buildMonotonicV7(1, 0xabcd, 0x3f, [16]byte{}))
+	if counter, _ := uuid.V7Counter(V7LayoutCounter); counter != 0xabcd {
+		t.Errorf("Expected counter 0xabcd, got %#x", counter)
+	}
+	if counter, _ := uuid.V7Counter(V7LayoutCounter12); counter != 0xabc {
+		t.Errorf("Expected 12-bit counter 0xabc, got %#x", counter)
+	}
+	if randA, _ := uuid.RandA(); randA != 0xabc {
+		t.Errorf("Expected rand_a 0xabc, got %#x", randA)
+	}
+}
+
+func TestV7CounterErrors(t *testing.T) {
+	v7 := UUID(mustParse(t, GenerateUUIDv7()))
+	if _, err := v7.V7Counter(V7LayoutNone); err == nil {
+		t.Error("Expected layout none to have no counter")
+	}
+
+	v4 := UUID(mustParse(t, GenerateUUIDv4()))
+	_, err := v4.V7Counter(V7LayoutCounter)
+	var versionErr *VersionError
+	if !errors.As(err, &versionErr) || versionErr.Actual != 4 {
+		t.Errorf("Expected a VersionError for a v4, got %v", err)
+	}
+	if _, err := v4.RandA(); !errors.Is(err, ErrWrongVersion) {
+		t.Errorf("Expected ErrWrongVersion from RandA, got %v", err)
+	}
+}
+
+func TestParseV7Layout(t *testing.T) {
+	for _, layout := range []V7Layout{V7LayoutNone, V7LayoutCounter12, V7LayoutCounter} {
+		parsed, err := ParseV7Layout(layout.String())
+		if err != nil || parsed != layout {
+			t.Errorf("Expected %s to round-trip, got %v, %v", layout, parsed, err)
+		}
+	}
+	if _, err := ParseV7Layout("counter8"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected ErrInvalidFormat, got %v", err)
+	}
+}
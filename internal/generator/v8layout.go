@@ -0,0 +1,250 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// v8LayoutBits is the number of bits a UUIDv8 leaves to the application once
+// the version (4) and variant (2) bits are set
+const v8LayoutBits = 122
+
+// V8 layout fillers
+const (
+	V8FillMillis   = "ms"
+	V8FillNode     = "node"
+	V8FillSequence = "seq"
+	V8FillRandom   = "rand"
+)
+
+// V8Field is one field of a V8Layout
+type V8Field struct {
+	Fill  string
+	Width int
+	// Value is the node value written by a node field
+	Value uint64
+}
+
+// V8Layout describes how the 122 free bits of a UUIDv8 are filled, field by
+// field from the most significant bit, skipping the version and variant
+// bits. It is written as comma-separated fill:width fields, for example
+// "ms:48,node:10=42,seq:12,rand:52":
+//
+//	ms:N      the low N bits of the Unix millisecond timestamp
+//	node:N=V  the fixed value V (decimal or 0x hex)
+//	seq:N     a counter incremented for every UUID, wrapping at N bits
+//	rand:N    random bits
+//
+// Widths are 1 to 64 bits and must add up to 122. A layout has at most one
+// ms, node, and seq field.
+type V8Layout struct {
+	Fields []V8Field
+}
+
+// V8FieldValue is a field decoded from a UUIDv8 by V8Layout.Decode
+type V8FieldValue struct {
+	V8Field
+	// Timestamp is set for ms fields of 48 bits or more, which hold the
+	// full millisecond timestamp
+	Timestamp *time.Time
+}
+
+// ParseV8Layout parses a layout in the form described by V8Layout
+func ParseV8Layout(spec string) (*V8Layout, error) {
+	layout := &V8Layout{}
+	seen := map[string]bool{}
+	total := 0
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		fill, rest, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, classify(ErrInvalidFormat, "field '%s' is not in the form fill:width", part)
+		}
+		widthText, valueText, hasValue := strings.Cut(rest, "=")
+		width, err := strconv.Atoi(widthText)
+		if err != nil || width < 1 || width > 64 {
+			return nil, classify(ErrInvalidFormat, "field '%s' must have a width from 1 to 64 bits", part)
+		}
+		field := V8Field{Fill: fill, Width: width}
+
+		switch fill {
+		case V8FillMillis, V8FillSequence, V8FillRandom:
+			if hasValue {
+				return nil, classify(ErrInvalidFormat, "field '%s' does not take a value; only node fields do", part)
+			}
+		case V8FillNode:
+			if !hasValue {
+				return nil, classify(ErrInvalidFormat, "field '%s' needs a value, as in node:%d=42", part, width)
+			}
+			field.Value, err = strconv.ParseUint(valueText, 0, 64)
+			if err != nil || (width < 64 && field.Value>>width != 0) {
+				return nil, classify(ErrInvalidFormat, "field '%s' has a value that does not fit in %d bits", part, width)
+			}
+		default:
+			return nil, classify(ErrInvalidFormat, "unknown fill '%s'. Available fills: ms, node, seq, rand", fill)
+		}
+		if fill != V8FillRandom {
+			if seen[fill] {
+				return nil, classify(ErrInvalidFormat, "layout has more than one %s field", fill)
+			}
+			seen[fill] = true
+		}
+
+		total += width
+		layout.Fields = append(layout.Fields, field)
+	}
+	if total != v8LayoutBits {
+		return nil, classify(ErrInvalidFormat, "layout widths add up to %d bits, but a UUIDv8 has %d to fill", total, v8LayoutBits)
+	}
+	return layout, nil
+}
+
+// String renders the layout in the form ParseV8Layout reads
+func (l *V8Layout) String() string {
+	parts := make([]string, len(l.Fields))
+	for i, f := range l.Fields {
+		parts[i] = fmt.Sprintf("%s:%d", f.Fill, f.Width)
+		if f.Fill == V8FillNode {
+			parts[i] += "=" + strconv.FormatUint(f.Value, 10)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// CheckTimestamp reports whether the layout's ms field can hold timestamp:
+// the field is an unsigned count of Unix milliseconds, so the timestamp
+// must not be before 1970 or need more bits than the field has. A 48-bit
+// field has the UUIDv7 range, checked by CheckUUIDv7Timestamp; a layout
+// without an ms field accepts any timestamp. The error matches
+// ErrTimestampOutOfRange.
+func (l *V8Layout) CheckTimestamp(timestamp time.Time) error {
+	for _, f := range l.Fields {
+		if f.Fill != V8FillMillis {
+			continue
+		}
+		if f.Width == 48 {
+			return CheckUUIDv7Timestamp(timestamp)
+		}
+		if timestamp.Before(time.Unix(0, 0)) {
+			return classify(ErrTimestampOutOfRange, "timestamp %s is before the Unix epoch; ms fields start at 1970-01-01T00:00:00Z", timestamp.UTC().Format(time.RFC3339Nano))
+		}
+		if f.Width < 63 && uint64(timestamp.UnixMilli())>>f.Width != 0 {
+			last := time.UnixMilli(int64(1)<<f.Width - 1).UTC()
+			return classify(ErrTimestampOutOfRange, "timestamp %s is after %s, the last timestamp a %d-bit ms field holds", timestamp.UTC().Format(time.RFC3339Nano), last.Format(time.RFC3339Nano), f.Width)
+		}
+	}
+	return nil
+}
+
+// New lays out a UUIDv8 for timestamp and sequence number seq, taking rand
+// fields from random
+func (l *V8Layout) New(timestamp time.Time, seq uint64, random [16]byte) [16]byte {
+	uuid := random
+	bit := 0
+	for _, f := range l.Fields {
+		switch f.Fill {
+		case V8FillMillis:
+			writeV8Bits(&uuid, bit, f.Width, uint64(timestamp.UnixMilli()))
+		case V8FillNode:
+			writeV8Bits(&uuid, bit, f.Width, f.Value)
+		case V8FillSequence:
+			writeV8Bits(&uuid, bit, f.Width, seq)
+		}
+		bit += f.Width
+	}
+
+	// Set version (4 bits): version 8
+	uuid[6] = (uuid[6] & 0x0f) | 0x80
+
+	// Set variant (2 bits): 10
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+
+	return uuid
+}
+
+// Generator returns a function generating UUIDv8s in this layout from the
+// current time, or from timestamp when it is not zero. The sequence starts
+// at zero for every generator.
+func (l *V8Layout) Generator(timestamp time.Time) func() string {
+	var seq uint64
+	return func() string {
+		ts := timestamp
+		if ts.IsZero() {
+			ts = currentTime()
+		}
+		uuid := FormatUUID(l.New(ts, seq, randomBytes()))
+		debug("generated UUID", "version", 8, "layout", l.String(), "sequence", seq, "uuid", uuid)
+		seq++
+		return uuid
+	}
+}
+
+// Decode reads the fields of a UUIDv8 generated with this layout. Node
+// fields report the value found in the UUID rather than the layout's.
+func (l *V8Layout) Decode(uuid [16]byte) ([]V8FieldValue, error) {
+	if d := Inspect(uuid); d.Version != 8 || d.Variant != VariantRFC {
+		return nil, wrongVersion([]int{8}, d.Version, "only UUIDv8 has a custom layout, got version %d", d.Version)
+	}
+	values := make([]V8FieldValue, len(l.Fields))
+	bit := 0
+	for i, f := range l.Fields {
+		f.Value = readV8Bits(uuid, bit, f.Width)
+		values[i] = V8FieldValue{V8Field: f}
+		if f.Fill == V8FillMillis && f.Width >= 48 {
+			ts := time.UnixMilli(int64(f.Value)).UTC()
+			values[i].Timestamp = &ts
+		}
+		bit += f.Width
+	}
+	return values, nil
+}
+
+// GenerateUUIDv8 builds a UUIDv8 from a caller-defined 128-bit payload.
+// Only the version bits (48-51, the high nibble of byte 6) and variant bits
+// (64-65, the top two bits of byte 8) are overwritten; every other payload
+// bit is kept where it is, leaving RFC 9562's custom_a (48 bits), custom_b
+// (12 bits), and custom_c (62 bits) to the caller.
+func GenerateUUIDv8(payload [16]byte) string {
+	payload[6] = (payload[6] & 0x0f) | 0x80
+	payload[8] = (payload[8] & 0x3f) | 0x80
+	return FormatUUID(payload)
+}
+
+// v8BitPosition maps the nth free bit of a UUIDv8 to its bit position in the
+// UUID, counting from the most significant bit and stepping over the version
+// (bits 48-51) and variant (bits 64-65)
+func v8BitPosition(n int) int {
+	if n >= 48 {
+		n += 4
+	}
+	if n >= 64 {
+		n += 2
+	}
+	return n
+}
+
+// writeV8Bits stores the low width bits of value, most significant first,
+// in the free bits starting at the start-th
+func writeV8Bits(uuid *[16]byte, start, width int, value uint64) {
+	for i := 0; i < width; i++ {
+		pos := v8BitPosition(start + i)
+		mask := byte(0x80) >> (pos % 8)
+		if value>>(width-1-i)&1 == 1 {
+			uuid[pos/8] |= mask
+		} else {
+			uuid[pos/8] &^= mask
+		}
+	}
+}
+
+// readV8Bits is the inverse of writeV8Bits
+func readV8Bits(uuid [16]byte, start, width int) uint64 {
+	var value uint64
+	for i := 0; i < width; i++ {
+		pos := v8BitPosition(start + i)
+		value = value<<1 | uint64(uuid[pos/8]>>(7-pos%8)&1)
+	}
+	return value
+}
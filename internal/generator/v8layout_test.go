@@ -0,0 +1,193 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestV8LayoutRoundTrip(t *testing.T) {
+	ts := time.Date(2023, 6, 14, 10, 30, 45, 123_000_000, time.UTC)
+	tests := []struct {
+		name   string
+		layout string
+	}{
+		{"timestamp first", "ms:48,node:10=42,seq:12,rand:52"},
+		// Shard-aware: the shard leads so each shard's IDs cluster together
+		{"shard first", "node:16=0xbeef,ms:48,seq:14,rand:44"},
+		// Fields that straddle the version and variant bits
+		{"straddling", "rand:46,seq:6,node:13=8191,ms:57"},
+		{"random only", "rand:64,rand:58"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := ParseV8Layout(tt.layout)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, random := range [][16]byte{{}, {0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}} {
+				uuid := layout.New(ts, 37, random)
+				if d := Inspect(uuid); d.Version != 8 || d.Variant != VariantRFC {
+					t.Fatalf("Expected an RFC variant UUIDv8, got %+v", d)
+				}
+				fields, err := layout.Decode(uuid)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				for i, f := range fields {
+					want := layout.Fields[i].Value
+					switch f.Fill {
+					case V8FillMillis:
+						want = uint64(ts.UnixMilli())
+					case V8FillSequence:
+						want = 37
+					case V8FillRandom:
+						continue
+					}
+					if f.Value != want {
+						t.Errorf("%s:%d: expected %d, got %d", f.Fill, f.Width, want, f.Value)
+					}
+					if f.Fill == V8FillMillis && (f.Timestamp == nil || !f.Timestamp.Equal(ts)) {
+						t.Errorf("Expected the ms field to decode to %v, got %v", ts, f.Timestamp)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestV8LayoutBitPlacement(t *testing.T) {
+	layout, err := ParseV8Layout("ms:48,node:14=0x3fff,rand:60")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The timestamp fills the first 48 bits as in UUIDv7, and the node's 14
+	// bits follow the version nibble and continue after the variant
+	uuid := layout.New(time.UnixMilli(0x0188b733b800), 0, [16]byte{})
+	if got := FormatUUID(uuid); got != "0188b733-b800-8fff-b000-000000000000" {
+		t.Errorf("Unexpected layout: %s", got)
+	}
+}
+
+func TestV8LayoutGenerator(t *testing.T) {
+	layout, err := ParseV8Layout("ms:48,seq:2,rand:8,rand:64")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ts := time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC)
+	generate := layout.Generator(ts)
+	for i, want := range []uint64{0, 1, 2, 3, 0} {
+		uuid, _ := ParseUUID(generate())
+		fields, err := layout.Decode(uuid)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fields[1].Value != want || !fields[0].Timestamp.Equal(ts) {
+			t.Errorf("UUID %d: expected sequence %d at %v, got %+v", i, want, ts, fields)
+		}
+	}
+}
+
+func TestParseV8LayoutErrors(t *testing.T) {
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{"ms:48,rand:64,rand:6", "layout widths add up to 118 bits, but a UUIDv8 has 122 to fill"},
+		{"ms:48,rand:64,rand:12", "layout widths add up to 124 bits, but a UUIDv8 has 122 to fill"},
+		{"ms:48,clock:10,rand:64", "unknown fill 'clock'. Available fills: ms, node, seq, rand"},
+		{"ms,rand:64", "field 'ms' is not in the form fill:width"},
+		{"ms:0,rand:64", "field 'ms:0' must have a width from 1 to 64 bits"},
+		{"rand:65,rand:57", "field 'rand:65' must have a width from 1 to 64 bits"},
+		{"ms:48,node:10,rand:64", "field 'node:10' needs a value, as in node:10=42"},
+		{"ms:48,node:10=1024,rand:64", "field 'node:10=1024' has a value that does not fit in 10 bits"},
+		{"ms:48,seq:10=1,rand:64", "field 'seq:10=1' does not take a value; only node fields do"},
+		{"ms:48,ms:10,rand:64", "layout has more than one ms field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			_, err := ParseV8Layout(tt.layout)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Expected %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestV8LayoutCheckTimestamp(t *testing.T) {
+	tests := []struct {
+		layout    string
+		timestamp time.Time
+		expected  string
+	}{
+		{"ms:48,rand:62,rand:12", time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC), ""},
+		{"ms:48,rand:62,rand:12", time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC), "timestamp 1500-01-01T00:00:00Z is before the Unix epoch; UUIDv7 timestamps start at 1970-01-01T00:00:00Z"},
+		{"ms:40,rand:64,rand:18", time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), "timestamp 1969-12-31T00:00:00Z is before the Unix epoch; ms fields start at 1970-01-01T00:00:00Z"},
+		{"ms:40,rand:64,rand:18", time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC), "timestamp 2023-06-14T00:00:00Z is after 2004-11-03T19:53:47.775Z, the last timestamp a 40-bit ms field holds"},
+		{"ms:64,rand:58", time.Date(2023, 6, 14, 0, 0, 0, 0, time.UTC), ""},
+		{"rand:64,rand:58", time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout+" "+tt.timestamp.String(), func(t *testing.T) {
+			layout, err := ParseV8Layout(tt.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = layout.CheckTimestamp(tt.timestamp)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrTimestampOutOfRange) || err.Error() != tt.expected {
+				t.Errorf("Expected %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestV8LayoutDecodeRejectsOtherVersions(t *testing.T) {
+	layout, _ := ParseV8Layout("rand:64,rand:58")
+	uuid, _ := ParseUUID(GenerateUUIDv7())
+	if _, err := layout.Decode(uuid); err == nil || !strings.Contains(err.Error(), "got version 7") {
+		t.Errorf("Expected a UUIDv7 to be rejected, got %v", err)
+	}
+}
+
+func TestV8LayoutString(t *testing.T) {
+	layout, _ := ParseV8Layout(" ms:48, node:10=0x2a ,seq:12,rand:52")
+	if got := layout.String(); got != "ms:48,node:10=42,seq:12,rand:52" {
+		t.Errorf("Unexpected layout string: %s", got)
+	}
+}
+
+func TestGenerateUUIDv8(t *testing.T) {
+	// All ones and all zeros show exactly which bits version and variant take
+	for _, fill := range []byte{0x00, 0xff, 0x5a} {
+		var payload [16]byte
+		for i := range payload {
+			payload[i] = fill
+		}
+		uuid := mustParse(t, GenerateUUIDv8(payload))
+		if uuid[6]>>4 != 8 || uuid[8]>>6 != 0b10 {
+			t.Errorf("Expected version 8 and variant 10, got %x", uuid)
+		}
+		for i := range uuid {
+			mask := byte(0xff)
+			switch i {
+			case 6:
+				mask = 0x0f // bits 48-51 hold the version
+			case 8:
+				mask = 0x3f // bits 64-65 hold the variant
+			}
+			if uuid[i]&mask != payload[i]&mask {
+				t.Errorf("Payload byte %d changed: %02x became %02x", i, payload[i], uuid[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package generator
+
+import "fmt"
+
+// Reason codes reported by Validate
+const (
+	ReasonLength  = "length"
+	ReasonHyphens = "hyphens"
+	ReasonHex     = "hex"
+	ReasonVersion = "version"
+	ReasonVariant = "variant"
+)
+
+// ValidationError explains why a value is not a valid UUID. Reason is one of
+// the Reason constants and is stable for scripts to match on.
+type ValidationError struct {
+	Reason  string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Reason + ": " + e.Message
+}
+
+// Is reports whether target is ErrInvalidFormat
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidFormat
+}
+
+// Validate strictly checks that value is a canonical RFC 9562 UUID: 36
+// characters, hyphens only at the four canonical positions, hex digits
+// elsewhere (either case), a version from 1 to 8, and the RFC variant. The
+// Nil and Max UUIDs are also valid. Checks run in that order and the first
+// failure is returned as a *ValidationError.
+func Validate(value string) error {
+	if len(value) != 36 {
+		return &ValidationError{ReasonLength, fmt.Sprintf("expected 36 characters, got %d", len(value))}
+	}
+	for i := 0; i < len(value); i++ {
+		isHyphenPosition := i == 8 || i == 13 || i == 18 || i == 23
+		switch {
+		case isHyphenPosition && value[i] != '-':
+			return &ValidationError{ReasonHyphens, fmt.Sprintf("expected '-' at position %d, got '%c'", i+1, value[i])}
+		case !isHyphenPosition && value[i] == '-':
+			return &ValidationError{ReasonHyphens, fmt.Sprintf("unexpected '-' at position %d", i+1)}
+		case !isHyphenPosition && !isHexDigit(value[i]):
+			return &ValidationError{ReasonHex, fmt.Sprintf("invalid character '%c' at position %d, expected a hex digit", value[i], i+1)}
+		}
+	}
+
+	uuid, _ := decodeCanonical(value)
+	if uuid == [16]byte{} || uuid == maxUUID {
+		return nil
+	}
+	if version := uuid[6] >> 4; version < 1 || version > 8 {
+		return &ValidationError{ReasonVersion, fmt.Sprintf("version nibble %x is not a defined version (1-8)", version)}
+	}
+	if variant := variantOf(uuid); variant != VariantRFC {
+		return &ValidationError{ReasonVariant, fmt.Sprintf("%s variant, expected the RFC 9562 variant (10xx)", variant)}
+	}
+	return nil
+}
+
+var maxUUID = [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
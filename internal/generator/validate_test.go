@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		value  string
+		reason string
+	}{
+		{"919108f7-52d1-4320-9bac-f847db4148a8", ""},
+		{"919108F7-52D1-4320-9BAC-F847DB4148A8", ""},
+		{"00000000-0000-0000-0000-000000000000", ""},
+		{"ffffffff-ffff-ffff-ffff-ffffffffffff", ""},
+		{"919108f7-52d1-4320-9bac-f847db4148a", ReasonLength},
+		{"919108f752d143209bacf847db4148a8", ReasonLength},
+		{"919108f-752d1-4320-9bac-f847db4148a8", ReasonHyphens},
+		{"919108f7-52d1-4320-9bac-f847db414-a8", ReasonHyphens},
+		{"919108f7-52d1-4320-9bac-f847db4148g8", ReasonHex},
+		{"919108f7-52d1-0320-9bac-f847db4148a8", ReasonVersion},
+		{"919108f7-52d1-9320-9bac-f847db4148a8", ReasonVersion},
+		{"919108f7-52d1-4320-cbac-f847db4148a8", ReasonVariant},
+		{"919108f7-52d1-4320-1bac-f847db4148a8", ReasonVariant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			err := Validate(tt.value)
+			if tt.reason == "" {
+				if err != nil {
+					t.Errorf("Expected valid, got: %v", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Reason != tt.reason {
+				t.Errorf("Expected reason %s, got: %v", tt.reason, err)
+			}
+		})
+	}
+}
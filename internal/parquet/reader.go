@@ -0,0 +1,193 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// File is the content of a Parquet file read back by Read
+type File struct {
+	Columns []Column
+	// RowGroups holds the number of rows in each row group
+	RowGroups []int64
+	// Values holds each column's values in row order: [16]byte for
+	// UUIDColumn, string for StringColumn, and time.Time (UTC) for
+	// TimestampMillisColumn
+	Values [][]any
+}
+
+// NumRows returns the total number of rows
+func (f *File) NumRows() int64 {
+	var n int64
+	for _, rows := range f.RowGroups {
+		n += rows
+	}
+	return n
+}
+
+// Read decodes a Parquet file holding the column kinds Writer produces:
+// flat, required columns with uncompressed, PLAIN-encoded data pages. It is
+// the check on Writer's output rather than a general reader, and rejects
+// anything else.
+func Read(data []byte) (*File, error) {
+	if len(data) < 12 || !bytes.HasPrefix(data, []byte(magic)) || !bytes.HasSuffix(data, []byte(magic)) {
+		return nil, fmt.Errorf("not a parquet file: missing %s magic", magic)
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	if footerLen > len(data)-12 {
+		return nil, fmt.Errorf("footer length %d is larger than the file", footerLen)
+	}
+	r := compactReader{data: data[len(data)-8-footerLen : len(data)-8]}
+	meta, err := r.readStruct()
+	if err != nil {
+		return nil, fmt.Errorf("invalid footer: %w", err)
+	}
+
+	f := &File{}
+	schema, _ := meta[2].([]any)
+	if len(schema) < 2 {
+		return nil, fmt.Errorf("schema has no columns")
+	}
+	if root, _ := schema[0].(map[int16]any); root[5] != int64(len(schema)-1) {
+		return nil, fmt.Errorf("schema root has %v children, expected %d flat columns", root[5], len(schema)-1)
+	}
+	for _, element := range schema[1:] {
+		element, _ := element.(map[int16]any)
+		column, err := readSchemaElement(element)
+		if err != nil {
+			return nil, err
+		}
+		f.Columns = append(f.Columns, column)
+	}
+	f.Values = make([][]any, len(f.Columns))
+
+	groups, _ := meta[4].([]any)
+	for g, group := range groups {
+		group, _ := group.(map[int16]any)
+		rows, _ := group[3].(int64)
+		chunks, _ := group[1].([]any)
+		if len(chunks) != len(f.Columns) {
+			return nil, fmt.Errorf("row group %d has %d columns, expected %d", g, len(chunks), len(f.Columns))
+		}
+		for i, chunk := range chunks {
+			chunk, _ := chunk.(map[int16]any)
+			columnMeta, _ := chunk[3].(map[int16]any)
+			values, err := readColumnChunk(data, columnMeta, f.Columns[i])
+			if err != nil {
+				return nil, fmt.Errorf("row group %d, column '%s': %w", g, f.Columns[i].Name, err)
+			}
+			if int64(len(values)) != rows {
+				return nil, fmt.Errorf("row group %d, column '%s': %d values for %d rows", g, f.Columns[i].Name, len(values), rows)
+			}
+			f.Values[i] = append(f.Values[i], values...)
+		}
+		f.RowGroups = append(f.RowGroups, rows)
+	}
+	if numRows, _ := meta[3].(int64); numRows != f.NumRows() {
+		return nil, fmt.Errorf("footer says %d rows, row groups hold %d", numRows, f.NumRows())
+	}
+	return f, nil
+}
+
+// readSchemaElement maps a leaf SchemaElement back to a column kind
+func readSchemaElement(element map[int16]any) (Column, error) {
+	name, _ := element[4].([]byte)
+	column := Column{Name: string(name)}
+	if element[3] != int64(0) {
+		return column, fmt.Errorf("column '%s' is not required", name)
+	}
+	logical, _ := element[10].(map[int16]any)
+	switch element[1] {
+	case int64(typeFixedLenByteArray):
+		if _, ok := logical[14]; ok && element[2] == int64(16) {
+			column.Kind = UUIDColumn
+			return column, nil
+		}
+	case int64(typeByteArray):
+		if _, ok := logical[1]; ok {
+			column.Kind = StringColumn
+			return column, nil
+		}
+	case int64(typeInt64):
+		timestamp, _ := logical[8].(map[int16]any)
+		unit, _ := timestamp[2].(map[int16]any)
+		if _, ok := unit[1]; ok && timestamp[1] == true {
+			column.Kind = TimestampMillisColumn
+			return column, nil
+		}
+	}
+	return column, fmt.Errorf("column '%s' has an unsupported type", name)
+}
+
+// readColumnChunk decodes the data pages of one column chunk
+func readColumnChunk(data []byte, meta map[int16]any, column Column) ([]any, error) {
+	if meta[4] != int64(0) {
+		return nil, fmt.Errorf("compression codec %v is not supported", meta[4])
+	}
+	offset, _ := meta[9].(int64)
+	numValues, _ := meta[5].(int64)
+	size, _ := meta[7].(int64)
+	if offset < 4 || offset+size > int64(len(data)) {
+		return nil, fmt.Errorf("chunk at %d+%d is outside the file", offset, size)
+	}
+
+	var values []any
+	r := compactReader{data: data[offset : offset+size]}
+	for int64(len(values)) < numValues {
+		header, err := r.readStruct()
+		if err != nil {
+			return nil, fmt.Errorf("invalid page header: %w", err)
+		}
+		page, _ := header[5].(map[int16]any)
+		if header[1] != int64(0) || page == nil || page[2] != int64(encodingPlain) {
+			return nil, fmt.Errorf("only PLAIN data pages are supported")
+		}
+		pageLen, _ := header[3].(int64)
+		if pageLen > int64(len(r.data)-r.pos) {
+			return nil, fmt.Errorf("page of %d bytes runs past the chunk", pageLen)
+		}
+		body := r.data[r.pos : r.pos+int(pageLen)]
+		r.pos += int(pageLen)
+		count, _ := page[1].(int64)
+		if values, err = decodePlain(values, body, int(count), column.Kind); err != nil {
+			return nil, err
+		}
+	}
+	if r.pos != len(r.data) {
+		return nil, fmt.Errorf("%d bytes left after %d values", len(r.data)-r.pos, numValues)
+	}
+	return values, nil
+}
+
+// decodePlain appends count PLAIN-encoded values from body to values
+func decodePlain(values []any, body []byte, count int, kind ColumnKind) ([]any, error) {
+	for range count {
+		switch kind {
+		case UUIDColumn:
+			if len(body) < 16 {
+				return nil, fmt.Errorf("truncated page")
+			}
+			values = append(values, [16]byte(body[:16]))
+			body = body[16:]
+		case StringColumn:
+			if len(body) < 4 || int(binary.LittleEndian.Uint32(body)) > len(body)-4 {
+				return nil, fmt.Errorf("truncated page")
+			}
+			n := int(binary.LittleEndian.Uint32(body))
+			values = append(values, string(body[4:4+n]))
+			body = body[4+n:]
+		case TimestampMillisColumn:
+			if len(body) < 8 {
+				return nil, fmt.Errorf("truncated page")
+			}
+			values = append(values, time.UnixMilli(int64(binary.LittleEndian.Uint64(body))).UTC())
+			body = body[8:]
+		}
+	}
+	if len(body) != 0 {
+		return nil, fmt.Errorf("%d bytes left after %d values in a page", len(body), count)
+	}
+	return values, nil
+}
@@ -0,0 +1,80 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// sampleFile writes two UUID rows and returns the file
+func sampleFile(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, []Column{{"id", UUIDColumn}}, 10)
+	w.WriteRow([16]byte{1})
+	w.WriteRow([16]byte{2})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadRejectsDamagedFiles(t *testing.T) {
+	good := sampleFile(t)
+	footerLen := int(binary.LittleEndian.Uint32(good[len(good)-8:]))
+
+	withFooterLen := func(n uint32) []byte {
+		data := bytes.Clone(good)
+		binary.LittleEndian.PutUint32(data[len(data)-8:], n)
+		return data
+	}
+	// Drop the footer's final stop byte
+	shortFooter := append(bytes.Clone(good[:len(good)-9]), good[len(good)-8:]...)
+	binary.LittleEndian.PutUint32(shortFooter[len(shortFooter)-8:], uint32(footerLen-1))
+	// The data page header starts right after the leading magic; its
+	// num_values of 2 (zigzag 0x04) is at offset 12. Claiming one value
+	// leaves the second unread.
+	shortPage := bytes.Clone(good)
+	if shortPage[12] != 0x04 {
+		t.Fatalf("Unexpected page header % x", good[4:16])
+	}
+	shortPage[12] = 0x02
+
+	tests := []struct {
+		name     string
+		data     []byte
+		contains string
+	}{
+		{"empty", nil, "missing PAR1 magic"},
+		{"no trailing magic", good[:len(good)-1], "missing PAR1 magic"},
+		{"huge footer", withFooterLen(1 << 30), "is larger than the file"},
+		{"short footer", shortFooter, "invalid footer: truncated thrift data"},
+		{"short page", shortPage, "row group 0, column 'id': 16 bytes left after 1 values in a page"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error containing %q, got: %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestReadRejectsUnsupportedColumns(t *testing.T) {
+	good := sampleFile(t)
+	// Rewrite the column's logical type from UUID (field 14) to JSON (12)
+	footerLen := int(binary.LittleEndian.Uint32(good[len(good)-8:]))
+	footer := good[len(good)-8-footerLen : len(good)-8]
+	i := bytes.Index(footer, []byte{0xec, 0x00})
+	if i < 0 {
+		t.Fatalf("Cannot find the UUID logical type in the footer % x", footer)
+	}
+	data := bytes.Clone(good)
+	data[len(good)-8-footerLen+i] = 0xcc
+
+	if _, err := Read(data); err == nil || err.Error() != "column 'id' has an unsupported type" {
+		t.Errorf("Expected an unsupported type error, got %v", err)
+	}
+}
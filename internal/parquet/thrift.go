@@ -0,0 +1,246 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Thrift compact protocol type codes used by the Parquet metadata
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftDouble = 7
+	thriftBinary = 8
+	thriftList   = 9
+	thriftSet    = 10
+	thriftMap    = 11
+	thriftStruct = 12
+)
+
+// compactWriter encodes Thrift structs with the compact protocol. Fields must
+// be written in increasing id order within each struct.
+type compactWriter struct {
+	buf   []byte
+	last  int16
+	stack []int16
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1 ^ v>>63)
+}
+
+func (c *compactWriter) varint(v uint64) {
+	c.buf = binary.AppendUvarint(c.buf, v)
+}
+
+// field writes a field header, using the one-byte delta form when it can
+func (c *compactWriter) field(id int16, typ byte) {
+	if delta := id - c.last; delta > 0 && delta <= 15 {
+		c.buf = append(c.buf, byte(delta)<<4|typ)
+	} else {
+		c.buf = append(c.buf, typ)
+		c.varint(zigzag(int64(id)))
+	}
+	c.last = id
+}
+
+func (c *compactWriter) i32(id int16, v int32) {
+	c.field(id, thriftI32)
+	c.varint(zigzag(int64(v)))
+}
+
+func (c *compactWriter) i64(id int16, v int64) {
+	c.field(id, thriftI64)
+	c.varint(zigzag(v))
+}
+
+func (c *compactWriter) boolean(id int16, v bool) {
+	if v {
+		c.field(id, thriftTrue)
+	} else {
+		c.field(id, thriftFalse)
+	}
+}
+
+func (c *compactWriter) str(id int16, s string) {
+	c.field(id, thriftBinary)
+	c.elemString(s)
+}
+
+// beginStruct opens a struct-valued field; close it with end
+func (c *compactWriter) beginStruct(id int16) {
+	c.field(id, thriftStruct)
+	c.beginElem()
+}
+
+// emptyStruct writes a struct-valued field with no fields, the form Parquet
+// uses for the members of its LogicalType and TimeUnit unions
+func (c *compactWriter) emptyStruct(id int16) {
+	c.beginStruct(id)
+	c.end()
+}
+
+// beginList opens a list field of n elements of type elem, which follow as
+// elemI32, elemString, or beginElem/end calls
+func (c *compactWriter) beginList(id int16, elem byte, n int) {
+	c.field(id, thriftList)
+	if n < 15 {
+		c.buf = append(c.buf, byte(n)<<4|elem)
+	} else {
+		c.buf = append(c.buf, 0xf0|elem)
+		c.varint(uint64(n))
+	}
+}
+
+func (c *compactWriter) elemI32(v int32) {
+	c.varint(zigzag(int64(v)))
+}
+
+func (c *compactWriter) elemString(s string) {
+	c.varint(uint64(len(s)))
+	c.buf = append(c.buf, s...)
+}
+
+// beginElem opens a struct that is a list element, or the top-level struct
+func (c *compactWriter) beginElem() {
+	c.stack = append(c.stack, c.last)
+	c.last = 0
+}
+
+// end writes the stop byte that closes the innermost open struct
+func (c *compactWriter) end() {
+	c.buf = append(c.buf, 0)
+	c.last = c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+}
+
+// compactReader decodes compact-protocol Thrift into generic values: structs
+// become map[int16]any keyed by field id, lists and sets []any, integers
+// int64, binary []byte, and booleans bool. Maps are not used by Parquet's
+// metadata and are rejected.
+type compactReader struct {
+	data []byte
+	pos  int
+}
+
+var errTruncated = errors.New("truncated thrift data")
+
+func (r *compactReader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, errTruncated
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *compactReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *compactReader) zigzag() (int64, error) {
+	v, err := r.varint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+// readStruct reads fields up to the stop byte
+func (r *compactReader) readStruct() (map[int16]any, error) {
+	fields := map[int16]any{}
+	var last int16
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if b == 0 {
+			return fields, nil
+		}
+		typ, delta := b&0x0f, int16(b>>4)
+		id := last + delta
+		if delta == 0 {
+			v, err := r.zigzag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		switch typ {
+		case thriftTrue:
+			fields[id] = true
+		case thriftFalse:
+			fields[id] = false
+		default:
+			if fields[id], err = r.readValue(typ); err != nil {
+				return nil, err
+			}
+		}
+		last = id
+	}
+}
+
+func (r *compactReader) readValue(typ byte) (any, error) {
+	switch typ {
+	case thriftTrue, thriftFalse:
+		// Booleans inside lists are a byte each
+		b, err := r.byte()
+		return b == thriftTrue, err
+	case thriftByte:
+		b, err := r.byte()
+		return int64(int8(b)), err
+	case thriftI16, thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftDouble:
+		if r.pos+8 > len(r.data) {
+			return nil, errTruncated
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.data[r.pos:]))
+		r.pos += 8
+		return v, nil
+	case thriftBinary:
+		n, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(len(r.data)-r.pos) {
+			return nil, errTruncated
+		}
+		v := r.data[r.pos : r.pos+int(n)]
+		r.pos += int(n)
+		return v, nil
+	case thriftList, thriftSet:
+		header, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		n, elem := uint64(header>>4), header&0x0f
+		if n == 15 {
+			if n, err = r.varint(); err != nil {
+				return nil, err
+			}
+		}
+		if n > uint64(len(r.data)-r.pos) {
+			return nil, errTruncated
+		}
+		list := make([]any, n)
+		for i := range list {
+			if list[i], err = r.readValue(elem); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case thriftStruct:
+		return r.readStruct()
+	}
+	return nil, fmt.Errorf("unsupported thrift type %d", typ)
+}
@@ -0,0 +1,88 @@
+package parquet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompactRoundTrip(t *testing.T) {
+	var c compactWriter
+	c.beginElem()
+	c.i32(1, -3)
+	c.boolean(2, true)
+	c.str(3, "name")
+	c.beginList(4, thriftI32, 20)
+	for i := range 20 {
+		c.elemI32(int32(i))
+	}
+	c.beginStruct(5)
+	c.i64(1, 1<<40)
+	c.boolean(2, false)
+	c.end()
+	// A gap of more than 15 needs the long field header
+	c.emptyStruct(30)
+	c.beginList(31, thriftStruct, 2)
+	c.beginElem()
+	c.str(1, "a")
+	c.end()
+	c.beginElem()
+	c.end()
+	c.end()
+
+	r := compactReader{data: c.buf}
+	got, err := r.readStruct()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.pos != len(c.buf) {
+		t.Errorf("Expected to consume %d bytes, consumed %d", len(c.buf), r.pos)
+	}
+	list := make([]any, 20)
+	for i := range list {
+		list[i] = int64(i)
+	}
+	want := map[int16]any{
+		1:  int64(-3),
+		2:  true,
+		3:  []byte("name"),
+		4:  list,
+		5:  map[int16]any{1: int64(1 << 40), 2: false},
+		30: map[int16]any{},
+		31: []any{map[int16]any{1: []byte("a")}, map[int16]any{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestCompactShortForm(t *testing.T) {
+	// Field 1 as i32 1 is the delta header 0x15 then zigzag(1) = 2, and the
+	// stop byte; field 17 needs the type byte then zigzag(17) = 34
+	var c compactWriter
+	c.beginElem()
+	c.i32(1, 1)
+	c.i32(17, 0)
+	c.end()
+	if want := []byte{0x15, 0x02, 0x05, 0x22, 0x00, 0x00}; !reflect.DeepEqual(c.buf, want) {
+		t.Errorf("Expected % x, got % x", want, c.buf)
+	}
+}
+
+func TestCompactReaderTruncated(t *testing.T) {
+	var c compactWriter
+	c.beginElem()
+	c.str(1, strings.Repeat("x", 20))
+	c.beginList(2, thriftI32, 3)
+	c.elemI32(1)
+	c.elemI32(2)
+	c.elemI32(3)
+	c.end()
+
+	for n := range len(c.buf) {
+		r := compactReader{data: c.buf[:n]}
+		if _, err := r.readStruct(); err == nil {
+			t.Errorf("Expected an error reading the first %d bytes", n)
+		}
+	}
+}
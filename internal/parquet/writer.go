@@ -0,0 +1,309 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// magic starts and ends every Parquet file
+const magic = "PAR1"
+
+// pageSize is the plain-encoded size at which a data page is closed and a
+// new one started within a column chunk
+const pageSize = 1 << 20
+
+// Parquet physical types, converted types, and encodings from parquet.thrift
+const (
+	typeInt64             = 2
+	typeByteArray         = 6
+	typeFixedLenByteArray = 7
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+)
+
+// ColumnKind selects a column's physical type and logical annotation
+type ColumnKind int
+
+const (
+	// UUIDColumn holds [16]byte values as FIXED_LEN_BYTE_ARRAY(16) annotated
+	// with the UUID logical type
+	UUIDColumn ColumnKind = iota
+	// StringColumn holds string values as BYTE_ARRAY annotated STRING
+	StringColumn
+	// TimestampMillisColumn holds time.Time values as INT64 milliseconds
+	// since the Unix epoch, annotated TIMESTAMP(MILLIS, UTC)
+	TimestampMillisColumn
+)
+
+func (k ColumnKind) String() string {
+	switch k {
+	case UUIDColumn:
+		return "uuid"
+	case StringColumn:
+		return "string"
+	case TimestampMillisColumn:
+		return "timestamp"
+	}
+	return fmt.Sprintf("ColumnKind(%d)", int(k))
+}
+
+// Column is one required, non-nested column of a file
+type Column struct {
+	Name string
+	Kind ColumnKind
+}
+
+// columnChunk is a column's data in the open row group: finished pages and
+// the values of the page still being filled
+type columnChunk struct {
+	pages      []byte
+	page       []byte
+	pageValues int
+}
+
+// chunkMeta records where a written column chunk is and how big it is
+type chunkMeta struct {
+	offset, size int64
+}
+
+// rowGroupMeta records a written row group for the footer
+type rowGroupMeta struct {
+	rows   int
+	chunks []chunkMeta
+}
+
+// Writer streams rows to a Parquet file with uncompressed, PLAIN-encoded
+// data pages. Only the open row group is held in memory.
+type Writer struct {
+	w            io.Writer
+	columns      []Column
+	rowGroupSize int
+	offset       int64
+	chunks       []columnChunk
+	rows         int
+	rowGroups    []rowGroupMeta
+	err          error
+}
+
+// NewWriter writes the leading magic to w and returns a Writer for columns
+// that closes a row group every rowGroupSize rows. Close must be called to
+// write the footer; it does not close w.
+func NewWriter(w io.Writer, columns []Column, rowGroupSize int) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("a parquet file needs at least one column")
+	}
+	if rowGroupSize < 1 {
+		return nil, fmt.Errorf("row group size must be at least 1, got %d", rowGroupSize)
+	}
+	pw := &Writer{w: w, columns: columns, rowGroupSize: rowGroupSize, chunks: make([]columnChunk, len(columns))}
+	pw.write([]byte(magic))
+	return pw, pw.err
+}
+
+// write writes b, remembering the first error so later calls fail fast
+func (pw *Writer) write(b []byte) {
+	if pw.err != nil {
+		return
+	}
+	n, err := pw.w.Write(b)
+	pw.offset += int64(n)
+	pw.err = err
+}
+
+// WriteRow appends one row, with a value per column of the type its kind
+// holds
+func (pw *Writer) WriteRow(values ...any) error {
+	if pw.err != nil {
+		return pw.err
+	}
+	if len(values) != len(pw.columns) {
+		return fmt.Errorf("expected %d values per row, got %d", len(pw.columns), len(values))
+	}
+	for i, column := range pw.columns {
+		chunk := &pw.chunks[i]
+		switch v := values[i].(type) {
+		case [16]byte:
+			if column.Kind != UUIDColumn {
+				return pw.typeError(column, v)
+			}
+			chunk.page = append(chunk.page, v[:]...)
+		case string:
+			if column.Kind != StringColumn {
+				return pw.typeError(column, v)
+			}
+			chunk.page = binary.LittleEndian.AppendUint32(chunk.page, uint32(len(v)))
+			chunk.page = append(chunk.page, v...)
+		case time.Time:
+			if column.Kind != TimestampMillisColumn {
+				return pw.typeError(column, v)
+			}
+			chunk.page = binary.LittleEndian.AppendUint64(chunk.page, uint64(v.UnixMilli()))
+		default:
+			return pw.typeError(column, v)
+		}
+		chunk.pageValues++
+		if len(chunk.page) >= pageSize {
+			chunk.closePage()
+		}
+	}
+	pw.rows++
+	if pw.rows == pw.rowGroupSize {
+		pw.flushRowGroup()
+	}
+	return pw.err
+}
+
+func (pw *Writer) typeError(column Column, v any) error {
+	return fmt.Errorf("column '%s' holds %s values, got %T", column.Name, column.Kind, v)
+}
+
+// closePage moves the values being filled into a finished data page
+func (c *columnChunk) closePage() {
+	if c.pageValues == 0 {
+		return
+	}
+	var header compactWriter
+	header.beginElem()
+	header.i32(1, 0) // DATA_PAGE
+	header.i32(2, int32(len(c.page)))
+	header.i32(3, int32(len(c.page)))
+	header.beginStruct(5)
+	header.i32(1, int32(c.pageValues))
+	header.i32(2, encodingPlain)
+	header.i32(3, encodingRLE)
+	header.i32(4, encodingRLE)
+	header.end()
+	header.end()
+
+	c.pages = append(c.pages, header.buf...)
+	c.pages = append(c.pages, c.page...)
+	c.page = c.page[:0]
+	c.pageValues = 0
+}
+
+// flushRowGroup writes the open row group's column chunks one after another
+func (pw *Writer) flushRowGroup() {
+	if pw.rows == 0 {
+		return
+	}
+	group := rowGroupMeta{rows: pw.rows}
+	for i := range pw.chunks {
+		chunk := &pw.chunks[i]
+		chunk.closePage()
+		group.chunks = append(group.chunks, chunkMeta{offset: pw.offset, size: int64(len(chunk.pages))})
+		pw.write(chunk.pages)
+		chunk.pages = chunk.pages[:0]
+	}
+	pw.rowGroups = append(pw.rowGroups, group)
+	pw.rows = 0
+}
+
+// Close writes the last row group and the footer
+func (pw *Writer) Close() error {
+	pw.flushRowGroup()
+	footer := pw.footer()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	pw.write(append(footer, magic...))
+	return pw.err
+}
+
+// footer encodes the FileMetaData struct
+func (pw *Writer) footer() []byte {
+	var numRows int64
+	for _, group := range pw.rowGroups {
+		numRows += int64(group.rows)
+	}
+
+	var c compactWriter
+	c.beginElem()
+	c.i32(1, 1)
+	c.beginList(2, thriftStruct, len(pw.columns)+1)
+	c.beginElem()
+	c.str(4, "schema")
+	c.i32(5, int32(len(pw.columns)))
+	c.end()
+	for _, column := range pw.columns {
+		c.beginElem()
+		writeSchemaElement(&c, column)
+		c.end()
+	}
+	c.i64(3, numRows)
+	c.beginList(4, thriftStruct, len(pw.rowGroups))
+	for _, group := range pw.rowGroups {
+		c.beginElem()
+		c.beginList(1, thriftStruct, len(group.chunks))
+		var size int64
+		for i, chunk := range group.chunks {
+			c.beginElem()
+			c.i64(2, chunk.offset)
+			c.beginStruct(3)
+			c.i32(1, physicalType(pw.columns[i].Kind))
+			c.beginList(2, thriftI32, 1)
+			c.elemI32(encodingPlain)
+			c.beginList(3, thriftBinary, 1)
+			c.elemString(pw.columns[i].Name)
+			c.i32(4, 0) // UNCOMPRESSED
+			c.i64(5, int64(group.rows))
+			c.i64(6, chunk.size)
+			c.i64(7, chunk.size)
+			c.i64(9, chunk.offset)
+			c.end()
+			c.end()
+			size += chunk.size
+		}
+		c.i64(2, size)
+		c.i64(3, int64(group.rows))
+		c.end()
+	}
+	c.str(6, "github.com/scottbrown/uuid")
+	c.end()
+	return c.buf
+}
+
+func physicalType(kind ColumnKind) int32 {
+	switch kind {
+	case UUIDColumn:
+		return typeFixedLenByteArray
+	case StringColumn:
+		return typeByteArray
+	}
+	return typeInt64
+}
+
+// writeSchemaElement writes a required leaf column's SchemaElement fields,
+// with the legacy converted type alongside the logical type for older readers
+func writeSchemaElement(c *compactWriter, column Column) {
+	c.i32(1, physicalType(column.Kind))
+	if column.Kind == UUIDColumn {
+		c.i32(2, 16)
+	}
+	c.i32(3, 0) // REQUIRED
+	c.str(4, column.Name)
+	switch column.Kind {
+	case UUIDColumn:
+		c.beginStruct(10)
+		c.emptyStruct(14)
+		c.end()
+	case StringColumn:
+		c.i32(6, convertedUTF8)
+		c.beginStruct(10)
+		c.emptyStruct(1)
+		c.end()
+	case TimestampMillisColumn:
+		c.i32(6, convertedTimestampMillis)
+		c.beginStruct(10)
+		c.beginStruct(8)
+		c.boolean(1, true)
+		c.beginStruct(2)
+		c.emptyStruct(1)
+		c.end()
+		c.end()
+		c.end()
+	}
+}
@@ -0,0 +1,163 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	columns := []Column{{"id", UUIDColumn}, {"text", StringColumn}, {"created", TimestampMillisColumn}}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, columns, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	want := make([][]any, len(columns))
+	for i := range 7 {
+		id := [16]byte{15: byte(i)}
+		text := strings.Repeat("x", i)
+		created := base.Add(time.Duration(i) * time.Millisecond)
+		if err := w.WriteRow(id, text, created); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want[0] = append(want[0], id)
+		want[1] = append(want[1], text)
+		want[2] = append(want[2], created)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatalf("Expected PAR1 at both ends")
+	}
+	f, err := Read(data)
+	if err != nil {
+		t.Fatalf("Cannot read the file back: %v", err)
+	}
+	if !reflect.DeepEqual(f.Columns, columns) {
+		t.Errorf("Expected columns %v, got %v", columns, f.Columns)
+	}
+	if !reflect.DeepEqual(f.RowGroups, []int64{3, 3, 1}) {
+		t.Errorf("Expected row groups of 3, 3, and 1 rows, got %v", f.RowGroups)
+	}
+	if !reflect.DeepEqual(f.Values, want) {
+		t.Errorf("Expected values %v, got %v", want, f.Values)
+	}
+}
+
+func TestWriterSplitsPages(t *testing.T) {
+	// 100,000 UUIDs are 1.6 MB of PLAIN data, so the one row group needs two
+	// data pages
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, []Column{{"id", UUIDColumn}}, 1_000_000)
+	for i := range 100_000 {
+		var id [16]byte
+		binary.BigEndian.PutUint64(id[8:], uint64(i))
+		if err := w.WriteRow(id); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	f, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Cannot read the file back: %v", err)
+	}
+	if f.NumRows() != 100_000 || len(f.RowGroups) != 1 {
+		t.Fatalf("Expected 100000 rows in one row group, got %v", f.RowGroups)
+	}
+	for _, i := range []int{0, 65535, 65536, 99_999} {
+		id := f.Values[0][i].([16]byte)
+		if got := binary.BigEndian.Uint64(id[8:]); got != uint64(i) {
+			t.Errorf("Expected row %d to hold %d, got %d", i, i, got)
+		}
+	}
+
+	// The chunk starts right after the leading magic
+	r := compactReader{data: buf.Bytes()[4:]}
+	var pages, values int64
+	for values < 100_000 {
+		header, err := r.readStruct()
+		if err != nil {
+			t.Fatalf("Cannot read page header %d: %v", pages+1, err)
+		}
+		pageLen, _ := header[3].(int64)
+		count, _ := header[5].(map[int16]any)[1].(int64)
+		if pageLen > pageSize+16 {
+			t.Errorf("Expected pages of about %d bytes, got %d", pageSize, pageLen)
+		}
+		r.pos += int(pageLen)
+		pages, values = pages+1, values+count
+	}
+	if pages != 2 {
+		t.Errorf("Expected 2 data pages, found %d", pages)
+	}
+}
+
+func TestWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, []Column{{"id", UUIDColumn}}, 10)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Cannot read the file back: %v", err)
+	}
+	if f.NumRows() != 0 || len(f.RowGroups) != 0 {
+		t.Errorf("Expected no rows, got %v", f.RowGroups)
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, nil, 10); err == nil || err.Error() != "a parquet file needs at least one column" {
+		t.Errorf("Expected a missing columns error, got %v", err)
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, []Column{{"id", UUIDColumn}}, 0); err == nil || err.Error() != "row group size must be at least 1, got 0" {
+		t.Errorf("Expected a row group size error, got %v", err)
+	}
+
+	w, _ := NewWriter(&bytes.Buffer{}, []Column{{"id", UUIDColumn}, {"at", TimestampMillisColumn}}, 10)
+	if err := w.WriteRow([16]byte{}); err == nil || err.Error() != "expected 2 values per row, got 1" {
+		t.Errorf("Expected a value count error, got %v", err)
+	}
+	if err := w.WriteRow("not bytes", time.Now()); err == nil || err.Error() != "column 'id' holds uuid values, got string" {
+		t.Errorf("Expected a type error, got %v", err)
+	}
+
+	failing := errors.New("disk full")
+	w, _ = NewWriter(&limitWriter{n: 4, err: failing}, []Column{{"id", UUIDColumn}}, 1)
+	if err := w.WriteRow([16]byte{}); !errors.Is(err, failing) {
+		t.Errorf("Expected the write error, got %v", err)
+	}
+	if err := w.Close(); !errors.Is(err, failing) {
+		t.Errorf("Expected Close to report the write error, got %v", err)
+	}
+}
+
+// limitWriter accepts n bytes and then fails with err
+type limitWriter struct {
+	n   int
+	err error
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > l.n {
+		n := l.n
+		l.n = 0
+		return n, l.err
+	}
+	l.n -= len(p)
+	return len(p), nil
+}
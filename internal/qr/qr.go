@@ -0,0 +1,261 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is an error-correction level
+type Level int
+
+// Error-correction levels, recovering roughly 7%, 15%, 25%, and 30% of a
+// damaged code
+const (
+	Low Level = iota
+	Medium
+	Quartile
+	High
+)
+
+func (l Level) String() string {
+	return [...]string{"L", "M", "Q", "H"}[l]
+}
+
+// formatBits is the two-bit level indicator of the format information,
+// which does not follow the order of the levels
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// eccPerBlock and numBlocks give, for each level and version, the
+// error-correction codewords in each block and the number of blocks, from
+// ISO/IEC 18004 table 9. Index 0 is unused.
+var eccPerBlock = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// alphanumeric is the character set of alphanumeric mode, in value order
+const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// Encode returns the smallest QR code holding data at level as rows of
+// modules, true for dark, without the quiet zone. Data is encoded as one
+// segment in numeric, alphanumeric, or byte mode, whichever is the most
+// compact that holds every character.
+func Encode(data string, level Level) ([][]bool, error) {
+	mode := modeFor(data)
+	for version := 1; version <= 40; version++ {
+		capacity := dataCodewords(version, level) * 8
+		bits := mode.segment(data, version)
+		if bits.len() > capacity {
+			continue
+		}
+		codewords := bits.pad(capacity)
+		return newSymbol(version).draw(level, addECC(codewords, version, level)), nil
+	}
+	return nil, fmt.Errorf("%d characters do not fit in a QR code at level %s", len(data), level)
+}
+
+// mode is a segment encoding: its 4-bit indicator and the width of its
+// character count for versions 1-9, 10-26, and 27-40
+type mode struct {
+	indicator  int
+	countWidth [3]int
+}
+
+var (
+	numericMode      = mode{0b0001, [3]int{10, 12, 14}}
+	alphanumericMode = mode{0b0010, [3]int{9, 11, 13}}
+	byteMode         = mode{0b0100, [3]int{8, 16, 16}}
+)
+
+func modeFor(data string) mode {
+	numeric, alnum := true, true
+	for i := 0; i < len(data); i++ {
+		numeric = numeric && data[i] >= '0' && data[i] <= '9'
+		alnum = alnum && strings.IndexByte(alphanumeric, data[i]) >= 0
+	}
+	switch {
+	case numeric:
+		return numericMode
+	case alnum:
+		return alphanumericMode
+	}
+	return byteMode
+}
+
+// segment encodes data in mode m for version
+func (m mode) segment(data string, version int) *bitBuffer {
+	b := new(bitBuffer)
+	b.append(m.indicator, 4)
+	b.append(len(data), m.countWidth[(version+7)/17])
+	switch m {
+	case numericMode:
+		// Groups of three digits in 10 bits, with a shorter final group
+		for i := 0; i < len(data); i += 3 {
+			group := data[i:min(i+3, len(data))]
+			value := 0
+			for _, c := range []byte(group) {
+				value = value*10 + int(c-'0')
+			}
+			b.append(value, len(group)*3+1)
+		}
+	case alphanumericMode:
+		// Pairs in 11 bits, with a final single character in 6
+		for i := 0; i+1 < len(data); i += 2 {
+			b.append(strings.IndexByte(alphanumeric, data[i])*45+strings.IndexByte(alphanumeric, data[i+1]), 11)
+		}
+		if len(data)%2 == 1 {
+			b.append(strings.IndexByte(alphanumeric, data[len(data)-1]), 6)
+		}
+	default:
+		for i := 0; i < len(data); i++ {
+			b.append(int(data[i]), 8)
+		}
+	}
+	return b
+}
+
+// bitBuffer accumulates bits most significant first
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) len() int {
+	return len(b.bits)
+}
+
+func (b *bitBuffer) append(value, width int) {
+	for i := width - 1; i >= 0; i-- {
+		b.bits = append(b.bits, value>>i&1 == 1)
+	}
+}
+
+// pad adds the terminator, zero bits up to a byte boundary, and the
+// alternating pad codewords up to capacity bits, and returns the codewords
+func (b *bitBuffer) pad(capacity int) []byte {
+	b.append(0, min(4, capacity-b.len()))
+	b.append(0, (8-b.len()%8)%8)
+	for pad := 0xEC; b.len() < capacity; pad ^= 0xEC ^ 0x11 {
+		b.append(pad, 8)
+	}
+	codewords := make([]byte, b.len()/8)
+	for i, bit := range b.bits {
+		if bit {
+			codewords[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return codewords
+}
+
+// rawModules is the number of modules of a version available for
+// codewords: everything but the function patterns and format and version
+// information
+func rawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+// dataCodewords is the number of data codewords of a version and level
+func dataCodewords(version int, level Level) int {
+	return rawModules(version)/8 - eccPerBlock[level][version]*numBlocks[level][version]
+}
+
+// addECC splits data into the blocks of version and level, appends each
+// block's Reed-Solomon codewords, and interleaves the result
+func addECC(data []byte, version int, level Level) []byte {
+	blocks := numBlocks[level][version]
+	eccLen := eccPerBlock[level][version]
+	total := rawModules(version) / 8
+	// The first shortBlocks blocks hold one data codeword fewer
+	shortBlocks := blocks - total%blocks
+	shortLen := total/blocks - eccLen
+	divisor := rsDivisor(eccLen)
+
+	dataBlocks := make([][]byte, blocks)
+	eccBlocks := make([][]byte, blocks)
+	for i, offset := 0, 0; i < blocks; i++ {
+		n := shortLen
+		if i >= shortBlocks {
+			n++
+		}
+		dataBlocks[i] = data[offset : offset+n]
+		eccBlocks[i] = rsRemainder(dataBlocks[i], divisor)
+		offset += n
+	}
+
+	result := make([]byte, 0, total)
+	for i := 0; i <= shortLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for _, block := range eccBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of degree n,
+// the product of (x - α^i) for i below n, as its coefficients from the
+// second highest power down; the leading coefficient is 1
+func rsDivisor(n int) []byte {
+	divisor := make([]byte, n)
+	divisor[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := range divisor {
+			divisor[j] = gfMultiply(divisor[j], root)
+			if j+1 < n {
+				divisor[j] ^= divisor[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return divisor
+}
+
+// rsRemainder returns the remainder of data divided by divisor, which is
+// the block's error-correction codewords
+func rsRemainder(data, divisor []byte) []byte {
+	remainder := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[len(remainder)-1] = 0
+		for i, d := range divisor {
+			remainder[i] ^= gfMultiply(d, factor)
+		}
+	}
+	return remainder
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
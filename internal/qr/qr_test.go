@@ -0,0 +1,102 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestModeFor(t *testing.T) {
+	tests := []struct {
+		data string
+		want mode
+	}{
+		{"0123456789", numericMode},
+		{"0190A3B2-1C4D", alphanumericMode},
+		{"URN:UUID:0190A3B2", alphanumericMode},
+		{"urn:uuid:0190a3b2", byteMode},
+		{"", numericMode},
+	}
+	for _, tt := range tests {
+		if got := modeFor(tt.data); got != tt.want {
+			t.Errorf("modeFor(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestSegmentAndECC(t *testing.T) {
+	// The worked example of ISO/IEC 18004 annex I: "01234567" at 1-M
+	want := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	data := numericMode.segment("01234567", 1).pad(dataCodewords(1, Medium) * 8)
+	if !bytes.Equal(data, want) {
+		t.Fatalf("Data codewords = % X, want % X", data, want)
+	}
+
+	wantECC := []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}
+	codewords := addECC(data, 1, Medium)
+	if !bytes.Equal(codewords[len(data):], wantECC) {
+		t.Errorf("Error-correction codewords = % X, want % X", codewords[len(data):], wantECC)
+	}
+}
+
+func TestAddECCInterleaves(t *testing.T) {
+	// 5-Q has two blocks of 15 data codewords then two of 16
+	data := make([]byte, dataCodewords(5, Quartile))
+	for i := range data {
+		data[i] = byte(i)
+	}
+	codewords := addECC(data, 5, Quartile)
+	if len(codewords) != rawModules(5)/8 {
+		t.Fatalf("Expected %d codewords, got %d", rawModules(5)/8, len(codewords))
+	}
+	want := []byte{0, 15, 30, 46, 1, 16, 31, 47}
+	if !bytes.Equal(codewords[:8], want) {
+		t.Errorf("Interleaved data starts % d, want % d", codewords[:8], want)
+	}
+	// The final data codeword comes from the long blocks only
+	if codewords[len(data)-2] != 45 || codewords[len(data)-1] != 61 {
+		t.Errorf("Interleaved data ends % d, want 45 61", codewords[len(data)-2:len(data)])
+	}
+}
+
+func TestEncodeChoosesSmallestVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		level Level
+		size  int
+	}{
+		{"byte capacity of 1-L", strings.Repeat("x", 17), Low, 21},
+		{"one past 1-L", strings.Repeat("x", 18), Low, 25},
+		{"numeric capacity of 1-H", strings.Repeat("7", 17), High, 21},
+		{"alphanumeric capacity of 2-M", strings.Repeat("A", 38), Medium, 25},
+		{"hyphenated UUID at H", "0190A3B2-1C4D-7E5F-8A9B-0C1D2E3F4A5B", High, 33},
+		{"byte capacity of 40-L", strings.Repeat("x", 2953), Low, 177},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules, err := Encode(tt.data, tt.level)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(modules) != tt.size {
+				t.Errorf("Expected a %dx%d symbol, got %dx%d", tt.size, tt.size, len(modules), len(modules))
+			}
+			for _, row := range modules {
+				if len(row) != len(modules) {
+					t.Fatalf("Expected square rows, got a row of %d", len(row))
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	_, err := Encode(strings.Repeat("x", 2954), Low)
+	if err == nil {
+		t.Fatal("Expected an error for data beyond version 40")
+	}
+	if !strings.Contains(err.Error(), "2954 characters do not fit in a QR code at level L") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,291 @@
+package qr
+
+import "math"
+
+// symbol is a QR code being drawn: its modules, and which of them belong
+// to function patterns and so carry no data
+type symbol struct {
+	version  int
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// newSymbol draws the function patterns of version, reserving the format
+// information areas, which are filled once the mask is chosen
+func newSymbol(version int) *symbol {
+	size := version*4 + 17
+	s := &symbol{version: version, size: size, modules: grid(size), function: grid(size)}
+
+	// Timing patterns first; the finders and alignment patterns overwrite
+	// their ends
+	for i := 0; i < size; i++ {
+		s.set(6, i, i%2 == 0)
+		s.set(i, 6, i%2 == 0)
+	}
+	s.finder(3, 3)
+	s.finder(size-4, 3)
+	s.finder(3, size-4)
+
+	positions := alignmentPositions(version)
+	last := len(positions) - 1
+	for i, row := range positions {
+		for j, col := range positions {
+			// Skip the three corners the finders occupy
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					s.set(row+dy, col+dx, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	s.drawFormat(0)
+	s.drawVersion()
+	return s
+}
+
+func grid(size int) [][]bool {
+	rows := make([][]bool, size)
+	for i := range rows {
+		rows[i] = make([]bool, size)
+	}
+	return rows
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// set draws a function module
+func (s *symbol) set(row, col int, dark bool) {
+	s.modules[row][col] = dark
+	s.function[row][col] = true
+}
+
+// finder draws a finder pattern centred on row, col with its separator
+func (s *symbol) finder(row, col int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			r, c := row+dy, col+dx
+			if r < 0 || r >= s.size || c < 0 || c >= s.size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			s.set(r, c, d != 2 && d != 4)
+		}
+	}
+}
+
+// alignmentPositions returns the rows (and columns) of the alignment
+// pattern centres of version, evenly spaced from the far edge back to 6
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	positions := make([]int, n)
+	positions[0] = 6
+	for i, pos := n-1, version*4+10; i > 0; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// drawFormat draws both copies of the format information for level and
+// mask, and the dark module; level and mask are packed as level<<3|mask
+func (s *symbol) drawFormat(format int) {
+	bits := formatInfo(format)
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	// Around the top-left finder
+	for i := 0; i <= 5; i++ {
+		s.set(i, 8, bit(i))
+	}
+	s.set(7, 8, bit(6))
+	s.set(8, 8, bit(7))
+	s.set(8, 7, bit(8))
+	for i := 9; i < 15; i++ {
+		s.set(8, 14-i, bit(i))
+	}
+
+	// Split between the other two finders
+	for i := 0; i < 8; i++ {
+		s.set(8, s.size-1-i, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		s.set(s.size-15+i, 8, bit(i))
+	}
+	s.set(s.size-8, 8, true)
+}
+
+// drawVersion draws both copies of the version information, which only
+// versions 7 and up carry
+func (s *symbol) drawVersion() {
+	if s.version < 7 {
+		return
+	}
+	bits := versionInfo(s.version)
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 == 1
+		a, b := s.size-11+i%3, i/3
+		s.set(b, a, dark)
+		s.set(a, b, dark)
+	}
+}
+
+// formatInfo is the 15-bit format information for format: five data bits
+// with their BCH(15,5) check bits, masked so they are never all zero
+func formatInfo(format int) int {
+	rem := format
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (format<<10 | rem) ^ 0x5412
+}
+
+// versionInfo is the 18-bit version information for version: six data
+// bits with their BCH(18,6) check bits
+func versionInfo(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+// masks are the eight data mask conditions; a module is inverted where its
+// mask is true
+var masks = [8]func(row, col int) bool{
+	func(r, c int) bool { return (r+c)%2 == 0 },
+	func(r, c int) bool { return r%2 == 0 },
+	func(r, c int) bool { return c%3 == 0 },
+	func(r, c int) bool { return (r+c)%3 == 0 },
+	func(r, c int) bool { return (r/2+c/3)%2 == 0 },
+	func(r, c int) bool { return r*c%2+r*c%3 == 0 },
+	func(r, c int) bool { return (r*c%2+r*c%3)%2 == 0 },
+	func(r, c int) bool { return ((r+c)%2+r*c%3)%2 == 0 },
+}
+
+// draw places codewords, then applies whichever mask gives the lowest
+// penalty and returns the finished modules
+func (s *symbol) draw(level Level, codewords []byte) [][]bool {
+	s.place(codewords)
+
+	best, bestPenalty := 0, math.MaxInt
+	for mask := range masks {
+		s.applyMask(mask)
+		s.drawFormat(level.formatBits()<<3 | mask)
+		if p := s.penalty(); p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		// Masking twice restores the data
+		s.applyMask(mask)
+	}
+	s.applyMask(best)
+	s.drawFormat(level.formatBits()<<3 | best)
+	return s.modules
+}
+
+// place fills the data modules with codewords, in two-module columns
+// zigzagging up and down from the bottom right corner and skipping the
+// vertical timing pattern. Remainder modules are left light.
+func (s *symbol) place(codewords []byte) {
+	i := 0
+	for right := s.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < s.size; vert++ {
+			row := vert
+			if upward {
+				row = s.size - 1 - vert
+			}
+			for col := right; col >= right-1; col-- {
+				if s.function[row][col] || i >= len(codewords)*8 {
+					continue
+				}
+				s.modules[row][col] = codewords[i/8]>>(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+func (s *symbol) applyMask(mask int) {
+	for row := 0; row < s.size; row++ {
+		for col := 0; col < s.size; col++ {
+			if !s.function[row][col] && masks[mask](row, col) {
+				s.modules[row][col] = !s.modules[row][col]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol by the four rules of ISO/IEC 18004 7.8.3:
+// long runs of one colour, 2x2 blocks, finder-like patterns, and an uneven
+// balance of dark and light
+func (s *symbol) penalty() int {
+	score := 0
+	at := func(row, col int, transpose bool) bool {
+		if transpose {
+			return s.modules[col][row]
+		}
+		return s.modules[row][col]
+	}
+
+	for _, transpose := range []bool{false, true} {
+		for i := 0; i < s.size; i++ {
+			run := 1
+			for j := 1; j <= s.size; j++ {
+				if j < s.size && at(i, j, transpose) == at(i, j-1, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+			// 1:1:3:1:1 dark-light pattern with four light modules on
+			// either side
+			for j := 0; j+11 <= s.size; j++ {
+				var window [11]bool
+				for k := range window {
+					window[k] = at(i, j+k, transpose)
+				}
+				if window == [11]bool{true, false, true, true, true, false, true, false, false, false, false} ||
+					window == [11]bool{false, false, false, false, true, false, true, true, true, false, true} {
+					score += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for row := 0; row < s.size; row++ {
+		for col := 0; col < s.size; col++ {
+			if s.modules[row][col] {
+				dark++
+			}
+			if row+1 < s.size && col+1 < s.size {
+				c := s.modules[row][col]
+				if c == s.modules[row][col+1] && c == s.modules[row+1][col] && c == s.modules[row+1][col+1] {
+					score += 3
+				}
+			}
+		}
+	}
+	total := s.size * s.size
+	// 10 points for every full 5% the dark proportion is away from half
+	score += (abs(dark*20-total*10)+total-1)/total*10 - 10
+	return score
+}
@@ -0,0 +1,104 @@
+package qr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFormatInfo(t *testing.T) {
+	// Values from ISO/IEC 18004 annex C
+	tests := []struct {
+		level Level
+		mask  int
+		want  int
+	}{
+		{Low, 0, 0b111011111000100},
+		{Medium, 0, 0b101010000010010},
+		{Quartile, 7, 0b010101111101101},
+		{High, 0, 0b001011010001001},
+	}
+	for _, tt := range tests {
+		if got := formatInfo(tt.level.formatBits()<<3 | tt.mask); got != tt.want {
+			t.Errorf("formatInfo(%s, mask %d) = %015b, want %015b", tt.level, tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestVersionInfo(t *testing.T) {
+	// Values from ISO/IEC 18004 annex D
+	tests := map[int]int{
+		7:  0x07C94,
+		21: 0x15683,
+		40: 0x28C69,
+	}
+	for version, want := range tests {
+		if got := versionInfo(version); got != want {
+			t.Errorf("versionInfo(%d) = %#05x, want %#05x", version, got, want)
+		}
+	}
+}
+
+func TestAlignmentPositions(t *testing.T) {
+	tests := map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	}
+	for version, want := range tests {
+		if got := alignmentPositions(version); !slices.Equal(got, want) {
+			t.Errorf("alignmentPositions(%d) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestFunctionPatternsLeaveRawModules(t *testing.T) {
+	for version := 1; version <= 40; version++ {
+		s := newSymbol(version)
+		free := 0
+		for _, row := range s.function {
+			for _, function := range row {
+				if !function {
+					free++
+				}
+			}
+		}
+		if free != rawModules(version) {
+			t.Errorf("Version %d leaves %d data modules, want %d", version, free, rawModules(version))
+		}
+	}
+}
+
+func TestFinderPatterns(t *testing.T) {
+	modules, err := Encode("finder", Medium)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	size := len(modules)
+	want := []string{
+		"#######.",
+		"#.....#.",
+		"#.###.#.",
+		"#.###.#.",
+		"#.###.#.",
+		"#.....#.",
+		"#######.",
+		"........",
+	}
+	for y, line := range want {
+		for x, c := range line {
+			dark := c == '#'
+			// Top left, then mirrored into the top right and bottom left
+			corners := [][2]int{{y, x}, {y, size - 1 - x}, {size - 1 - y, x}}
+			for _, at := range corners {
+				if modules[at[0]][at[1]] != dark {
+					t.Fatalf("Module %v should be dark=%v", at, dark)
+				}
+			}
+		}
+	}
+	if !modules[size-8][8] {
+		t.Error("Expected the dark module beside the bottom left finder")
+	}
+}
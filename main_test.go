@@ -1,7 +1,11 @@
 package main
 
 import (
+	"os/exec"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestMainPackage(t *testing.T) {
@@ -11,3 +15,40 @@ func TestMainPackage(t *testing.T) {
 	// If we can import and run this test, the main package is working
 	t.Log("Main package test successful")
 }
+
+// BenchmarkColdStart measures the wall time of whole 'uuid' invocations,
+// including process start-up, as Make and shell loops see it. Besides the
+// mean it reports the p50 and p90 of the runs, which are steadier:
+//
+//	go test -run '^$' -bench ColdStart -benchtime 500x .
+func BenchmarkColdStart(b *testing.B) {
+	binary := filepath.Join(b.TempDir(), "uuid")
+	if out, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		b.Fatalf("go build: %v\n%s", err, out)
+	}
+
+	cases := []struct {
+		name string
+		args []string
+	}{
+		{"default", nil},
+		{"v7", []string{"-7"}},
+		{"count10", []string{"-n", "10"}},
+	}
+	for _, c := range cases {
+		args := c.args
+		b.Run(c.name, func(b *testing.B) {
+			runs := make([]time.Duration, 0, b.N)
+			for b.Loop() {
+				start := time.Now()
+				if err := exec.Command(binary, args...).Run(); err != nil {
+					b.Fatal(err)
+				}
+				runs = append(runs, time.Since(start))
+			}
+			slices.Sort(runs)
+			b.ReportMetric(float64(runs[len(runs)/2].Microseconds()), "p50-us")
+			b.ReportMetric(float64(runs[len(runs)*9/10].Microseconds()), "p90-us")
+		})
+	}
+}